@@ -0,0 +1,298 @@
+package appconn
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	abcicli "github.com/cometbft/cometbft/abci/client"
+	"github.com/cometbft/cometbft/libs/service"
+	"github.com/cometbft/cometbft/proxy"
+)
+
+const (
+	// defaultInitialBackoff is a delay before the first reconnection attempt.
+	defaultInitialBackoff = 100 * time.Millisecond
+	// defaultMaxBackoff limits the delay between reconnection attempts.
+	defaultMaxBackoff = 10 * time.Second
+)
+
+// ErrConnectionClosed is reported when ABCI connection was closed without error.
+var ErrConnectionClosed = errors.New("connection closed")
+
+type connType int
+
+const (
+	connQuery connType = iota
+	connSnapshot
+	connMempool
+	connConsensus
+	numConns
+)
+
+var connNames = [numConns]string{"query", "snapshot", "mempool", "consensus"}
+
+var _ proxy.AppConns = &AppConns{}
+
+// AppConns is an implementation of proxy.AppConns that survives ABCI application restarts.
+//
+// Unlike proxy.NewAppConns (which kills the process when application crashes), AppConns watches
+// all ABCI connections, and when any of them is terminated it closes remaining connections,
+// notifies OnDisconnect callbacks, and reconnects with exponential backoff. After successful
+// reconnection OnReconnect callbacks are notified.
+//
+// Connections returned by Consensus, Mempool, Query and Snapshot remain valid after reconnection.
+type AppConns struct {
+	service.BaseService
+
+	clientCreator proxy.ClientCreator
+	metrics       *proxy.Metrics
+
+	initialBackoff time.Duration
+	maxBackoff     time.Duration
+
+	// mtx guards clients, connections and response callbacks
+	mtx         sync.RWMutex
+	clients     [numConns]abcicli.Client
+	consensus   proxy.AppConnConsensus
+	mempool     proxy.AppConnMempool
+	query       proxy.AppConnQuery
+	snapshot    proxy.AppConnSnapshot
+	consensusCb abcicli.Callback
+	mempoolCb   abcicli.Callback
+	connected   bool
+
+	hooksMtx     sync.Mutex
+	onDisconnect []func(error)
+	onReconnect  []func()
+}
+
+// NewAppConns creates new AppConns, using clientCreator to establish connections to ABCI application.
+func NewAppConns(clientCreator proxy.ClientCreator, metrics *proxy.Metrics) *AppConns {
+	a := &AppConns{
+		clientCreator:  clientCreator,
+		metrics:        metrics,
+		initialBackoff: defaultInitialBackoff,
+		maxBackoff:     defaultMaxBackoff,
+	}
+	a.BaseService = *service.NewBaseService(nil, "AppConns", a)
+	return a
+}
+
+// OnDisconnect registers a function called when connection to ABCI application is lost.
+func (a *AppConns) OnDisconnect(fn func(error)) {
+	a.hooksMtx.Lock()
+	defer a.hooksMtx.Unlock()
+	a.onDisconnect = append(a.onDisconnect, fn)
+}
+
+// OnReconnect registers a function called after connection to ABCI application is re-established.
+func (a *AppConns) OnReconnect(fn func()) {
+	a.hooksMtx.Lock()
+	defer a.hooksMtx.Unlock()
+	a.onReconnect = append(a.onReconnect, fn)
+}
+
+// IsConnected returns true if all connections to ABCI application are established.
+func (a *AppConns) IsConnected() bool {
+	a.mtx.RLock()
+	defer a.mtx.RUnlock()
+	return a.connected
+}
+
+// Consensus returns consensus connection.
+func (a *AppConns) Consensus() proxy.AppConnConsensus {
+	return &consensusConn{a: a}
+}
+
+// Mempool returns mempool connection.
+func (a *AppConns) Mempool() proxy.AppConnMempool {
+	return &mempoolConn{a: a}
+}
+
+// Query returns query connection.
+func (a *AppConns) Query() proxy.AppConnQuery {
+	return &queryConn{a: a}
+}
+
+// Snapshot returns snapshot connection.
+func (a *AppConns) Snapshot() proxy.AppConnSnapshot {
+	return &snapshotConn{a: a}
+}
+
+// OnStart establishes connections to ABCI application and starts watching them.
+func (a *AppConns) OnStart() error {
+	clients, err := a.connect()
+	if err != nil {
+		return err
+	}
+	go a.watch(clients)
+	return nil
+}
+
+// OnStop closes connections to ABCI application.
+func (a *AppConns) OnStop() {
+	a.mtx.Lock()
+	clients := a.clients
+	a.connected = false
+	a.mtx.Unlock()
+	a.stopClients(clients)
+}
+
+// connect creates and starts clients for all connections, and makes them current.
+func (a *AppConns) connect() ([numConns]abcicli.Client, error) {
+	var clients [numConns]abcicli.Client
+	for i := connType(0); i < numConns; i++ {
+		c, err := a.clientCreator.NewABCIClient()
+		if err != nil {
+			a.stopClients(clients)
+			return clients, fmt.Errorf("error creating ABCI client (%s connection): %w", connNames[i], err)
+		}
+		c.SetLogger(a.Logger.With("module", "abci-client", "connection", connNames[i]))
+		if err := c.Start(); err != nil {
+			a.stopClients(clients)
+			return clients, fmt.Errorf("error starting ABCI client (%s connection): %w", connNames[i], err)
+		}
+		clients[i] = c
+	}
+
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+	if !a.IsRunning() {
+		a.stopClients(clients)
+		return clients, ErrConnectionClosed
+	}
+	a.clients = clients
+	a.query = proxy.NewAppConnQuery(clients[connQuery], a.metrics)
+	a.snapshot = proxy.NewAppConnSnapshot(clients[connSnapshot], a.metrics)
+	a.mempool = proxy.NewAppConnMempool(clients[connMempool], a.metrics)
+	a.consensus = proxy.NewAppConnConsensus(clients[connConsensus], a.metrics)
+	if a.mempoolCb != nil {
+		a.mempool.SetResponseCallback(a.mempoolCb)
+	}
+	if a.consensusCb != nil {
+		a.consensus.SetResponseCallback(a.consensusCb)
+	}
+	a.connected = true
+	return clients, nil
+}
+
+// watch waits for connection loss, and reconnects to ABCI application.
+func (a *AppConns) watch(clients [numConns]abcicli.Client) {
+	for {
+		err := a.waitForTermination(clients)
+		if err == nil {
+			return
+		}
+		a.Logger.Error("connection to ABCI application lost", "error", err)
+		a.mtx.Lock()
+		a.connected = false
+		a.mtx.Unlock()
+		a.stopClients(clients)
+
+		a.hooksMtx.Lock()
+		onDisconnect := a.onDisconnect
+		a.hooksMtx.Unlock()
+		for _, fn := range onDisconnect {
+			fn(err)
+		}
+
+		clients, err = a.reconnect()
+		if err != nil {
+			return
+		}
+
+		a.hooksMtx.Lock()
+		onReconnect := a.onReconnect
+		a.hooksMtx.Unlock()
+		for _, fn := range onReconnect {
+			fn()
+		}
+	}
+}
+
+// waitForTermination blocks until any of the clients is terminated. Nil is returned if AppConns is stopped.
+func (a *AppConns) waitForTermination(clients [numConns]abcicli.Client) error {
+	errCh := make(chan error, numConns)
+	for i, c := range clients {
+		go func(name string, c abcicli.Client) {
+			select {
+			case <-c.Quit():
+				err := c.Error()
+				if err == nil {
+					err = ErrConnectionClosed
+				}
+				errCh <- fmt.Errorf("%s connection: %w", name, err)
+			case <-a.Quit():
+			}
+		}(connNames[i], c)
+	}
+
+	select {
+	case err := <-errCh:
+		if !a.IsRunning() {
+			return nil
+		}
+		return err
+	case <-a.Quit():
+		return nil
+	}
+}
+
+// reconnect tries to connect to ABCI application with exponential backoff, until AppConns is stopped.
+func (a *AppConns) reconnect() ([numConns]abcicli.Client, error) {
+	backoff := a.initialBackoff
+	for attempt := 1; ; attempt++ {
+		select {
+		case <-a.Quit():
+			return [numConns]abcicli.Client{}, ErrConnectionClosed
+		case <-time.After(backoff):
+		}
+		clients, err := a.connect()
+		if err == nil {
+			a.Logger.Info("reconnected to ABCI application", "attempts", attempt)
+			return clients, nil
+		}
+		backoff *= 2
+		if backoff > a.maxBackoff {
+			backoff = a.maxBackoff
+		}
+		a.Logger.Error("failed to reconnect to ABCI application", "attempt", attempt, "retry in", backoff, "error", err)
+	}
+}
+
+func (a *AppConns) stopClients(clients [numConns]abcicli.Client) {
+	for i, c := range clients {
+		if c == nil || !c.IsRunning() {
+			continue
+		}
+		if err := c.Stop(); err != nil {
+			a.Logger.Error("error while stopping ABCI client", "connection", connNames[i], "error", err)
+		}
+	}
+}
+
+func (a *AppConns) currentConsensus() proxy.AppConnConsensus {
+	a.mtx.RLock()
+	defer a.mtx.RUnlock()
+	return a.consensus
+}
+
+func (a *AppConns) currentMempool() proxy.AppConnMempool {
+	a.mtx.RLock()
+	defer a.mtx.RUnlock()
+	return a.mempool
+}
+
+func (a *AppConns) currentQuery() proxy.AppConnQuery {
+	a.mtx.RLock()
+	defer a.mtx.RUnlock()
+	return a.query
+}
+
+func (a *AppConns) currentSnapshot() proxy.AppConnSnapshot {
+	a.mtx.RLock()
+	defer a.mtx.RUnlock()
+	return a.snapshot
+}
@@ -0,0 +1,83 @@
+package appconn
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/cometbft/cometbft/abci/example/kvstore"
+	"github.com/cometbft/cometbft/abci/server"
+	"github.com/cometbft/cometbft/libs/log"
+	"github.com/cometbft/cometbft/libs/service"
+	"github.com/cometbft/cometbft/proxy"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func startServer(t *testing.T, addr string) service.Service {
+	t.Helper()
+	srv := server.NewSocketServer(addr, kvstore.NewApplication())
+	srv.SetLogger(log.TestingLogger())
+	require.NoError(t, srv.Start())
+	return srv
+}
+
+func TestReconnect(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	addr := "unix://" + filepath.Join(t.TempDir(), "app.sock")
+	srv := startServer(t, addr)
+
+	conns := NewAppConns(proxy.NewRemoteClientCreator(addr, "socket", true), proxy.NopMetrics())
+	conns.SetLogger(log.TestingLogger())
+	conns.initialBackoff = 10 * time.Millisecond
+	conns.maxBackoff = 50 * time.Millisecond
+
+	disconnected := make(chan error, 1)
+	reconnected := make(chan struct{}, 1)
+	conns.OnDisconnect(func(err error) { disconnected <- err })
+	conns.OnReconnect(func() { reconnected <- struct{}{} })
+
+	require.NoError(conns.Start())
+	defer func() {
+		require.NoError(conns.Stop())
+	}()
+	assert.True(conns.IsConnected())
+
+	// connections are obtained once, and should be usable after reconnection
+	query := conns.Query()
+	_, err := query.InfoSync(proxy.RequestInfo)
+	require.NoError(err)
+
+	// application crash
+	require.NoError(srv.Stop())
+	select {
+	case err := <-disconnected:
+		assert.Error(err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("connection loss not detected")
+	}
+	assert.False(conns.IsConnected())
+
+	// application restart
+	srv = startServer(t, addr)
+	defer func() {
+		require.NoError(srv.Stop())
+	}()
+	select {
+	case <-reconnected:
+	case <-time.After(5 * time.Second):
+		t.Fatal("failed to reconnect")
+	}
+	assert.True(conns.IsConnected())
+	_, err = query.InfoSync(proxy.RequestInfo)
+	assert.NoError(err)
+}
+
+func TestStartWithoutApplication(t *testing.T) {
+	addr := "unix://" + filepath.Join(t.TempDir(), "app.sock")
+	conns := NewAppConns(proxy.NewRemoteClientCreator(addr, "socket", true), proxy.NopMetrics())
+	conns.SetLogger(log.TestingLogger())
+	assert.Error(t, conns.Start())
+}
@@ -0,0 +1,136 @@
+package appconn
+
+import (
+	abcicli "github.com/cometbft/cometbft/abci/client"
+	"github.com/cometbft/cometbft/abci/types"
+	"github.com/cometbft/cometbft/proxy"
+)
+
+// Connections below delegate all calls to current connection of AppConns, so they can be used
+// across reconnections.
+
+type consensusConn struct {
+	a *AppConns
+}
+
+var _ proxy.AppConnConsensus = &consensusConn{}
+
+func (c *consensusConn) SetResponseCallback(cb abcicli.Callback) {
+	c.a.mtx.Lock()
+	defer c.a.mtx.Unlock()
+	c.a.consensusCb = cb
+	c.a.consensus.SetResponseCallback(cb)
+}
+
+func (c *consensusConn) Error() error {
+	return c.a.currentConsensus().Error()
+}
+
+func (c *consensusConn) InitChainSync(req types.RequestInitChain) (*types.ResponseInitChain, error) {
+	return c.a.currentConsensus().InitChainSync(req)
+}
+
+func (c *consensusConn) PrepareProposalSync(req types.RequestPrepareProposal) (*types.ResponsePrepareProposal, error) {
+	return c.a.currentConsensus().PrepareProposalSync(req)
+}
+
+func (c *consensusConn) ProcessProposalSync(req types.RequestProcessProposal) (*types.ResponseProcessProposal, error) {
+	return c.a.currentConsensus().ProcessProposalSync(req)
+}
+
+func (c *consensusConn) BeginBlockSync(req types.RequestBeginBlock) (*types.ResponseBeginBlock, error) {
+	return c.a.currentConsensus().BeginBlockSync(req)
+}
+
+func (c *consensusConn) DeliverTxAsync(req types.RequestDeliverTx) *abcicli.ReqRes {
+	return c.a.currentConsensus().DeliverTxAsync(req)
+}
+
+func (c *consensusConn) EndBlockSync(req types.RequestEndBlock) (*types.ResponseEndBlock, error) {
+	return c.a.currentConsensus().EndBlockSync(req)
+}
+
+func (c *consensusConn) CommitSync() (*types.ResponseCommit, error) {
+	return c.a.currentConsensus().CommitSync()
+}
+
+type mempoolConn struct {
+	a *AppConns
+}
+
+var _ proxy.AppConnMempool = &mempoolConn{}
+
+func (c *mempoolConn) SetResponseCallback(cb abcicli.Callback) {
+	c.a.mtx.Lock()
+	defer c.a.mtx.Unlock()
+	c.a.mempoolCb = cb
+	c.a.mempool.SetResponseCallback(cb)
+}
+
+func (c *mempoolConn) Error() error {
+	return c.a.currentMempool().Error()
+}
+
+func (c *mempoolConn) CheckTxAsync(req types.RequestCheckTx) *abcicli.ReqRes {
+	return c.a.currentMempool().CheckTxAsync(req)
+}
+
+func (c *mempoolConn) CheckTxSync(req types.RequestCheckTx) (*types.ResponseCheckTx, error) {
+	return c.a.currentMempool().CheckTxSync(req)
+}
+
+func (c *mempoolConn) FlushAsync() *abcicli.ReqRes {
+	return c.a.currentMempool().FlushAsync()
+}
+
+func (c *mempoolConn) FlushSync() error {
+	return c.a.currentMempool().FlushSync()
+}
+
+type queryConn struct {
+	a *AppConns
+}
+
+var _ proxy.AppConnQuery = &queryConn{}
+
+func (c *queryConn) Error() error {
+	return c.a.currentQuery().Error()
+}
+
+func (c *queryConn) EchoSync(msg string) (*types.ResponseEcho, error) {
+	return c.a.currentQuery().EchoSync(msg)
+}
+
+func (c *queryConn) InfoSync(req types.RequestInfo) (*types.ResponseInfo, error) {
+	return c.a.currentQuery().InfoSync(req)
+}
+
+func (c *queryConn) QuerySync(req types.RequestQuery) (*types.ResponseQuery, error) {
+	return c.a.currentQuery().QuerySync(req)
+}
+
+type snapshotConn struct {
+	a *AppConns
+}
+
+var _ proxy.AppConnSnapshot = &snapshotConn{}
+
+func (c *snapshotConn) Error() error {
+	return c.a.currentSnapshot().Error()
+}
+
+func (c *snapshotConn) ListSnapshotsSync(req types.RequestListSnapshots) (*types.ResponseListSnapshots, error) {
+	return c.a.currentSnapshot().ListSnapshotsSync(req)
+}
+
+func (c *snapshotConn) OfferSnapshotSync(req types.RequestOfferSnapshot) (*types.ResponseOfferSnapshot, error) {
+	return c.a.currentSnapshot().OfferSnapshotSync(req)
+}
+
+func (c *snapshotConn) LoadSnapshotChunkSync(req types.RequestLoadSnapshotChunk) (*types.ResponseLoadSnapshotChunk, error) {
+	return c.a.currentSnapshot().LoadSnapshotChunkSync(req)
+}
+
+func (c *snapshotConn) ApplySnapshotChunkSync(req types.RequestApplySnapshotChunk) (*types.ResponseApplySnapshotChunk, error) {
+	return c.a.currentSnapshot().ApplySnapshotChunkSync(req)
+}
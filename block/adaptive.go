@@ -0,0 +1,112 @@
+package block
+
+import (
+	"context"
+	"time"
+
+	"github.com/rollkit/rollkit/execution"
+	"github.com/rollkit/rollkit/third_party/log"
+)
+
+const (
+	// highLoad is the mempool load (relative to maximum block size or gas) at which block interval is halved.
+	highLoad = 0.5
+	// lowLoad is the mempool load below which block interval is doubled.
+	lowLoad = 0.1
+)
+
+// adaptiveAggregationLoop produces blocks with interval adapting to load (see config.BlockManagerConfig).
+//
+// Block is produced when the current interval elapses, or earlier (but not sooner than MinBlockTime after the
+// previous block) if mempool holds a full block of transactions. After every block, interval is adjusted to the
+// load of the mempool (see nextBlockInterval).
+func (m *Manager) adaptiveAggregationLoop(ctx context.Context) {
+	reporter, ok := m.exec.(execution.PendingTxsReporter)
+	if !ok {
+		log.Warn(m.logger, "execution environment doesn't report pending transactions, block time will grow to maximum")
+	}
+
+	interval := clampDuration(m.conf.BlockTime, m.conf.MinBlockTime, m.conf.MaxBlockTime)
+	m.metrics.BlockInterval.Set(interval.Seconds())
+	var lastBlock time.Time
+	timer := m.clock.Timer(0)
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+		}
+
+		var load float64
+		if reporter != nil {
+			load = m.mempoolLoad(reporter)
+		}
+		elapsed := m.clock.Since(lastBlock)
+		full := load >= 1
+		if elapsed >= interval || (full && elapsed >= m.conf.MinBlockTime) {
+			if elapsed < interval {
+				m.metrics.EarlyBlocks.Add(1)
+			}
+			m.metrics.MempoolLoad.Set(load)
+			lastBlock = m.clock.Now()
+			if err := m.publishBlock(ctx); err != nil && ctx.Err() == nil {
+				m.logger.Error("error while publishing block", "error", err)
+			}
+			interval = nextBlockInterval(interval, load, m.conf.MinBlockTime, m.conf.MaxBlockTime)
+			m.metrics.BlockInterval.Set(interval.Seconds())
+		}
+
+		// mempool is checked at least every MinBlockTime, to produce full blocks early
+		wait := interval - m.clock.Since(lastBlock)
+		if wait > m.conf.MinBlockTime {
+			wait = m.conf.MinBlockTime
+		}
+		if wait < 0 {
+			wait = 0
+		}
+		timer.Reset(wait)
+	}
+}
+
+// mempoolLoad returns size (or gas) of pending transactions relative to maximum block size (or gas), whichever is
+// higher. Load of 1 means that mempool holds a full block of transactions.
+func (m *Manager) mempoolLoad(reporter execution.PendingTxsReporter) float64 {
+	bytes, gas := reporter.PendingTxs()
+	m.lastStateMtx.RLock()
+	params := m.lastState.ConsensusParams.GetBlock()
+	m.lastStateMtx.RUnlock()
+
+	var load float64
+	if maxBytes := params.GetMaxBytes(); maxBytes > 0 {
+		load = float64(bytes) / float64(maxBytes)
+	}
+	if maxGas := params.GetMaxGas(); maxGas > 0 {
+		if gasLoad := float64(gas) / float64(maxGas); gasLoad > load {
+			load = gasLoad
+		}
+	}
+	return load
+}
+
+// nextBlockInterval adjusts block interval to the load of mempool: interval is halved under high load and doubled
+// under low load, staying in [minInterval, maxInterval] range.
+func nextBlockInterval(current time.Duration, load float64, minInterval, maxInterval time.Duration) time.Duration {
+	switch {
+	case load >= highLoad:
+		current /= 2
+	case load < lowLoad:
+		current *= 2
+	}
+	return clampDuration(current, minInterval, maxInterval)
+}
+
+func clampDuration(d, minDuration, maxDuration time.Duration) time.Duration {
+	if d > maxDuration {
+		d = maxDuration
+	}
+	if d < minDuration {
+		d = minDuration
+	}
+	return d
+}
@@ -0,0 +1,153 @@
+package block
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	cmproto "github.com/cometbft/cometbft/proto/tendermint/types"
+	cmtypes "github.com/cometbft/cometbft/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rollkit/rollkit/config"
+	"github.com/rollkit/rollkit/execution/kv"
+	"github.com/rollkit/rollkit/store"
+	test "github.com/rollkit/rollkit/test/log"
+	"github.com/rollkit/rollkit/types"
+)
+
+func TestNextBlockInterval(t *testing.T) {
+	const (
+		minInterval = 100 * time.Millisecond
+		maxInterval = 4 * time.Second
+	)
+	cases := []struct {
+		name     string
+		current  time.Duration
+		load     float64
+		expected time.Duration
+	}{
+		{"full mempool", time.Second, 1.5, 500 * time.Millisecond},
+		{"high load", time.Second, 0.5, 500 * time.Millisecond},
+		{"moderate load", time.Second, 0.3, time.Second},
+		{"low load", time.Second, 0.05, 2 * time.Second},
+		{"idle", time.Second, 0, 2 * time.Second},
+		{"min interval", 150 * time.Millisecond, 1, minInterval},
+		{"max interval", 3 * time.Second, 0, maxInterval},
+		{"out of range", 10 * time.Second, 0.3, maxInterval},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			assert.Equal(t, c.expected, nextBlockInterval(c.current, c.load, minInterval, maxInterval))
+		})
+	}
+}
+
+func TestMempoolLoad(t *testing.T) {
+	assert := assert.New(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	conf := config.BlockManagerConfig{
+		BlockTime: time.Second,
+		Namespace: types.MustNewNamespace(types.NamespaceVersionZero, []byte{1, 2, 3, 4, 5, 6, 7, 8}),
+	}
+	m, exec, _ := getKVManager(ctx, t, conf, test.NewFileLogger(t))
+	setBlockParams := func(maxBytes, maxGas int64) {
+		m.lastStateMtx.Lock()
+		m.lastState.ConsensusParams.Block = &cmproto.BlockParams{MaxBytes: maxBytes, MaxGas: maxGas}
+		m.lastStateMtx.Unlock()
+	}
+
+	setBlockParams(100, -1)
+	assert.Equal(0.0, m.mempoolLoad(exec))
+	exec.InjectTx(types.Tx("key=value")) // 9 bytes
+	exec.InjectTx(types.Tx("k=v"))       // 3 bytes
+	assert.InDelta(0.12, m.mempoolLoad(exec), 1e-9)
+
+	// gas limit is more restrictive
+	setBlockParams(100, 10)
+	assert.InDelta(1.2, m.mempoolLoad(exec), 1e-9)
+}
+
+func TestAdaptiveAggregationLoop(t *testing.T) {
+	require := require.New(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	conf := config.BlockManagerConfig{
+		BlockTime:         200 * time.Millisecond,
+		AdaptiveBlockTime: true,
+		MinBlockTime:      20 * time.Millisecond,
+		MaxBlockTime:      10 * time.Second,
+		Namespace:         types.MustNewNamespace(types.NamespaceVersionZero, []byte{1, 2, 3, 4, 5, 6, 7, 8}),
+	}
+	m, exec, blockStore := getKVManager(ctx, t, conf, test.NewFileLogger(t))
+	m.lastStateMtx.Lock()
+	m.lastState.ConsensusParams.Block = &cmproto.BlockParams{MaxBytes: 10, MaxGas: -1}
+	m.lastStateMtx.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		m.AggregationLoop(ctx, false)
+	}()
+
+	// first block is produced immediately; it's empty, so next interval is doubled
+	require.Eventually(func() bool { return blockStore.Height() >= 1 }, time.Second, 5*time.Millisecond)
+
+	// full block of transactions is produced long before the interval elapses
+	exec.InjectTx(types.Tx("key=value1"))
+	require.Eventually(func() bool { return blockStore.Height() >= 2 }, 300*time.Millisecond, 5*time.Millisecond)
+	block, err := blockStore.GetBlock(2)
+	require.NoError(err)
+	require.Equal(types.Txs{types.Tx("key=value1")}, block.Data.Txs)
+
+	cancel()
+	<-done
+}
+
+func TestAdaptiveBlockTimeValidation(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	logger := test.NewFileLogger(t)
+	genesisValidators, signingKey := types.GetGenesisValidatorSetWithSigner()
+	genesis := &cmtypes.GenesisDoc{
+		ChainID:       "test",
+		InitialHeight: 1,
+		Validators:    genesisValidators,
+	}
+	dalc := getMockDALC(logger)
+	defer func() {
+		require.NoError(t, dalc.Stop())
+	}()
+
+	cases := []struct {
+		name     string
+		min, max time.Duration
+		valid    bool
+	}{
+		{"valid", 100 * time.Millisecond, 10 * time.Second, true},
+		{"equal", time.Second, time.Second, true},
+		{"zero min", 0, 10 * time.Second, false},
+		{"min above max", 2 * time.Second, time.Second, false},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			kvStore, _ := store.NewDefaultInMemoryKVStore()
+			conf := config.BlockManagerConfig{
+				BlockTime:         time.Second,
+				AdaptiveBlockTime: true,
+				MinBlockTime:      c.min,
+				MaxBlockTime:      c.max,
+			}
+			_, err := NewManager(signingKey, conf, genesis, store.New(ctx, kvStore), kv.NewExecutor(), dalc, logger, nil)
+			if c.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
@@ -43,6 +43,17 @@ func (bc *BlockCache) deleteBlock(height uint64) {
 	delete(bc.blocks, height)
 }
 
+// deleteSoftBlocksAbove removes cached blocks above given height, that are not included in DA layer.
+func (bc *BlockCache) deleteSoftBlocksAbove(height uint64) {
+	bc.mtx.Lock()
+	defer bc.mtx.Unlock()
+	for h, block := range bc.blocks {
+		if h > height && !bc.daIncluded[block.Hash().String()] {
+			delete(bc.blocks, h)
+		}
+	}
+}
+
 func (bc *BlockCache) isSeen(hash string) bool {
 	bc.mtx.Lock()
 	defer bc.mtx.Unlock()
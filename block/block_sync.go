@@ -5,6 +5,7 @@ import (
 	"encoding/hex"
 	"errors"
 	"fmt"
+	"sync"
 
 	"github.com/celestiaorg/go-header"
 	goheaderp2p "github.com/celestiaorg/go-header/p2p"
@@ -21,6 +22,7 @@ import (
 
 	"github.com/rollkit/rollkit/config"
 	"github.com/rollkit/rollkit/p2p"
+	"github.com/rollkit/rollkit/store"
 	"github.com/rollkit/rollkit/types"
 )
 
@@ -39,25 +41,34 @@ type BlockSyncService struct {
 	syncer       *goheadersync.Syncer[*types.Block]
 	syncerStatus *SyncerStatus
 
+	// kv is the datastore of block store
+	kv ds.Batching
+	// storeMtx guards blockStore, p2pServer and syncer, replaced on rollback
+	storeMtx sync.RWMutex
+	// network is the P2P network, set on start
+	network string
+
 	logger log.Logger
 	ctx    context.Context
 }
 
+var _ BlockStore = &BlockSyncService{}
+
 // NewBlockSyncService returns a new BlockSyncService.
-func NewBlockSyncService(ctx context.Context, store ds.TxnDatastore, conf config.NodeConfig, genesis *cmtypes.GenesisDoc, p2p *p2p.Client, logger log.Logger) (*BlockSyncService, error) {
+func NewBlockSyncService(ctx context.Context, datastore ds.TxnDatastore, conf config.NodeConfig, genesis *cmtypes.GenesisDoc, p2p *p2p.Client, logger log.Logger) (*BlockSyncService, error) {
 	if genesis == nil {
 		return nil, errors.New("genesis doc cannot be nil")
 	}
 	if p2p == nil {
 		return nil, errors.New("p2p client cannot be nil")
 	}
-	// store is TxnDatastore, but we require Batching, hence the type assertion
+	// datastore is TxnDatastore, but we require Batching, hence the type assertion
 	// note, the badger datastore impl that is used in the background implements both
-	storeBatch, ok := store.(ds.Batching)
+	storeBatch, ok := datastore.(ds.Batching)
 	if !ok {
 		return nil, errors.New("failed to access the datastore")
 	}
-	ss, err := goheaderstore.NewStore[*types.Block](storeBatch, goheaderstore.WithStorePrefix("blockSync"))
+	ss, err := goheaderstore.NewStore[*types.Block](storeBatch, goheaderstore.WithStorePrefix(store.BlockSyncPrefix))
 	if err != nil {
 		return nil, fmt.Errorf("failed to initialize the block store: %w", err)
 	}
@@ -70,19 +81,32 @@ func NewBlockSyncService(ctx context.Context, store ds.TxnDatastore, conf config
 		blockStore:   ss,
 		logger:       logger,
 		syncerStatus: new(SyncerStatus),
+		kv:           storeBatch,
 	}, nil
 }
 
 // BlockStore returns the blockstore of the BlockSyncService
 func (bSyncService *BlockSyncService) BlockStore() *goheaderstore.Store[*types.Block] {
+	bSyncService.storeMtx.RLock()
+	defer bSyncService.storeMtx.RUnlock()
 	return bSyncService.blockStore
 }
 
+// Height returns the height of the last block in the block store.
+func (bSyncService *BlockSyncService) Height() uint64 {
+	return bSyncService.BlockStore().Height()
+}
+
+// GetByHeight returns the block at given height from the block store.
+func (bSyncService *BlockSyncService) GetByHeight(ctx context.Context, height uint64) (*types.Block, error) {
+	return bSyncService.BlockStore().GetByHeight(ctx, height)
+}
+
 func (bSyncService *BlockSyncService) initBlockStoreAndStartSyncer(ctx context.Context, initial *types.Block) error {
 	if initial == nil {
 		return fmt.Errorf("failed to initialize the blockstore and start syncer")
 	}
-	if err := bSyncService.blockStore.Init(ctx, initial); err != nil {
+	if err := bSyncService.BlockStore().Init(ctx, initial); err != nil {
 		return err
 	}
 	if err := bSyncService.StartSyncer(); err != nil {
@@ -98,7 +122,7 @@ func (bSyncService *BlockSyncService) initBlockStoreAndStartSyncer(ctx context.C
 func (bSyncService *BlockSyncService) WriteToBlockStoreAndBroadcast(ctx context.Context, block *types.Block) error {
 	// For genesis block initialize the store and start the syncer
 	if int64(block.Height()) == bSyncService.genesis.InitialHeight {
-		if err := bSyncService.blockStore.Init(ctx, block); err != nil {
+		if err := bSyncService.BlockStore().Init(ctx, block); err != nil {
 			return fmt.Errorf("failed to initialize block store")
 		}
 
@@ -115,7 +139,7 @@ func (bSyncService *BlockSyncService) WriteToBlockStoreAndBroadcast(ctx context.
 }
 
 func (bSyncService *BlockSyncService) isInitialized() bool {
-	return bSyncService.blockStore.Height() > 0
+	return bSyncService.BlockStore().Height() > 0
 }
 
 // Start is a part of Service interface.
@@ -149,6 +173,7 @@ func (bSyncService *BlockSyncService) Start() error {
 	if err != nil {
 		return fmt.Errorf("error while fetching the network: %w", err)
 	}
+	bSyncService.network = network
 	networkIDBlock := network + "-block"
 
 	if bSyncService.p2pServer, err = newBlockP2PServer(bSyncService.p2p.Host(), bSyncService.blockStore, networkIDBlock); err != nil {
@@ -210,6 +235,8 @@ func (bSyncService *BlockSyncService) Start() error {
 
 // Stop is a part of Service interface.
 func (bSyncService *BlockSyncService) Stop() error {
+	bSyncService.storeMtx.Lock()
+	defer bSyncService.storeMtx.Unlock()
 	err := bSyncService.blockStore.Stop(bSyncService.ctx)
 	err = multierr.Append(err, bSyncService.p2pServer.Stop(bSyncService.ctx))
 	err = multierr.Append(err, bSyncService.ex.Stop(bSyncService.ctx))
@@ -259,6 +286,12 @@ func newBlockSyncer(
 
 // StartSyncer starts the BlockSyncService's syncer
 func (bSyncService *BlockSyncService) StartSyncer() error {
+	bSyncService.storeMtx.RLock()
+	defer bSyncService.storeMtx.RUnlock()
+	return bSyncService.startSyncer()
+}
+
+func (bSyncService *BlockSyncService) startSyncer() error {
 	bSyncService.syncerStatus.m.Lock()
 	defer bSyncService.syncerStatus.m.Unlock()
 	if bSyncService.syncerStatus.started {
@@ -271,3 +304,57 @@ func (bSyncService *BlockSyncService) StartSyncer() error {
 	bSyncService.syncerStatus.started = true
 	return nil
 }
+
+// Rollback removes blocks above given height from the block store, when soft blocks diverging from DA layer were
+// rolled back (see Manager.OnReorg). P2P server and syncer are restarted with the block store opened again. If the
+// store didn't contain the block at given height, it's emptied and the syncer is not restarted.
+func (bSyncService *BlockSyncService) Rollback(ctx context.Context, height uint64) error {
+	bSyncService.storeMtx.Lock()
+	defer bSyncService.storeMtx.Unlock()
+	if bSyncService.blockStore.Height() <= height {
+		return nil
+	}
+
+	var err error
+	if bSyncService.syncerStatus.isStarted() {
+		err = bSyncService.syncer.Stop(ctx)
+		// validator of gossiped blocks is registered again by the new syncer
+		err = multierr.Append(err, bSyncService.p2p.PubSub().UnregisterTopicValidator(goheaderp2p.PubsubTopicID(bSyncService.genesis.ChainID+"-block")))
+	}
+	err = multierr.Append(err, bSyncService.p2pServer.Stop(ctx))
+	err = multierr.Append(err, bSyncService.blockStore.Stop(ctx))
+	if err != nil {
+		return fmt.Errorf("failed to stop block store: %w", err)
+	}
+	bSyncService.syncerStatus.m.Lock()
+	bSyncService.syncerStatus.started = false
+	bSyncService.syncerStatus.m.Unlock()
+
+	if err := store.RollbackSyncStore[*types.Block](ctx, bSyncService.kv, store.BlockSyncPrefix, height); err != nil {
+		return err
+	}
+	if bSyncService.blockStore, err = goheaderstore.NewStore[*types.Block](bSyncService.kv, goheaderstore.WithStorePrefix(store.BlockSyncPrefix)); err != nil {
+		return fmt.Errorf("failed to initialize the block store: %w", err)
+	}
+	if err := bSyncService.blockStore.Start(bSyncService.ctx); err != nil {
+		return fmt.Errorf("error while starting block store: %w", err)
+	}
+	if bSyncService.p2pServer, err = newBlockP2PServer(bSyncService.p2p.Host(), bSyncService.blockStore, bSyncService.network+"-block"); err != nil {
+		return fmt.Errorf("error while creating p2p server: %w", err)
+	}
+	if err := bSyncService.p2pServer.Start(bSyncService.ctx); err != nil {
+		return fmt.Errorf("error while starting p2p server: %w", err)
+	}
+	if bSyncService.syncer, err = newBlockSyncer(
+		bSyncService.ex,
+		bSyncService.blockStore,
+		bSyncService.sub,
+		[]goheadersync.Option{goheadersync.WithBlockTime(bSyncService.conf.BlockTime)},
+	); err != nil {
+		return fmt.Errorf("error while creating syncer: %w", err)
+	}
+	if bSyncService.blockStore.Height() > 0 {
+		return bSyncService.startSyncer()
+	}
+	return nil
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/hex"
 	"errors"
 	"fmt"
+	"sync"
 
 	"github.com/celestiaorg/go-header"
 	goheaderp2p "github.com/celestiaorg/go-header/p2p"
@@ -21,6 +22,7 @@ import (
 
 	"github.com/rollkit/rollkit/config"
 	"github.com/rollkit/rollkit/p2p"
+	"github.com/rollkit/rollkit/store"
 	"github.com/rollkit/rollkit/types"
 )
 
@@ -40,25 +42,32 @@ type HeaderSyncService struct {
 	syncer       *goheadersync.Syncer[*types.SignedHeader]
 	syncerStatus *SyncerStatus
 
+	// kv is the datastore of header store
+	kv ds.Batching
+	// storeMtx guards headerStore, p2pServer and syncer, replaced on rollback
+	storeMtx sync.RWMutex
+	// network is the P2P network, set on start
+	network string
+
 	logger log.Logger
 	ctx    context.Context
 }
 
 // NewHeaderSyncService returns a new HeaderSyncService.
-func NewHeaderSyncService(ctx context.Context, store ds.TxnDatastore, conf config.NodeConfig, genesis *cmtypes.GenesisDoc, p2p *p2p.Client, logger log.Logger) (*HeaderSyncService, error) {
+func NewHeaderSyncService(ctx context.Context, datastore ds.TxnDatastore, conf config.NodeConfig, genesis *cmtypes.GenesisDoc, p2p *p2p.Client, logger log.Logger) (*HeaderSyncService, error) {
 	if genesis == nil {
 		return nil, errors.New("genesis doc cannot be nil")
 	}
 	if p2p == nil {
 		return nil, errors.New("p2p client cannot be nil")
 	}
-	// store is TxnDatastore, but we require Batching, hence the type assertion
+	// datastore is TxnDatastore, but we require Batching, hence the type assertion
 	// note, the badger datastore impl that is used in the background implements both
-	storeBatch, ok := store.(ds.Batching)
+	storeBatch, ok := datastore.(ds.Batching)
 	if !ok {
 		return nil, errors.New("failed to access the datastore")
 	}
-	ss, err := goheaderstore.NewStore[*types.SignedHeader](storeBatch, goheaderstore.WithStorePrefix("headerSync"))
+	ss, err := goheaderstore.NewStore[*types.SignedHeader](storeBatch, goheaderstore.WithStorePrefix(store.HeaderSyncPrefix))
 	if err != nil {
 		return nil, fmt.Errorf("failed to initialize the header store: %w", err)
 	}
@@ -71,11 +80,14 @@ func NewHeaderSyncService(ctx context.Context, store ds.TxnDatastore, conf confi
 		headerStore:  ss,
 		logger:       logger,
 		syncerStatus: new(SyncerStatus),
+		kv:           storeBatch,
 	}, nil
 }
 
 // HeaderStore returns the headerstore of the HeaderSynceService
 func (hSyncService *HeaderSyncService) HeaderStore() *goheaderstore.Store[*types.SignedHeader] {
+	hSyncService.storeMtx.RLock()
+	defer hSyncService.storeMtx.RUnlock()
 	return hSyncService.headerStore
 }
 
@@ -83,7 +95,7 @@ func (hSyncService *HeaderSyncService) initHeaderStoreAndStartSyncer(ctx context
 	if initial == nil {
 		return fmt.Errorf("failed to initialize the headerstore and start syncer")
 	}
-	if err := hSyncService.headerStore.Init(ctx, initial); err != nil {
+	if err := hSyncService.HeaderStore().Init(ctx, initial); err != nil {
 		return err
 	}
 	if err := hSyncService.StartSyncer(); err != nil {
@@ -97,7 +109,7 @@ func (hSyncService *HeaderSyncService) initHeaderStoreAndStartSyncer(ctx context
 func (hSyncService *HeaderSyncService) WriteToHeaderStoreAndBroadcast(ctx context.Context, signedHeader *types.SignedHeader) error {
 	// For genesis header initialize the store and start the syncer
 	if int64(signedHeader.Height()) == hSyncService.genesis.InitialHeight {
-		if err := hSyncService.headerStore.Init(ctx, signedHeader); err != nil {
+		if err := hSyncService.HeaderStore().Init(ctx, signedHeader); err != nil {
 			return fmt.Errorf("failed to initialize header store")
 		}
 
@@ -114,7 +126,7 @@ func (hSyncService *HeaderSyncService) WriteToHeaderStoreAndBroadcast(ctx contex
 }
 
 func (hSyncService *HeaderSyncService) isInitialized() bool {
-	return hSyncService.headerStore.Height() > 0
+	return hSyncService.HeaderStore().Height() > 0
 }
 
 // Start is a part of Service interface.
@@ -147,6 +159,7 @@ func (hSyncService *HeaderSyncService) Start() error {
 	if err != nil {
 		return fmt.Errorf("error while fetching the network: %w", err)
 	}
+	hSyncService.network = network
 	if hSyncService.p2pServer, err = newP2PServer(hSyncService.p2p.Host(), hSyncService.headerStore, network); err != nil {
 		return fmt.Errorf("error while creating p2p server: %w", err)
 	}
@@ -207,6 +220,8 @@ func (hSyncService *HeaderSyncService) Start() error {
 
 // Stop is a part of Service interface.
 func (hSyncService *HeaderSyncService) Stop() error {
+	hSyncService.storeMtx.Lock()
+	defer hSyncService.storeMtx.Unlock()
 	err := hSyncService.headerStore.Stop(hSyncService.ctx)
 	err = multierr.Append(err, hSyncService.p2pServer.Stop(hSyncService.ctx))
 	err = multierr.Append(err, hSyncService.ex.Stop(hSyncService.ctx))
@@ -257,6 +272,12 @@ func newSyncer(
 
 // StartSyncer starts the HeaderSyncService's syncer
 func (hSyncService *HeaderSyncService) StartSyncer() error {
+	hSyncService.storeMtx.RLock()
+	defer hSyncService.storeMtx.RUnlock()
+	return hSyncService.startSyncer()
+}
+
+func (hSyncService *HeaderSyncService) startSyncer() error {
 	hSyncService.syncerStatus.m.Lock()
 	defer hSyncService.syncerStatus.m.Unlock()
 	if hSyncService.syncerStatus.started {
@@ -269,3 +290,57 @@ func (hSyncService *HeaderSyncService) StartSyncer() error {
 	hSyncService.syncerStatus.started = true
 	return nil
 }
+
+// Rollback removes headers above given height from the header store, when soft blocks diverging from DA layer were
+// rolled back (see Manager.OnReorg). P2P server and syncer are restarted with the header store opened again. If the
+// store didn't contain the header at given height, it's emptied and the syncer is not restarted.
+func (hSyncService *HeaderSyncService) Rollback(ctx context.Context, height uint64) error {
+	hSyncService.storeMtx.Lock()
+	defer hSyncService.storeMtx.Unlock()
+	if hSyncService.headerStore.Height() <= height {
+		return nil
+	}
+
+	var err error
+	if hSyncService.syncerStatus.isStarted() {
+		err = hSyncService.syncer.Stop(ctx)
+		// validator of gossiped headers is registered again by the new syncer
+		err = multierr.Append(err, hSyncService.p2p.PubSub().UnregisterTopicValidator(goheaderp2p.PubsubTopicID(hSyncService.genesis.ChainID)))
+	}
+	err = multierr.Append(err, hSyncService.p2pServer.Stop(ctx))
+	err = multierr.Append(err, hSyncService.headerStore.Stop(ctx))
+	if err != nil {
+		return fmt.Errorf("failed to stop header store: %w", err)
+	}
+	hSyncService.syncerStatus.m.Lock()
+	hSyncService.syncerStatus.started = false
+	hSyncService.syncerStatus.m.Unlock()
+
+	if err := store.RollbackSyncStore[*types.SignedHeader](ctx, hSyncService.kv, store.HeaderSyncPrefix, height); err != nil {
+		return err
+	}
+	if hSyncService.headerStore, err = goheaderstore.NewStore[*types.SignedHeader](hSyncService.kv, goheaderstore.WithStorePrefix(store.HeaderSyncPrefix)); err != nil {
+		return fmt.Errorf("failed to initialize the header store: %w", err)
+	}
+	if err := hSyncService.headerStore.Start(hSyncService.ctx); err != nil {
+		return fmt.Errorf("error while starting header store: %w", err)
+	}
+	if hSyncService.p2pServer, err = newP2PServer(hSyncService.p2p.Host(), hSyncService.headerStore, hSyncService.network); err != nil {
+		return fmt.Errorf("error while creating p2p server: %w", err)
+	}
+	if err := hSyncService.p2pServer.Start(hSyncService.ctx); err != nil {
+		return fmt.Errorf("error while starting p2p server: %w", err)
+	}
+	if hSyncService.syncer, err = newSyncer(
+		hSyncService.ex,
+		hSyncService.headerStore,
+		hSyncService.sub,
+		[]goheadersync.Option{goheadersync.WithBlockTime(hSyncService.conf.BlockTime)},
+	); err != nil {
+		return fmt.Errorf("error while creating syncer: %w", err)
+	}
+	if hSyncService.headerStore.Height() > 0 {
+		return hSyncService.startSyncer()
+	}
+	return nil
+}
@@ -4,25 +4,22 @@ import (
 	"bytes"
 	"context"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"sync"
 	"sync/atomic"
 	"time"
 
-	goheaderstore "github.com/celestiaorg/go-header/store"
-	abci "github.com/cometbft/cometbft/abci/types"
+	"github.com/benbjohnson/clock"
 	cmcrypto "github.com/cometbft/cometbft/crypto"
-	"github.com/cometbft/cometbft/crypto/merkle"
-	cmstate "github.com/cometbft/cometbft/proto/tendermint/state"
-	"github.com/cometbft/cometbft/proxy"
 	cmtypes "github.com/cometbft/cometbft/types"
+	ds "github.com/ipfs/go-datastore"
 	"github.com/libp2p/go-libp2p/core/crypto"
 	"go.uber.org/multierr"
 
 	"github.com/rollkit/rollkit/config"
 	"github.com/rollkit/rollkit/da"
-	"github.com/rollkit/rollkit/mempool"
-	"github.com/rollkit/rollkit/state"
+	"github.com/rollkit/rollkit/execution"
 	"github.com/rollkit/rollkit/store"
 	"github.com/rollkit/rollkit/third_party/log"
 	"github.com/rollkit/rollkit/types"
@@ -34,6 +31,9 @@ const defaultDABlockTime = 15 * time.Second
 // defaultBlockTime is used only if BlockTime is not configured for manager
 const defaultBlockTime = 1 * time.Second
 
+// defaultLazyBlockTime is used only if LazyBlockTime is not configured for manager
+const defaultLazyBlockTime = 1 * time.Second
+
 // maxSubmitAttempts defines how many times Rollkit will re-try to publish block to DA layer.
 // This is temporary solution. It will be removed in future versions.
 const maxSubmitAttempts = 30
@@ -47,6 +47,10 @@ const blockInChLength = 10000
 // initialBackoff defines initial value for block submission backoff
 var initialBackoff = 100 * time.Millisecond
 
+// errNotDAFinal is returned by trySyncNextBlock when the next block can't be synced in DA-only sync mode yet,
+// because it was not retrieved from DA layer. It's expected for every block received from P2P network.
+var errNotDAFinal = errors.New("block is not DA-final")
+
 type newBlockEvent struct {
 	block    *types.Block
 	daHeight uint64
@@ -62,20 +66,37 @@ type Manager struct {
 	conf    config.BlockManagerConfig
 	genesis *cmtypes.GenesisDoc
 
-	proposerKey crypto.PrivKey
+	proposerKey     crypto.PrivKey
+	proposerAddress []byte
 
-	executor *state.BlockExecutor
+	exec execution.Executor
 
 	dalc      da.DataAvailabilityLayerClient
 	retriever da.BlockRetriever
-	// daHeight is the height of the latest processed DA block
+	// daHeight is the next DA height to retrieve blocks from (retrieval cursor), it's persisted in the store
 	daHeight uint64
+	// daIncludedHeight is the highest height of block retrieved from DA layer
+	daIncludedHeight uint64
+	// finalizedHeight is the highest height of committed block, such that all blocks up to this height are
+	// included in DA layer. It's read atomically, and updated while holding finalityMtx.
+	finalizedHeight uint64
+	finalityMtx     sync.Mutex
+	// startHeight is the DA-final height when manager was created; DA inclusion of blocks up to this height is not
+	// tracked
+	startHeight uint64
+	// reorgHooks are called after soft blocks are rolled back (see OnReorg)
+	reorgHooks []func(context.Context, execution.Reorg) error
+
+	// daStartHeight is the DA height of the first block of the chain, 0 if it's not known yet. It's read atomically.
+	daStartHeight uint64
 
 	HeaderCh chan *types.SignedHeader
 	BlockCh  chan *types.Block
+	// DAStartHeightCh receives DA height of the first block of the chain, when it's recorded by the manager
+	DAStartHeightCh chan uint64
 
 	blockInCh  chan newBlockEvent
-	blockStore *goheaderstore.Store[*types.Block]
+	blockStore BlockStore
 
 	blockCache *BlockCache
 
@@ -91,8 +112,28 @@ type Manager struct {
 	buildingBlock     bool
 	txsAvailable      <-chan struct{}
 	doneBuildingBlock chan struct{}
+	// lazyBlockTime is accessed atomically, as it can be changed at runtime
+	lazyBlockTime int64
+
+	// resumeCh is not nil when block production and syncing is paused; it's closed on resume
+	resumeCh chan struct{}
+	pauseMtx sync.Mutex
 
 	pendingBlocks *PendingBlocks
+
+	// commitWg tracks the block that is committed in the background by the aggregator, publishWg tracks the
+	// same block until it's also persisted and broadcast.
+	// commitErr and publishErr are set when committing the block or saving the state failed; they're accessed
+	// only after commitWg.Wait() and publishWg.Wait() respectively
+	commitWg   sync.WaitGroup
+	commitErr  error
+	publishWg  sync.WaitGroup
+	publishErr error
+
+	// clock is the source of time for all loops, timers and block timestamps
+	clock clock.Clock
+
+	metrics *Metrics
 }
 
 // getInitialState tries to load lastState from Store, and if it's not available it reads GenesisDoc.
@@ -104,18 +145,24 @@ func getInitialState(store store.Store, genesis *cmtypes.GenesisDoc) (types.Stat
 	return s, err
 }
 
+// BlockStore is the store of blocks received from P2P network (see BlockSyncService).
+type BlockStore interface {
+	// Height returns the height of the last block in the store.
+	Height() uint64
+	// GetByHeight returns the block at given height.
+	GetByHeight(ctx context.Context, height uint64) (*types.Block, error)
+}
+
 // NewManager creates new block Manager.
 func NewManager(
 	proposerKey crypto.PrivKey,
 	conf config.BlockManagerConfig,
 	genesis *cmtypes.GenesisDoc,
 	store store.Store,
-	mempool mempool.Mempool,
-	proxyApp proxy.AppConnConsensus,
+	exec execution.Executor,
 	dalc da.DataAvailabilityLayerClient,
-	eventBus *cmtypes.EventBus,
 	logger log.Logger,
-	blockStore *goheaderstore.Store[*types.Block],
+	blockStore BlockStore,
 ) (*Manager, error) {
 	s, err := getInitialState(store, genesis)
 	if err != nil {
@@ -124,6 +171,19 @@ func NewManager(
 	if s.DAHeight < conf.DAStartHeight {
 		s.DAHeight = conf.DAStartHeight
 	}
+	// retrieval cursor is persisted separately from the state, as DA heights without rollup blocks don't update it
+	daHeight := s.DAHeight
+	cursor, err := store.GetDAHeight()
+	if err != nil && !errors.Is(err, ds.ErrNotFound) {
+		return nil, err
+	}
+	if cursor > daHeight {
+		daHeight = cursor
+	}
+	daStartHeight, err := store.GetDAStartHeight()
+	if err != nil && !errors.Is(err, ds.ErrNotFound) {
+		return nil, err
+	}
 
 	proposerAddress, err := getAddress(proposerKey)
 	if err != nil {
@@ -140,39 +200,69 @@ func NewManager(
 		conf.BlockTime = defaultBlockTime
 	}
 
-	exec := state.NewBlockExecutor(proposerAddress, conf.NamespaceID, genesis.ChainID, mempool, proxyApp, eventBus, logger)
+	if conf.LazyBlockTime == 0 {
+		conf.LazyBlockTime = defaultLazyBlockTime
+	}
+
+	if conf.AdaptiveBlockTime && (conf.MinBlockTime <= 0 || conf.MaxBlockTime < conf.MinBlockTime) {
+		return nil, fmt.Errorf("invalid adaptive block time range: min %v, max %v", conf.MinBlockTime, conf.MaxBlockTime)
+	}
+
+	if err := config.ValidateSyncMode(conf.SyncMode); err != nil {
+		return nil, err
+	}
+	if conf.SyncMode == "" {
+		conf.SyncMode = config.SyncModeSoft
+	}
+	// DA-final height is persisted; if it's not known, blocks committed before restart are assumed to be included
+	// in DA layer. Only DA blocks are committed in da-only mode.
+	finalizedHeight, err := store.GetFinalizedHeight()
+	if errors.Is(err, ds.ErrNotFound) || conf.SyncMode == config.SyncModeDAOnly || finalizedHeight > s.LastBlockHeight {
+		finalizedHeight = s.LastBlockHeight
+		err = store.SetFinalizedHeight(finalizedHeight)
+	}
+	if err != nil {
+		return nil, err
+	}
+
 	if s.LastBlockHeight+1 == uint64(genesis.InitialHeight) {
-		res, err := exec.InitChain(genesis)
+		s, err = exec.InitChain(context.Background(), genesis, s)
 		if err != nil {
 			return nil, err
 		}
 
-		updateState(&s, res)
 		if err := store.UpdateState(s); err != nil {
 			return nil, err
 		}
 	}
+	// soft blocks can be rolled back to the last state, even if it was not saved as soft state before
+	if err := store.SaveSoftState(s.LastBlockHeight, s); err != nil {
+		return nil, err
+	}
 
 	var txsAvailableCh <-chan struct{}
-	if mempool != nil {
-		txsAvailableCh = mempool.TxsAvailable()
-	} else {
-		txsAvailableCh = nil
+	if notifier, ok := exec.(execution.TxNotifier); ok {
+		txsAvailableCh = notifier.TxsAvailable()
 	}
 
 	agg := &Manager{
-		proposerKey: proposerKey,
-		conf:        conf,
-		genesis:     genesis,
-		lastState:   s,
-		store:       store,
-		executor:    exec,
-		dalc:        dalc,
-		retriever:   dalc.(da.BlockRetriever), // TODO(tzdybal): do it in more gentle way (after MVP)
-		daHeight:    s.DAHeight,
+		proposerKey:     proposerKey,
+		proposerAddress: proposerAddress,
+		conf:            conf,
+		genesis:         genesis,
+		lastState:       s,
+		store:           store,
+		exec:            exec,
+		dalc:            dalc,
+		retriever:       dalc.(da.BlockRetriever), // TODO(tzdybal): do it in more gentle way (after MVP)
+		daHeight:        daHeight,
+		daStartHeight:   daStartHeight,
+		finalizedHeight: finalizedHeight,
+		startHeight:     finalizedHeight,
 		// channels are buffered to avoid blocking on input/output operations, buffer sizes are arbitrary
 		HeaderCh:          make(chan *types.SignedHeader, channelLength),
 		BlockCh:           make(chan *types.Block, channelLength),
+		DAStartHeightCh:   make(chan uint64, 1),
 		blockInCh:         make(chan newBlockEvent, blockInChLength),
 		blockStoreCh:      make(chan struct{}, 1),
 		blockStore:        blockStore,
@@ -184,6 +274,9 @@ func NewManager(
 		doneBuildingBlock: make(chan struct{}),
 		buildingBlock:     false,
 		pendingBlocks:     NewPendingBlocks(),
+		lazyBlockTime:     int64(conf.LazyBlockTime),
+		clock:             clock.New(),
+		metrics:           NopMetrics(),
 	}
 	return agg, nil
 }
@@ -202,36 +295,294 @@ func (m *Manager) SetDALC(dalc da.DataAvailabilityLayerClient) {
 	m.retriever = dalc.(da.BlockRetriever)
 }
 
+// SetClock replaces the clock used by Manager; by default system clock is used.
+//
+// It has to be called before any of the loops is started. It's intended for tests and simulations, that
+// use virtual time (see clock.Mock).
+func (m *Manager) SetClock(c clock.Clock) {
+	m.clock = c
+}
+
+// OnReorg registers a function called after soft blocks diverging from DA layer are rolled back, before blocks
+// retrieved from DA layer are synced. It's used to roll back data derived from the blocks outside of the manager
+// and executor (e.g. P2P block stores and indexers).
+//
+// It has to be called before any of the loops is started.
+func (m *Manager) OnReorg(hook func(context.Context, execution.Reorg) error) {
+	m.reorgHooks = append(m.reorgHooks, hook)
+}
+
+// SetMetrics sets metrics collector of the manager. It has to be called before any of the loops is started.
+func (m *Manager) SetMetrics(metrics *Metrics) {
+	m.metrics = metrics
+}
+
+// SetLazyBlockTime changes time spent collecting transactions before producing a block in lazy aggregator mode.
+//
+// Zero value restores the default. New value is used starting from the next block.
+func (m *Manager) SetLazyBlockTime(d time.Duration) {
+	if d == 0 {
+		d = defaultLazyBlockTime
+	}
+	atomic.StoreInt64(&m.lazyBlockTime, int64(d))
+}
+
+// Pause suspends block production and syncing, until Resume is called.
+//
+// Block that is being processed when Pause is called is not affected.
+func (m *Manager) Pause() {
+	m.pauseMtx.Lock()
+	defer m.pauseMtx.Unlock()
+	if m.resumeCh == nil {
+		m.resumeCh = make(chan struct{})
+	}
+}
+
+// Resume restarts block production and syncing suspended by Pause.
+func (m *Manager) Resume() {
+	m.pauseMtx.Lock()
+	defer m.pauseMtx.Unlock()
+	if m.resumeCh != nil {
+		close(m.resumeCh)
+		m.resumeCh = nil
+	}
+}
+
+// waitIfPaused blocks while manager is paused.
+func (m *Manager) waitIfPaused(ctx context.Context) error {
+	m.pauseMtx.Lock()
+	resumeCh := m.resumeCh
+	m.pauseMtx.Unlock()
+	if resumeCh == nil {
+		return nil
+	}
+	m.logger.Debug("waiting for manager to be resumed")
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-resumeCh:
+		return nil
+	}
+}
+
 // GetStoreHeight returns the manager's store height
 func (m *Manager) GetStoreHeight() uint64 {
 	return m.store.Height()
 }
 
+// GetLastState returns the state after the last block committed by the manager.
+//
+// Block is committed before its state is saved in the store, so the stored state may lag behind by one block.
+func (m *Manager) GetLastState() types.State {
+	m.lastStateMtx.RLock()
+	defer m.lastStateMtx.RUnlock()
+	return m.lastState
+}
+
+// ReplayBlocks executes and commits stored blocks above execHeight (height of the last block committed by the
+// executor), so that the executor catches up with the manager. If the executor lost all its state, chain is
+// initialized from genesis first. App hash returned by the executor after every replayed block is verified.
+//
+// Block production and syncing must be paused (see Pause) while blocks are replayed.
+func (m *Manager) ReplayBlocks(ctx context.Context, execHeight uint64) error {
+	last := m.GetLastState()
+	initialHeight := uint64(m.genesis.InitialHeight)
+	if execHeight >= last.LastBlockHeight || last.LastBlockHeight < initialHeight {
+		return nil
+	}
+	start := execHeight + 1
+	if start < initialHeight {
+		s, err := types.NewFromGenesisDoc(m.genesis)
+		if err != nil {
+			return err
+		}
+		if _, err := m.exec.InitChain(ctx, m.genesis, s); err != nil {
+			return fmt.Errorf("failed to initialize chain: %w", err)
+		}
+		start = initialHeight
+	}
+
+	m.logger.Info("replaying blocks", "from", start, "to", last.LastBlockHeight)
+	block, err := m.store.GetBlock(start)
+	if err != nil {
+		return fmt.Errorf("failed to load block at height %d: %w", start, err)
+	}
+	for h := start; h <= last.LastBlockHeight; h++ {
+		// state preceding the block is recovered from its header
+		s := last
+		s.LastBlockHeight = h - 1
+		s.AppHash = block.SignedHeader.AppHash
+		s.LastResultsHash = block.SignedHeader.LastResultsHash
+
+		execCtx := m.withFinality(ctx, m.Finality(h))
+		newState, err := m.exec.ExecuteTxs(execCtx, s, block)
+		if err != nil {
+			return fmt.Errorf("failed to execute block at height %d: %w", h, err)
+		}
+		appHash, err := m.exec.Commit(execCtx, newState, block)
+		if err != nil {
+			return fmt.Errorf("failed to commit block at height %d: %w", h, err)
+		}
+
+		// app hash after the block is stored in the next block, or in the state, if it's the last block
+		expected := last.AppHash
+		if h < last.LastBlockHeight {
+			if block, err = m.store.GetBlock(h + 1); err != nil {
+				return fmt.Errorf("failed to load block at height %d: %w", h+1, err)
+			}
+			expected = block.SignedHeader.AppHash
+		}
+		if !bytes.Equal(appHash, expected) {
+			return fmt.Errorf("app hash (%X) after replaying block at height %d doesn't match node app hash (%X)", appHash, h, expected)
+		}
+	}
+	return nil
+}
+
 // IsDAIncluded returns true if the block with the given hash has been seen on DA.
 func (m *Manager) IsDAIncluded(hash types.Hash) bool {
 	return m.blockCache.isDAIncluded(hash.String())
 }
 
+// DAStatus describes progress of data availability layer submission and retrieval.
+type DAStatus struct {
+	// DAHeight is the next DA height to retrieve blocks from
+	DAHeight uint64 `json:"da_height"`
+	// DAIncludedHeight is the highest height of block seen on DA
+	DAIncludedHeight uint64 `json:"da_included_height"`
+	// DAFinalHeight is the highest height of committed block, such that all blocks up to this height are
+	// included in DA layer
+	DAFinalHeight uint64 `json:"da_final_height"`
+	// PendingBlocks is the number of produced blocks, not yet submitted to DA
+	PendingBlocks int `json:"pending_blocks"`
+	// SyncMode is the sync mode of the node (soft, da-only or hybrid)
+	SyncMode string `json:"sync_mode"`
+	// DAStartHeight is the DA height of the first block of the chain, 0 if it's not known yet
+	DAStartHeight uint64 `json:"da_start_height"`
+}
+
+// DAStatus returns current status of DA submission and retrieval.
+func (m *Manager) DAStatus() DAStatus {
+	return DAStatus{
+		DAHeight:         atomic.LoadUint64(&m.daHeight),
+		DAIncludedHeight: atomic.LoadUint64(&m.daIncludedHeight),
+		DAFinalHeight:    atomic.LoadUint64(&m.finalizedHeight),
+		PendingBlocks:    len(m.pendingBlocks.getPendingBlocks()),
+		SyncMode:         m.conf.SyncMode,
+		DAStartHeight:    atomic.LoadUint64(&m.daStartHeight),
+	}
+}
+
+// recordDAStartHeight records DA height of the first block of the chain, if given block is the first block, and
+// the DA height is not recorded yet. The DA height is saved in the store, and sent to DAStartHeightCh.
+func (m *Manager) recordDAStartHeight(block *types.Block, daHeight uint64) {
+	if block.Height() != uint64(m.genesis.InitialHeight) || !atomic.CompareAndSwapUint64(&m.daStartHeight, 0, daHeight) {
+		return
+	}
+	m.logger.Info("DA start height recorded", "daHeight", daHeight)
+	if err := m.store.SetDAStartHeight(daHeight); err != nil {
+		m.logger.Error("failed to save DA start height", "daHeight", daHeight, "error", err)
+	}
+	select {
+	case m.DAStartHeightCh <- daHeight:
+	default:
+	}
+}
+
+// Finality returns finality level of committed block at given height.
+func (m *Manager) Finality(height uint64) execution.Finality {
+	if height <= atomic.LoadUint64(&m.finalizedHeight) {
+		return execution.FinalityDA
+	}
+	return execution.FinalitySoft
+}
+
+// labelsFinality returns true if the sync mode distinguishes finality levels of blocks; executor gets finality
+// level in context (see execution.WithFinality) and is notified when soft blocks become DA-final.
+func (m *Manager) labelsFinality() bool {
+	return m.conf.SyncMode == config.SyncModeHybrid || m.conf.SyncMode == config.SyncModeDAOnly
+}
+
+// withFinality returns context labelled with finality level, if the sync mode distinguishes finality levels.
+func (m *Manager) withFinality(ctx context.Context, f execution.Finality) context.Context {
+	if !m.labelsFinality() {
+		return ctx
+	}
+	return execution.WithFinality(ctx, f)
+}
+
+// updateFinalizedHeight advances DA-final height to the highest committed block included in DA layer.
+// If the sync mode distinguishes finality levels, executor is notified about blocks that became DA-final
+// after being committed with soft finality.
+func (m *Manager) updateFinalizedHeight(ctx context.Context) {
+	m.finalityMtx.Lock()
+	defer m.finalityMtx.Unlock()
+
+	m.lastStateMtx.RLock()
+	committedHeight := m.lastState.LastBlockHeight
+	m.lastStateMtx.RUnlock()
+
+	finalized := atomic.LoadUint64(&m.finalizedHeight)
+	height := finalized
+	limit := atomic.LoadUint64(&m.daIncludedHeight)
+	if committedHeight < limit {
+		limit = committedHeight
+	}
+	for height < limit && m.isCommittedDAIncluded(height+1) {
+		height++
+	}
+	if height <= finalized {
+		return
+	}
+	m.setFinalizedHeight(height)
+	m.logger.Debug("DA-final height advanced", "height", height)
+
+	notifier, ok := m.exec.(execution.FinalityNotifier)
+	if !ok || !m.labelsFinality() {
+		return
+	}
+	for h := finalized + 1; h <= height; h++ {
+		if err := notifier.BlockFinalized(ctx, h); err != nil {
+			m.logger.Error("failed to notify executor about DA-final block", "height", h, "error", err)
+		}
+	}
+}
+
 // AggregationLoop is responsible for aggregating transactions into rollup-blocks.
 func (m *Manager) AggregationLoop(ctx context.Context, lazy bool) {
+	defer func() {
+		if err := m.waitForPublished(); err != nil {
+			m.logger.Error("failed to persist last block", "error", err)
+		}
+	}()
+
 	initialHeight := uint64(m.genesis.InitialHeight)
 	height := m.store.Height()
 	var delay time.Duration
 
 	// TODO(tzdybal): double-check when https://github.com/celestiaorg/rollmint/issues/699 is resolved
 	if height < initialHeight {
-		delay = time.Until(m.genesis.GenesisTime)
+		delay = m.clock.Until(m.genesis.GenesisTime)
 	} else {
 		lastBlockTime := m.getLastBlockTime()
-		delay = time.Until(lastBlockTime.Add(m.conf.BlockTime))
+		delay = m.clock.Until(lastBlockTime.Add(m.conf.BlockTime))
 	}
 
 	if delay > 0 {
 		m.logger.Info("Waiting to produce block", "delay", delay)
-		time.Sleep(delay)
+		select {
+		case <-ctx.Done():
+			return
+		case <-m.clock.After(delay):
+		}
+	}
+
+	if !lazy && m.conf.AdaptiveBlockTime {
+		m.adaptiveAggregationLoop(ctx)
+		return
 	}
 
-	timer := time.NewTimer(0)
+	timer := m.clock.Timer(0)
 
 	if !lazy {
 		for {
@@ -240,7 +591,7 @@ func (m *Manager) AggregationLoop(ctx context.Context, lazy bool) {
 				return
 			case <-timer.C:
 			}
-			start := time.Now()
+			start := m.clock.Now()
 			err := m.publishBlock(ctx)
 			if err != nil && ctx.Err() == nil {
 				m.logger.Error("error while publishing block", "error", err)
@@ -258,10 +609,10 @@ func (m *Manager) AggregationLoop(ctx context.Context, lazy bool) {
 			case <-m.txsAvailable:
 				if !m.buildingBlock {
 					m.buildingBlock = true
-					timer.Reset(1 * time.Second)
+					timer.Reset(time.Duration(atomic.LoadInt64(&m.lazyBlockTime)))
 				}
 			case <-timer.C:
-				// build a block with all the transactions received in the last 1 second
+				// build a block with all the transactions received during lazy block time
 				err := m.publishBlock(ctx)
 				if err != nil && ctx.Err() == nil {
 					m.logger.Error("error while publishing block", "error", err)
@@ -278,7 +629,7 @@ func (m *Manager) AggregationLoop(ctx context.Context, lazy bool) {
 
 // BlockSubmissionLoop is responsible for submitting blocks to the DA layer.
 func (m *Manager) BlockSubmissionLoop(ctx context.Context) {
-	timer := time.NewTicker(m.conf.DABlockTime)
+	timer := m.clock.Ticker(m.conf.DABlockTime)
 	for {
 		select {
 		case <-ctx.Done():
@@ -289,19 +640,53 @@ func (m *Manager) BlockSubmissionLoop(ctx context.Context) {
 			continue
 		}
 		err := m.submitBlocksToDA(ctx)
-		if err != nil {
+		if err != nil && ctx.Err() == nil {
 			m.logger.Error("error while submitting block to DA", "error", err)
 		}
 	}
 }
 
+// FlushPendingBlocks submits all blocks that were not yet published to DA layer.
+//
+// It's intended to be used during shutdown, after AggregationLoop and BlockSubmissionLoop are stopped.
+func (m *Manager) FlushPendingBlocks(ctx context.Context) error {
+	pending := len(m.pendingBlocks.getPendingBlocks())
+	if pending == 0 {
+		return nil
+	}
+	m.logger.Info("submitting pending blocks to DA layer", "count", pending)
+	return m.submitBlocksToDA(ctx)
+}
+
+// isCommittedDAIncluded returns true if committed block at given height was retrieved from DA layer. DA inclusion
+// of blocks committed before restart is not tracked, they are assumed to be included.
+func (m *Manager) isCommittedDAIncluded(height uint64) bool {
+	if height <= m.startHeight {
+		return true
+	}
+	block, err := m.store.GetBlock(height)
+	return err == nil && m.blockCache.isDAIncluded(block.Hash().String())
+}
+
+// setFinalizedHeight sets and persists DA-final height, and drops states of soft blocks that can't be rolled back
+// anymore. It has to be called while holding finalityMtx.
+func (m *Manager) setFinalizedHeight(height uint64) {
+	atomic.StoreUint64(&m.finalizedHeight, height)
+	if err := m.store.SetFinalizedHeight(height); err != nil {
+		m.logger.Error("failed to save DA-final height", "height", height, "error", err)
+	}
+	if err := m.store.PruneSoftStates(height); err != nil {
+		m.logger.Error("failed to prune soft states", "height", height, "error", err)
+	}
+}
+
 // SyncLoop is responsible for syncing blocks.
 //
 // SyncLoop processes headers gossiped in P2P network to know what's the latest block height,
 // block data is retrieved from DA layer.
 func (m *Manager) SyncLoop(ctx context.Context, cancel context.CancelFunc) {
-	daTicker := time.NewTicker(m.conf.DABlockTime)
-	blockTicker := time.NewTicker(m.conf.BlockTime)
+	daTicker := m.clock.Ticker(m.conf.DABlockTime)
+	blockTicker := m.clock.Ticker(m.conf.BlockTime)
 	for {
 		select {
 		case <-daTicker.C:
@@ -322,12 +707,31 @@ func (m *Manager) SyncLoop(ctx context.Context, cancel context.CancelFunc) {
 				m.logger.Debug("block already seen", "height", blockHeight, "block hash", blockHash)
 				continue
 			}
+			if blockHeight <= m.store.Height() {
+				if err := m.reconcile(ctx, block, daHeight); err != nil {
+					m.logger.Error("failed to reconcile committed blocks with DA layer", "height", blockHeight, "error", err)
+					continue
+				}
+				if blockHeight <= m.store.Height() {
+					m.logger.Debug("block already committed", "height", blockHeight, "block hash", blockHash)
+					m.blockCache.setSeen(blockHash)
+					continue
+				}
+			}
+			// DA layer is canonical, block retrieved from DA is not replaced by block received from P2P network
+			if cached, ok := m.blockCache.getBlock(blockHeight); ok && m.blockCache.isDAIncluded(cached.Hash().String()) && !m.blockCache.isDAIncluded(blockHash) {
+				continue
+			}
 			m.blockCache.setBlock(blockHeight, block)
 
 			m.sendNonBlockingSignalToBlockStoreCh()
 			m.sendNonBlockingSignalToRetrieveCh()
 
 			err := m.trySyncNextBlock(ctx, daHeight)
+			if errors.Is(err, errNotDAFinal) {
+				m.logger.Debug("waiting for next block to be retrieved from DA layer", "error", err)
+				continue
+			}
 			if err != nil {
 				m.logger.Info("failed to sync next block", "error", err)
 				continue
@@ -353,41 +757,49 @@ func (m *Manager) sendNonBlockingSignalToRetrieveCh() {
 	}
 }
 
-// trySyncNextBlock tries to progress one step (one block) in sync process.
+// trySyncNextBlock tries to progress in sync process, one block at a time, as long as next blocks are available
+// in sync cache.
+//
+// All consecutive cached blocks are synced, not only the block that triggered the call. Blocks don't arrive in
+// order: many blocks are retrieved from single DA height, blocks received from P2P network may arrive before
+// their predecessors, and blocks re-applied after rollback are put into the cache without any event. Once the
+// missing block is synced, nothing else would trigger syncing of the blocks cached above it.
 //
-// To be able to apply block and height h, we need to have its Commit. It is contained in block at height h+1.
-// If block at height h+1 is not available, value of last gossiped commit is checked.
-// If commit for block h is available, we proceed with sync process, and remove synced block from sync cache.
+// To be able to apply block and height h, we need to have its Commit. It is contained in the signed header of the
+// block. Synced block is removed from sync cache.
 func (m *Manager) trySyncNextBlock(ctx context.Context, daHeight uint64) error {
-	var commit *types.Commit
-	currentHeight := m.store.Height() // TODO(tzdybal): maybe store a copy in memory
-
-	b, ok := m.blockCache.getBlock(currentHeight + 1)
-	if !ok {
-		return nil
+	if err := m.waitIfPaused(ctx); err != nil {
+		return err
 	}
+	for {
+		currentHeight := m.store.Height() // TODO(tzdybal): maybe store a copy in memory
 
-	signedHeader := &b.SignedHeader
-	if signedHeader != nil {
-		commit = &b.SignedHeader.Commit
-	}
-	valset := m.getValidatorSet(ctx)
+		b, ok := m.blockCache.getBlock(currentHeight + 1)
+		if !ok {
+			return nil
+		}
+		// block is DA-final if it's included in DA layer, and all previous blocks are DA-final
+		daFinal := m.blockCache.isDAIncluded(b.Hash().String()) && atomic.LoadUint64(&m.finalizedHeight) == currentHeight
+		if !daFinal && m.conf.SyncMode == config.SyncModeDAOnly {
+			return fmt.Errorf("%w: height %d", errNotDAFinal, currentHeight+1)
+		}
+		finality := execution.FinalitySoft
+		if daFinal {
+			finality = execution.FinalityDA
+		}
+		execCtx := m.withFinality(ctx, finality)
 
-	if b != nil && commit != nil {
+		commit := &b.SignedHeader.Commit
 		bHeight := uint64(b.Height())
 		m.logger.Info("Syncing block", "height", bHeight)
-		// Validate the received block before applying
-		if err := m.executor.Validate(m.lastState, b); err != nil {
-			return fmt.Errorf("failed to validate block: %w", err)
-		}
-
 		// need to set validators into  header for light client compatibility
 		// because valset isn't change so always set the valset is the same with valset in genesis.
 		// TODO: set it once when create block manager instead of set per block
-		b.SignedHeader.Validators = valset
+		b.SignedHeader.Validators = m.getValidatorSet(ctx)
 		b.SignedHeader.ValidatorHash = b.SignedHeader.Validators.Hash()
 
-		newState, responses, err := m.executor.ApplyBlock(ctx, m.lastState, b)
+		// Validate the received block before applying
+		newState, err := m.applyBlock(execCtx, b)
 		if err != nil {
 			return fmt.Errorf("failed to ApplyBlock: %w", err)
 		}
@@ -396,34 +808,130 @@ func (m *Manager) trySyncNextBlock(ctx context.Context, daHeight uint64) error {
 			return fmt.Errorf("failed to save block: %w", err)
 		}
 
-		appHash, _, err := m.executor.Commit(ctx, newState, b, responses)
+		appHash, err := m.exec.Commit(execCtx, newState, b)
 		if err != nil {
 			return fmt.Errorf("failed to Commit: %w", err)
 		}
 		newState.AppHash = appHash
 
-		err = m.store.SaveBlockResponses(uint64(bHeight), responses)
-		if err != nil {
-			return fmt.Errorf("failed to save block responses: %w", err)
-		}
-
 		m.store.SetHeight(bHeight)
 
 		if daHeight > newState.DAHeight {
 			newState.DAHeight = daHeight
 		}
+		// block committed as DA-final must not be reported to executor by updateFinalizedHeight
+		m.finalityMtx.Lock()
 		err = m.updateState(newState)
+		if err == nil {
+			err = m.store.SaveSoftState(bHeight, newState)
+		}
 		if err != nil {
 			m.logger.Error("failed to save updated state", "error", err)
+		} else if daFinal {
+			m.setFinalizedHeight(bHeight)
 		}
+		m.finalityMtx.Unlock()
 		m.blockCache.deleteBlock(currentHeight + 1)
+		m.updateFinalizedHeight(ctx)
 	}
+}
 
+// reconcile compares block retrieved from DA layer with committed block at the same height. DA layer is canonical:
+// if blocks differ, committed soft blocks starting at this height are rolled back, so the chain from DA layer can
+// be synced instead. Blocks received from P2P network never replace committed blocks.
+func (m *Manager) reconcile(ctx context.Context, daBlock *types.Block, daHeight uint64) error {
+	if !m.blockCache.isDAIncluded(daBlock.Hash().String()) {
+		return nil
+	}
+	height := uint64(daBlock.Height())
+	committed, err := m.store.GetBlock(height)
+	if err != nil {
+		return fmt.Errorf("failed to load committed block: %w", err)
+	}
+	// if many blocks at the same height are included in DA layer, the one retrieved first is canonical
+	if bytes.Equal(committed.Hash(), daBlock.Hash()) || m.blockCache.isDAIncluded(committed.Hash().String()) {
+		return nil
+	}
+	if err := m.rollback(ctx, height-1, daBlock, daHeight); err != nil {
+		return err
+	}
+	m.blockCache.setBlock(height, daBlock)
+	return nil
+}
+
+// rollback reverts committed soft blocks above given height: executor, store and state are rolled back, and
+// executor is notified about the reorg, if it implements execution.ReorgNotifier. DA-final blocks are never
+// rolled back.
+func (m *Manager) rollback(ctx context.Context, height uint64, canonical *types.Block, daHeight uint64) error {
+	rollbacker, ok := m.exec.(execution.Rollbacker)
+	if !ok {
+		return errors.New("executor doesn't support rollback, committed blocks diverge from DA layer")
+	}
+
+	m.finalityMtx.Lock()
+	defer m.finalityMtx.Unlock()
+	if finalized := atomic.LoadUint64(&m.finalizedHeight); height < finalized {
+		return fmt.Errorf("block at height %d conflicts with DA-final block", height+1)
+	}
+	state, err := m.store.GetSoftState(height)
+	if err != nil {
+		return fmt.Errorf("state at height %d is not available: %w", height, err)
+	}
+
+	// reverted blocks included in DA layer are synced again, after the canonical block
+	storeHeight := m.store.Height()
+	reverted := make([]types.Hash, 0, storeHeight-height)
+	var daBlocks []*types.Block
+	for h := height + 1; h <= storeHeight; h++ {
+		block, err := m.store.GetBlock(h)
+		if err != nil {
+			return fmt.Errorf("failed to load block at height %d: %w", h, err)
+		}
+		reverted = append(reverted, block.Hash())
+		if m.blockCache.isDAIncluded(block.Hash().String()) {
+			daBlocks = append(daBlocks, block)
+		}
+	}
+
+	if err := rollbacker.Rollback(ctx, height); err != nil {
+		return fmt.Errorf("failed to roll back executor: %w", err)
+	}
+	if err := m.store.Rollback(height); err != nil {
+		return fmt.Errorf("failed to roll back store: %w", err)
+	}
+	if err := m.updateState(state); err != nil {
+		return fmt.Errorf("failed to save state: %w", err)
+	}
+	m.blockCache.deleteSoftBlocksAbove(height)
+	for _, block := range daBlocks {
+		m.blockCache.setBlock(uint64(block.Height()), block)
+	}
+
+	m.metrics.Reorgs.Add(1)
+	m.metrics.RevertedBlocks.Add(float64(len(reverted)))
+	m.logger.Info("rolled back soft blocks diverging from DA layer", "height", height, "reverted", len(reverted), "daHeight", daHeight)
+
+	reorg := execution.Reorg{Height: height, Reverted: reverted, Canonical: canonical.Hash(), DAHeight: daHeight}
+	if notifier, ok := m.exec.(execution.ReorgNotifier); ok {
+		if err := notifier.BlocksReorged(ctx, reorg); err != nil {
+			m.logger.Error("failed to notify executor about reorg", "height", height, "error", err)
+		}
+	}
+	for _, hook := range m.reorgHooks {
+		if err := hook(ctx, reorg); err != nil {
+			m.logger.Error("reorg hook failed", "height", height, "error", err)
+		}
+	}
 	return nil
 }
 
 // BlockStoreRetrieveLoop is responsible for retrieving blocks from the Block Store.
+//
+// In da-only sync mode blocks received from P2P network are never executed, and the loop returns immediately.
 func (m *Manager) BlockStoreRetrieveLoop(ctx context.Context) {
+	if m.conf.SyncMode == config.SyncModeDAOnly {
+		return
+	}
 	lastBlockStoreHeight := uint64(0)
 	for {
 		select {
@@ -491,7 +999,21 @@ func (m *Manager) RetrieveLoop(ctx context.Context) {
 		case blockFoundCh <- struct{}{}:
 		default:
 		}
-		atomic.AddUint64(&m.daHeight, 1)
+		m.advanceDAHeight()
+	}
+}
+
+// advanceDAHeight moves the retrieval cursor to the next DA height. The cursor is persisted only if all blocks
+// retrieved from DA layer so far are committed (DA-final); otherwise blocks retrieved from lower DA heights, still
+// waiting to be synced, would not be retrieved again after restart. The cursor is persisted once they are committed,
+// when the next DA height is processed.
+func (m *Manager) advanceDAHeight() {
+	daHeight := atomic.AddUint64(&m.daHeight, 1)
+	if atomic.LoadUint64(&m.finalizedHeight) < atomic.LoadUint64(&m.daIncludedHeight) {
+		return
+	}
+	if err := m.store.SetDAHeight(daHeight); err != nil {
+		m.logger.Error("failed to save DA height", "daHeight", daHeight, "error", err)
 	}
 }
 
@@ -512,12 +1034,17 @@ func (m *Manager) processNextDABlock(ctx context.Context) error {
 			m.logger.Debug("retrieved potential blocks", "n", len(blockResp.Blocks), "daHeight", daHeight)
 			for _, block := range blockResp.Blocks {
 				blockHash := block.Hash().String()
+				if block.ValidateBasic() == nil {
+					m.recordDAStartHeight(block, daHeight)
+				}
 				m.blockCache.setDAIncluded(blockHash)
 				m.logger.Info("block marked as DA included", "blockHeight", block.Height(), "blockHash", blockHash)
+				m.setDAIncludedHeight(uint64(block.Height()))
 				if !m.blockCache.isSeen(blockHash) {
 					m.blockInCh <- newBlockEvent{block, daHeight}
 				}
 			}
+			m.updateFinalizedHeight(ctx)
 			return nil
 		}
 
@@ -527,12 +1054,21 @@ func (m *Manager) processNextDABlock(ctx context.Context) error {
 		select {
 		case <-ctx.Done():
 			return err
-		case <-time.After(100 * time.Millisecond):
+		case <-m.clock.After(100 * time.Millisecond):
 		}
 	}
 	return err
 }
 
+func (m *Manager) setDAIncludedHeight(height uint64) {
+	for {
+		current := atomic.LoadUint64(&m.daIncludedHeight)
+		if height <= current || atomic.CompareAndSwapUint64(&m.daIncludedHeight, current, height) {
+			return
+		}
+	}
+}
+
 func (m *Manager) fetchBlock(ctx context.Context, daHeight uint64) (da.ResultRetrieveBlocks, error) {
 	var err error
 	blockRes := m.retriever.RetrieveBlocks(ctx, daHeight)
@@ -544,7 +1080,7 @@ func (m *Manager) fetchBlock(ctx context.Context, daHeight uint64) (da.ResultRet
 }
 
 func (m *Manager) getRemainingSleep(start time.Time) time.Duration {
-	publishingDuration := time.Since(start)
+	publishingDuration := m.clock.Since(start)
 	sleepDuration := m.conf.BlockTime - publishingDuration
 	if sleepDuration < 0 {
 		sleepDuration = 0
@@ -576,11 +1112,20 @@ func (m *Manager) IsProposer() (bool, error) {
 }
 
 func (m *Manager) publishBlock(ctx context.Context) error {
+	if err := m.waitIfPaused(ctx); err != nil {
+		return err
+	}
+	// Next block is built on top of the app hash of the previous block. Also, transactions must not be reaped from
+	// mempool until it's updated with transactions of the previous block, on commit.
+	if err := m.waitForCommitted(); err != nil {
+		return err
+	}
 
 	var lastCommit *types.Commit
 	var lastHeaderHash types.Hash
 	var err error
-	height := m.store.Height()
+	// block saved in the store, but not committed (e.g. because commit failed), is used as pending block
+	height := m.GetLastState().LastBlockHeight
 	newHeight := height + 1
 
 	isProposer, err := m.IsProposer()
@@ -618,7 +1163,10 @@ func (m *Manager) publishBlock(ctx context.Context) error {
 		block = pendingBlock
 	} else {
 		m.logger.Info("Creating and publishing block", "height", newHeight)
-		block = m.createBlock(newHeight, lastCommit, lastHeaderHash)
+		block, err = m.createBlock(ctx, newHeight, lastCommit, lastHeaderHash)
+		if err != nil {
+			return fmt.Errorf("failed to create block: %w", err)
+		}
 		m.logger.Debug("block info", "num_tx", len(block.Data.Txs))
 
 		block.SignedHeader.DataHash, err = block.Data.Hash()
@@ -648,7 +1196,7 @@ func (m *Manager) publishBlock(ctx context.Context) error {
 	}
 
 	// Apply the block but DONT commit
-	newState, responses, err := m.applyBlock(ctx, block)
+	newState, err := m.applyBlock(m.withFinality(ctx, execution.FinalitySoft), block)
 	if err != nil {
 		return err
 	}
@@ -669,7 +1217,7 @@ func (m *Manager) publishBlock(ctx context.Context) error {
 	block.SignedHeader.Commit = *commit
 
 	// Validate the created block before storing
-	if err := m.executor.Validate(m.lastState, block); err != nil {
+	if err := validateBlock(m.lastState, block); err != nil {
 		return fmt.Errorf("failed to validate block: %w", err)
 	}
 
@@ -689,44 +1237,94 @@ func (m *Manager) publishBlock(ctx context.Context) error {
 	// Submit block to be published to the DA layer
 	m.pendingBlocks.addPendingBlock(block)
 
-	// Commit the new state and block which writes to disk on the proxy app
-	appHash, _, err := m.executor.Commit(ctx, newState, block, responses)
-	if err != nil {
+	// Previous block has to be persisted before the next one is committed, so the application
+	// is never more than one block ahead of the stored state
+	if err := m.waitForPublished(); err != nil {
 		return err
 	}
 
-	// Update app hash in state
-	newState.AppHash = appHash
+	// Block is committed, state is persisted and block is broadcast in the background, while the manager waits
+	// for the next block time
+	m.commitWg.Add(1)
+	m.publishWg.Add(1)
+	go m.commitAndPublish(ctx, block, newState)
+
+	return nil
+}
+
+// commitAndPublish commits the block, saves the state after committing it, and publishes the block to
+// channels, so that header and block exchange services can broadcast it.
+func (m *Manager) commitAndPublish(ctx context.Context, block *types.Block, s types.State) {
+	defer m.publishWg.Done()
 
-	// SaveBlockResponses commits the DB tx
-	err = m.store.SaveBlockResponses(blockHeight, responses)
+	// Commit the new state and block which writes to disk on the proxy app
+	appHash, err := m.exec.Commit(m.withFinality(ctx, execution.FinalitySoft), s, block)
 	if err != nil {
-		return err
+		m.logger.Error("failed to commit block", "height", block.Height(), "error", err)
+		m.commitErr = fmt.Errorf("failed to commit block at height %d: %w", block.Height(), err)
+		m.commitWg.Done()
+		return
 	}
 
-	newState.DAHeight = atomic.LoadUint64(&m.daHeight)
-	// After this call m.lastState is the NEW state returned from ApplyBlock
-	// updateState also commits the DB tx
-	err = m.updateState(newState)
-	if err != nil {
-		return err
+	// Update app hash in state
+	s.AppHash = appHash
+
+	s.DAHeight = atomic.LoadUint64(&m.daHeight)
+	// After this call m.lastState is the NEW state returned from ApplyBlock, and the next block
+	// can be built on top of it
+	m.setLastState(s)
+	m.commitWg.Done()
+
+	// UpdateState commits the DB tx
+	if err := m.store.UpdateState(s); err != nil {
+		m.logger.Error("failed to save updated state", "height", block.Height(), "error", err)
+		m.publishErr = err
 	}
 
-	// Check if the node has shutdown prior to publishing to channels
+	// Publish header to channel so that header exchange service can broadcast
 	select {
 	case <-ctx.Done():
-		return nil
-	default:
+		return
+	case m.HeaderCh <- &block.SignedHeader:
 	}
 
-	// Publish header to channel so that header exchange service can broadcast
-	m.HeaderCh <- &block.SignedHeader
-
 	// Publish block to channel so that block exchange service can broadcast
-	m.BlockCh <- block
+	select {
+	case <-ctx.Done():
+		return
+	case m.BlockCh <- block:
+	}
 
-	m.logger.Debug("successfully proposed block", "proposer", hex.EncodeToString(block.SignedHeader.ProposerAddress), "height", blockHeight)
+	m.logger.Debug("successfully proposed block", "proposer", hex.EncodeToString(block.SignedHeader.ProposerAddress), "height", block.Height())
+}
 
+// waitForCommitted blocks until the block handed over to commitAndPublish is committed, and returns an error if
+// commit failed.
+func (m *Manager) waitForCommitted() error {
+	m.commitWg.Wait()
+	err := m.commitErr
+	m.commitErr = nil
+	return err
+}
+
+// waitForPublished blocks until the block handed over to commitAndPublish is processed.
+//
+// If saving the state failed, another attempt is made, and error is returned if it fails again.
+func (m *Manager) waitForPublished() error {
+	m.publishWg.Wait()
+	if err := m.waitForCommitted(); err != nil {
+		return err
+	}
+	if m.publishErr == nil {
+		return nil
+	}
+	m.lastStateMtx.RLock()
+	s := m.lastState
+	m.lastStateMtx.RUnlock()
+	if err := m.store.UpdateState(s); err != nil {
+		return fmt.Errorf("failed to save updated state: %w", err)
+	}
+	m.publishErr = nil
 	return nil
 }
 
@@ -747,22 +1345,34 @@ func (m *Manager) getValidatorSet(ctx context.Context) *cmtypes.ValidatorSet {
 func (m *Manager) submitBlocksToDA(ctx context.Context) error {
 	submitted := false
 	backoff := initialBackoff
+	var blocks []*types.Block
 	for attempt := 1; ctx.Err() == nil && !submitted && attempt <= maxSubmitAttempts; attempt++ {
-		res := m.dalc.SubmitBlocks(ctx, m.pendingBlocks.getPendingBlocks())
+		blocks = m.pendingBlocks.getPendingBlocks()
+		res := m.dalc.SubmitBlocks(ctx, blocks)
 		if res.Code == da.StatusSuccess {
 			m.logger.Info("successfully submitted Rollkit block to DA layer", "daHeight", res.DAHeight)
 			submitted = true
+			for _, block := range blocks {
+				m.recordDAStartHeight(block, res.DAHeight)
+			}
 		} else {
-			m.logger.Error("DA layer submission failed", "error", res.Message, "attempt", attempt)
-			time.Sleep(backoff)
+			log.Warn(m.logger, "DA layer submission failed", "error", res.Message, "attempt", attempt)
+			select {
+			case <-ctx.Done():
+			case <-m.clock.After(backoff):
+			}
 			backoff = m.exponentialBackoff(backoff)
 		}
 	}
 
 	if !submitted {
+		if ctx.Err() != nil {
+			return fmt.Errorf("block submission to DA layer interrupted: %w", ctx.Err())
+		}
 		return fmt.Errorf("failed to submit block to DA layer after %d attempts", maxSubmitAttempts)
 	}
-	m.pendingBlocks.resetPendingBlocks()
+	// blocks produced during submission are submitted next time
+	m.pendingBlocks.removeSubmittedBlocks(len(blocks))
 	return nil
 }
 
@@ -786,54 +1396,142 @@ func (m *Manager) updateState(s types.State) error {
 	return nil
 }
 
+// setLastState updates manager's lastState, without saving it in the store.
+func (m *Manager) setLastState(s types.State) {
+	m.lastStateMtx.Lock()
+	defer m.lastStateMtx.Unlock()
+	m.lastState = s
+}
+
 func (m *Manager) getLastBlockTime() time.Time {
 	m.lastStateMtx.RLock()
 	defer m.lastStateMtx.RUnlock()
 	return m.lastState.LastBlockTime
 }
 
-func (m *Manager) createBlock(height uint64, lastCommit *types.Commit, lastHeaderHash types.Hash) *types.Block {
+func (m *Manager) createBlock(ctx context.Context, height uint64, lastCommit *types.Commit, lastHeaderHash types.Hash) (*types.Block, error) {
 	m.lastStateMtx.RLock()
 	defer m.lastStateMtx.RUnlock()
-	return m.executor.CreateBlock(height, lastCommit, lastHeaderHash, m.lastState)
+
+	txs, err := m.exec.GetTxs(ctx, m.lastState)
+	if err != nil {
+		return nil, err
+	}
+
+	return m.newBlock(m.lastState, height, txs, lastCommit, lastHeaderHash), nil
+}
+
+// newBlock builds a block containing txs, on top of the state.
+func (m *Manager) newBlock(s types.State, height uint64, txs types.Txs, lastCommit *types.Commit, lastHeaderHash types.Hash) *types.Block {
+	block := &types.Block{
+		SignedHeader: types.SignedHeader{
+			Header: types.Header{
+				Version: types.Version{
+					Block: s.Version.Consensus.Block,
+					App:   s.Version.Consensus.App,
+				},
+				BaseHeader: types.BaseHeader{
+					ChainID: m.genesis.ChainID,
+					Height:  height,
+					Time:    uint64(m.clock.Now().UnixNano()),
+				},
+				//LastHeaderHash: lastHeaderHash,
+				//LastCommitHash:  lastCommitHash,
+				DataHash:        make(types.Hash, 32),
+				ConsensusHash:   make(types.Hash, 32),
+				AppHash:         s.AppHash,
+				LastResultsHash: s.LastResultsHash,
+				ProposerAddress: m.proposerAddress,
+			},
+			Commit: *lastCommit,
+		},
+		Data: types.Data{
+			Txs:                    txs,
+			IntermediateStateRoots: types.IntermediateStateRoots{RawRootsList: nil},
+			// Note: Temporarily remove Evidence #896
+			// Evidence:               types.EvidenceData{Evidence: nil},
+		},
+	}
+	block.SignedHeader.LastCommitHash = lastCommit.GetCommitHash(&block.SignedHeader.Header, m.proposerAddress)
+	block.SignedHeader.LastHeaderHash = lastHeaderHash
+
+	return block
 }
 
-func (m *Manager) applyBlock(ctx context.Context, block *types.Block) (types.State, *cmstate.ABCIResponses, error) {
+// SimulateTxs builds a candidate block containing txs on top of the latest state, and executes it
+// without committing anything.
+//
+// execution.ErrSimulationNotSupported is returned if executor doesn't implement execution.Simulator.
+func (m *Manager) SimulateTxs(ctx context.Context, txs types.Txs) (*execution.SimulationResult, error) {
+	simulator, ok := m.exec.(execution.Simulator)
+	if !ok {
+		return nil, execution.ErrSimulationNotSupported
+	}
+
+	m.lastStateMtx.RLock()
+	s := m.lastState
+	m.lastStateMtx.RUnlock()
+
+	height := s.LastBlockHeight + 1
+	if s.LastBlockHeight == 0 {
+		height = s.InitialHeight
+	}
+	block := m.newBlock(s, height, txs, &types.Commit{}, nil)
+	block.SignedHeader.Validators = m.getValidatorSet(ctx)
+	block.SignedHeader.ValidatorHash = block.SignedHeader.Validators.Hash()
+	var err error
+	block.SignedHeader.DataHash, err = block.Data.Hash()
+	if err != nil {
+		return nil, err
+	}
+
+	results, err := simulator.SimulateTxs(ctx, s, block)
+	if err != nil {
+		return nil, err
+	}
+	res := &execution.SimulationResult{
+		Height:    height,
+		TxResults: results,
+	}
+	for _, r := range results {
+		res.GasUsed += r.GasUsed
+	}
+	return res, nil
+}
+
+// applyBlock validates the block and executes it, without committing.
+func (m *Manager) applyBlock(ctx context.Context, block *types.Block) (types.State, error) {
 	m.lastStateMtx.RLock()
 	defer m.lastStateMtx.RUnlock()
-	return m.executor.ApplyBlock(ctx, m.lastState, block)
+	if err := validateBlock(m.lastState, block); err != nil {
+		return types.State{}, err
+	}
+	return m.exec.ExecuteTxs(ctx, m.lastState, block)
 }
 
-func updateState(s *types.State, res *abci.ResponseInitChain) {
-	// If the app did not return an app hash, we keep the one set from the genesis doc in
-	// the state. We don't set appHash since we don't want the genesis doc app hash
-	// recorded in the genesis block. We should probably just remove GenesisDoc.AppHash.
-	if len(res.AppHash) > 0 {
-		s.AppHash = res.AppHash
+// validateBlock validates the block against the state it's going to be applied to.
+func validateBlock(state types.State, block *types.Block) error {
+	err := block.ValidateBasic()
+	if err != nil {
+		return err
+	}
+	if block.SignedHeader.Version.App != state.Version.Consensus.App ||
+		block.SignedHeader.Version.Block != state.Version.Consensus.Block {
+		return errors.New("block version mismatch")
+	}
+	if state.LastBlockHeight <= 0 && block.Height() != state.InitialHeight {
+		return errors.New("initial block height mismatch")
+	}
+	if state.LastBlockHeight > 0 && block.Height() != state.LastBlockHeight+1 {
+		return errors.New("block height mismatch")
+	}
+	if !bytes.Equal(block.SignedHeader.AppHash[:], state.AppHash[:]) {
+		return errors.New("AppHash mismatch")
 	}
 
-	if res.ConsensusParams != nil {
-		params := res.ConsensusParams
-		if params.Block != nil {
-			s.ConsensusParams.Block.MaxBytes = params.Block.MaxBytes
-			s.ConsensusParams.Block.MaxGas = params.Block.MaxGas
-		}
-		if params.Evidence != nil {
-			s.ConsensusParams.Evidence.MaxAgeNumBlocks = params.Evidence.MaxAgeNumBlocks
-			s.ConsensusParams.Evidence.MaxAgeDuration = params.Evidence.MaxAgeDuration
-			s.ConsensusParams.Evidence.MaxBytes = params.Evidence.MaxBytes
-		}
-		if params.Validator != nil {
-			// Copy params.Validator.PubkeyTypes, and set result's value to the copy.
-			// This avoids having to initialize the slice to 0 values, and then write to it again.
-			s.ConsensusParams.Validator.PubKeyTypes = append([]string{}, params.Validator.PubKeyTypes...)
-		}
-		if params.Version != nil {
-			s.ConsensusParams.Version.App = params.Version.App
-		}
-		s.Version.Consensus.App = s.ConsensusParams.Version.App
+	if !bytes.Equal(block.SignedHeader.LastResultsHash[:], state.LastResultsHash[:]) {
+		return errors.New("LastResultsHash mismatch")
 	}
-	// We update the last results hash with the empty hash, to conform with RFC-6962.
-	s.LastResultsHash = merkle.HashFromByteSlices(nil)
 
+	return nil
 }
@@ -0,0 +1,153 @@
+package block
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/cometbft/cometbft/abci/example/kvstore"
+	cfg "github.com/cometbft/cometbft/config"
+	"github.com/cometbft/cometbft/libs/log"
+	"github.com/cometbft/cometbft/proxy"
+	cmtypes "github.com/cometbft/cometbft/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rollkit/rollkit/config"
+	"github.com/rollkit/rollkit/execution/kv"
+	"github.com/rollkit/rollkit/mempool"
+	mempoolv1 "github.com/rollkit/rollkit/mempool/v1"
+	"github.com/rollkit/rollkit/state"
+	"github.com/rollkit/rollkit/store"
+	"github.com/rollkit/rollkit/types"
+)
+
+const benchTxsPerBlock = 1000
+
+// drainChannels consumes published headers and blocks, like header and block exchange services.
+func drainChannels(ctx context.Context, m *Manager) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-m.HeaderCh:
+		case <-m.BlockCh:
+		}
+	}
+}
+
+func injectTxs(exec *kv.Executor, height, n int) {
+	for i := 0; i < n; i++ {
+		exec.InjectTx(types.Tx(fmt.Sprintf("key-%d-%d=value", height, i)))
+	}
+}
+
+// BenchmarkPublishBlock measures how fast aggregator can produce blocks, without waiting for block time.
+func BenchmarkPublishBlock(b *testing.B) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	conf := config.BlockManagerConfig{
+		BlockTime: time.Second,
+		Namespace: types.MustNewNamespace(types.NamespaceVersionZero, []byte{1, 2, 3, 4, 5, 6, 7, 8}),
+	}
+	m, exec, _ := getKVManager(ctx, b, conf, log.NewNopLogger())
+	go drainChannels(ctx, m)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		injectTxs(exec, i, benchTxsPerBlock)
+		b.StartTimer()
+		if err := m.publishBlock(ctx); err != nil {
+			b.Fatal(err)
+		}
+	}
+	if err := m.waitForPublished(); err != nil {
+		b.Fatal(err)
+	}
+	b.StopTimer()
+	b.ReportMetric(float64(b.N*benchTxsPerBlock)/b.Elapsed().Seconds(), "txs/s")
+}
+
+// BenchmarkPublishBlockABCI measures how fast aggregator can produce blocks with ABCI executor, without waiting
+// for block time. Transactions are checked by the mempool and executed by kvstore application through local client.
+func BenchmarkPublishBlockABCI(b *testing.B) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	logger := log.NewNopLogger()
+	client, err := proxy.NewLocalClientCreator(kvstore.NewApplication()).NewABCIClient()
+	require.NoError(b, err)
+	mpool := mempoolv1.NewTxMempool(logger, cfg.DefaultMempoolConfig(), proxy.NewAppConnMempool(client, proxy.NopMetrics()), 0)
+
+	genesisValidators, signingKey := types.GetGenesisValidatorSetWithSigner()
+	genesis := &cmtypes.GenesisDoc{ChainID: "test", InitialHeight: 1, Validators: genesisValidators}
+	kvStore, _ := store.NewDefaultInMemoryKVStore()
+	blockStore := store.New(ctx, kvStore)
+	exec := state.NewBlockExecutor(genesis.ChainID, mpool, proxy.NewAppConnConsensus(client, proxy.NopMetrics()), proxy.NewAppConnQuery(client, proxy.NopMetrics()), blockStore, nil, logger)
+	dalc := getMockDALC(logger)
+	b.Cleanup(func() {
+		require.NoError(b, dalc.Stop())
+	})
+	conf := config.BlockManagerConfig{
+		BlockTime: time.Second,
+		Namespace: types.MustNewNamespace(types.NamespaceVersionZero, []byte{1, 2, 3, 4, 5, 6, 7, 8}),
+	}
+	m, err := NewManager(signingKey, conf, genesis, blockStore, exec, dalc, logger, nil)
+	require.NoError(b, err)
+	go drainChannels(ctx, m)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		for j := 0; j < benchTxsPerBlock; j++ {
+			tx := cmtypes.Tx(fmt.Sprintf("key-%d-%d=value", i, j))
+			require.NoError(b, mpool.CheckTx(tx, nil, mempool.TxInfo{}))
+		}
+		b.StartTimer()
+		if err := m.publishBlock(ctx); err != nil {
+			b.Fatal(err)
+		}
+	}
+	if err := m.waitForPublished(); err != nil {
+		b.Fatal(err)
+	}
+	b.StopTimer()
+	b.ReportMetric(float64(b.N*benchTxsPerBlock)/b.Elapsed().Seconds(), "txs/s")
+}
+
+// BenchmarkAggregationLoop100ms measures throughput of aggregator producing blocks every 100ms.
+func BenchmarkAggregationLoop100ms(b *testing.B) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	conf := config.BlockManagerConfig{
+		BlockTime: 100 * time.Millisecond,
+		Namespace: types.MustNewNamespace(types.NamespaceVersionZero, []byte{1, 2, 3, 4, 5, 6, 7, 8}),
+	}
+	m, exec, blockStore := getKVManager(ctx, b, conf, log.NewNopLogger())
+	go drainChannels(ctx, m)
+
+	// transactions are always available, so every block is full
+	for i := 0; i < b.N; i++ {
+		injectTxs(exec, i, benchTxsPerBlock)
+	}
+
+	loopCtx, stopLoop := context.WithCancel(ctx)
+	done := make(chan struct{})
+	b.ResetTimer()
+	go func() {
+		m.AggregationLoop(loopCtx, false)
+		close(done)
+	}()
+	for blockStore.Height() < uint64(b.N) {
+		time.Sleep(time.Millisecond)
+	}
+	stopLoop()
+	<-done
+	b.StopTimer()
+
+	b.ReportMetric(float64(b.N)/b.Elapsed().Seconds(), "blocks/s")
+	b.ReportMetric(float64(b.N*benchTxsPerBlock)/b.Elapsed().Seconds(), "txs/s")
+}
@@ -3,9 +3,14 @@ package block
 import (
 	"context"
 	"crypto/rand"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/benbjohnson/clock"
 	"github.com/cometbft/cometbft/libs/log"
 	cmtypes "github.com/cometbft/cometbft/types"
 	"github.com/libp2p/go-libp2p/core/crypto"
@@ -15,6 +20,8 @@ import (
 	"github.com/rollkit/rollkit/config"
 	"github.com/rollkit/rollkit/da"
 	mockda "github.com/rollkit/rollkit/da/mock"
+	"github.com/rollkit/rollkit/execution"
+	"github.com/rollkit/rollkit/execution/kv"
 	"github.com/rollkit/rollkit/store"
 	test "github.com/rollkit/rollkit/test/log"
 	"github.com/rollkit/rollkit/types"
@@ -71,8 +78,8 @@ func TestInitialState(t *testing.T) {
 
 	key, _, _ := crypto.GenerateEd25519Key(rand.Reader)
 	conf := config.BlockManagerConfig{
-		BlockTime:   10 * time.Second,
-		NamespaceID: types.NamespaceID{1, 2, 3, 4, 5, 6, 7, 8},
+		BlockTime: 10 * time.Second,
+		Namespace: types.MustNewNamespace(types.NamespaceVersionZero, []byte{1, 2, 3, 4, 5, 6, 7, 8}),
 	}
 
 	for _, c := range cases {
@@ -83,7 +90,7 @@ func TestInitialState(t *testing.T) {
 			defer func() {
 				require.NoError(t, dalc.Stop())
 			}()
-			agg, err := NewManager(key, conf, c.genesis, c.store, nil, nil, dalc, nil, logger, nil)
+			agg, err := NewManager(key, conf, c.genesis, c.store, kv.NewExecutor(), dalc, logger, nil)
 			assert.NoError(err)
 			assert.NotNil(agg)
 			agg.lastStateMtx.RLock()
@@ -97,11 +104,237 @@ func TestInitialState(t *testing.T) {
 
 func getMockDALC(logger log.Logger) da.DataAvailabilityLayerClient {
 	dalc := &mockda.DataAvailabilityLayerClient{}
-	_ = dalc.Init([8]byte{}, nil, nil, logger)
+	_ = dalc.Init(types.Namespace{}, nil, nil, logger)
 	_ = dalc.Start()
 	return dalc
 }
 
+// getMockDALCWithClock returns mock DA layer client, producing DA blocks when returned clock is advanced by a second.
+func getMockDALCWithClock(tb testing.TB, logger log.Logger) (*mockda.DataAvailabilityLayerClient, *clock.Mock) {
+	kvStore, err := store.NewDefaultInMemoryKVStore()
+	require.NoError(tb, err)
+	clk := clock.NewMock()
+	dalc := &mockda.DataAvailabilityLayerClient{}
+	dalc.SetClock(clk)
+	require.NoError(tb, dalc.Init(types.Namespace{}, []byte(time.Second.String()), kvStore, logger))
+	require.NoError(tb, dalc.Start())
+	return dalc, clk
+}
+
+// getKVManager returns block manager of a single node chain, executing blocks with kv.Executor.
+func getKVManager(ctx context.Context, tb testing.TB, conf config.BlockManagerConfig, logger log.Logger) (*Manager, *kv.Executor, store.Store) {
+	genesisValidators, signingKey := types.GetGenesisValidatorSetWithSigner()
+	genesis := &cmtypes.GenesisDoc{
+		ChainID:       "test",
+		InitialHeight: 1,
+		Validators:    genesisValidators,
+		AppState:      []byte(`{"a":"1"}`),
+	}
+	exec := kv.NewExecutor()
+	m, blockStore := newManagerWithExecutor(ctx, tb, conf, genesis, signingKey, exec, logger)
+	return m, exec, blockStore
+}
+
+// newManagerWithExecutor returns block manager of a chain, using in-memory store and mock DA layer client.
+func newManagerWithExecutor(ctx context.Context, tb testing.TB, conf config.BlockManagerConfig, genesis *cmtypes.GenesisDoc, signingKey crypto.PrivKey, exec execution.Executor, logger log.Logger) (*Manager, store.Store) {
+	kvStore, _ := store.NewDefaultInMemoryKVStore()
+	blockStore := store.New(ctx, kvStore)
+
+	dalc := getMockDALC(logger)
+	tb.Cleanup(func() {
+		require.NoError(tb, dalc.Stop())
+	})
+	m, err := NewManager(signingKey, conf, genesis, blockStore, exec, dalc, logger, nil)
+	require.NoError(tb, err)
+	return m, blockStore
+}
+
+func TestPublishBlockWithKVExecutor(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	conf := config.BlockManagerConfig{
+		BlockTime: time.Second,
+		Namespace: types.MustNewNamespace(types.NamespaceVersionZero, []byte{1, 2, 3, 4, 5, 6, 7, 8}),
+	}
+	m, exec, blockStore := getKVManager(ctx, t, conf, test.NewFileLogger(t))
+
+	// genesis app state is loaded by executor
+	v, ok := exec.Get("a")
+	assert.True(ok)
+	assert.Equal("1", v)
+
+	exec.InjectTx(types.Tx("b=2"))
+	require.NoError(m.publishBlock(ctx))
+	require.NoError(m.waitForPublished())
+
+	assert.Equal(uint64(1), blockStore.Height())
+	block, err := blockStore.GetBlock(1)
+	require.NoError(err)
+	assert.Equal(types.Txs{types.Tx("b=2")}, block.Data.Txs)
+	v, ok = exec.Get("b")
+	assert.True(ok)
+	assert.Equal("2", v)
+
+	state, err := blockStore.GetState()
+	require.NoError(err)
+	assert.Equal(uint64(1), state.LastBlockHeight)
+	assert.Equal(types.Hash(exec.AppHash()), state.AppHash)
+
+	// next block is built on top of the committed app hash
+	require.NoError(m.publishBlock(ctx))
+	require.NoError(m.waitForPublished())
+	block, err = blockStore.GetBlock(2)
+	require.NoError(err)
+	assert.Empty(block.Data.Txs)
+	assert.Equal(state.AppHash, block.SignedHeader.AppHash)
+
+	// both blocks were published for broadcasting
+	assert.Len(m.HeaderCh, 2)
+	assert.Len(m.BlockCh, 2)
+}
+
+// blockingCommitter blocks Commit until a value is received from release; received error is returned by Commit.
+type blockingCommitter struct {
+	*kv.Executor
+	release chan error
+}
+
+func (b *blockingCommitter) Commit(ctx context.Context, state types.State, block *types.Block) ([]byte, error) {
+	if err := <-b.release; err != nil {
+		return nil, err
+	}
+	return b.Executor.Commit(ctx, state, block)
+}
+
+func TestCommitPipeline(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	genesisValidators, signingKey := types.GetGenesisValidatorSetWithSigner()
+	genesis := &cmtypes.GenesisDoc{ChainID: "test", InitialHeight: 1, Validators: genesisValidators}
+	exec := &blockingCommitter{Executor: kv.NewExecutor(), release: make(chan error)}
+	conf := config.BlockManagerConfig{BlockTime: time.Second}
+	m, blockStore := newManagerWithExecutor(ctx, t, conf, genesis, signingKey, exec, test.NewFileLogger(t))
+
+	// block is committed in the background
+	require.NoError(m.publishBlock(ctx))
+	assert.Equal(uint64(1), blockStore.Height())
+	assert.Equal(uint64(0), m.GetLastState().LastBlockHeight)
+
+	// next block is built only after the previous one is committed
+	done := make(chan error)
+	go func() {
+		done <- m.publishBlock(ctx)
+	}()
+	select {
+	case <-done:
+		t.Fatal("block built before previous block was committed")
+	case <-time.After(50 * time.Millisecond):
+	}
+	exec.release <- nil
+	require.NoError(<-done)
+	assert.Equal(uint64(2), blockStore.Height())
+	assert.Equal(uint64(1), m.GetLastState().LastBlockHeight)
+
+	// failed commit is reported, and the block is committed again by the next attempt
+	exec.release <- errors.New("application crashed")
+	assert.ErrorContains(m.waitForPublished(), "failed to commit block at height 2")
+	assert.Equal(uint64(1), m.GetLastState().LastBlockHeight)
+	require.NoError(m.publishBlock(ctx))
+	exec.release <- nil
+	require.NoError(m.waitForPublished())
+	state := m.GetLastState()
+	assert.Equal(uint64(2), state.LastBlockHeight)
+	assert.Equal(uint64(2), blockStore.Height())
+	assert.Equal([]byte(state.AppHash), exec.AppHash())
+	stored, err := blockStore.GetState()
+	require.NoError(err)
+	assert.Equal(state.AppHash, stored.AppHash)
+}
+
+// publishingDALC publishes a block (once) while blocks are being submitted to DA layer.
+type publishingDALC struct {
+	da.DataAvailabilityLayerClient
+	publish func()
+}
+
+func (d *publishingDALC) SubmitBlocks(ctx context.Context, blocks []*types.Block) da.ResultSubmitBlocks {
+	if d.publish != nil {
+		d.publish()
+		d.publish = nil
+	}
+	return d.DataAvailabilityLayerClient.SubmitBlocks(ctx, blocks)
+}
+
+func TestSubmitBlocksProducedDuringSubmission(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	conf := config.BlockManagerConfig{
+		BlockTime: time.Second,
+		Namespace: types.MustNewNamespace(types.NamespaceVersionZero, []byte{1, 2, 3, 4, 5, 6, 7, 8}),
+	}
+	m, _, _ := getKVManager(ctx, t, conf, test.NewFileLogger(t))
+	require.NoError(m.publishBlock(ctx))
+	require.NoError(m.waitForPublished())
+
+	dalc, _ := getMockDALCWithClock(t, test.NewFileLogger(t))
+	m.dalc = &publishingDALC{
+		DataAvailabilityLayerClient: dalc,
+		publish: func() {
+			require.NoError(m.publishBlock(ctx))
+			require.NoError(m.waitForPublished())
+		},
+	}
+	require.NoError(m.submitBlocksToDA(ctx))
+	pending := m.pendingBlocks.getPendingBlocks()
+	require.Len(pending, 1)
+	assert.Equal(uint64(2), pending[0].Height())
+
+	require.NoError(m.submitBlocksToDA(ctx))
+	assert.True(m.pendingBlocks.isEmpty())
+}
+
+func TestSimulateTxs(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	conf := config.BlockManagerConfig{
+		BlockTime: time.Second,
+		Namespace: types.MustNewNamespace(types.NamespaceVersionZero, []byte{1, 2, 3, 4, 5, 6, 7, 8}),
+	}
+	m, exec, blockStore := getKVManager(ctx, t, conf, test.NewFileLogger(t))
+	appHash := exec.AppHash()
+
+	res, err := m.SimulateTxs(ctx, types.Txs{types.Tx("b=2"), types.Tx("invalid"), types.Tx("c=33")})
+	require.NoError(err)
+	assert.Equal(uint64(1), res.Height)
+	require.Len(res.TxResults, 3)
+	assert.Equal(uint32(0), res.TxResults[0].Code)
+	assert.NotEqual(uint32(0), res.TxResults[1].Code)
+	assert.Equal(uint32(0), res.TxResults[2].Code)
+	assert.Equal(int64(7), res.GasUsed)
+
+	// nothing was committed
+	_, ok := exec.Get("b")
+	assert.False(ok)
+	assert.Equal(appHash, exec.AppHash())
+	assert.Equal(uint64(0), blockStore.Height())
+}
+
 func TestIsDAIncluded(t *testing.T) {
 	require := require.New(t)
 
@@ -118,3 +351,511 @@ func TestIsDAIncluded(t *testing.T) {
 	m.blockCache.setDAIncluded(hash.String())
 	require.True(m.IsDAIncluded(hash))
 }
+
+func TestPauseResume(t *testing.T) {
+	require := require.New(t)
+
+	m := &Manager{logger: log.TestingLogger()}
+	ctx := context.Background()
+
+	// not paused
+	require.NoError(m.waitIfPaused(ctx))
+
+	m.Pause()
+	m.Pause() // pausing twice is allowed
+
+	timeoutCtx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+	require.ErrorIs(m.waitIfPaused(timeoutCtx), context.DeadlineExceeded)
+
+	done := make(chan error)
+	go func() {
+		done <- m.waitIfPaused(ctx)
+	}()
+	m.Resume()
+	select {
+	case err := <-done:
+		require.NoError(err)
+	case <-time.After(time.Second):
+		t.Fatal("manager not resumed")
+	}
+	m.Resume() // resuming not paused manager is allowed
+	require.NoError(m.waitIfPaused(ctx))
+}
+
+func TestReplayBlocks(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	conf := config.BlockManagerConfig{BlockTime: time.Second}
+	m, exec, _ := getKVManager(ctx, t, conf, test.NewFileLogger(t))
+	for i := 0; i < 3; i++ {
+		exec.InjectTx(types.Tx(fmt.Sprintf("k%d=%d", i, i)))
+		require.NoError(m.publishBlock(ctx))
+	}
+	require.NoError(m.waitForPublished())
+	state := m.GetLastState()
+	require.Equal(uint64(3), state.LastBlockHeight)
+
+	// nothing to replay
+	require.NoError(m.ReplayBlocks(ctx, 3))
+
+	// executor lost the last block
+	require.NoError(exec.Rollback(ctx, 2))
+	_, ok := exec.Get("k2")
+	require.False(ok)
+	require.NoError(m.ReplayBlocks(ctx, 2))
+	assert.Equal([]byte(state.AppHash), exec.AppHash())
+	v, ok := exec.Get("k2")
+	assert.True(ok)
+	assert.Equal("2", v)
+
+	// executor lost all blocks, chain is initialized again
+	require.NoError(exec.Rollback(ctx, 0))
+	require.NoError(m.ReplayBlocks(ctx, 0))
+	assert.Equal([]byte(state.AppHash), exec.AppHash())
+
+	// executor committed different block at height 2, replayed block doesn't lead to node app hash
+	require.NoError(exec.Rollback(ctx, 1))
+	block := types.GetRandomBlock(2, 0)
+	block.Data.Txs = types.Txs{types.Tx("k1=x")}
+	s, err := exec.ExecuteTxs(ctx, state, block)
+	require.NoError(err)
+	_, err = exec.Commit(ctx, s, block)
+	require.NoError(err)
+	assert.ErrorContains(m.ReplayBlocks(ctx, 2), "doesn't match node app hash")
+}
+
+// finalityRecorder records finality levels of committed blocks and DA finality notifications.
+type finalityRecorder struct {
+	*kv.Executor
+	committed map[uint64]execution.Finality
+	finalized []uint64
+}
+
+func (r *finalityRecorder) Commit(ctx context.Context, state types.State, block *types.Block) ([]byte, error) {
+	f, _ := execution.FinalityFromContext(ctx)
+	r.committed[block.Height()] = f
+	return r.Executor.Commit(ctx, state, block)
+}
+
+func (r *finalityRecorder) BlockFinalized(_ context.Context, height uint64) error {
+	r.finalized = append(r.finalized, height)
+	return nil
+}
+
+func TestSyncModes(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	logger := test.NewFileLogger(t)
+
+	genesisValidators, signingKey := types.GetGenesisValidatorSetWithSigner()
+	genesis := &cmtypes.GenesisDoc{
+		ChainID:       "test",
+		InitialHeight: 1,
+		Validators:    genesisValidators,
+		AppState:      []byte(`{"a":"1"}`),
+	}
+
+	// blocks are produced by aggregator, and synced by nodes in different sync modes
+	aggregator, aggStore := newManagerWithExecutor(ctx, t, config.BlockManagerConfig{BlockTime: time.Second}, genesis, signingKey, kv.NewExecutor(), logger)
+	for i := 0; i < 2; i++ {
+		require.NoError(t, aggregator.publishBlock(ctx))
+		require.NoError(t, aggregator.waitForPublished())
+	}
+	blocks := make([]*types.Block, 2)
+	for i := range blocks {
+		var err error
+		blocks[i], err = aggStore.GetBlock(uint64(i + 1))
+		require.NoError(t, err)
+	}
+
+	newSyncer := func(mode string) (*Manager, *finalityRecorder) {
+		exec := &finalityRecorder{Executor: kv.NewExecutor(), committed: make(map[uint64]execution.Finality)}
+		m, _ := newManagerWithExecutor(ctx, t, config.BlockManagerConfig{BlockTime: time.Second, SyncMode: mode}, genesis, signingKey, exec, logger)
+		return m, exec
+	}
+	// markDAIncluded does what processNextDABlock does for block retrieved from DA layer
+	markDAIncluded := func(m *Manager, b *types.Block) {
+		m.blockCache.setDAIncluded(b.Hash().String())
+		m.setDAIncludedHeight(b.Height())
+		m.updateFinalizedHeight(ctx)
+	}
+
+	t.Run("hybrid", func(t *testing.T) {
+		assert := assert.New(t)
+		require := require.New(t)
+		m, exec := newSyncer(config.SyncModeHybrid)
+
+		// soft block is executed, and becomes DA-final when it's retrieved from DA
+		m.blockCache.setBlock(1, blocks[0])
+		require.NoError(m.trySyncNextBlock(ctx, 0))
+		assert.Equal(execution.FinalitySoft, exec.committed[1])
+		assert.Equal(execution.FinalitySoft, m.Finality(1))
+		markDAIncluded(m, blocks[0])
+		assert.Equal(execution.FinalityDA, m.Finality(1))
+		assert.Equal([]uint64{1}, exec.finalized)
+
+		// block retrieved from DA is committed as DA-final, executor is not notified again
+		markDAIncluded(m, blocks[1])
+		m.blockCache.setBlock(2, blocks[1])
+		require.NoError(m.trySyncNextBlock(ctx, 0))
+		assert.Equal(execution.FinalityDA, exec.committed[2])
+		assert.Equal([]uint64{1}, exec.finalized)
+
+		status := m.DAStatus()
+		assert.Equal(uint64(2), status.DAFinalHeight)
+		assert.Equal(config.SyncModeHybrid, status.SyncMode)
+	})
+
+	t.Run("da-only", func(t *testing.T) {
+		assert := assert.New(t)
+		require := require.New(t)
+		m, exec := newSyncer(config.SyncModeDAOnly)
+
+		// soft block is not executed
+		m.blockCache.setBlock(1, blocks[0])
+		assert.ErrorIs(m.trySyncNextBlock(ctx, 0), errNotDAFinal)
+		assert.Zero(m.GetStoreHeight())
+
+		markDAIncluded(m, blocks[0])
+		require.NoError(m.trySyncNextBlock(ctx, 0))
+		assert.Equal(uint64(1), m.GetStoreHeight())
+		assert.Equal(execution.FinalityDA, exec.committed[1])
+		assert.Empty(exec.finalized)
+	})
+
+	t.Run("soft", func(t *testing.T) {
+		assert := assert.New(t)
+		require := require.New(t)
+		m, exec := newSyncer("")
+
+		// finality is tracked, but blocks are not labelled
+		m.blockCache.setBlock(1, blocks[0])
+		require.NoError(m.trySyncNextBlock(ctx, 0))
+		markDAIncluded(m, blocks[0])
+		assert.Equal(execution.Finality(""), exec.committed[1])
+		assert.Equal(execution.FinalityDA, m.Finality(1))
+		assert.Empty(exec.finalized)
+		assert.Equal(config.SyncModeSoft, m.DAStatus().SyncMode)
+	})
+
+	dalc := getMockDALC(logger)
+	defer func() {
+		require.NoError(t, dalc.Stop())
+	}()
+	_, err := NewManager(signingKey, config.BlockManagerConfig{SyncMode: "optimistic"}, genesis, aggStore, kv.NewExecutor(), dalc, logger, nil)
+	assert.Error(t, err)
+}
+
+func TestSyncOutOfOrderBlocks(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	logger := test.NewFileLogger(t)
+
+	genesisValidators, signingKey := types.GetGenesisValidatorSetWithSigner()
+	genesis := &cmtypes.GenesisDoc{ChainID: "test", InitialHeight: 1, Validators: genesisValidators}
+	aggregator, aggStore := newManagerWithExecutor(ctx, t, config.BlockManagerConfig{BlockTime: time.Second}, genesis, signingKey, kv.NewExecutor(), logger)
+	for i := 0; i < 3; i++ {
+		require.NoError(aggregator.publishBlock(ctx))
+		require.NoError(aggregator.waitForPublished())
+	}
+
+	m, _ := newManagerWithExecutor(ctx, t, config.BlockManagerConfig{BlockTime: time.Second}, genesis, signingKey, kv.NewExecutor(), logger)
+	cacheBlock := func(height uint64) {
+		b, err := aggStore.GetBlock(height)
+		require.NoError(err)
+		m.blockCache.setBlock(height, b)
+	}
+
+	// blocks above the next height can't be synced yet
+	cacheBlock(3)
+	cacheBlock(2)
+	require.NoError(m.trySyncNextBlock(ctx, 0))
+	assert.Zero(m.GetStoreHeight())
+
+	// missing block arrives, all cached blocks are synced at once
+	cacheBlock(1)
+	require.NoError(m.trySyncNextBlock(ctx, 0))
+	assert.Equal(uint64(3), m.GetStoreHeight())
+	for h := uint64(1); h <= 3; h++ {
+		_, ok := m.blockCache.getBlock(h)
+		assert.False(ok, "block at height %d is still cached", h)
+	}
+}
+
+// reorgRecorder records reorgs reported to executor.
+type reorgRecorder struct {
+	*kv.Executor
+	mtx    sync.Mutex
+	reorgs []execution.Reorg
+}
+
+func (r *reorgRecorder) BlocksReorged(_ context.Context, reorg execution.Reorg) error {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	r.reorgs = append(r.reorgs, reorg)
+	return nil
+}
+
+func (r *reorgRecorder) getReorgs() []execution.Reorg {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	return append([]execution.Reorg(nil), r.reorgs...)
+}
+
+func TestReconcileWithDA(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	logger := test.NewFileLogger(t)
+
+	genesisValidators, signingKey := types.GetGenesisValidatorSetWithSigner()
+	genesis := &cmtypes.GenesisDoc{
+		ChainID:       "test",
+		InitialHeight: 1,
+		Validators:    genesisValidators,
+		AppState:      []byte(`{"a":"1"}`),
+	}
+	conf := config.BlockManagerConfig{BlockTime: time.Second}
+	syncBlocks := func(m *Manager, blocks ...*types.Block) {
+		for _, b := range blocks {
+			m.blockCache.setBlock(b.Height(), b)
+			require.NoError(t, m.trySyncNextBlock(ctx, 0))
+		}
+		require.Equal(t, blocks[len(blocks)-1].Height(), m.GetStoreHeight())
+	}
+
+	// sequencer gossips blocks 1-3 over P2P network
+	gossiped, gossipedStore := newManagerWithExecutor(ctx, t, conf, genesis, signingKey, kv.NewExecutor(), logger)
+	for i := 0; i < 3; i++ {
+		require.NoError(t, gossiped.publishBlock(ctx))
+		require.NoError(t, gossiped.waitForPublished())
+	}
+	soft := make([]*types.Block, 3)
+	for i := range soft {
+		var err error
+		soft[i], err = gossipedStore.GetBlock(uint64(i + 1))
+		require.NoError(t, err)
+	}
+
+	// but posts different blocks 3 and 4 to DA layer
+	canonicalExec := kv.NewExecutor()
+	canonical, canonicalStore := newManagerWithExecutor(ctx, t, conf, genesis, signingKey, canonicalExec, logger)
+	syncBlocks(canonical, soft[0], soft[1])
+	canonicalExec.InjectTx(types.Tx("b=2"))
+	for i := 0; i < 2; i++ {
+		require.NoError(t, canonical.publishBlock(ctx))
+		require.NoError(t, canonical.waitForPublished())
+	}
+	daBlocks := make([]*types.Block, 2)
+	for i := range daBlocks {
+		var err error
+		daBlocks[i], err = canonicalStore.GetBlock(uint64(i + 3))
+		require.NoError(t, err)
+	}
+	require.NotEqual(t, soft[2].Hash(), daBlocks[0].Hash())
+
+	dalc, clk := getMockDALCWithClock(t, logger)
+	dalc.InjectFault(3, daBlocks[0])
+	res := dalc.SubmitBlocks(ctx, soft)
+	require.Equal(t, da.StatusSuccess, res.Code, res.Message)
+	require.Equal(t, da.StatusSuccess, dalc.SubmitBlocks(ctx, daBlocks[1:]).Code)
+	require.Eventually(t, func() bool {
+		clk.Add(time.Second)
+		return dalc.RetrieveBlocks(ctx, res.DAHeight).Code == da.StatusSuccess
+	}, time.Second, 10*time.Millisecond)
+
+	t.Run("rollback", func(t *testing.T) {
+		assert := assert.New(t)
+		require := require.New(t)
+
+		exec := &reorgRecorder{Executor: kv.NewExecutor()}
+		m, _ := newManagerWithExecutor(ctx, t, conf, genesis, signingKey, exec, logger)
+		syncBlocks(m, soft...)
+		m.SetDALC(dalc)
+		m.daHeight = res.DAHeight
+		hooked := make(chan execution.Reorg, 1)
+		m.OnReorg(func(_ context.Context, reorg execution.Reorg) error {
+			hooked <- reorg
+			return nil
+		})
+
+		go m.SyncLoop(ctx, cancel)
+		require.NoError(m.processNextDABlock(ctx))
+		require.Eventually(func() bool {
+			return m.GetStoreHeight() == 4
+		}, time.Second, 10*time.Millisecond)
+
+		// soft block 3 is replaced by the chain from DA layer
+		for _, b := range daBlocks {
+			synced, err := m.store.GetBlock(b.Height())
+			require.NoError(err)
+			assert.Equal(b.Hash(), synced.Hash())
+		}
+		value, _ := exec.Get("b")
+		assert.Equal("2", value)
+		assert.Equal(canonicalExec.AppHash(), exec.AppHash())
+
+		reorgs := exec.getReorgs()
+		require.Len(reorgs, 1)
+		assert.Equal(uint64(2), reorgs[0].Height)
+		assert.Equal([]types.Hash{soft[2].Hash()}, reorgs[0].Reverted)
+		assert.Equal(daBlocks[0].Hash(), reorgs[0].Canonical)
+		assert.Equal(res.DAHeight, reorgs[0].DAHeight)
+		assert.Equal(reorgs[0], <-hooked)
+		require.Eventually(func() bool {
+			m.updateFinalizedHeight(ctx)
+			return m.Finality(4) == execution.FinalityDA
+		}, time.Second, 10*time.Millisecond)
+
+		// DA-final height is persisted, states of DA-final blocks are pruned
+		finalized, err := m.store.GetFinalizedHeight()
+		require.NoError(err)
+		assert.Equal(uint64(4), finalized)
+		_, err = m.store.GetSoftState(3)
+		assert.Error(err)
+		state, err := m.store.GetSoftState(4)
+		require.NoError(err)
+		assert.Equal(canonicalExec.AppHash(), []byte(state.AppHash))
+	})
+
+	t.Run("no rollback support", func(t *testing.T) {
+		// executor can't be rolled back if it only implements Executor
+		exec := struct{ execution.Executor }{kv.NewExecutor()}
+		m, _ := newManagerWithExecutor(ctx, t, conf, genesis, signingKey, exec, logger)
+		syncBlocks(m, soft...)
+		m.blockCache.setDAIncluded(daBlocks[0].Hash().String())
+		assert.Error(t, m.reconcile(ctx, daBlocks[0], res.DAHeight))
+		synced, err := m.store.GetBlock(3)
+		require.NoError(t, err)
+		assert.Equal(t, soft[2].Hash(), synced.Hash())
+	})
+}
+
+func TestPersistSoftStates(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	logger := test.NewFileLogger(t)
+
+	genesisValidators, signingKey := types.GetGenesisValidatorSetWithSigner()
+	genesis := &cmtypes.GenesisDoc{ChainID: "test", InitialHeight: 1, Validators: genesisValidators}
+	conf := config.BlockManagerConfig{BlockTime: time.Second}
+
+	aggregator, aggStore := newManagerWithExecutor(ctx, t, conf, genesis, signingKey, kv.NewExecutor(), logger)
+	for i := 0; i < 2; i++ {
+		require.NoError(aggregator.publishBlock(ctx))
+		require.NoError(aggregator.waitForPublished())
+	}
+
+	kvStore, err := store.NewDefaultInMemoryKVStore()
+	require.NoError(err)
+	nodeStore := store.New(ctx, kvStore)
+	newNode := func() *Manager {
+		m, err := NewManager(signingKey, conf, genesis, nodeStore, kv.NewExecutor(), getMockDALC(logger), logger, nil)
+		require.NoError(err)
+		return m
+	}
+
+	// soft blocks are synced from P2P network
+	m := newNode()
+	for h := uint64(1); h <= 2; h++ {
+		block, err := aggStore.GetBlock(h)
+		require.NoError(err)
+		m.blockCache.setBlock(h, block)
+		require.NoError(m.trySyncNextBlock(ctx, 0))
+	}
+	assert.Equal(execution.FinalitySoft, m.Finality(2))
+
+	// blocks are still soft after restart, and can be rolled back
+	m = newNode()
+	assert.Equal(uint64(0), atomic.LoadUint64(&m.finalizedHeight))
+	assert.Equal(execution.FinalitySoft, m.Finality(1))
+	for h := uint64(0); h <= 2; h++ {
+		state, err := nodeStore.GetSoftState(h)
+		require.NoError(err)
+		assert.Equal(h, state.LastBlockHeight)
+	}
+}
+
+func TestPersistDAHeight(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	logger := test.NewFileLogger(t)
+
+	genesisValidators, signingKey := types.GetGenesisValidatorSetWithSigner()
+	genesis := &cmtypes.GenesisDoc{ChainID: "test", InitialHeight: 1, Validators: genesisValidators}
+	conf := config.BlockManagerConfig{BlockTime: time.Second}
+
+	// blocks are posted at single DA height, followed by a few empty DA heights
+	aggregator, aggStore := newManagerWithExecutor(ctx, t, conf, genesis, signingKey, kv.NewExecutor(), logger)
+	for i := 0; i < 2; i++ {
+		require.NoError(aggregator.publishBlock(ctx))
+		require.NoError(aggregator.waitForPublished())
+	}
+	blocks := make([]*types.Block, 2)
+	for i := range blocks {
+		var err error
+		blocks[i], err = aggStore.GetBlock(uint64(i + 1))
+		require.NoError(err)
+	}
+	dalc, clk := getMockDALCWithClock(t, logger)
+	res := dalc.SubmitBlocks(ctx, blocks)
+	require.Equal(da.StatusSuccess, res.Code, res.Message)
+	require.Eventually(func() bool {
+		clk.Add(time.Second)
+		return dalc.RetrieveBlocks(ctx, res.DAHeight+2).Code == da.StatusSuccess
+	}, time.Second, 10*time.Millisecond)
+
+	kvStore, err := store.NewDefaultInMemoryKVStore()
+	require.NoError(err)
+	nodeStore := store.New(ctx, kvStore)
+	newNode := func() *Manager {
+		m, err := NewManager(signingKey, conf, genesis, nodeStore, kv.NewExecutor(), dalc, logger, nil)
+		require.NoError(err)
+		return m
+	}
+	// retrieveNext does what RetrieveLoop does for single DA height
+	retrieveNext := func(m *Manager) {
+		require.NoError(m.processNextDABlock(ctx))
+		m.advanceDAHeight()
+	}
+
+	m := newNode()
+	require.Equal(res.DAHeight, m.DAStatus().DAHeight)
+	retrieveNext(m)
+
+	// retrieved blocks are not committed yet, so they are retrieved again after restart
+	_, err = nodeStore.GetDAHeight()
+	assert.Error(err)
+	restarted := newNode().DAStatus()
+	assert.Equal(res.DAHeight, restarted.DAHeight)
+
+	// DA height of the first block is recorded
+	assert.Equal(res.DAHeight, m.DAStatus().DAStartHeight)
+	assert.Equal(res.DAHeight, <-m.DAStartHeightCh)
+	assert.Equal(res.DAHeight, restarted.DAStartHeight)
+
+	for len(m.blockInCh) > 0 {
+		event := <-m.blockInCh
+		m.blockCache.setBlock(event.block.Height(), event.block)
+		require.NoError(m.trySyncNextBlock(ctx, event.daHeight))
+	}
+	require.Equal(uint64(2), m.GetStoreHeight())
+
+	// empty DA heights are not scanned again after restart
+	retrieveNext(m)
+	retrieveNext(m)
+	daHeight, err := nodeStore.GetDAHeight()
+	require.NoError(err)
+	assert.Equal(res.DAHeight+3, daHeight)
+	assert.Equal(res.DAHeight+3, newNode().DAStatus().DAHeight)
+}
@@ -0,0 +1,90 @@
+package block
+
+import (
+	"github.com/go-kit/kit/metrics"
+	"github.com/go-kit/kit/metrics/discard"
+	"github.com/go-kit/kit/metrics/prometheus"
+	stdprometheus "github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	// MetricsSubsystem is a subsystem shared by all metrics exposed by this
+	// package.
+	MetricsSubsystem = "block_manager"
+)
+
+// Metrics contains metrics exposed by this package.
+type Metrics struct {
+	// Interval to the next block chosen by aggregator, in seconds.
+	BlockInterval metrics.Gauge
+
+	// Load of the mempool when block was produced (pending transactions relative to maximum block size
+	// or gas, 1 means full block).
+	MempoolLoad metrics.Gauge
+
+	// Number of blocks produced before block interval elapsed, because mempool held a full block.
+	EarlyBlocks metrics.Counter
+
+	// Number of rollbacks of soft blocks diverging from DA layer.
+	Reorgs metrics.Counter
+
+	// Number of soft blocks reverted by rollbacks.
+	RevertedBlocks metrics.Counter
+}
+
+// PrometheusMetrics returns Metrics build using Prometheus client library.
+// Optionally, labels can be provided along with their values ("foo",
+// "fooValue").
+func PrometheusMetrics(namespace string, labelsAndValues ...string) *Metrics {
+	labels := []string{}
+	for i := 0; i < len(labelsAndValues); i += 2 {
+		labels = append(labels, labelsAndValues[i])
+	}
+	return &Metrics{
+		BlockInterval: prometheus.NewGaugeFrom(stdprometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "block_interval_seconds",
+			Help:      "Interval to the next block chosen by aggregator, in seconds.",
+		}, labels).With(labelsAndValues...),
+
+		MempoolLoad: prometheus.NewGaugeFrom(stdprometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "mempool_load",
+			Help:      "Pending transactions relative to maximum block size or gas, when block was produced.",
+		}, labels).With(labelsAndValues...),
+
+		EarlyBlocks: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "early_blocks",
+			Help:      "Number of blocks produced early, because mempool held a full block.",
+		}, labels).With(labelsAndValues...),
+
+		Reorgs: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "reorgs",
+			Help:      "Number of rollbacks of soft blocks diverging from DA layer.",
+		}, labels).With(labelsAndValues...),
+
+		RevertedBlocks: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "reverted_blocks",
+			Help:      "Number of soft blocks reverted by rollbacks.",
+		}, labels).With(labelsAndValues...),
+	}
+}
+
+// NopMetrics returns no-op Metrics.
+func NopMetrics() *Metrics {
+	return &Metrics{
+		BlockInterval:  discard.NewGauge(),
+		MempoolLoad:    discard.NewGauge(),
+		EarlyBlocks:    discard.NewCounter(),
+		Reorgs:         discard.NewCounter(),
+		RevertedBlocks: discard.NewCounter(),
+	}
+}
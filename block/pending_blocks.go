@@ -37,8 +37,10 @@ func (pb *PendingBlocks) addPendingBlock(block *types.Block) {
 	pb.pendingBlocks = append(pb.pendingBlocks, block)
 }
 
-func (pb *PendingBlocks) resetPendingBlocks() {
+// removeSubmittedBlocks removes first n blocks, submitted to DA layer. Blocks added while they were submitted are
+// kept.
+func (pb *PendingBlocks) removeSubmittedBlocks(n int) {
 	pb.mtx.Lock()
 	defer pb.mtx.Unlock()
-	pb.pendingBlocks = make([]*types.Block, 0)
+	pb.pendingBlocks = append([]*types.Block{}, pb.pendingBlocks[n:]...)
 }
@@ -0,0 +1,134 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/rollkit/rollkit/node"
+)
+
+const (
+	flagBackupNode   = "node"
+	flagBackupOutput = "output"
+	flagRestoreInput = "input"
+)
+
+// NewBackupCmd returns command downloading consistent snapshot of data of running node.
+func NewBackupCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "backup",
+		Short: "Download backup of all data of running node (requires access to unsafe RPC endpoints)",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			addr, err := cmd.Flags().GetString(flagBackupNode)
+			if err != nil {
+				return err
+			}
+			output, err := cmd.Flags().GetString(flagBackupOutput)
+			if err != nil {
+				return err
+			}
+			if output == "" {
+				return errors.New("output file is not set")
+			}
+			ctx := cmd.Context()
+			if ctx == nil {
+				ctx = context.Background()
+			}
+			n, err := downloadBackup(ctx, addr, output)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "backup written to %s (%d bytes)\n", output, n)
+			return nil
+		},
+	}
+	cmd.Flags().String(flagBackupNode, "tcp://127.0.0.1:26657", "RPC address of the node")
+	cmd.Flags().String(flagBackupOutput, "", "backup file")
+	return cmd
+}
+
+// downloadBackup writes backup streamed by node to temporary file, and renames it to output when backup is complete.
+func downloadBackup(ctx context.Context, addr, output string) (int64, error) {
+	url := strings.Replace(addr, "tcp://", "http://", 1) + "/unsafe_backup"
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return 0, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("failed to request backup: %w", err)
+	}
+	defer resp.Body.Close() //nolint:errcheck
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return 0, fmt.Errorf("failed to request backup: %s: %s", resp.Status, strings.TrimSpace(string(msg)))
+	}
+
+	tmp := output + ".tmp"
+	f, err := os.OpenFile(tmp, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600) //nolint:gosec
+	if err != nil {
+		return 0, err
+	}
+	n, err := io.Copy(f, resp.Body)
+	if err == nil {
+		err = f.Sync()
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(tmp)
+		return n, fmt.Errorf("failed to download backup: %w", err)
+	}
+	return n, os.Rename(tmp, output)
+}
+
+// NewRestoreCmd returns command restoring node data from backup created with backup command.
+func NewRestoreCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "restore",
+		Short: "Restore node data from backup (node has to be stopped and its data has to be reset)",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			nodeConf, err := ParseConfig(cmd)
+			if err != nil {
+				return err
+			}
+			if nodeConf.DBPath, err = cmd.Flags().GetString(flagDBPath); err != nil {
+				return err
+			}
+			input, err := cmd.Flags().GetString(flagRestoreInput)
+			if err != nil {
+				return err
+			}
+			if nodeConf.RootDir == "" {
+				return errors.New("home directory is not set")
+			}
+			f, err := os.Open(input) //nolint:gosec
+			if err != nil {
+				return err
+			}
+			defer f.Close() //nolint:errcheck
+
+			ctx := cmd.Context()
+			if ctx == nil {
+				ctx = context.Background()
+			}
+			logger, err := newLogger(cmd.OutOrStdout(), nodeConf)
+			if err != nil {
+				return err
+			}
+			return node.Restore(ctx, nodeConf, f, logger)
+		},
+	}
+	cmd.Flags().String(flagRestoreInput, "", "backup file")
+	cmd.Flags().String(flagDBPath, "data", "database directory (relative to home directory)")
+	_ = cmd.MarkFlagRequired(flagRestoreInput)
+	return cmd
+}
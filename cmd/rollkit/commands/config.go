@@ -0,0 +1,52 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/rollkit/rollkit/config"
+)
+
+// NewInitCmd returns command writing Rollkit configuration file with default values.
+func NewInitCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "init",
+		Short: "Write Rollkit configuration file with default values (if it doesn't exist)",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			home, err := cmd.Flags().GetString(flagHome)
+			if err != nil {
+				return err
+			}
+			if err := config.EnsureConfigFile(home); err != nil {
+				return err
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), "configuration file:", config.ConfigFilePath(home))
+			return nil
+		},
+	}
+}
+
+// NewShowConfigCmd returns command printing effective Rollkit configuration.
+//
+// Printed configuration is a valid Rollkit configuration file.
+func NewShowConfigCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "show-config",
+		Short: "Print effective Rollkit configuration (defaults, config file, environment and flags merged)",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			nodeConf, err := ParseConfig(cmd)
+			if err != nil {
+				return err
+			}
+			data, err := config.RenderConfig(nodeConf)
+			if err != nil {
+				return err
+			}
+			_, err = cmd.OutOrStdout().Write(data)
+			return err
+		},
+	}
+	config.AddFlags(cmd)
+	return cmd
+}
@@ -0,0 +1,172 @@
+package commands
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/cometbft/cometbft/abci/example/kvstore"
+	cmlog "github.com/cometbft/cometbft/libs/log"
+	"github.com/cometbft/cometbft/proxy"
+	cmtypes "github.com/cometbft/cometbft/types"
+	"github.com/libp2p/go-libp2p/core/crypto"
+	"github.com/spf13/cobra"
+
+	"github.com/rollkit/rollkit/config"
+	"github.com/rollkit/rollkit/node"
+	"github.com/rollkit/rollkit/test/loadgen"
+	"github.com/rollkit/rollkit/types"
+)
+
+const (
+	flagLoadNode         = "node"
+	flagLoadMode         = "mode"
+	flagLoadRate         = "rate"
+	flagLoadDuration     = "duration"
+	flagLoadMinTxSize    = "min-tx-size"
+	flagLoadMaxTxSize    = "max-tx-size"
+	flagLoadDrainTimeout = "drain-timeout"
+	flagLoadSeed         = "seed"
+	flagLoadOutput       = "output"
+	flagLoadBlockTime    = "block-time"
+)
+
+// NewLoadGenCmd returns command generating transaction load and reporting throughput and latency as JSON.
+//
+// Load is sent to node listening on RPC address given with --node, or to in-process aggregator with kvstore
+// application and mock DA layer, if address is not given.
+func NewLoadGenCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "loadgen",
+		Short: "Send transactions at configured rate and report throughput and latency as JSON",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			flags := cmd.Flags()
+			var (
+				conf loadgen.Config
+				err  error
+			)
+			if conf.Rate, err = flags.GetFloat64(flagLoadRate); err != nil {
+				return err
+			}
+			if conf.Duration, err = flags.GetDuration(flagLoadDuration); err != nil {
+				return err
+			}
+			if conf.MinTxSize, err = flags.GetInt(flagLoadMinTxSize); err != nil {
+				return err
+			}
+			if conf.MaxTxSize, err = flags.GetInt(flagLoadMaxTxSize); err != nil {
+				return err
+			}
+			if conf.DrainTimeout, err = flags.GetDuration(flagLoadDrainTimeout); err != nil {
+				return err
+			}
+			if conf.Seed, err = flags.GetInt64(flagLoadSeed); err != nil {
+				return err
+			}
+			if err := conf.Validate(); err != nil {
+				return err
+			}
+			addr, err := flags.GetString(flagLoadNode)
+			if err != nil {
+				return err
+			}
+			mode, err := flags.GetString(flagLoadMode)
+			if err != nil {
+				return err
+			}
+			output, err := flags.GetString(flagLoadOutput)
+			if err != nil {
+				return err
+			}
+
+			ctx := cmd.Context()
+			if ctx == nil {
+				ctx = context.Background()
+			}
+			var target loadgen.Target
+			if addr != "" {
+				if target, err = loadgen.NewRemoteTarget(addr, loadgen.BroadcastMode(mode)); err != nil {
+					return err
+				}
+			} else {
+				blockTime, err := flags.GetDuration(flagLoadBlockTime)
+				if err != nil {
+					return err
+				}
+				n, err := startLoadGenNode(ctx, blockTime)
+				if err != nil {
+					return err
+				}
+				defer func() {
+					_ = n.Stop()
+				}()
+				if target, err = loadgen.NewNodeTarget(n, loadgen.BroadcastMode(mode)); err != nil {
+					return err
+				}
+			}
+
+			report, err := loadgen.Run(ctx, target, conf)
+			if err != nil {
+				return err
+			}
+			out := cmd.OutOrStdout()
+			if output != "" {
+				f, err := os.Create(output) //nolint:gosec
+				if err != nil {
+					return err
+				}
+				defer f.Close() //nolint:errcheck
+				out = f
+			}
+			return report.WriteJSON(out)
+		},
+	}
+	flags := cmd.Flags()
+	flags.String(flagLoadNode, "", "RPC address of the node (e.g. tcp://127.0.0.1:26657); in-process aggregator with mock DA is used if empty")
+	flags.String(flagLoadMode, string(loadgen.BroadcastAsync), "transaction submission mode: async, sync or mempool (in-process node only)")
+	flags.Float64(flagLoadRate, 100, "transactions per second")
+	flags.Duration(flagLoadDuration, 30*time.Second, "time of sending transactions")
+	flags.Int(flagLoadMinTxSize, 64, "minimum transaction size in bytes")
+	flags.Int(flagLoadMaxTxSize, 256, "maximum transaction size in bytes")
+	flags.Duration(flagLoadDrainTimeout, 30*time.Second, "maximum time of waiting for inclusion of sent transactions")
+	flags.Int64(flagLoadSeed, 0, "seed of transaction contents and sizes")
+	flags.String(flagLoadOutput, "", "file to write JSON report to (standard output if empty)")
+	flags.Duration(flagLoadBlockTime, time.Second, "block time of in-process aggregator")
+	return cmd
+}
+
+// startLoadGenNode starts in-process aggregator, with kvstore application, in-memory storage and mock DA.
+func startLoadGenNode(ctx context.Context, blockTime time.Duration) (*node.FullNode, error) {
+	p2pKey, _, err := crypto.GenerateEd25519Key(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	validators, signingKey := types.GetGenesisValidatorSetWithSigner()
+	genesis := &cmtypes.GenesisDoc{
+		ChainID:         "loadgen",
+		GenesisTime:     time.Now(),
+		InitialHeight:   1,
+		Validators:      validators,
+		ConsensusParams: cmtypes.DefaultConsensusParams(),
+	}
+	nodeConf := config.NodeConfig{
+		Aggregator: true,
+		DALayer:    "newda",
+		P2P:        config.P2PConfig{ListenAddress: "/ip4/127.0.0.1/tcp/0"},
+		BlockManagerConfig: config.BlockManagerConfig{
+			BlockTime:   blockTime,
+			DABlockTime: blockTime,
+			Namespace:   types.MustNewNamespace(types.NamespaceVersionZero, []byte{1, 2, 3, 4, 5, 6, 7, 8}),
+		},
+	}
+	n, err := node.NewNode(ctx, nodeConf, p2pKey, signingKey, proxy.NewLocalClientCreator(kvstore.NewApplication()), genesis, cmlog.NewNopLogger())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create in-process node: %w", err)
+	}
+	if err := n.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start in-process node: %w", err)
+	}
+	return n.(*node.FullNode), nil
+}
@@ -0,0 +1,142 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/multierr"
+
+	"github.com/rollkit/rollkit/config"
+	"github.com/rollkit/rollkit/store"
+)
+
+const (
+	flagMigrateFrom   = "from"
+	flagMigrateTo     = "to"
+	flagMigrateDBName = "db-name"
+)
+
+// NewMigrateDBCmd returns command copying node data between datastore backends.
+//
+// Data is copied into a new datastore, which replaces the original one. Original datastore is kept as a backup,
+// in directory with ".<backend>.bak" suffix. Node has to be stopped during migration.
+func NewMigrateDBCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "migrate-db",
+		Short: "Copy node data to a datastore with different backend (node has to be stopped)",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			nodeConf, err := ParseConfig(cmd)
+			if err != nil {
+				return err
+			}
+			if nodeConf.RootDir == "" {
+				return errors.New("home directory is not set")
+			}
+			flags := cmd.Flags()
+			if nodeConf.DBPath, err = flags.GetString(flagDBPath); err != nil {
+				return err
+			}
+			dbName, err := flags.GetString(flagMigrateDBName)
+			if err != nil {
+				return err
+			}
+			from, err := flags.GetString(flagMigrateFrom)
+			if err != nil {
+				return err
+			}
+			if from == "" {
+				from = nodeConf.DBBackend
+			}
+			to, err := flags.GetString(flagMigrateTo)
+			if err != nil {
+				return err
+			}
+			srcConf := config.DBConfig{DBBackend: from, Badger: nodeConf.Badger}
+			dstConf := config.DBConfig{DBBackend: to, Badger: nodeConf.Badger}
+			if err := srcConf.Validate(); err != nil {
+				return err
+			}
+			if err := dstConf.Validate(); err != nil {
+				return err
+			}
+			if backendName(from) == backendName(to) {
+				return fmt.Errorf("datastore already uses %s backend", backendName(to))
+			}
+
+			copied, err := migrateDB(cmd, nodeConf, dbName, srcConf, dstConf)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "copied %d entries from %s to %s datastore\n", copied, backendName(from), backendName(to))
+			fmt.Fprintf(cmd.OutOrStdout(), "set db_backend = %q in %s before starting the node\n", backendName(to), config.ConfigFilePath(nodeConf.RootDir))
+			return nil
+		},
+	}
+	flags := cmd.Flags()
+	flags.String(flagMigrateFrom, "", "backend of existing datastore (db_backend from configuration if empty)")
+	flags.String(flagMigrateTo, "", "backend of new datastore (badger or leveldb)")
+	flags.String(flagDBPath, "data", "database directory (relative to home directory)")
+	flags.String(flagMigrateDBName, "rollkit", "name of the datastore (rollkit for full node, rollkit-light for light node)")
+	_ = cmd.MarkFlagRequired(flagMigrateTo)
+	return cmd
+}
+
+// migrateDB copies data to a temporary datastore, and swaps it with the original one.
+func migrateDB(cmd *cobra.Command, nodeConf config.NodeConfig, dbName string, srcConf, dstConf config.DBConfig) (int, error) {
+	dbDir := nodeConf.DBPath
+	if !filepath.IsAbs(dbDir) {
+		dbDir = filepath.Join(nodeConf.RootDir, dbDir)
+	}
+	srcPath := filepath.Join(dbDir, dbName)
+	tmpName := dbName + ".migrating"
+	tmpPath := filepath.Join(dbDir, tmpName)
+	backupPath := srcPath + "." + backendName(srcConf.DBBackend) + ".bak"
+
+	if _, err := os.Stat(srcPath); err != nil {
+		return 0, fmt.Errorf("failed to find datastore: %w", err)
+	}
+	for _, p := range []string{tmpPath, backupPath} {
+		if _, err := os.Stat(p); err == nil {
+			return 0, fmt.Errorf("%s already exists, remove it before migration", p)
+		}
+	}
+
+	src, err := store.NewKVStore(nodeConf.RootDir, nodeConf.DBPath, dbName, srcConf)
+	if err != nil {
+		return 0, fmt.Errorf("failed to open source datastore: %w", err)
+	}
+	dst, err := store.NewKVStore(nodeConf.RootDir, nodeConf.DBPath, tmpName, dstConf)
+	if err != nil {
+		_ = src.Close()
+		return 0, fmt.Errorf("failed to create destination datastore: %w", err)
+	}
+
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	copied, err := store.Migrate(ctx, src, dst)
+	err = multierr.Combine(err, src.Close(), dst.Close())
+	if err != nil {
+		return copied, err
+	}
+
+	if err := os.Rename(srcPath, backupPath); err != nil {
+		return copied, fmt.Errorf("failed to back up source datastore: %w", err)
+	}
+	if err := os.Rename(tmpPath, srcPath); err != nil {
+		return copied, fmt.Errorf("failed to replace source datastore: %w", err)
+	}
+	return copied, nil
+}
+
+func backendName(backend string) string {
+	if backend == "" {
+		return config.DBBackendBadger
+	}
+	return backend
+}
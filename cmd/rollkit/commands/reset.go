@@ -0,0 +1,54 @@
+package commands
+
+import (
+	"errors"
+
+	"github.com/spf13/cobra"
+
+	"github.com/rollkit/rollkit/node"
+)
+
+const (
+	flagDBPath       = "db-path"
+	flagKeepAddrBook = "keep-addr-book"
+	flagIndexerOnly  = "indexer-only"
+)
+
+// NewUnsafeResetAllCmd returns command removing all node data, keeping keys and configuration.
+func NewUnsafeResetAllCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "unsafe-reset-all",
+		Short: "Remove all node data (blocks, state, DA and sync data, indexes); keys and configuration are preserved",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			nodeConf, err := ParseConfig(cmd)
+			if err != nil {
+				return err
+			}
+			if nodeConf.DBPath, err = cmd.Flags().GetString(flagDBPath); err != nil {
+				return err
+			}
+			keepAddrBook, err := cmd.Flags().GetBool(flagKeepAddrBook)
+			if err != nil {
+				return err
+			}
+			indexerOnly, err := cmd.Flags().GetBool(flagIndexerOnly)
+			if err != nil {
+				return err
+			}
+			if nodeConf.RootDir == "" {
+				return errors.New("home directory is not set")
+			}
+
+			logger, err := newLogger(cmd.OutOrStdout(), nodeConf)
+			if err != nil {
+				return err
+			}
+			return node.Reset(nodeConf, node.ResetOptions{KeepAddrBook: keepAddrBook, IndexerOnly: indexerOnly}, logger)
+		},
+	}
+	cmd.Flags().String(flagDBPath, "data", "database directory (relative to home directory)")
+	cmd.Flags().Bool(flagKeepAddrBook, false, "keep P2P peer data (blocked peers, addresses and subnets)")
+	cmd.Flags().Bool(flagIndexerOnly, false, "remove only transaction and block indexes")
+	cmd.MarkFlagsMutuallyExclusive(flagKeepAddrBook, flagIndexerOnly)
+	return cmd
+}
@@ -0,0 +1,72 @@
+package commands
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+
+	cmlog "github.com/cometbft/cometbft/libs/log"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/rollkit/rollkit/config"
+	rollkitlog "github.com/rollkit/rollkit/third_party/log"
+)
+
+const flagHome = "home"
+
+// defaultHome is the default root directory of Rollkit node.
+var defaultHome = filepath.Join(os.Getenv("HOME"), ".rollkit")
+
+// NewRootCmd returns the root command of Rollkit CLI with all sub-commands attached.
+func NewRootCmd() *cobra.Command {
+	rootCmd := &cobra.Command{
+		Use:   "rollkit",
+		Short: "Rollkit node utilities",
+	}
+	rootCmd.PersistentFlags().String(flagHome, defaultHome, "directory for config and data")
+	rootCmd.AddCommand(
+		NewInitCmd(),
+		NewStartCmd(),
+		NewShowConfigCmd(),
+		NewUnsafeResetAllCmd(),
+		NewMigrateDBCmd(),
+		NewBackupCmd(),
+		NewRestoreCmd(),
+		NewStoreCmd(),
+		NewLoadGenCmd(),
+	)
+	return rootCmd
+}
+
+// ParseConfig builds effective Rollkit configuration of the command.
+//
+// Defaults are merged with configuration file from home directory, ROLLKIT_* environment variables and command line flags.
+func ParseConfig(cmd *cobra.Command) (config.NodeConfig, error) {
+	nodeConf := config.DefaultNodeConfig
+	home, err := cmd.Flags().GetString(flagHome)
+	if err != nil {
+		return nodeConf, err
+	}
+	v := viper.New()
+	if err := v.BindPFlags(cmd.Flags()); err != nil {
+		return nodeConf, err
+	}
+	if err := config.ReadConfig(v, home); err != nil {
+		return nodeConf, err
+	}
+	if err := nodeConf.GetViperConfig(v); err != nil {
+		return nodeConf, err
+	}
+	nodeConf.RootDir = home
+	return nodeConf, nil
+}
+
+// newLogger returns logger writing to w, using log format and log levels from nodeConf.
+func newLogger(w io.Writer, nodeConf config.NodeConfig) (cmlog.Logger, error) {
+	logger, err := rollkitlog.NewLogger(w, nodeConf.LogFormat)
+	if err != nil {
+		return nil, err
+	}
+	return rollkitlog.NewLevelFilter(logger, nodeConf.LogLevel)
+}
@@ -0,0 +1,171 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	cmcfg "github.com/cometbft/cometbft/config"
+	cmjson "github.com/cometbft/cometbft/libs/json"
+	"github.com/cometbft/cometbft/p2p"
+	"github.com/cometbft/cometbft/privval"
+	"github.com/cometbft/cometbft/proxy"
+	"github.com/libp2p/go-libp2p/core/crypto"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/rollkit/rollkit/config"
+	"github.com/rollkit/rollkit/node"
+	"github.com/rollkit/rollkit/rpc"
+	rollkitlog "github.com/rollkit/rollkit/third_party/log"
+	"github.com/rollkit/rollkit/types"
+)
+
+const (
+	flagProxyApp   = "proxy_app"
+	flagABCI       = "abci"
+	flagP2PLaddr   = "p2p.laddr"
+	flagP2PSeeds   = "p2p.seeds"
+	flagRPCLaddr   = "rpc.laddr"
+	flagRPCUnsafe  = "rpc.unsafe"
+	defaultRPCAddr = "tcp://127.0.0.1:26657"
+)
+
+// NewStartCmd returns command running a node, connected to ABCI application.
+//
+// Home directory uses CometBFT layout: genesis is read from config/genesis.json, P2P key from config/node_key.json
+// and signing key of aggregator from config/priv_validator_key.json. Configuration is re-read from configuration
+// file, environment and flags every time the process receives SIGHUP (see node.ReloadOnSignal).
+func NewStartCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "start",
+		Short: "Run the node",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			home, err := cmd.Flags().GetString(flagHome)
+			if err != nil {
+				return err
+			}
+			cmConf, err := getCometConfig(cmd, home)
+			if err != nil {
+				return err
+			}
+			base := config.DefaultNodeConfig
+			config.GetNodeConfig(&base, cmConf)
+			v := viper.New()
+			if err := v.BindPFlags(cmd.Flags()); err != nil {
+				return err
+			}
+			loader := config.NewConfigLoader(v, base)
+			nodeConf, err := loader()
+			if err != nil {
+				return err
+			}
+
+			// full node filters log messages by itself, so levels can be changed on configuration reload
+			logger, err := rollkitlog.NewLogger(cmd.OutOrStdout(), nodeConf.LogFormat)
+			if err != nil {
+				return err
+			}
+			if nodeConf.Light {
+				if logger, err = newLogger(cmd.OutOrStdout(), nodeConf); err != nil {
+					return err
+				}
+			}
+
+			nodeKey, err := p2p.LoadNodeKey(cmConf.NodeKeyFile())
+			if err != nil {
+				return fmt.Errorf("failed to load node key: %w", err)
+			}
+			p2pKey, err := types.GetNodeKey(nodeKey)
+			if err != nil {
+				return err
+			}
+			var signingKey crypto.PrivKey
+			if !nodeConf.Light {
+				if signingKey, err = loadSigningKey(cmConf.PrivValidatorKeyFile()); err != nil {
+					return err
+				}
+			}
+
+			sigCtx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+
+			clientCreator := proxy.DefaultClientCreator(cmConf.ProxyApp, cmConf.ABCI, cmConf.DBDir())
+			n, err := node.NewNodeFromGenesisFile(context.Background(), nodeConf, p2pKey, signingKey, clientCreator, cmConf.GenesisFile(), logger)
+			if err != nil {
+				return fmt.Errorf("failed to create node: %w", err)
+			}
+			if fullNode, ok := n.(*node.FullNode); ok {
+				fullNode.SetConfigLoader(loader)
+				go node.ReloadOnSignal(sigCtx, fullNode)
+			}
+			if err := n.Start(); err != nil {
+				return fmt.Errorf("failed to start node: %w", err)
+			}
+			server := rpc.NewServer(n, cmConf.RPC, logger)
+			if err := server.Start(); err != nil {
+				_ = n.Stop()
+				return fmt.Errorf("failed to start RPC server: %w", err)
+			}
+
+			<-sigCtx.Done()
+			logger.Info("shutting down")
+			if err := server.Stop(); err != nil {
+				logger.Error("failed to stop RPC server", "error", err)
+			}
+			return n.Stop()
+		},
+	}
+	flags := cmd.Flags()
+	flags.String(flagProxyApp, "tcp://127.0.0.1:26658", "address of ABCI application")
+	flags.String(flagABCI, "socket", "transport of ABCI application connection (socket or grpc)")
+	flags.String(flagP2PLaddr, config.DefaultListenAddress, "P2P listen address (multiaddr)")
+	flags.String(flagP2PSeeds, "", "comma separated list of seed nodes (multiaddrs)")
+	flags.String(flagRPCLaddr, defaultRPCAddr, "RPC listen address")
+	flags.Bool(flagRPCUnsafe, false, "enable unsafe RPC methods (e.g. unsafe_reload_config)")
+	config.AddFlags(cmd)
+	return cmd
+}
+
+// getCometConfig returns CometBFT configuration with home directory, application connection, P2P and RPC
+// options set from command line flags.
+func getCometConfig(cmd *cobra.Command, home string) (*cmcfg.Config, error) {
+	flags := cmd.Flags()
+	cmConf := cmcfg.DefaultConfig()
+	cmConf.SetRoot(home)
+	var err error
+	if cmConf.ProxyApp, err = flags.GetString(flagProxyApp); err != nil {
+		return nil, err
+	}
+	if cmConf.ABCI, err = flags.GetString(flagABCI); err != nil {
+		return nil, err
+	}
+	if cmConf.P2P.ListenAddress, err = flags.GetString(flagP2PLaddr); err != nil {
+		return nil, err
+	}
+	if cmConf.P2P.Seeds, err = flags.GetString(flagP2PSeeds); err != nil {
+		return nil, err
+	}
+	if cmConf.RPC.ListenAddress, err = flags.GetString(flagRPCLaddr); err != nil {
+		return nil, err
+	}
+	if cmConf.RPC.Unsafe, err = flags.GetBool(flagRPCUnsafe); err != nil {
+		return nil, err
+	}
+	return cmConf, nil
+}
+
+// loadSigningKey reads CometBFT validator key file, and returns the key as libp2p private key.
+func loadSigningKey(path string) (crypto.PrivKey, error) {
+	data, err := os.ReadFile(path) //nolint:gosec
+	if err != nil {
+		return nil, fmt.Errorf("failed to read validator key: %w", err)
+	}
+	var pvKey privval.FilePVKey
+	if err := cmjson.Unmarshal(data, &pvKey); err != nil {
+		return nil, fmt.Errorf("failed to parse validator key %s: %w", path, err)
+	}
+	return types.GetNodeKey(&p2p.NodeKey{PrivKey: pvKey.PrivKey})
+}
@@ -0,0 +1,76 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/spf13/cobra"
+
+	"github.com/rollkit/rollkit/node"
+	"github.com/rollkit/rollkit/store"
+)
+
+const flagRepair = "repair"
+
+// errStoreInconsistent is returned by store verify command, when problems were found (and not repaired).
+var errStoreInconsistent = errors.New("store is inconsistent")
+
+// NewStoreCmd returns command grouping operations on the node store.
+func NewStoreCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "store",
+		Short: "Node store utilities",
+	}
+	cmd.AddCommand(NewStoreVerifyCmd())
+	return cmd
+}
+
+// NewStoreVerifyCmd returns command checking consistency of the store of stopped full node and printing
+// found problems as JSON.
+func NewStoreVerifyCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "verify",
+		Short: "Check consistency of blocks, commits, indexes, state and sync stores (node has to be stopped)",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			nodeConf, err := ParseConfig(cmd)
+			if err != nil {
+				return err
+			}
+			if nodeConf.DBPath, err = cmd.Flags().GetString(flagDBPath); err != nil {
+				return err
+			}
+			repair, err := cmd.Flags().GetBool(flagRepair)
+			if err != nil {
+				return err
+			}
+			if nodeConf.RootDir == "" {
+				return errors.New("home directory is not set")
+			}
+			ctx := cmd.Context()
+			if ctx == nil {
+				ctx = context.Background()
+			}
+			logger, err := newLogger(cmd.ErrOrStderr(), nodeConf)
+			if err != nil {
+				return err
+			}
+			report, err := node.VerifyStore(ctx, nodeConf, store.VerifyOptions{Repair: repair}, logger)
+			if err != nil {
+				return err
+			}
+			enc := json.NewEncoder(cmd.OutOrStdout())
+			enc.SetIndent("", "  ")
+			if err := enc.Encode(report); err != nil {
+				return err
+			}
+			if !report.OK() {
+				return errStoreInconsistent
+			}
+			return nil
+		},
+	}
+	cmd.Flags().String(flagDBPath, "data", "database directory (relative to home directory)")
+	cmd.Flags().Bool(flagRepair, false, "repair index-only problems (missing or invalid height index entries)")
+	return cmd
+}
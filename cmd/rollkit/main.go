@@ -0,0 +1,13 @@
+package main
+
+import (
+	"os"
+
+	"github.com/rollkit/rollkit/cmd/rollkit/commands"
+)
+
+func main() {
+	if err := commands.NewRootCmd().Execute(); err != nil {
+		os.Exit(1)
+	}
+}
@@ -1,7 +1,8 @@
 package config
 
 import (
-	"encoding/hex"
+	"errors"
+	"fmt"
 	"time"
 
 	cmcfg "github.com/cometbft/cometbft/config"
@@ -13,16 +14,39 @@ import (
 )
 
 const (
-	flagAggregator     = "rollkit.aggregator"
-	flagDALayer        = "rollkit.da_layer"
-	flagDAConfig       = "rollkit.da_config"
-	flagBlockTime      = "rollkit.block_time"
-	flagDABlockTime    = "rollkit.da_block_time"
-	flagDAStartHeight  = "rollkit.da_start_height"
-	flagNamespaceID    = "rollkit.namespace_id"
-	flagLight          = "rollkit.light"
-	flagTrustedHash    = "rollkit.trusted_hash"
-	flagLazyAggregator = "rollkit.lazy_aggregator"
+	flagAggregator      = "rollkit.aggregator"
+	flagDALayer         = "rollkit.da_layer"
+	flagDAConfig        = "rollkit.da_config"
+	flagBlockTime       = "rollkit.block_time"
+	flagDABlockTime     = "rollkit.da_block_time"
+	flagDAStartHeight   = "rollkit.da_start_height"
+	flagNamespaceID     = "rollkit.namespace_id"
+	flagLight           = "rollkit.light"
+	flagTrustedHash     = "rollkit.trusted_hash"
+	flagLazyAggregator  = "rollkit.lazy_aggregator"
+	flagLazyBlockTime   = "rollkit.lazy_block_time"
+	flagAdaptiveBlock   = "rollkit.adaptive_block_time"
+	flagMinBlockTime    = "rollkit.min_block_time"
+	flagMaxBlockTime    = "rollkit.max_block_time"
+	flagSyncMode        = "rollkit.sync_mode"
+	flagLogLevel        = "rollkit.log_level"
+	flagLogFormat       = "rollkit.log_format"
+	flagLogSample       = "rollkit.log_sample_interval"
+	flagBlockedPeers    = "rollkit.blocked_peers"
+	flagAllowedPeers    = "rollkit.allowed_peers"
+	flagRPCRateLimit    = "rollkit.rpc_rate_limit"
+	flagRPCRateBurst    = "rollkit.rpc_rate_burst"
+	flagShutdownTimeout = "rollkit.shutdown_timeout"
+
+	flagDBBackend            = "rollkit.db_backend"
+	flagBadgerValueLogSize   = "rollkit.badger_value_log_file_size"
+	flagBadgerBlockCacheSize = "rollkit.badger_block_cache_size"
+	flagBadgerIndexCacheSize = "rollkit.badger_index_cache_size"
+	flagBadgerCompression    = "rollkit.badger_compression"
+	flagBadgerSyncWrites     = "rollkit.badger_sync_writes"
+	flagBadgerGCInterval     = "rollkit.badger_gc_interval"
+	flagBadgerGCDiscardRatio = "rollkit.badger_gc_discard_ratio"
+	flagStoreCacheSize       = "rollkit.store_cache_size"
 )
 
 // NodeConfig stores Rollkit node configuration.
@@ -39,7 +63,15 @@ type NodeConfig struct {
 	DAConfig           string `mapstructure:"da_config"`
 	Light              bool   `mapstructure:"light"`
 	HeaderConfig       `mapstructure:",squash"`
-	LazyAggregator     bool `mapstructure:"lazy_aggregator"`
+	LazyAggregator     bool   `mapstructure:"lazy_aggregator"`
+	LogLevel           string `mapstructure:"log_level"`
+	// LogFormat is the output format (plain or json) of loggers created with log.NewLogger.
+	LogFormat string `mapstructure:"log_format"`
+	// LogSampleInterval limits repetitive debug and info messages to one per interval (0 disables sampling).
+	LogSampleInterval time.Duration `mapstructure:"log_sample_interval"`
+	// ShutdownTimeout limits time spent on submitting pending blocks to DA layer when node is stopped
+	ShutdownTimeout time.Duration `mapstructure:"shutdown_timeout"`
+	DBConfig        `mapstructure:",squash"`
 }
 
 // HeaderConfig allows node to pass the initial trusted header hash to start the header exchange service
@@ -53,9 +85,52 @@ type BlockManagerConfig struct {
 	BlockTime time.Duration `mapstructure:"block_time"`
 	// DABlockTime informs about block time of underlying data availability layer
 	DABlockTime time.Duration `mapstructure:"da_block_time"`
-	// DAStartHeight allows skipping first DAStartHeight-1 blocks when querying for blocks.
-	DAStartHeight uint64            `mapstructure:"da_start_height"`
-	NamespaceID   types.NamespaceID `mapstructure:"namespace_id"`
+	// DAStartHeight allows skipping first DAStartHeight-1 blocks when querying for blocks. If node is started
+	// from genesis file with Rollkit genesis extension, it defaults to DA height of the first block of the chain.
+	DAStartHeight uint64 `mapstructure:"da_start_height"`
+	// Namespace identifies data of the chain in DA layer; namespaces of versions above 0 also separate P2P topics
+	// of chains with the same chain ID. Legacy 8 byte hex namespace IDs are parsed as version 0 namespaces.
+	Namespace types.Namespace `mapstructure:"namespace_id"`
+	// LazyBlockTime defines how long lazy aggregator collects transactions before producing a block
+	LazyBlockTime time.Duration `mapstructure:"lazy_block_time"`
+	// AdaptiveBlockTime enables adaptive block time: block is produced early (but not sooner than MinBlockTime
+	// after the previous block) when mempool holds a full block of transactions, and block time grows up
+	// to MaxBlockTime when traffic is low.
+	AdaptiveBlockTime bool          `mapstructure:"adaptive_block_time"`
+	MinBlockTime      time.Duration `mapstructure:"min_block_time"`
+	MaxBlockTime      time.Duration `mapstructure:"max_block_time"`
+	// SyncMode defines which blocks are executed by syncing (non-aggregator) node: soft, da-only or hybrid.
+	// Empty value means soft.
+	SyncMode string `mapstructure:"sync_mode"`
+}
+
+// Sync modes of the block manager.
+const (
+	// SyncModeSoft executes blocks as soon as they are received, either from P2P network or DA layer.
+	SyncModeSoft = "soft"
+	// SyncModeDAOnly executes only blocks retrieved from DA layer, so node state is always DA-final.
+	SyncModeDAOnly = "da-only"
+	// SyncModeHybrid executes blocks as soon as they are received (like soft), but tracks DA-final height
+	// separately and labels events with finality level.
+	SyncModeHybrid = "hybrid"
+)
+
+// ValidateSyncMode returns an error if mode is not a known sync mode.
+func ValidateSyncMode(mode string) error {
+	switch mode {
+	case "", SyncModeSoft, SyncModeDAOnly, SyncModeHybrid:
+		return nil
+	default:
+		return fmt.Errorf("unknown sync mode: %q", mode)
+	}
+}
+
+// ValidateNamespace checks that DA namespace of the chain is set.
+func ValidateNamespace(namespace types.Namespace) error {
+	if namespace.IsZero() {
+		return errors.New("namespace is not set")
+	}
+	return nil
 }
 
 // GetNodeConfig translates Tendermint's configuration into Rollkit configuration.
@@ -93,13 +168,37 @@ func (nc *NodeConfig) GetViperConfig(v *viper.Viper) error {
 	nc.DABlockTime = v.GetDuration(flagDABlockTime)
 	nc.BlockTime = v.GetDuration(flagBlockTime)
 	nc.LazyAggregator = v.GetBool(flagLazyAggregator)
-	nsID := v.GetString(flagNamespaceID)
+	nc.LazyBlockTime = v.GetDuration(flagLazyBlockTime)
+	nc.AdaptiveBlockTime = v.GetBool(flagAdaptiveBlock)
+	nc.MinBlockTime = v.GetDuration(flagMinBlockTime)
+	nc.MaxBlockTime = v.GetDuration(flagMaxBlockTime)
+	nc.SyncMode = v.GetString(flagSyncMode)
+	nc.LogLevel = v.GetString(flagLogLevel)
+	nc.LogFormat = v.GetString(flagLogFormat)
+	nc.LogSampleInterval = v.GetDuration(flagLogSample)
+	nc.P2P.BlockedPeers = v.GetString(flagBlockedPeers)
+	nc.P2P.AllowedPeers = v.GetString(flagAllowedPeers)
+	nc.RPC.RateLimit = v.GetFloat64(flagRPCRateLimit)
+	nc.RPC.RateBurst = v.GetInt(flagRPCRateBurst)
+	nc.ShutdownTimeout = v.GetDuration(flagShutdownTimeout)
+	nc.DBBackend = v.GetString(flagDBBackend)
+	nc.Badger.ValueLogFileSize = v.GetInt64(flagBadgerValueLogSize)
+	nc.Badger.BlockCacheSize = v.GetInt64(flagBadgerBlockCacheSize)
+	nc.Badger.IndexCacheSize = v.GetInt64(flagBadgerIndexCacheSize)
+	nc.Badger.Compression = v.GetString(flagBadgerCompression)
+	nc.Badger.SyncWrites = v.GetBool(flagBadgerSyncWrites)
+	nc.Badger.GCInterval = v.GetDuration(flagBadgerGCInterval)
+	nc.Badger.GCDiscardRatio = v.GetFloat64(flagBadgerGCDiscardRatio)
+	nc.StoreCacheSize = v.GetInt(flagStoreCacheSize)
 	nc.Light = v.GetBool(flagLight)
-	bytes, err := hex.DecodeString(nsID)
+	namespace, err := types.ParseNamespace(v.GetString(flagNamespaceID))
 	if err != nil {
 		return err
 	}
-	copy(nc.NamespaceID[:], bytes)
+	if err := ValidateNamespace(namespace); err != nil {
+		return err
+	}
+	nc.Namespace = namespace
 	nc.TrustedHash = v.GetString(flagTrustedHash)
 	return nil
 }
@@ -111,12 +210,34 @@ func AddFlags(cmd *cobra.Command) {
 	def := DefaultNodeConfig
 	cmd.Flags().Bool(flagAggregator, def.Aggregator, "run node in aggregator mode")
 	cmd.Flags().Bool(flagLazyAggregator, def.LazyAggregator, "wait for transactions, don't build empty blocks")
+	cmd.Flags().Duration(flagLazyBlockTime, def.LazyBlockTime, "time spent collecting transactions before producing a block (for lazy aggregator mode)")
+	cmd.Flags().Bool(flagAdaptiveBlock, def.AdaptiveBlockTime, "adapt block time to load, between min and max block time (for aggregator mode)")
+	cmd.Flags().Duration(flagMinBlockTime, def.MinBlockTime, "minimum block time (for adaptive block time)")
+	cmd.Flags().Duration(flagMaxBlockTime, def.MaxBlockTime, "maximum block time (for adaptive block time)")
+	cmd.Flags().String(flagLogLevel, def.LogLevel, "log level of Rollkit node (debug, info, warn, error or none), optionally per module (e.g. p2p:error,*:info)")
+	cmd.Flags().String(flagLogFormat, def.LogFormat, "log output format (plain or json)")
+	cmd.Flags().Duration(flagLogSample, def.LogSampleInterval, "log repetitive debug and info messages at most once per interval (0 disables sampling)")
+	cmd.Flags().String(flagBlockedPeers, def.P2P.BlockedPeers, "comma separated list of P2P nodes to ignore")
+	cmd.Flags().String(flagAllowedPeers, def.P2P.AllowedPeers, "comma separated list of P2P nodes to whitelist")
+	cmd.Flags().Float64(flagRPCRateLimit, def.RPC.RateLimit, "maximum number of RPC requests per second (0 means unlimited)")
+	cmd.Flags().Int(flagRPCRateBurst, def.RPC.RateBurst, "maximum burst of RPC requests above rate limit")
+	cmd.Flags().Duration(flagShutdownTimeout, def.ShutdownTimeout, "maximum time spent on submitting pending blocks to DA layer during shutdown")
 	cmd.Flags().String(flagDALayer, def.DALayer, "Data Availability Layer Client name (mock or grpc")
 	cmd.Flags().String(flagDAConfig, def.DAConfig, "Data Availability Layer Client config")
 	cmd.Flags().Duration(flagBlockTime, def.BlockTime, "block time (for aggregator mode)")
 	cmd.Flags().Duration(flagDABlockTime, def.DABlockTime, "DA chain block time (for syncing)")
-	cmd.Flags().Uint64(flagDAStartHeight, def.DAStartHeight, "starting DA block height (for syncing)")
-	cmd.Flags().BytesHex(flagNamespaceID, def.NamespaceID[:], "namespace identifies (8 bytes in hex)")
+	cmd.Flags().Uint64(flagDAStartHeight, def.DAStartHeight, "starting DA block height (for syncing), derived from Rollkit genesis extension if not set")
+	cmd.Flags().String(flagSyncMode, def.SyncMode, "blocks executed by syncing node: soft (as soon as received), da-only (only blocks retrieved from DA) or hybrid (soft, tracking DA-final height)")
+	cmd.Flags().String(flagNamespaceID, def.Namespace.String(), "namespace of the chain in DA layer (<version>:<hex identifier>, or legacy 8 bytes in hex)")
 	cmd.Flags().Bool(flagLight, def.Light, "run light client")
 	cmd.Flags().String(flagTrustedHash, def.TrustedHash, "initial trusted hash to start the header exchange service")
+	cmd.Flags().String(flagDBBackend, def.DBBackend, "datastore backend (badger or leveldb)")
+	cmd.Flags().Int64(flagBadgerValueLogSize, def.Badger.ValueLogFileSize, "maximum size of badger value log file in bytes (0 means badger default)")
+	cmd.Flags().Int64(flagBadgerBlockCacheSize, def.Badger.BlockCacheSize, "size of badger block cache in bytes (0 means badger default)")
+	cmd.Flags().Int64(flagBadgerIndexCacheSize, def.Badger.IndexCacheSize, "size of badger index cache in bytes (0 keeps all indexes in memory)")
+	cmd.Flags().String(flagBadgerCompression, def.Badger.Compression, "badger compression algorithm (none, snappy or zstd)")
+	cmd.Flags().Bool(flagBadgerSyncWrites, def.Badger.SyncWrites, "sync every badger write to disk")
+	cmd.Flags().Duration(flagBadgerGCInterval, def.Badger.GCInterval, "interval between badger value log garbage collection cycles")
+	cmd.Flags().Float64(flagBadgerGCDiscardRatio, def.Badger.GCDiscardRatio, "minimum ratio of stale data in badger value log file required to rewrite it")
+	cmd.Flags().Int(flagStoreCacheSize, def.StoreCacheSize, "number of decoded blocks, commits and block responses cached in memory (0 disables cache)")
 }
@@ -51,6 +51,7 @@ func TestViperAndCobra(t *testing.T) {
 	assert.NoError(cmd.Flags().Set(flagDAConfig, `{"json":true}`))
 	assert.NoError(cmd.Flags().Set(flagBlockTime, "1234s"))
 	assert.NoError(cmd.Flags().Set(flagNamespaceID, "0102030405060708"))
+	assert.NoError(cmd.Flags().Set(flagSyncMode, SyncModeDAOnly))
 
 	nc := DefaultNodeConfig
 	assert.NoError(nc.GetViperConfig(v))
@@ -59,5 +60,25 @@ func TestViperAndCobra(t *testing.T) {
 	assert.Equal("foobar", nc.DALayer)
 	assert.Equal(`{"json":true}`, nc.DAConfig)
 	assert.Equal(1234*time.Second, nc.BlockTime)
-	assert.Equal(types.NamespaceID{1, 2, 3, 4, 5, 6, 7, 8}, nc.NamespaceID)
+	assert.Equal(types.MustNewNamespace(types.NamespaceVersionZero, []byte{1, 2, 3, 4, 5, 6, 7, 8}), nc.Namespace)
+	assert.Equal(SyncModeDAOnly, nc.SyncMode)
+}
+
+func TestViperNamespace(t *testing.T) {
+	t.Parallel()
+	assert := assert.New(t)
+
+	cmd := &cobra.Command{}
+	AddFlags(cmd)
+	v := viper.New()
+	assert.NoError(v.BindPFlags(cmd.Flags()))
+
+	// default is legacy namespace ID with all zero bytes
+	nc := DefaultNodeConfig
+	assert.NoError(nc.GetViperConfig(v))
+	assert.Equal(types.MustNewNamespace(types.NamespaceVersionZero, make([]byte, 8)), nc.Namespace)
+
+	// namespace has to be set
+	assert.NoError(cmd.Flags().Set(flagNamespaceID, ""))
+	assert.Error(nc.GetViperConfig(v))
 }
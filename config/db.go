@@ -0,0 +1,76 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	// DBBackendBadger is the default datastore backend, based on BadgerDB v3.
+	DBBackendBadger = "badger"
+	// DBBackendLevelDB is a datastore backend based on goleveldb.
+	DBBackendLevelDB = "leveldb"
+)
+
+// Badger compression algorithms.
+const (
+	BadgerCompressionNone   = "none"
+	BadgerCompressionSnappy = "snappy"
+	BadgerCompressionZSTD   = "zstd"
+)
+
+// DBConfig selects and tunes the datastore used by the node.
+type DBConfig struct {
+	// DBBackend is the name of the datastore backend (badger or leveldb). Empty value means badger.
+	DBBackend string       `mapstructure:"db_backend"`
+	Badger    BadgerConfig `mapstructure:",squash"`
+	// StoreCacheSize is the number of decoded blocks, commits and block responses (each) kept in memory.
+	// Zero disables the cache.
+	StoreCacheSize int `mapstructure:"store_cache_size"`
+}
+
+// BadgerConfig holds BadgerDB tuning options. Zero values mean BadgerDB defaults.
+type BadgerConfig struct {
+	// ValueLogFileSize is the maximum size of single value log file, in bytes.
+	ValueLogFileSize int64 `mapstructure:"badger_value_log_file_size"`
+	// BlockCacheSize is the size of block cache, in bytes.
+	BlockCacheSize int64 `mapstructure:"badger_block_cache_size"`
+	// IndexCacheSize is the size of index cache, in bytes. Zero means that all indexes are kept in memory.
+	IndexCacheSize int64 `mapstructure:"badger_index_cache_size"`
+	// Compression is the compression algorithm of SST files (none, snappy or zstd).
+	Compression string `mapstructure:"badger_compression"`
+	// SyncWrites enables syncing every write to disk.
+	SyncWrites bool `mapstructure:"badger_sync_writes"`
+	// GCInterval is the interval between value log garbage collection cycles.
+	GCInterval time.Duration `mapstructure:"badger_gc_interval"`
+	// GCDiscardRatio is the minimum ratio of stale data in value log file required to rewrite it.
+	GCDiscardRatio float64 `mapstructure:"badger_gc_discard_ratio"`
+}
+
+// Validate checks if datastore configuration is correct.
+func (c DBConfig) Validate() error {
+	switch c.DBBackend {
+	case "", DBBackendBadger, DBBackendLevelDB:
+	default:
+		return fmt.Errorf("unknown datastore backend: %q", c.DBBackend)
+	}
+	switch c.Badger.Compression {
+	case "", BadgerCompressionNone, BadgerCompressionSnappy, BadgerCompressionZSTD:
+	default:
+		return fmt.Errorf("unknown badger compression: %q", c.Badger.Compression)
+	}
+	if c.StoreCacheSize < 0 {
+		return fmt.Errorf("store cache size can't be negative: %d", c.StoreCacheSize)
+	}
+	if c.Badger.ValueLogFileSize < 0 || c.Badger.BlockCacheSize < 0 || c.Badger.IndexCacheSize < 0 {
+		return errors.New("badger file and cache sizes can't be negative")
+	}
+	if c.Badger.GCInterval < 0 {
+		return fmt.Errorf("badger GC interval can't be negative: %v", c.Badger.GCInterval)
+	}
+	if c.Badger.GCDiscardRatio < 0 || c.Badger.GCDiscardRatio >= 1 {
+		return fmt.Errorf("badger GC discard ratio must be in range [0, 1): %v", c.Badger.GCDiscardRatio)
+	}
+	return nil
+}
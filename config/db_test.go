@@ -0,0 +1,40 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDBConfigValidate(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name string
+		conf DBConfig
+		err  bool
+	}{
+		{"default", DefaultNodeConfig.DBConfig, false},
+		{"empty", DBConfig{}, false},
+		{"leveldb", DBConfig{DBBackend: DBBackendLevelDB}, false},
+		{"unknown backend", DBConfig{DBBackend: "rocksdb"}, true},
+		{"unknown compression", DBConfig{Badger: BadgerConfig{Compression: "lz4"}}, true},
+		{"negative cache", DBConfig{Badger: BadgerConfig{BlockCacheSize: -1}}, true},
+		{"negative store cache", DBConfig{StoreCacheSize: -1}, true},
+		{"negative GC interval", DBConfig{Badger: BadgerConfig{GCInterval: -time.Second}}, true},
+		{"discard ratio too high", DBConfig{Badger: BadgerConfig{GCDiscardRatio: 1}}, true},
+	}
+
+	for _, c := range cases {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			err := c.conf.Validate()
+			if c.err {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
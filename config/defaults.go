@@ -20,12 +20,19 @@ var DefaultNodeConfig = NodeConfig{
 		ListenAddress: DefaultListenAddress,
 		Seeds:         "",
 	},
-	Aggregator:     false,
-	LazyAggregator: false,
+	Aggregator:      false,
+	LazyAggregator:  false,
+	LogLevel:        "info",
+	LogFormat:       "plain",
+	ShutdownTimeout: 30 * time.Second,
 	BlockManagerConfig: BlockManagerConfig{
-		BlockTime:   1 * time.Second,
-		DABlockTime: 15 * time.Second,
-		NamespaceID: types.NamespaceID{},
+		BlockTime:     1 * time.Second,
+		DABlockTime:   15 * time.Second,
+		Namespace:     types.MustNewNamespace(types.NamespaceVersionZero, make([]byte, 8)),
+		LazyBlockTime: 1 * time.Second,
+		MinBlockTime:  100 * time.Millisecond,
+		MaxBlockTime:  10 * time.Second,
+		SyncMode:      SyncModeSoft,
 	},
 	DALayer:  "newda",
 	DAConfig: "",
@@ -33,4 +40,13 @@ var DefaultNodeConfig = NodeConfig{
 	HeaderConfig: HeaderConfig{
 		TrustedHash: "",
 	},
+	DBConfig: DBConfig{
+		DBBackend:      DBBackendBadger,
+		StoreCacheSize: 100,
+		Badger: BadgerConfig{
+			Compression:    BadgerCompressionSnappy,
+			GCInterval:     15 * time.Minute,
+			GCDiscardRatio: 0.2,
+		},
+	},
 }
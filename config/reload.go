@@ -0,0 +1,136 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/multiformats/go-multiaddr"
+	"github.com/spf13/viper"
+
+	"github.com/rollkit/rollkit/third_party/log"
+)
+
+// ErrNotReloadable is returned when configuration change requires node restart.
+var ErrNotReloadable = errors.New("option can't be changed without restarting the node")
+
+// ReloadableConfig groups options that can be changed while the node is running.
+type ReloadableConfig struct {
+	LogLevel      string
+	BlockedPeers  string
+	AllowedPeers  string
+	RPCRateLimit  float64
+	RPCRateBurst  int
+	LazyBlockTime time.Duration
+}
+
+// ConfigChange describes a change of single configuration option.
+type ConfigChange struct {
+	Option string
+	Old    interface{}
+	New    interface{}
+}
+
+// Reloadable returns options of nc that can be changed while the node is running.
+func (nc NodeConfig) Reloadable() ReloadableConfig {
+	return ReloadableConfig{
+		LogLevel:      nc.LogLevel,
+		BlockedPeers:  nc.P2P.BlockedPeers,
+		AllowedPeers:  nc.P2P.AllowedPeers,
+		RPCRateLimit:  nc.RPC.RateLimit,
+		RPCRateBurst:  nc.RPC.RateBurst,
+		LazyBlockTime: nc.LazyBlockTime,
+	}
+}
+
+// WithReloadable returns copy of nc with reloadable options replaced by values from rc.
+func (nc NodeConfig) WithReloadable(rc ReloadableConfig) NodeConfig {
+	nc.LogLevel = rc.LogLevel
+	nc.P2P.BlockedPeers = rc.BlockedPeers
+	nc.P2P.AllowedPeers = rc.AllowedPeers
+	nc.RPC.RateLimit = rc.RPCRateLimit
+	nc.RPC.RateBurst = rc.RPCRateBurst
+	nc.LazyBlockTime = rc.LazyBlockTime
+	return nc
+}
+
+// Validate checks if values of reloadable options are correct.
+func (rc ReloadableConfig) Validate() error {
+	if _, err := log.ParseLevels(rc.LogLevel); err != nil {
+		return err
+	}
+	if err := validatePeerList(rc.BlockedPeers); err != nil {
+		return fmt.Errorf("invalid blocked peers: %w", err)
+	}
+	if err := validatePeerList(rc.AllowedPeers); err != nil {
+		return fmt.Errorf("invalid allowed peers: %w", err)
+	}
+	if rc.RPCRateLimit < 0 {
+		return fmt.Errorf("RPC rate limit can't be negative: %v", rc.RPCRateLimit)
+	}
+	if rc.RPCRateBurst < 0 {
+		return fmt.Errorf("RPC rate burst can't be negative: %v", rc.RPCRateBurst)
+	}
+	if rc.LazyBlockTime < 0 {
+		return fmt.Errorf("lazy block time can't be negative: %v", rc.LazyBlockTime)
+	}
+	return nil
+}
+
+// Diff validates newConf and returns list of changed options.
+//
+// ErrNotReloadable is returned if any option that can't be changed at runtime differs.
+func Diff(oldConf, newConf NodeConfig) ([]ConfigChange, error) {
+	newRC := newConf.Reloadable()
+	if err := newRC.Validate(); err != nil {
+		return nil, err
+	}
+	if !reflect.DeepEqual(oldConf.WithReloadable(newRC), newConf) {
+		return nil, ErrNotReloadable
+	}
+
+	oldVal := reflect.ValueOf(oldConf.Reloadable())
+	newVal := reflect.ValueOf(newRC)
+	var changes []ConfigChange
+	for i := 0; i < oldVal.NumField(); i++ {
+		o, n := oldVal.Field(i).Interface(), newVal.Field(i).Interface()
+		if o != n {
+			changes = append(changes, ConfigChange{Option: oldVal.Type().Field(i).Name, Old: o, New: n})
+		}
+	}
+	return changes, nil
+}
+
+// NewConfigLoader returns a function that re-reads Rollkit configuration (configuration file,
+// environment variables and flags bound to v) on top of base configuration.
+//
+// It's intended to be used as a source of configuration for runtime reloads.
+func NewConfigLoader(v *viper.Viper, base NodeConfig) func() (NodeConfig, error) {
+	return func() (NodeConfig, error) {
+		conf := base
+		if err := ReadConfig(v, base.RootDir); err != nil {
+			return conf, err
+		}
+		err := conf.GetViperConfig(v)
+		return conf, err
+	}
+}
+
+func validatePeerList(peers string) error {
+	if peers == "" {
+		return nil
+	}
+	for _, p := range strings.Split(peers, ",") {
+		maddr, err := multiaddr.NewMultiaddr(p)
+		if err != nil {
+			return fmt.Errorf("%s: %w", p, err)
+		}
+		if _, err := peer.AddrInfoFromP2pAddr(maddr); err != nil {
+			return fmt.Errorf("%s: %w", p, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testPeer = "/ip4/127.0.0.1/tcp/7676/p2p/12D3KooWM1NFkZozoatQi3JvFE57eBaX56mNgBA68Lk5MTPxBE4U"
+
+func TestDiff(t *testing.T) {
+	cases := []struct {
+		name    string
+		modify  func(*NodeConfig)
+		changes []string
+		err     error
+	}{
+		{"no changes", func(nc *NodeConfig) {}, nil, nil},
+		{"log level", func(nc *NodeConfig) { nc.LogLevel = "error" }, []string{"LogLevel"}, nil},
+		{"module log levels", func(nc *NodeConfig) { nc.LogLevel = "p2p:error,*:info" }, []string{"LogLevel"}, nil},
+		{"log format", func(nc *NodeConfig) { nc.LogFormat = "json" }, nil, ErrNotReloadable},
+		{"peers and rate limit", func(nc *NodeConfig) {
+			nc.P2P.BlockedPeers = testPeer
+			nc.RPC.RateLimit = 10
+			nc.RPC.RateBurst = 20
+		}, []string{"BlockedPeers", "RPCRateLimit", "RPCRateBurst"}, nil},
+		{"lazy block time", func(nc *NodeConfig) { nc.LazyBlockTime = 5 * time.Second }, []string{"LazyBlockTime"}, nil},
+		{"block time", func(nc *NodeConfig) { nc.BlockTime = 5 * time.Second }, nil, ErrNotReloadable},
+		{"aggregator", func(nc *NodeConfig) { nc.Aggregator = !nc.Aggregator }, nil, ErrNotReloadable},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			newConf := DefaultNodeConfig
+			c.modify(&newConf)
+			changes, err := Diff(DefaultNodeConfig, newConf)
+			if c.err != nil {
+				assert.ErrorIs(t, err, c.err)
+				return
+			}
+			require.NoError(t, err)
+			var options []string
+			for _, ch := range changes {
+				options = append(options, ch.Option)
+			}
+			assert.Equal(t, c.changes, options)
+		})
+	}
+}
+
+func TestReloadableValidate(t *testing.T) {
+	valid := DefaultNodeConfig.Reloadable()
+	assert.NoError(t, valid.Validate())
+
+	cases := []struct {
+		name   string
+		modify func(*ReloadableConfig)
+	}{
+		{"log level", func(rc *ReloadableConfig) { rc.LogLevel = "verbose" }},
+		{"module log level", func(rc *ReloadableConfig) { rc.LogLevel = "p2p:verbose,*:info" }},
+		{"blocked peers", func(rc *ReloadableConfig) { rc.BlockedPeers = "not-a-multiaddr" }},
+		{"allowed peers without ID", func(rc *ReloadableConfig) { rc.AllowedPeers = "/ip4/127.0.0.1/tcp/7676" }},
+		{"rate limit", func(rc *ReloadableConfig) { rc.RPCRateLimit = -1 }},
+		{"rate burst", func(rc *ReloadableConfig) { rc.RPCRateBurst = -1 }},
+		{"lazy block time", func(rc *ReloadableConfig) { rc.LazyBlockTime = -time.Second }},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			rc := valid
+			c.modify(&rc)
+			assert.Error(t, rc.Validate())
+		})
+	}
+}
@@ -35,4 +35,9 @@ type RPCConfig struct {
 	// NOTE: both tls-cert-file and tls-key-file must be present for Tendermint to create HTTPS server.
 	// Otherwise, HTTP server is run.
 	TLSKeyFile string `mapstructure:"tls-key-file"`
+
+	// Maximum number of requests per second served by RPC server. 0 - unlimited.
+	RateLimit float64 `mapstructure:"rpc_rate_limit"`
+	// Maximum number of requests allowed to exceed RateLimit at once.
+	RateBurst int `mapstructure:"rpc_rate_burst"`
 }
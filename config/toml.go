@@ -0,0 +1,215 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/spf13/viper"
+)
+
+const (
+	// DefaultConfigDir is the name of the directory (relative to node root) containing configuration files.
+	DefaultConfigDir = "config"
+	// DefaultConfigFileName is the name of the Rollkit configuration file.
+	DefaultConfigFileName = "rollkit.toml"
+)
+
+var configTemplate *template.Template
+
+func init() {
+	tmpl := template.New("rollkitConfigFileTemplate").Funcs(template.FuncMap{
+		"quote": strconv.Quote,
+	})
+	configTemplate = template.Must(tmpl.Parse(defaultConfigTemplate))
+}
+
+// ConfigFilePath returns path to the Rollkit configuration file for given node root directory.
+func ConfigFilePath(rootDir string) string {
+	return filepath.Join(rootDir, DefaultConfigDir, DefaultConfigFileName)
+}
+
+// RenderConfig renders Rollkit specific part of the configuration in TOML format.
+func RenderConfig(conf NodeConfig) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := configTemplate.Execute(&buf, conf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// WriteConfigFile renders Rollkit configuration and writes it to the file at given path.
+func WriteConfigFile(path string, conf NodeConfig) error {
+	data, err := RenderConfig(conf)
+	if err != nil {
+		return fmt.Errorf("failed to render config: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+	return os.WriteFile(path, data, 0o600)
+}
+
+// EnsureConfigFile writes configuration file with default values, if it doesn't exist yet.
+func EnsureConfigFile(rootDir string) error {
+	path := ConfigFilePath(rootDir)
+	if _, err := os.Stat(path); err == nil {
+		return nil
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return WriteConfigFile(path, DefaultNodeConfig)
+}
+
+// ReadConfig configures Viper instance to read Rollkit configuration.
+//
+// Values are resolved in following order (highest priority first): command line flags,
+// environment variables (ROLLKIT_...), configuration file, defaults.
+// Missing configuration file is not considered an error.
+func ReadConfig(v *viper.Viper, rootDir string) error {
+	// keys are already prefixed with "rollkit.", so rollkit.block_time is overridden by ROLLKIT_BLOCK_TIME
+	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+	v.AutomaticEnv()
+
+	path := ConfigFilePath(rootDir)
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	v.SetConfigFile(path)
+	if err := v.MergeInConfig(); err != nil {
+		return fmt.Errorf("failed to read config file %s: %w", path, err)
+	}
+	return nil
+}
+
+// defaultConfigTemplate is a template of Rollkit configuration file.
+// Keys in [rollkit] section are equivalent to rollkit.* command line flags.
+const defaultConfigTemplate = `# This is a TOML config file for Rollkit.
+# For more information, see https://github.com/toml-lang/toml
+#
+# Every option can be overridden by command line flag (--rollkit.<option>)
+# or environment variable (ROLLKIT_<OPTION>, for example ROLLKIT_BLOCK_TIME).
+#
+# Options log_level, blocked_peers, allowed_peers, rpc_rate_limit, rpc_rate_burst
+# and lazy_block_time can be reloaded without restarting the node.
+
+#######################################################
+###             Rollkit Configuration               ###
+#######################################################
+[rollkit]
+
+# Run node in aggregator (sequencer) mode.
+aggregator = {{ .Aggregator }}
+
+# Wait for transactions before producing a block, don't build empty blocks.
+lazy_aggregator = {{ .LazyAggregator }}
+
+# Time spent collecting transactions before producing a block (for lazy aggregator mode).
+lazy_block_time = "{{ .LazyBlockTime }}"
+
+# Run node as a light client (header sync only).
+light = {{ .Light }}
+
+# Block time (for aggregator mode).
+block_time = "{{ .BlockTime }}"
+
+# Adapt block time to load (for aggregator mode): block is produced early when mempool holds
+# a full block of transactions, and block time grows when traffic is low. Block time stays
+# between min_block_time and max_block_time, starting from block_time.
+adaptive_block_time = {{ .AdaptiveBlockTime }}
+min_block_time = "{{ .MinBlockTime }}"
+max_block_time = "{{ .MaxBlockTime }}"
+
+# Name of the Data Availability Layer Client.
+da_layer = "{{ .DALayer }}"
+
+# Data Availability Layer Client configuration (format depends on DA layer).
+da_config = {{ quote .DAConfig }}
+
+# Block time of the underlying Data Availability layer (used for syncing).
+da_block_time = "{{ .DABlockTime }}"
+
+# Starting DA block height (for syncing). If 0, it is derived from Rollkit genesis extension
+# (rollkit_genesis.json next to genesis file), if present. The extension is written by the aggregator,
+# and served by full nodes with genesis_extension RPC method; it has to be copied next to genesis file.
+da_start_height = {{ .DAStartHeight }}
+
+# Blocks executed by syncing (non-aggregator) node:
+# - "soft" executes blocks as soon as they are received from P2P network or DA layer,
+# - "da-only" executes only blocks retrieved from DA layer,
+# - "hybrid" executes blocks as soon as they are received, but tracks DA-final height separately.
+sync_mode = "{{ .SyncMode }}"
+
+# Namespace used by the rollup in DA layer: "<version>:<hex identifier>" (e.g. "0:00000102030405060708",
+# Celestia allows version 0 identifiers of up to 10 bytes). Legacy 8 byte hex identifiers are version 0.
+namespace_id = "{{ .Namespace }}"
+
+# Initial trusted hash used to start the header exchange service.
+trusted_hash = "{{ .TrustedHash }}"
+
+# Maximum time spent on submitting pending blocks to DA layer during shutdown.
+shutdown_timeout = "{{ .ShutdownTimeout }}"
+
+# Log level (debug, info, warn, error or none).
+# Levels of individual modules can be set with comma separated module:level pairs,
+# where "*" sets the level of remaining modules, for example "p2p:error,blockmanager:debug,*:info".
+log_level = "{{ .LogLevel }}"
+
+# Log output format (plain or json).
+log_format = "{{ .LogFormat }}"
+
+# Repetitive debug and info messages (like messages logged for every block) are logged
+# at most once per interval, with the number of suppressed messages (0 disables sampling).
+log_sample_interval = "{{ .LogSampleInterval }}"
+
+# Comma separated list of P2P nodes to ignore (multiaddr with /p2p/<ID>).
+blocked_peers = "{{ .P2P.BlockedPeers }}"
+
+# Comma separated list of P2P nodes to whitelist (multiaddr with /p2p/<ID>).
+allowed_peers = "{{ .P2P.AllowedPeers }}"
+
+# Maximum number of RPC requests per second (0 means unlimited).
+rpc_rate_limit = {{ .RPC.RateLimit }}
+
+# Maximum burst of RPC requests above rate limit.
+rpc_rate_burst = {{ .RPC.RateBurst }}
+
+#######################################################
+###             Datastore Configuration             ###
+#######################################################
+
+# Datastore backend (badger or leveldb).
+# Data can be copied between backends with "rollkit migrate-db".
+db_backend = "{{ .DBBackend }}"
+
+# Number of decoded blocks, commits and block responses (each) cached in memory (0 disables cache).
+store_cache_size = {{ .StoreCacheSize }}
+
+# Options below apply only to badger backend. Sizes are in bytes, 0 means badger default.
+
+# Maximum size of single value log file.
+badger_value_log_file_size = {{ .Badger.ValueLogFileSize }}
+
+# Size of block cache.
+badger_block_cache_size = {{ .Badger.BlockCacheSize }}
+
+# Size of index cache (0 keeps all indexes in memory).
+badger_index_cache_size = {{ .Badger.IndexCacheSize }}
+
+# Compression algorithm (none, snappy or zstd).
+badger_compression = "{{ .Badger.Compression }}"
+
+# Sync every write to disk.
+badger_sync_writes = {{ .Badger.SyncWrites }}
+
+# Interval between value log garbage collection cycles.
+badger_gc_interval = "{{ .Badger.GCInterval }}"
+
+# Minimum ratio of stale data in value log file required to rewrite it.
+badger_gc_discard_ratio = {{ .Badger.GCDiscardRatio }}
+`
@@ -0,0 +1,123 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rollkit/rollkit/types"
+)
+
+func TestConfigFileRoundTrip(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	rootDir := t.TempDir()
+	expected := DefaultNodeConfig
+	expected.Aggregator = true
+	expected.LazyAggregator = true
+	expected.BlockTime = 3 * time.Second
+	expected.AdaptiveBlockTime = true
+	expected.MinBlockTime = 500 * time.Millisecond
+	expected.MaxBlockTime = 30 * time.Second
+	expected.DABlockTime = 7 * time.Second
+	expected.DAStartHeight = 42
+	expected.SyncMode = SyncModeHybrid
+	expected.DALayer = "celestia"
+	expected.DAConfig = `{"base_url":"http://localhost:26658","timeout":"30s"}`
+	expected.Namespace = types.MustNewNamespace(types.NamespaceVersionZero, []byte{1, 2, 3, 4, 5, 6, 7, 8})
+	expected.TrustedHash = "deadbeef"
+	expected.LogLevel = "p2p:error,*:info"
+	expected.LogFormat = "json"
+	expected.LogSampleInterval = 10 * time.Second
+	expected.DBBackend = DBBackendLevelDB
+	expected.StoreCacheSize = 500
+	expected.Badger = BadgerConfig{
+		ValueLogFileSize: 64 << 20,
+		BlockCacheSize:   32 << 20,
+		IndexCacheSize:   16 << 20,
+		Compression:      BadgerCompressionZSTD,
+		SyncWrites:       true,
+		GCInterval:       5 * time.Minute,
+		GCDiscardRatio:   0.5,
+	}
+
+	require.NoError(WriteConfigFile(ConfigFilePath(rootDir), expected))
+
+	v := viper.New()
+	require.NoError(ReadConfig(v, rootDir))
+	actual := DefaultNodeConfig
+	require.NoError(actual.GetViperConfig(v))
+
+	assert.Equal(expected.Aggregator, actual.Aggregator)
+	assert.Equal(expected.LazyAggregator, actual.LazyAggregator)
+	assert.Equal(expected.BlockManagerConfig, actual.BlockManagerConfig)
+	assert.Equal(expected.DALayer, actual.DALayer)
+	assert.Equal(expected.DAConfig, actual.DAConfig)
+	assert.Equal(expected.TrustedHash, actual.TrustedHash)
+	assert.Equal(expected.LogLevel, actual.LogLevel)
+	assert.Equal(expected.LogFormat, actual.LogFormat)
+	assert.Equal(expected.LogSampleInterval, actual.LogSampleInterval)
+	assert.Equal(expected.DBConfig, actual.DBConfig)
+}
+
+func TestEnsureConfigFile(t *testing.T) {
+	require := require.New(t)
+
+	rootDir := t.TempDir()
+	require.NoError(EnsureConfigFile(rootDir))
+	data, err := os.ReadFile(filepath.Clean(ConfigFilePath(rootDir)))
+	require.NoError(err)
+	expected, err := RenderConfig(DefaultNodeConfig)
+	require.NoError(err)
+	require.Equal(expected, data)
+
+	// existing file is not overwritten
+	require.NoError(os.WriteFile(ConfigFilePath(rootDir), []byte("[rollkit]\naggregator = true\n"), 0o600))
+	require.NoError(EnsureConfigFile(rootDir))
+	data, err = os.ReadFile(filepath.Clean(ConfigFilePath(rootDir)))
+	require.NoError(err)
+	require.Equal("[rollkit]\naggregator = true\n", string(data))
+}
+
+func TestConfigPrecedence(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	rootDir := t.TempDir()
+	fileConf := DefaultNodeConfig
+	fileConf.BlockTime = 5 * time.Second
+	fileConf.DABlockTime = 5 * time.Second
+	fileConf.DALayer = "grpc"
+	require.NoError(WriteConfigFile(ConfigFilePath(rootDir), fileConf))
+
+	t.Setenv("ROLLKIT_DA_BLOCK_TIME", "11s")
+	t.Setenv("ROLLKIT_DA_LAYER", "celestia")
+
+	cmd := &cobra.Command{}
+	AddFlags(cmd)
+	v := viper.New()
+	require.NoError(v.BindPFlags(cmd.Flags()))
+	require.NoError(cmd.Flags().Set(flagDALayer, "mock"))
+	require.NoError(ReadConfig(v, rootDir))
+
+	nc := DefaultNodeConfig
+	require.NoError(nc.GetViperConfig(v))
+	// config file overrides defaults
+	assert.Equal(5*time.Second, nc.BlockTime)
+	// environment overrides config file
+	assert.Equal(11*time.Second, nc.DABlockTime)
+	// flags override environment
+	assert.Equal("mock", nc.DALayer)
+}
+
+func TestReadConfigWithoutFile(t *testing.T) {
+	v := viper.New()
+	assert.NoError(t, ReadConfig(v, t.TempDir()))
+}
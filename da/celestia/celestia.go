@@ -1,9 +1,13 @@
 package celestia
 
 import (
+	"bytes"
 	"context"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
 	"strings"
 	"time"
 
@@ -32,6 +36,7 @@ type DataAvailabilityLayerClient struct {
 
 var _ da.DataAvailabilityLayerClient = &DataAvailabilityLayerClient{}
 var _ da.BlockRetriever = &DataAvailabilityLayerClient{}
+var _ da.NamespacedClient = &DataAvailabilityLayerClient{}
 
 // Config stores Celestia DALC configuration parameters.
 type Config struct {
@@ -43,12 +48,12 @@ type Config struct {
 }
 
 // Init initializes DataAvailabilityLayerClient instance.
-func (c *DataAvailabilityLayerClient) Init(namespaceID types.NamespaceID, config []byte, kvStore ds.Datastore, logger log.Logger) error {
-	namespace, err := share.NewBlobNamespaceV0(namespaceID[:])
+func (c *DataAvailabilityLayerClient) Init(namespaceID types.Namespace, config []byte, kvStore ds.Datastore, logger log.Logger) error {
+	namespace, err := toNamespace(namespaceID)
 	if err != nil {
 		return err
 	}
-	c.namespace = namespace.ToAppNamespace()
+	c.namespace = namespace
 	c.logger = logger
 
 	if len(config) > 0 {
@@ -74,18 +79,31 @@ func (c *DataAvailabilityLayerClient) Stop() error {
 
 // SubmitBlocks submits blocks to DA layer.
 func (c *DataAvailabilityLayerClient) SubmitBlocks(ctx context.Context, blocks []*types.Block) da.ResultSubmitBlocks {
-	blobs := make([]*blob.Blob, len(blocks))
-	for blockIndex, block := range blocks {
-		data, err := block.MarshalBinary()
-		if err != nil {
-			return da.ResultSubmitBlocks{
-				BaseResult: da.BaseResult{
-					Code:    da.StatusError,
-					Message: err.Error(),
-				},
-			}
+	blobs, err := toBlobs(c.namespace, blocks)
+	if err != nil {
+		return da.ResultSubmitBlocks{
+			BaseResult: da.BaseResult{
+				Code:    da.StatusError,
+				Message: err.Error(),
+			},
 		}
-		blockBlob, err := blob.NewBlobV0(c.namespace.Bytes(), data)
+	}
+	return c.submit(ctx, blobs)
+}
+
+// SubmitNamespacedBlocks submits blocks of many namespaces to DA layer, in single transaction.
+func (c *DataAvailabilityLayerClient) SubmitNamespacedBlocks(ctx context.Context, blocks map[types.Namespace][]*types.Block) da.ResultSubmitBlocks {
+	namespaceIDs := make([]types.Namespace, 0, len(blocks))
+	for namespaceID := range blocks {
+		namespaceIDs = append(namespaceIDs, namespaceID)
+	}
+	sort.Slice(namespaceIDs, func(i, j int) bool {
+		return bytes.Compare(namespaceIDs[i].Bytes(), namespaceIDs[j].Bytes()) < 0
+	})
+
+	var blobs []*blob.Blob
+	for _, namespaceID := range namespaceIDs {
+		nsBlobs, err := toNamespacedBlobs(namespaceID, blocks[namespaceID])
 		if err != nil {
 			return da.ResultSubmitBlocks{
 				BaseResult: da.BaseResult{
@@ -94,9 +112,12 @@ func (c *DataAvailabilityLayerClient) SubmitBlocks(ctx context.Context, blocks [
 				},
 			}
 		}
-		blobs[blockIndex] = blockBlob
+		blobs = append(blobs, nsBlobs...)
 	}
+	return c.submit(ctx, blobs)
+}
 
+func (c *DataAvailabilityLayerClient) submit(ctx context.Context, blobs []*blob.Blob) da.ResultSubmitBlocks {
 	dataLayerHeight, err := c.rpc.Blob.Submit(ctx, blobs, openrpc.DefaultSubmitOptions())
 	if err != nil {
 		return da.ResultSubmitBlocks{
@@ -119,8 +140,26 @@ func (c *DataAvailabilityLayerClient) SubmitBlocks(ctx context.Context, blocks [
 
 // RetrieveBlocks gets a batch of blocks from DA layer.
 func (c *DataAvailabilityLayerClient) RetrieveBlocks(ctx context.Context, dataLayerHeight uint64) da.ResultRetrieveBlocks {
-	c.logger.Debug("trying to retrieve blob using Blob.GetAll", "daHeight", dataLayerHeight, "namespace", hex.EncodeToString(c.namespace.Bytes()))
-	blobs, err := c.rpc.Blob.GetAll(ctx, dataLayerHeight, []share.Namespace{c.namespace.Bytes()})
+	return c.retrieve(ctx, c.namespace, dataLayerHeight)
+}
+
+// RetrieveNamespacedBlocks gets a batch of blocks of given namespace from DA layer.
+func (c *DataAvailabilityLayerClient) RetrieveNamespacedBlocks(ctx context.Context, namespaceID types.Namespace, dataLayerHeight uint64) da.ResultRetrieveBlocks {
+	namespace, err := toNamespace(namespaceID)
+	if err != nil {
+		return da.ResultRetrieveBlocks{
+			BaseResult: da.BaseResult{
+				Code:    da.StatusError,
+				Message: err.Error(),
+			},
+		}
+	}
+	return c.retrieve(ctx, namespace, dataLayerHeight)
+}
+
+func (c *DataAvailabilityLayerClient) retrieve(ctx context.Context, namespace openrpcns.Namespace, dataLayerHeight uint64) da.ResultRetrieveBlocks {
+	c.logger.Debug("trying to retrieve blob using Blob.GetAll", "daHeight", dataLayerHeight, "namespace", hex.EncodeToString(namespace.Bytes()))
+	blobs, err := c.rpc.Blob.GetAll(ctx, dataLayerHeight, []share.Namespace{namespace.Bytes()})
 	status := dataRequestErrorToStatus(err)
 	if status != da.StatusSuccess {
 		return da.ResultRetrieveBlocks{
@@ -160,6 +199,46 @@ func (c *DataAvailabilityLayerClient) RetrieveBlocks(ctx context.Context, dataLa
 	}
 }
 
+// toNamespace returns Celestia blob namespace. Only version 0 namespaces, with up to 10 bytes of identifier,
+// are supported; identifier is left-padded with zeros, so legacy 8 byte namespace IDs map to the same blob namespace.
+func toNamespace(namespaceID types.Namespace) (openrpcns.Namespace, error) {
+	if namespaceID.IsZero() {
+		return openrpcns.Namespace{}, errors.New("namespace is not set")
+	}
+	if namespaceID.Version() != types.NamespaceVersionZero {
+		return openrpcns.Namespace{}, fmt.Errorf("unsupported namespace version: %d", namespaceID.Version())
+	}
+	namespace, err := share.NewBlobNamespaceV0(namespaceID.ID())
+	if err != nil {
+		return openrpcns.Namespace{}, err
+	}
+	return namespace.ToAppNamespace(), nil
+}
+
+func toNamespacedBlobs(namespaceID types.Namespace, blocks []*types.Block) ([]*blob.Blob, error) {
+	namespace, err := toNamespace(namespaceID)
+	if err != nil {
+		return nil, err
+	}
+	return toBlobs(namespace, blocks)
+}
+
+func toBlobs(namespace openrpcns.Namespace, blocks []*types.Block) ([]*blob.Blob, error) {
+	blobs := make([]*blob.Blob, len(blocks))
+	for blockIndex, block := range blocks {
+		data, err := block.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		blockBlob, err := blob.NewBlobV0(namespace.Bytes(), data)
+		if err != nil {
+			return nil, err
+		}
+		blobs[blockIndex] = blockBlob
+	}
+	return blobs, nil
+}
+
 func dataRequestErrorToStatus(err error) da.StatusCode {
 	switch {
 	case err == nil,
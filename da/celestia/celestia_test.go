@@ -5,8 +5,10 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/rollkit/rollkit/da"
+	"github.com/rollkit/rollkit/types"
 )
 
 func TestDataRequestErrorToStatus(t *testing.T) {
@@ -41,3 +43,20 @@ func TestDataRequestErrorToStatus(t *testing.T) {
 		assert.Equal(tt.statusCode, dataRequestErrorToStatus(tt.err))
 	}
 }
+
+func TestToNamespace(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	// identifiers shorter than 10 bytes are left-padded, so legacy 8 byte namespace IDs are unchanged
+	legacy, err := toNamespace(types.MustNewNamespace(types.NamespaceVersionZero, []byte{1, 2, 3, 4, 5, 6, 7, 8}))
+	require.NoError(err)
+	padded, err := toNamespace(types.MustNewNamespace(types.NamespaceVersionZero, []byte{0, 0, 1, 2, 3, 4, 5, 6, 7, 8}))
+	require.NoError(err)
+	assert.Equal(padded, legacy)
+
+	_, err = toNamespace(types.MustNewNamespace(types.NamespaceVersionZero, []byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11}))
+	assert.Error(err)
+	_, err = toNamespace(types.MustNewNamespace(1, []byte{1}))
+	assert.Error(err)
+}
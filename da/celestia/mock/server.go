@@ -74,7 +74,7 @@ func (s *Server) Start() (string, error) {
 	if err != nil {
 		return "", err
 	}
-	err = s.mock.Init([8]byte{}, []byte(s.blockTime.String()), kvStore, s.logger)
+	err = s.mock.Init(types.Namespace{}, []byte(s.blockTime.String()), kvStore, s.logger)
 	if err != nil {
 		return "", err
 	}
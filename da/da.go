@@ -73,7 +73,7 @@ type ResultRetrieveBlocks struct {
 // It also contains life-cycle methods.
 type DataAvailabilityLayerClient interface {
 	// Init is called once to allow DA client to read configuration and initialize resources.
-	Init(namespaceID types.NamespaceID, config []byte, kvStore ds.Datastore, logger log.Logger) error
+	Init(namespaceID types.Namespace, config []byte, kvStore ds.Datastore, logger log.Logger) error
 
 	// Start is called once, after Init. It's implementation should start operation of DataAvailabilityLayerClient.
 	Start() error
@@ -93,3 +93,14 @@ type BlockRetriever interface {
 	// RetrieveBlocks returns blocks at given data layer height from data availability layer.
 	RetrieveBlocks(ctx context.Context, dataLayerHeight uint64) ResultRetrieveBlocks
 }
+
+// NamespacedClient is additional interface that can be implemented by Data Availability Layer Client that is able to
+// submit and retrieve blocks of many namespaces. It allows single DA client to be shared by many chains
+// (see da/shared package).
+type NamespacedClient interface {
+	// SubmitNamespacedBlocks submits blocks of many namespaces to the DA layer, in single transaction.
+	SubmitNamespacedBlocks(ctx context.Context, blocks map[types.Namespace][]*types.Block) ResultSubmitBlocks
+
+	// RetrieveNamespacedBlocks returns blocks of given namespace at given data layer height from data availability layer.
+	RetrieveNamespacedBlocks(ctx context.Context, namespaceID types.Namespace, dataLayerHeight uint64) ResultRetrieveBlocks
+}
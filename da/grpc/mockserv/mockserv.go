@@ -19,7 +19,7 @@ import (
 func GetServer(kv ds.Datastore, conf grpcda.Config, mockConfig []byte, logger cmlog.Logger) *grpc.Server {
 	srv := grpc.NewServer()
 	mockImpl := &mockImpl{mock: &newda.NewDA{DA: test.NewDummyDA()}}
-	err := mockImpl.mock.Init([8]byte{}, mockConfig, kv, logger)
+	err := mockImpl.mock.Init(types.Namespace{}, mockConfig, kv, logger)
 	if err != nil {
 		logger.Error("failed to initialize mock DALC", "error", err)
 		panic(err)
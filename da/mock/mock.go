@@ -6,11 +6,14 @@ import (
 	"encoding/hex"
 	"fmt"
 	"math/rand"
+	"strconv"
 	"sync"
 	"sync/atomic"
 	"time"
 
+	"github.com/benbjohnson/clock"
 	ds "github.com/ipfs/go-datastore"
+	ktds "github.com/ipfs/go-datastore/keytransform"
 
 	"github.com/rollkit/celestia-openrpc/types/core"
 	"github.com/rollkit/rollkit/da"
@@ -30,6 +33,19 @@ type DataAvailabilityLayerClient struct {
 
 	daHeight uint64
 	config   config
+
+	// started is the time of Start; produced is the number of DA blocks produced since then
+	started     time.Time
+	produced    uint64
+	producedMtx sync.Mutex
+
+	clock   clock.Clock
+	rand    *rand.Rand
+	randMtx sync.Mutex
+
+	// faults maps heights of rollup blocks to blocks submitted instead of them (nil means block is dropped)
+	faults    map[uint64]*types.Block
+	faultsMtx sync.Mutex
 }
 
 const defaultBlockTime = 3 * time.Second
@@ -40,12 +56,16 @@ type config struct {
 
 var _ da.DataAvailabilityLayerClient = &DataAvailabilityLayerClient{}
 var _ da.BlockRetriever = &DataAvailabilityLayerClient{}
+var _ da.NamespacedClient = &DataAvailabilityLayerClient{}
 
 // Init is called once to allow DA client to read configuration and initialize resources.
-func (m *DataAvailabilityLayerClient) Init(_ types.NamespaceID, config []byte, dalcKV ds.Datastore, logger log.Logger) error {
+func (m *DataAvailabilityLayerClient) Init(_ types.Namespace, config []byte, dalcKV ds.Datastore, logger log.Logger) error {
 	m.logger = logger
 	m.dalcKV = dalcKV
 	m.daHeight = 1
+	if m.clock == nil {
+		m.clock = clock.New()
+	}
 	m.daHeaders = make(map[uint64]*core.DataAvailabilityHeader)
 
 	eds, err := RandEDS(4)
@@ -73,21 +93,42 @@ func (m *DataAvailabilityLayerClient) Init(_ types.NamespaceID, config []byte, d
 }
 
 // Start implements DataAvailabilityLayerClient interface.
+//
+// DA blocks are produced every block time after Start. They are produced lazily, when the client is used, so DA
+// height always matches the time of the clock, also when virtual clock is advanced.
 func (m *DataAvailabilityLayerClient) Start() error {
 	m.logger.Debug("Mock Data Availability Layer Client starting")
-	go func() {
-		// This infinite loop is expected to be stopped and cleaned up
-		// by the GC. This is OK because it is only a test helper and
-		// the block time is sufficiently long that there should not be
-		// a lot of wasted cycles.
-		for {
-			time.Sleep(m.config.BlockTime)
-			m.updateDAHeight()
-		}
-	}()
+	m.producedMtx.Lock()
+	defer m.producedMtx.Unlock()
+	m.started = m.clock.Now()
 	return nil
 }
 
+// SetClock replaces the clock used to produce DA blocks; by default system clock is used.
+// It has to be called before Start.
+func (m *DataAvailabilityLayerClient) SetClock(c clock.Clock) {
+	m.clock = c
+}
+
+// SetRand replaces the source of randomness used to advance DA height, making it reproducible.
+func (m *DataAvailabilityLayerClient) SetRand(r *rand.Rand) {
+	m.randMtx.Lock()
+	defer m.randMtx.Unlock()
+	m.rand = r
+}
+
+// InjectFault makes DA layer include substitute instead of any block at given height, submitted later. If substitute
+// is nil, blocks at given height are silently dropped. It simulates sequencer that gossips one block over P2P
+// network, but posts different block to DA layer, or never posts it.
+func (m *DataAvailabilityLayerClient) InjectFault(height uint64, substitute *types.Block) {
+	m.faultsMtx.Lock()
+	defer m.faultsMtx.Unlock()
+	if m.faults == nil {
+		m.faults = make(map[uint64]*types.Block)
+	}
+	m.faults[height] = substitute
+}
+
 // Stop implements DataAvailabilityLayerClient interface.
 func (m *DataAvailabilityLayerClient) Stop() error {
 	m.logger.Debug("Mock Data Availability Layer Client stopped")
@@ -96,6 +137,7 @@ func (m *DataAvailabilityLayerClient) Stop() error {
 
 // GetHeaderByHeight returns the header at the given height.
 func (m *DataAvailabilityLayerClient) GetHeaderByHeight(height uint64) *core.DataAvailabilityHeader {
+	m.produceBlocks()
 	m.daHeadersLock.RLock()
 	dah := m.daHeaders[height]
 	m.daHeadersLock.RUnlock()
@@ -124,6 +166,7 @@ func isEqual(headerA, headerB *core.DataAvailabilityHeader) bool {
 
 // GetHeightByHeader returns the height for the given header.
 func (m *DataAvailabilityLayerClient) GetHeightByHeader(dah *core.DataAvailabilityHeader) uint64 {
+	m.produceBlocks()
 	daHeight := atomic.LoadUint64(&m.daHeight)
 	for height := uint64(0); height < daHeight; height++ {
 		m.daHeadersLock.RLock()
@@ -143,50 +186,101 @@ func (m *DataAvailabilityLayerClient) GetHeightByHeader(dah *core.DataAvailabili
 // This should create a transaction which (potentially)
 // triggers a state transition in the DA layer.
 func (m *DataAvailabilityLayerClient) SubmitBlocks(ctx context.Context, blocks []*types.Block) da.ResultSubmitBlocks {
+	m.produceBlocks()
 	daHeight := atomic.LoadUint64(&m.daHeight)
+	if err := m.submit(ctx, m.dalcKV, daHeight, blocks); err != nil {
+		return da.ResultSubmitBlocks{BaseResult: da.BaseResult{Code: da.StatusError, Message: err.Error()}}
+	}
+	return da.ResultSubmitBlocks{
+		BaseResult: da.BaseResult{
+			Code:     da.StatusSuccess,
+			Message:  "OK",
+			DAHeight: daHeight,
+		},
+	}
+}
 
+// SubmitNamespacedBlocks submits blocks of many namespaces to the DA layer. All blocks are included at the same
+// DA height.
+func (m *DataAvailabilityLayerClient) SubmitNamespacedBlocks(ctx context.Context, blocks map[types.Namespace][]*types.Block) da.ResultSubmitBlocks {
+	m.produceBlocks()
+	daHeight := atomic.LoadUint64(&m.daHeight)
+	for namespaceID, nsBlocks := range blocks {
+		if err := m.submit(ctx, m.namespaceKV(namespaceID), daHeight, nsBlocks); err != nil {
+			return da.ResultSubmitBlocks{BaseResult: da.BaseResult{Code: da.StatusError, Message: err.Error()}}
+		}
+	}
+	return da.ResultSubmitBlocks{
+		BaseResult: da.BaseResult{
+			Code:     da.StatusSuccess,
+			Message:  "OK",
+			DAHeight: daHeight,
+		},
+	}
+}
+
+func (m *DataAvailabilityLayerClient) submit(ctx context.Context, kv ds.Datastore, daHeight uint64, blocks []*types.Block) error {
 	for _, block := range blocks {
 		blockHeight := uint64(block.Height())
+		if substitute, ok := m.fault(blockHeight); ok {
+			if substitute == nil {
+				m.logger.Debug("Dropping block", "height", blockHeight, "dataLayerHeight", daHeight)
+				continue
+			}
+			block = substitute
+		}
 		m.logger.Debug("Submitting blocks to DA layer!", "height", blockHeight, "dataLayerHeight", daHeight)
 		hash := block.Hash()
 		blob, err := block.MarshalBinary()
 		if err != nil {
-			return da.ResultSubmitBlocks{BaseResult: da.BaseResult{Code: da.StatusError, Message: err.Error()}}
+			return err
 		}
 
-		err = m.dalcKV.Put(ctx, getKey(daHeight, blockHeight), hash[:])
+		err = kv.Put(ctx, getKey(daHeight, blockHeight), hash[:])
 		if err != nil {
-			return da.ResultSubmitBlocks{BaseResult: da.BaseResult{Code: da.StatusError, Message: err.Error()}}
+			return err
 		}
 
-		err = m.dalcKV.Put(ctx, ds.NewKey(hex.EncodeToString(hash[:])), blob)
+		err = kv.Put(ctx, ds.NewKey(hex.EncodeToString(hash[:])), blob)
 		if err != nil {
-			return da.ResultSubmitBlocks{BaseResult: da.BaseResult{Code: da.StatusError, Message: err.Error()}}
+			return err
 		}
 	}
-	return da.ResultSubmitBlocks{
-		BaseResult: da.BaseResult{
-			Code:     da.StatusSuccess,
-			Message:  "OK",
-			DAHeight: daHeight,
-		},
-	}
+	return nil
+}
+
+func (m *DataAvailabilityLayerClient) fault(height uint64) (*types.Block, bool) {
+	m.faultsMtx.Lock()
+	defer m.faultsMtx.Unlock()
+	substitute, ok := m.faults[height]
+	return substitute, ok
 }
 
 // RetrieveBlocks returns block at given height from data availability layer.
 func (m *DataAvailabilityLayerClient) RetrieveBlocks(ctx context.Context, daHeight uint64) da.ResultRetrieveBlocks {
+	return m.retrieve(ctx, m.dalcKV, daHeight)
+}
+
+// RetrieveNamespacedBlocks returns blocks of given namespace at given height from data availability layer.
+// Only blocks submitted with SubmitNamespacedBlocks are returned.
+func (m *DataAvailabilityLayerClient) RetrieveNamespacedBlocks(ctx context.Context, namespaceID types.Namespace, daHeight uint64) da.ResultRetrieveBlocks {
+	return m.retrieve(ctx, m.namespaceKV(namespaceID), daHeight)
+}
+
+func (m *DataAvailabilityLayerClient) retrieve(ctx context.Context, kv ds.Datastore, daHeight uint64) da.ResultRetrieveBlocks {
+	m.produceBlocks()
 	if daHeight >= atomic.LoadUint64(&m.daHeight) {
 		return da.ResultRetrieveBlocks{BaseResult: da.BaseResult{Code: da.StatusError, Message: "block not found"}}
 	}
 
-	results, err := store.PrefixEntries(ctx, m.dalcKV, getPrefix(daHeight))
+	results, err := store.PrefixEntries(ctx, kv, getPrefix(daHeight))
 	if err != nil {
 		return da.ResultRetrieveBlocks{BaseResult: da.BaseResult{Code: da.StatusError, Message: err.Error()}}
 	}
 
 	var blocks []*types.Block
 	for result := range results.Next() {
-		blob, err := m.dalcKV.Get(ctx, ds.NewKey(hex.EncodeToString(result.Entry.Value)))
+		blob, err := kv.Get(ctx, ds.NewKey(hex.EncodeToString(result.Entry.Value)))
 		if err != nil {
 			return da.ResultRetrieveBlocks{BaseResult: da.BaseResult{Code: da.StatusError, Message: err.Error()}}
 		}
@@ -202,6 +296,11 @@ func (m *DataAvailabilityLayerClient) RetrieveBlocks(ctx context.Context, daHeig
 	return da.ResultRetrieveBlocks{BaseResult: da.BaseResult{Code: da.StatusSuccess}, Blocks: blocks}
 }
 
+// namespaceKV returns part of the store used for blocks of given namespace.
+func (m *DataAvailabilityLayerClient) namespaceKV(namespaceID types.Namespace) ds.Datastore {
+	return ktds.Wrap(m.dalcKV, ktds.PrefixTransform{Prefix: ds.NewKey("ns").ChildString(hex.EncodeToString(namespaceID.Bytes()))})
+}
+
 func getPrefix(daHeight uint64) string {
 	return store.GenerateKey([]interface{}{daHeight})
 }
@@ -210,8 +309,81 @@ func getKey(daHeight uint64, height uint64) ds.Key {
 	return ds.NewKey(store.GenerateKey([]interface{}{daHeight, height}))
 }
 
+// Reorg simulates reorganization of the DA chain. Blocks submitted within last depth DA heights are removed
+// from their original DA heights and included again at the current DA height. DA headers of affected heights
+// are replaced. Reorg returns the number of moved blocks.
+func (m *DataAvailabilityLayerClient) Reorg(ctx context.Context, depth uint64) (int, error) {
+	m.produceBlocks()
+	m.daHeadersLock.Lock()
+	defer m.daHeadersLock.Unlock()
+
+	tip := atomic.LoadUint64(&m.daHeight)
+	from := uint64(1)
+	if tip > depth {
+		from = tip - depth
+	}
+
+	moved := 0
+	for daHeight := from; daHeight < tip; daHeight++ {
+		results, err := store.PrefixEntries(ctx, m.dalcKV, getPrefix(daHeight))
+		if err != nil {
+			return moved, err
+		}
+		entries, err := results.Rest()
+		if err != nil {
+			return moved, err
+		}
+		for _, entry := range entries {
+			key := ds.NewKey(entry.Key)
+			blockHeight, err := strconv.ParseUint(key.BaseNamespace(), 10, 64)
+			if err != nil {
+				return moved, fmt.Errorf("invalid key %q: %w", entry.Key, err)
+			}
+			if err := m.dalcKV.Delete(ctx, key); err != nil {
+				return moved, err
+			}
+			if err := m.dalcKV.Put(ctx, getKey(tip, blockHeight), entry.Value); err != nil {
+				return moved, err
+			}
+			moved++
+		}
+
+		eds, err := RandEDS(4)
+		if err != nil {
+			return moved, err
+		}
+		dah, err := core.NewDataAvailabilityHeader(eds)
+		if err != nil {
+			return moved, err
+		}
+		m.daHeaders[daHeight] = &dah
+	}
+	m.logger.Info("DA reorg", "depth", depth, "height", tip, "movedBlocks", moved)
+	return moved, nil
+}
+
+// produceBlocks produces DA blocks for all block times elapsed since Start.
+func (m *DataAvailabilityLayerClient) produceBlocks() {
+	m.producedMtx.Lock()
+	defer m.producedMtx.Unlock()
+	if m.started.IsZero() {
+		return
+	}
+	elapsed := uint64(m.clock.Since(m.started) / m.config.BlockTime)
+	for ; m.produced < elapsed; m.produced++ {
+		m.updateDAHeight()
+	}
+}
+
 func (m *DataAvailabilityLayerClient) updateDAHeight() {
-	blockStep := rand.Uint64()%10 + 1 //nolint:gosec
+	var blockStep uint64
+	m.randMtx.Lock()
+	if m.rand != nil {
+		blockStep = m.rand.Uint64()%10 + 1
+	} else {
+		blockStep = rand.Uint64()%10 + 1 //nolint:gosec
+	}
+	m.randMtx.Unlock()
 	atomic.AddUint64(&m.daHeight, blockStep)
 	eds, err := RandEDS(4)
 	if err != nil {
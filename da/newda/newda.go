@@ -23,7 +23,7 @@ type NewDA struct {
 }
 
 // Init is called once to allow DA client to read configuration and initialize resources.
-func (n *NewDA) Init(namespaceID types.NamespaceID, config []byte, kvStore ds.Datastore, logger log.Logger) error {
+func (n *NewDA) Init(namespaceID types.Namespace, config []byte, kvStore ds.Datastore, logger log.Logger) error {
 	n.logger = logger
 	return nil
 }
@@ -0,0 +1,205 @@
+// Package shared allows many chains, run in one process, to share single data availability layer client.
+//
+// Blocks submitted by all chains are collected for a short period of time (batch window) and submitted to
+// DA layer in single transaction, each chain using its own namespace.
+package shared
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	ds "github.com/ipfs/go-datastore"
+
+	"github.com/rollkit/rollkit/da"
+	"github.com/rollkit/rollkit/third_party/log"
+	"github.com/rollkit/rollkit/types"
+)
+
+// ErrNotNamespaced is returned if DA layer client can't submit blocks of many namespaces.
+var ErrNotNamespaced = errors.New("data availability layer client doesn't support multiple namespaces")
+
+// errClientStopped is returned for submissions pending when client is stopped.
+var errClientStopped = errors.New("shared data availability layer client is stopped")
+
+// namespacedClient is implemented by DA layer clients that can be shared.
+type namespacedClient interface {
+	da.DataAvailabilityLayerClient
+	da.NamespacedClient
+}
+
+// submission is a request to submit blocks of single namespace.
+type submission struct {
+	namespaceID types.Namespace
+	blocks      []*types.Block
+	result      chan da.ResultSubmitBlocks
+}
+
+// Client is a data availability layer client shared by many chains. Each chain uses its own view of the client,
+// returned by ForNamespace.
+type Client struct {
+	dalc        namespacedClient
+	batchWindow time.Duration
+	logger      log.Logger
+
+	queue  chan *submission
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// New creates shared client. dalc has to be initialized, and has to implement da.NamespacedClient.
+// Submissions are collected for batchWindow before being submitted to DA layer.
+func New(dalc da.DataAvailabilityLayerClient, batchWindow time.Duration, logger log.Logger) (*Client, error) {
+	nsClient, ok := dalc.(namespacedClient)
+	if !ok {
+		return nil, ErrNotNamespaced
+	}
+	return &Client{
+		dalc:        nsClient,
+		batchWindow: batchWindow,
+		logger:      logger,
+		queue:       make(chan *submission),
+	}, nil
+}
+
+// Start starts underlying DA layer client and batching of submissions.
+func (c *Client) Start() error {
+	if err := c.dalc.Start(); err != nil {
+		return err
+	}
+	var ctx context.Context
+	ctx, c.cancel = context.WithCancel(context.Background())
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+		c.batchLoop(ctx)
+	}()
+	return nil
+}
+
+// Stop stops batching of submissions and underlying DA layer client.
+func (c *Client) Stop() error {
+	if c.cancel != nil {
+		c.cancel()
+		c.wg.Wait()
+	}
+	return c.dalc.Stop()
+}
+
+// ForNamespace returns a view of shared client, that submits and retrieves blocks of given namespace.
+//
+// Life-cycle of shared client is managed by its owner - Init, Start and Stop of returned client are no-op.
+func (c *Client) ForNamespace(namespaceID types.Namespace) da.DataAvailabilityLayerClient {
+	return &namespaceClient{shared: c, namespaceID: namespaceID}
+}
+
+// submit adds blocks to the next batch and waits for result of batch submission.
+func (c *Client) submit(ctx context.Context, namespaceID types.Namespace, blocks []*types.Block) da.ResultSubmitBlocks {
+	s := &submission{namespaceID: namespaceID, blocks: blocks, result: make(chan da.ResultSubmitBlocks, 1)}
+	select {
+	case c.queue <- s:
+	case <-ctx.Done():
+		return errorResult(ctx.Err())
+	}
+	select {
+	case res := <-s.result:
+		return res
+	case <-ctx.Done():
+		// blocks may still be submitted with the batch
+		return errorResult(ctx.Err())
+	}
+}
+
+// batchLoop collects submissions of all namespaces and submits them to DA layer in batches.
+func (c *Client) batchLoop(ctx context.Context) {
+	for {
+		var batch []*submission
+		select {
+		case <-ctx.Done():
+			return
+		case s := <-c.queue:
+			batch = append(batch, s)
+		}
+
+		window := time.NewTimer(c.batchWindow)
+	collect:
+		for {
+			select {
+			case <-ctx.Done():
+				window.Stop()
+				for _, s := range batch {
+					s.result <- errorResult(errClientStopped)
+				}
+				return
+			case s := <-c.queue:
+				batch = append(batch, s)
+			case <-window.C:
+				break collect
+			}
+		}
+
+		c.submitBatch(ctx, batch)
+	}
+}
+
+// submitBatch submits blocks of all submissions in single DA layer transaction, and passes the result to all
+// submitters.
+func (c *Client) submitBatch(ctx context.Context, batch []*submission) {
+	blocks := make(map[types.Namespace][]*types.Block)
+	count := 0
+	for _, s := range batch {
+		blocks[s.namespaceID] = append(blocks[s.namespaceID], s.blocks...)
+		count += len(s.blocks)
+	}
+	c.logger.Debug("submitting batch to DA layer", "namespaces", len(blocks), "blocks", count)
+	res := c.dalc.SubmitNamespacedBlocks(ctx, blocks)
+	if res.Code != da.StatusSuccess {
+		c.logger.Error("failed to submit batch to DA layer", "namespaces", len(blocks), "blocks", count, "error", res.Message)
+	}
+	for _, s := range batch {
+		s.result <- res
+	}
+}
+
+func errorResult(err error) da.ResultSubmitBlocks {
+	return da.ResultSubmitBlocks{BaseResult: da.BaseResult{Code: da.StatusError, Message: err.Error()}}
+}
+
+// namespaceClient is a view of shared client, used by single chain.
+type namespaceClient struct {
+	shared      *Client
+	namespaceID types.Namespace
+}
+
+var _ da.DataAvailabilityLayerClient = &namespaceClient{}
+var _ da.BlockRetriever = &namespaceClient{}
+
+// Init verifies that chain uses the namespace of this view. Shared client is initialized by its owner.
+func (n *namespaceClient) Init(namespaceID types.Namespace, _ []byte, _ ds.Datastore, _ log.Logger) error {
+	if namespaceID != n.namespaceID {
+		return fmt.Errorf("client is bound to namespace %s, not %s", n.namespaceID, namespaceID)
+	}
+	return nil
+}
+
+// Start is no-op, shared client is started by its owner.
+func (n *namespaceClient) Start() error {
+	return nil
+}
+
+// Stop is no-op, shared client is stopped by its owner.
+func (n *namespaceClient) Stop() error {
+	return nil
+}
+
+// SubmitBlocks submits blocks with the next batch of shared client.
+func (n *namespaceClient) SubmitBlocks(ctx context.Context, blocks []*types.Block) da.ResultSubmitBlocks {
+	return n.shared.submit(ctx, n.namespaceID, blocks)
+}
+
+// RetrieveBlocks returns blocks of the namespace at given data layer height.
+func (n *namespaceClient) RetrieveBlocks(ctx context.Context, dataLayerHeight uint64) da.ResultRetrieveBlocks {
+	return n.shared.dalc.RetrieveNamespacedBlocks(ctx, n.namespaceID, dataLayerHeight)
+}
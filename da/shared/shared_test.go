@@ -0,0 +1,133 @@
+package shared
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rollkit/go-da/test"
+	"github.com/rollkit/rollkit/da"
+	"github.com/rollkit/rollkit/da/mock"
+	"github.com/rollkit/rollkit/da/newda"
+	"github.com/rollkit/rollkit/store"
+	testlog "github.com/rollkit/rollkit/test/log"
+	"github.com/rollkit/rollkit/types"
+)
+
+const mockDABlockTime = time.Second
+
+// countingDALC counts submissions of namespaced blocks.
+type countingDALC struct {
+	*mock.DataAvailabilityLayerClient
+	submissions atomic.Int32
+}
+
+func (c *countingDALC) SubmitNamespacedBlocks(ctx context.Context, blocks map[types.Namespace][]*types.Block) da.ResultSubmitBlocks {
+	c.submissions.Add(1)
+	return c.DataAvailabilityLayerClient.SubmitNamespacedBlocks(ctx, blocks)
+}
+
+func newMockDALC(t *testing.T) (*countingDALC, *clock.Mock) {
+	t.Helper()
+	kvStore, err := store.NewDefaultInMemoryKVStore()
+	require.NoError(t, err)
+	clk := clock.NewMock()
+	dalc := &countingDALC{DataAvailabilityLayerClient: &mock.DataAvailabilityLayerClient{}}
+	dalc.SetClock(clk)
+	require.NoError(t, dalc.Init(types.Namespace{}, []byte(mockDABlockTime.String()), kvStore, testlog.NewFileLogger(t)))
+	return dalc, clk
+}
+
+func TestBatching(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	dalc, clk := newMockDALC(t)
+	shared, err := New(dalc, 100*time.Millisecond, testlog.NewFileLogger(t))
+	require.NoError(err)
+	require.NoError(shared.Start())
+	defer func() {
+		assert.NoError(shared.Stop())
+	}()
+
+	namespaces := []types.Namespace{
+		types.MustNewNamespace(types.NamespaceVersionZero, []byte{1}),
+		types.MustNewNamespace(types.NamespaceVersionZero, []byte{2}),
+		types.MustNewNamespace(types.NamespaceVersionZero, []byte{3}),
+	}
+	blocks := make(map[types.Namespace][]*types.Block)
+	results := make([]da.ResultSubmitBlocks, len(namespaces))
+	var wg sync.WaitGroup
+	for i, ns := range namespaces {
+		nsClient := shared.ForNamespace(ns)
+		require.NoError(nsClient.Init(ns, nil, nil, nil))
+		require.NoError(nsClient.Start())
+		blocks[ns] = []*types.Block{types.GetRandomBlock(1, 2), types.GetRandomBlock(2, 2)}
+		wg.Add(1)
+		go func(i int, c da.DataAvailabilityLayerClient, blocks []*types.Block) {
+			defer wg.Done()
+			results[i] = c.SubmitBlocks(context.Background(), blocks)
+		}(i, nsClient, blocks[ns])
+	}
+	wg.Wait()
+
+	// all namespaces were submitted in single batch
+	assert.EqualValues(1, dalc.submissions.Load())
+	for _, res := range results {
+		require.Equal(da.StatusSuccess, res.Code, res.Message)
+		assert.Equal(results[0].DAHeight, res.DAHeight)
+	}
+
+	// advance DA height, to make submitted blocks retrievable
+	require.Eventually(func() bool {
+		clk.Add(mockDABlockTime)
+		res := shared.ForNamespace(namespaces[0]).(da.BlockRetriever).RetrieveBlocks(context.Background(), results[0].DAHeight)
+		return res.Code == da.StatusSuccess
+	}, time.Second, 10*time.Millisecond)
+	for _, ns := range namespaces {
+		res := shared.ForNamespace(ns).(da.BlockRetriever).RetrieveBlocks(context.Background(), results[0].DAHeight)
+		require.Equal(da.StatusSuccess, res.Code, res.Message)
+		require.Len(res.Blocks, len(blocks[ns]))
+		for i, b := range res.Blocks {
+			assert.Equal(blocks[ns][i].Hash(), b.Hash())
+		}
+	}
+
+	// blocks of other namespaces are not returned
+	res := shared.ForNamespace(types.MustNewNamespace(types.NamespaceVersionZero, []byte{4})).(da.BlockRetriever).RetrieveBlocks(context.Background(), results[0].DAHeight)
+	require.Equal(da.StatusSuccess, res.Code)
+	assert.Empty(res.Blocks)
+}
+
+func TestSubmitCanceled(t *testing.T) {
+	dalc, _ := newMockDALC(t)
+	shared, err := New(dalc, time.Hour, testlog.NewFileLogger(t))
+	require.NoError(t, err)
+	require.NoError(t, shared.Start())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	res := shared.ForNamespace(types.MustNewNamespace(types.NamespaceVersionZero, []byte{1})).SubmitBlocks(ctx, []*types.Block{types.GetRandomBlock(1, 1)})
+	assert.Equal(t, da.StatusError, res.Code)
+
+	require.NoError(t, shared.Stop())
+	assert.Zero(t, dalc.submissions.Load())
+}
+
+func TestNotNamespaced(t *testing.T) {
+	_, err := New(&newda.NewDA{DA: test.NewDummyDA()}, time.Second, testlog.NewFileLogger(t))
+	assert.ErrorIs(t, err, ErrNotNamespaced)
+}
+
+func TestNamespaceMismatch(t *testing.T) {
+	dalc, _ := newMockDALC(t)
+	shared, err := New(dalc, time.Second, testlog.NewFileLogger(t))
+	require.NoError(t, err)
+	assert.Error(t, shared.ForNamespace(types.MustNewNamespace(types.NamespaceVersionZero, []byte{1})).Init(types.MustNewNamespace(types.NamespaceVersionZero, []byte{2}), nil, nil, nil))
+}
@@ -32,7 +32,7 @@ import (
 const mockDaBlockTime = 100 * time.Millisecond
 
 var (
-	testNamespaceID = types.NamespaceID{0, 1, 2, 3, 4, 5, 6, 7}
+	testNamespaceID = types.MustNewNamespace(types.NamespaceVersionZero, []byte{0, 1, 2, 3, 4, 5, 6, 7})
 
 	testConfig = celestia.Config{
 		Timeout:  15 * time.Second,
@@ -0,0 +1,158 @@
+package execution
+
+import (
+	"context"
+	"errors"
+
+	cmtypes "github.com/cometbft/cometbft/types"
+
+	"github.com/rollkit/rollkit/types"
+)
+
+// ErrSimulationNotSupported is returned when execution environment can't simulate block execution.
+var ErrSimulationNotSupported = errors.New("block simulation is not supported by execution environment")
+
+// ErrRollbackNotSupported is returned when execution environment can't roll back committed blocks.
+var ErrRollbackNotSupported = errors.New("rollback is not supported by execution environment")
+
+// Executor is the interface between Rollkit and the execution environment of the rollup (ABCI application,
+// EVM engine, etc).
+//
+// Block manager drives the execution exclusively through this interface: it initializes the chain once,
+// collects transactions for new blocks, executes blocks (produced or synced) and finally commits them.
+// Calls are never made concurrently, and for every height ExecuteTxs is followed by Commit.
+type Executor interface {
+	// InitChain initializes the execution environment using genesis document. It's called exactly once,
+	// before the first block is executed. Returned state is the initial state of the chain.
+	InitChain(ctx context.Context, genesis *cmtypes.GenesisDoc, state types.State) (types.State, error)
+
+	// GetTxs returns transactions that should be included in the next block. Limits defined in consensus
+	// parameters of state have to be respected.
+	GetTxs(ctx context.Context, state types.State) (types.Txs, error)
+
+	// ExecuteTxs executes transactions included in the block, without committing the results.
+	// Returned state reflects the execution of the block; its AppHash is set by Commit.
+	ExecuteTxs(ctx context.Context, state types.State, block *types.Block) (types.State, error)
+
+	// Commit finalizes the block previously executed with ExecuteTxs and returns the resulting app hash.
+	Commit(ctx context.Context, state types.State, block *types.Block) ([]byte, error)
+}
+
+// TxNotifier is an optional interface of Executor, used by lazy aggregator to produce blocks only when
+// transactions are available.
+type TxNotifier interface {
+	// TxsAvailable returns a channel that is signalled when new transactions are available.
+	TxsAvailable() <-chan struct{}
+}
+
+// PendingTxsReporter is an optional interface of Executor, used by aggregator with adaptive block time to
+// produce blocks early when enough transactions are waiting.
+type PendingTxsReporter interface {
+	// PendingTxs returns total size (in bytes) and total gas wanted of transactions waiting for inclusion
+	// in a block.
+	PendingTxs() (bytes int64, gas int64)
+}
+
+// Simulator is an optional interface of Executor, implemented by execution environments able to execute
+// a block without committing anything.
+type Simulator interface {
+	// SimulateTxs executes transactions of the block on top of the state in a throwaway branch, and returns
+	// results of all transactions. Neither the state of the execution environment nor the mempool is modified.
+	SimulateTxs(ctx context.Context, state types.State, block *types.Block) ([]TxResult, error)
+}
+
+// Finality is the finality level of a block.
+type Finality string
+
+const (
+	// FinalitySoft is the finality of blocks produced by aggregator or received from P2P network, not yet
+	// included in DA layer.
+	FinalitySoft Finality = "soft"
+	// FinalityDA is the finality of blocks included in DA layer.
+	FinalityDA Finality = "da"
+)
+
+type finalityKey struct{}
+
+// WithFinality returns context carrying finality level of the block being executed or committed.
+// Block manager sets it only in sync modes that distinguish finality levels (see config.SyncModeHybrid).
+func WithFinality(ctx context.Context, f Finality) context.Context {
+	return context.WithValue(ctx, finalityKey{}, f)
+}
+
+// FinalityFromContext returns finality level set by WithFinality.
+func FinalityFromContext(ctx context.Context) (Finality, bool) {
+	f, ok := ctx.Value(finalityKey{}).(Finality)
+	return f, ok
+}
+
+// FinalityNotifier is an optional interface of Executor, used in hybrid sync mode to notify the execution
+// environment that already committed block became DA-final.
+type FinalityNotifier interface {
+	// BlockFinalized is called once for every block committed with soft finality, after it's included in
+	// DA layer.
+	BlockFinalized(ctx context.Context, height uint64) error
+}
+
+// Rollbacker is an optional interface of Executor, used to roll back blocks committed with soft finality, when
+// DA layer orders different blocks at their heights. Executors that don't implement it can't recover from such
+// divergence.
+type Rollbacker interface {
+	// Rollback reverts the execution environment to the state right after committing block at given height.
+	// Blocks above DA-final height are rolled back only.
+	Rollback(ctx context.Context, height uint64) error
+}
+
+// Reorg describes blocks committed with soft finality, that were rolled back and replaced by blocks retrieved
+// from DA layer.
+type Reorg struct {
+	// Height is the height of the last block that was kept.
+	Height uint64
+	// Reverted contains hashes of rolled back blocks, in order of heights.
+	Reverted []types.Hash
+	// Canonical is the hash of block retrieved from DA layer, at the first rolled back height.
+	Canonical types.Hash
+	// DAHeight is the DA height of canonical block.
+	DAHeight uint64
+}
+
+// ReorgNotifier is an optional interface of Executor, notified about reorgs of soft blocks.
+type ReorgNotifier interface {
+	// BlocksReorged is called after the blocks were rolled back (see Rollbacker). Blocks retrieved from DA
+	// layer are executed and committed afterwards, as usual.
+	BlocksReorged(ctx context.Context, reorg Reorg) error
+}
+
+// TxResult is the result of transaction execution.
+type TxResult struct {
+	Code      uint32  `json:"code"`
+	Data      []byte  `json:"data"`
+	Log       string  `json:"log"`
+	Info      string  `json:"info"`
+	GasWanted int64   `json:"gas_wanted"`
+	GasUsed   int64   `json:"gas_used"`
+	Events    []Event `json:"events"`
+	Codespace string  `json:"codespace"`
+}
+
+// Event is an event emitted during transaction execution.
+type Event struct {
+	Type       string           `json:"type"`
+	Attributes []EventAttribute `json:"attributes"`
+}
+
+// EventAttribute is a key-value pair describing an Event.
+type EventAttribute struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+	Index bool   `json:"index"`
+}
+
+// SimulationResult is the result of simulated execution of a candidate block.
+type SimulationResult struct {
+	// Height of the candidate block
+	Height uint64 `json:"height"`
+	// GasUsed is the total gas used by all transactions
+	GasUsed   int64      `json:"gas_used"`
+	TxResults []TxResult `json:"tx_results"`
+}
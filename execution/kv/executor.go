@@ -0,0 +1,256 @@
+package kv
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/cometbft/cometbft/crypto/merkle"
+	cmbytes "github.com/cometbft/cometbft/libs/bytes"
+	cmtypes "github.com/cometbft/cometbft/types"
+
+	"github.com/rollkit/rollkit/execution"
+	"github.com/rollkit/rollkit/types"
+)
+
+const (
+	codeOK      = 0
+	codeInvalid = 1
+
+	// historySize is the number of most recent committed states kept for rollback
+	historySize = 1000
+)
+
+var _ execution.Executor = &Executor{}
+var _ execution.TxNotifier = &Executor{}
+var _ execution.Simulator = &Executor{}
+var _ execution.PendingTxsReporter = &Executor{}
+var _ execution.Rollbacker = &Executor{}
+
+// Executor is a reference, in-process implementation of execution.Executor.
+//
+// It's a simple key-value store. Valid transactions have "key=value" format, other transactions are included in
+// blocks but have no effect. Initial key-value pairs can be provided as JSON object in genesis app state.
+// App hash is the SHA-256 hash of all key-value pairs, in order of keys. States committed at last historySize
+// heights are kept, so blocks can be rolled back.
+type Executor struct {
+	mtx sync.Mutex
+
+	txs          types.Txs
+	txsAvailable chan struct{}
+
+	committed     map[string]string
+	pending       map[string]string
+	pendingHeight uint64
+
+	// history contains committed states by height; states are never modified, only replaced
+	history map[uint64]map[string]string
+}
+
+// NewExecutor creates new, empty Executor.
+func NewExecutor() *Executor {
+	return &Executor{
+		txsAvailable: make(chan struct{}, 1),
+		committed:    make(map[string]string),
+		history:      make(map[uint64]map[string]string),
+	}
+}
+
+// InjectTx adds transaction to the queue of transactions returned by GetTxs.
+func (e *Executor) InjectTx(tx types.Tx) {
+	e.mtx.Lock()
+	defer e.mtx.Unlock()
+	e.txs = append(e.txs, tx)
+	select {
+	case e.txsAvailable <- struct{}{}:
+	default:
+	}
+}
+
+// Get returns committed value for the key.
+func (e *Executor) Get(key string) (string, bool) {
+	e.mtx.Lock()
+	defer e.mtx.Unlock()
+	v, ok := e.committed[key]
+	return v, ok
+}
+
+// AppHash returns app hash of committed state.
+func (e *Executor) AppHash() []byte {
+	e.mtx.Lock()
+	defer e.mtx.Unlock()
+	return appHash(e.committed)
+}
+
+// TxsAvailable implements execution.TxNotifier.
+func (e *Executor) TxsAvailable() <-chan struct{} {
+	return e.txsAvailable
+}
+
+// PendingTxs implements execution.PendingTxsReporter. Gas wanted by transaction is equal to its size.
+func (e *Executor) PendingTxs() (int64, int64) {
+	e.mtx.Lock()
+	defer e.mtx.Unlock()
+	var size int64
+	for _, tx := range e.txs {
+		size += int64(len(tx))
+	}
+	return size, size
+}
+
+// InitChain loads initial key-value pairs from genesis app state.
+func (e *Executor) InitChain(ctx context.Context, genesis *cmtypes.GenesisDoc, state types.State) (types.State, error) {
+	e.mtx.Lock()
+	defer e.mtx.Unlock()
+
+	kv := make(map[string]string)
+	if len(genesis.AppState) > 0 {
+		if err := json.Unmarshal(genesis.AppState, &kv); err != nil {
+			return types.State{}, fmt.Errorf("invalid app state in genesis: %w", err)
+		}
+	}
+	e.committed = kv
+	// genesis state is the state "committed" at the height preceding initial height
+	genesisHeight := uint64(0)
+	if genesis.InitialHeight > 1 {
+		genesisHeight = uint64(genesis.InitialHeight - 1)
+	}
+	e.history[genesisHeight] = kv
+
+	state.AppHash = appHash(kv)
+	state.LastResultsHash = merkle.HashFromByteSlices(nil)
+	return state, nil
+}
+
+// GetTxs returns queued transactions, up to the maximum block size.
+func (e *Executor) GetTxs(ctx context.Context, state types.State) (types.Txs, error) {
+	e.mtx.Lock()
+	defer e.mtx.Unlock()
+
+	maxBytes := int64(-1)
+	if state.ConsensusParams.Block != nil && state.ConsensusParams.Block.MaxBytes > 0 {
+		maxBytes = state.ConsensusParams.Block.MaxBytes
+	}
+
+	var size int64
+	n := 0
+	for ; n < len(e.txs); n++ {
+		size += int64(len(e.txs[n]))
+		if maxBytes >= 0 && size > maxBytes {
+			break
+		}
+	}
+	txs := e.txs[:n:n]
+	e.txs = e.txs[n:]
+	return txs, nil
+}
+
+// ExecuteTxs applies transactions of the block to a copy of committed state.
+func (e *Executor) ExecuteTxs(ctx context.Context, state types.State, block *types.Block) (types.State, error) {
+	e.mtx.Lock()
+	defer e.mtx.Unlock()
+
+	kv, results := e.execute(block.Data.Txs)
+	e.pending = kv
+	e.pendingHeight = block.Height()
+
+	codes := make([][]byte, len(results))
+	for i, r := range results {
+		codes[i] = []byte{byte(r.Code)}
+	}
+	state.LastBlockHeight = block.Height()
+	state.LastBlockTime = block.Time()
+	state.LastBlockID = cmtypes.BlockID{Hash: cmbytes.HexBytes(block.Hash())}
+	state.LastResultsHash = merkle.HashFromByteSlices(codes)
+	return state, nil
+}
+
+// SimulateTxs executes transactions of the block on a copy of committed state, that is discarded afterwards.
+func (e *Executor) SimulateTxs(ctx context.Context, state types.State, block *types.Block) ([]execution.TxResult, error) {
+	e.mtx.Lock()
+	defer e.mtx.Unlock()
+
+	_, results := e.execute(block.Data.Txs)
+	return results, nil
+}
+
+// execute applies transactions to a copy of committed state. Gas used by transaction is equal to its size.
+func (e *Executor) execute(txs types.Txs) (map[string]string, []execution.TxResult) {
+	kv := make(map[string]string, len(e.committed))
+	for k, v := range e.committed {
+		kv[k] = v
+	}
+	results := make([]execution.TxResult, len(txs))
+	for i, tx := range txs {
+		key, value, ok := bytes.Cut(tx, []byte("="))
+		if !ok || len(key) == 0 {
+			results[i] = execution.TxResult{Code: codeInvalid, Log: "invalid transaction format, expected key=value"}
+			continue
+		}
+		kv[string(key)] = string(value)
+		results[i] = execution.TxResult{
+			Code:      codeOK,
+			GasWanted: int64(len(tx)),
+			GasUsed:   int64(len(tx)),
+			Events: []execution.Event{{
+				Type:       "kv",
+				Attributes: []execution.EventAttribute{{Key: "key", Value: string(key), Index: true}},
+			}},
+		}
+	}
+	return kv, results
+}
+
+// Commit makes the results of last executed block the committed state.
+func (e *Executor) Commit(ctx context.Context, state types.State, block *types.Block) ([]byte, error) {
+	e.mtx.Lock()
+	defer e.mtx.Unlock()
+
+	if e.pending == nil || e.pendingHeight != block.Height() {
+		return nil, fmt.Errorf("block at height %d was not executed", block.Height())
+	}
+	e.committed = e.pending
+	e.pending = nil
+	e.history[e.pendingHeight] = e.committed
+	if e.pendingHeight >= historySize {
+		delete(e.history, e.pendingHeight-historySize)
+	}
+	return appHash(e.committed), nil
+}
+
+// Rollback implements execution.Rollbacker. State committed at given height becomes the committed state.
+func (e *Executor) Rollback(ctx context.Context, height uint64) error {
+	e.mtx.Lock()
+	defer e.mtx.Unlock()
+
+	kv, ok := e.history[height]
+	if !ok {
+		return fmt.Errorf("state at height %d is not available", height)
+	}
+	for h := range e.history {
+		if h > height {
+			delete(e.history, h)
+		}
+	}
+	e.committed = kv
+	e.pending = nil
+	return nil
+}
+
+func appHash(kv map[string]string) []byte {
+	keys := make([]string, 0, len(kv))
+	for k := range kv {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	h := sha256.New()
+	for _, k := range keys {
+		_, _ = fmt.Fprintf(h, "%d:%s%d:%s", len(k), k, len(kv[k]), kv[k])
+	}
+	return h.Sum(nil)
+}
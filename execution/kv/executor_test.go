@@ -0,0 +1,109 @@
+package kv
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	cmproto "github.com/cometbft/cometbft/proto/tendermint/types"
+	cmtypes "github.com/cometbft/cometbft/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rollkit/rollkit/types"
+)
+
+func TestExecutor(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	ctx := context.Background()
+
+	exec := NewExecutor()
+	genesis := &cmtypes.GenesisDoc{ChainID: "test", AppState: []byte(`{"a":"1"}`)}
+	state, err := exec.InitChain(ctx, genesis, types.State{
+		ConsensusParams: cmproto.ConsensusParams{Block: &cmproto.BlockParams{MaxBytes: 10}},
+	})
+	require.NoError(err)
+	assert.Equal(types.Hash(exec.AppHash()), state.AppHash)
+	v, ok := exec.Get("a")
+	assert.True(ok)
+	assert.Equal("1", v)
+
+	exec.InjectTx(types.Tx("b=2"))
+	exec.InjectTx(types.Tx("invalid"))
+	exec.InjectTx(types.Tx("c=3"))
+	select {
+	case <-exec.TxsAvailable():
+	default:
+		t.Fatal("expected notification about available transactions")
+	}
+
+	// only first two transactions fit in 10 bytes
+	txs, err := exec.GetTxs(ctx, state)
+	require.NoError(err)
+	assert.Equal(types.Txs{types.Tx("b=2"), types.Tx("invalid")}, txs)
+
+	block := &types.Block{}
+	block.SignedHeader.BaseHeader.Height = 1
+	block.Data.Txs = txs
+
+	_, err = exec.Commit(ctx, state, block)
+	assert.Error(err)
+
+	oldHash := exec.AppHash()
+	newState, err := exec.ExecuteTxs(ctx, state, block)
+	require.NoError(err)
+	assert.Equal(uint64(1), newState.LastBlockHeight)
+	assert.NotEqual(state.LastResultsHash, newState.LastResultsHash)
+
+	// results are not visible before commit
+	_, ok = exec.Get("b")
+	assert.False(ok)
+	assert.Equal(oldHash, exec.AppHash())
+
+	appHash, err := exec.Commit(ctx, newState, block)
+	require.NoError(err)
+	assert.Equal(exec.AppHash(), appHash)
+	assert.NotEqual(oldHash, appHash)
+	v, ok = exec.Get("b")
+	assert.True(ok)
+	assert.Equal("2", v)
+
+	txs, err = exec.GetTxs(ctx, newState)
+	require.NoError(err)
+	assert.Equal(types.Txs{types.Tx("c=3")}, txs)
+}
+
+func TestRollback(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	ctx := context.Background()
+
+	exec := NewExecutor()
+	genesis := &cmtypes.GenesisDoc{ChainID: "test", InitialHeight: 1, AppState: []byte(`{"a":"1"}`)}
+	state, err := exec.InitChain(ctx, genesis, types.State{})
+	require.NoError(err)
+	genesisHash := exec.AppHash()
+
+	hashes := make([][]byte, 3)
+	for i := range hashes {
+		block := &types.Block{}
+		block.SignedHeader.BaseHeader.Height = uint64(i + 1)
+		block.Data.Txs = types.Txs{types.Tx(fmt.Sprintf("a=%d", i+2))}
+		state, err = exec.ExecuteTxs(ctx, state, block)
+		require.NoError(err)
+		hashes[i], err = exec.Commit(ctx, state, block)
+		require.NoError(err)
+	}
+
+	require.NoError(exec.Rollback(ctx, 1))
+	assert.Equal(hashes[0], exec.AppHash())
+	v, _ := exec.Get("a")
+	assert.Equal("2", v)
+
+	// rolled back states are not available
+	assert.Error(exec.Rollback(ctx, 2))
+
+	require.NoError(exec.Rollback(ctx, 0))
+	assert.Equal(genesisHash, exec.AppHash())
+}
@@ -44,6 +44,7 @@ type TxMempool struct {
 
 	// Atomically-updated fields
 	txsBytes  int64 // atomic: the total size of all transactions in the mempool, in bytes
+	txsGas    int64 // atomic: the total gas wanted by all transactions in the mempool
 	txRecheck int64 // atomic: the number of pending recheck calls
 
 	// Synchronized fields, protected by mtx.
@@ -126,6 +127,10 @@ func (txmp *TxMempool) Size() int { return txmp.txs.Len() }
 // mempool. It is thread-safe.
 func (txmp *TxMempool) SizeBytes() int64 { return atomic.LoadInt64(&txmp.txsBytes) }
 
+// GasWanted returns the total gas wanted by all the valid transactions in the
+// mempool. It is thread-safe.
+func (txmp *TxMempool) GasWanted() int64 { return atomic.LoadInt64(&txmp.txsGas) }
+
 // FlushAppConn executes FlushSync on the mempool's proxyAppConn.
 //
 // The caller must hold an exclusive mempool lock (by calling txmp.Lock) before
@@ -265,6 +270,7 @@ func (txmp *TxMempool) removeTxByKey(key types.TxKey) error {
 		elt.DetachPrev()
 		elt.DetachNext()
 		atomic.AddInt64(&txmp.txsBytes, -w.Size())
+		atomic.AddInt64(&txmp.txsGas, -w.GasWanted())
 		return nil
 	}
 	return fmt.Errorf("transaction %x not found", key)
@@ -620,6 +626,7 @@ func (txmp *TxMempool) insertTx(wtx *WrappedTx) {
 	}
 
 	atomic.AddInt64(&txmp.txsBytes, wtx.Size())
+	atomic.AddInt64(&txmp.txsGas, wtx.GasWanted())
 }
 
 // handleRecheckResult handles the responses from ABCI CheckTx calls issued
@@ -0,0 +1,79 @@
+package node
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"github.com/cometbft/cometbft/proxy"
+)
+
+// onAppDisconnect pauses block production and syncing when connection to ABCI application is lost.
+func (n *FullNode) onAppDisconnect(err error) {
+	n.Logger.Error("connection to ABCI application lost, pausing block production and sync", "error", err)
+	n.blockManager.Pause()
+}
+
+// onAppReconnect verifies state of the application after reconnection, and resumes block production and syncing.
+//
+// If application is behind the node, committed blocks are replayed. If application state is not consistent with
+// node state, node is halted.
+func (n *FullNode) onAppReconnect() {
+	if err := n.appHandshake(n.ctx); err != nil {
+		n.Logger.Error("ABCI application state is inconsistent with node state, halting node", "error", err)
+		go func() {
+			if err := n.Stop(); err != nil {
+				n.Logger.Error("failed to stop node", "error", err)
+			}
+		}()
+		return
+	}
+	n.Logger.Info("reconnected to ABCI application, resuming block production and sync")
+	n.blockManager.Resume()
+}
+
+// appHandshake checks if height and app hash reported by ABCI application match the node state.
+// It's performed when node is started and after reconnection to the application.
+//
+// If application is behind the node (e.g. it lost recently committed blocks on crash), missing blocks are
+// replayed from the store, and application is queried again. Application ahead of the node can't be fixed.
+func (n *FullNode) appHandshake(ctx context.Context) error {
+	res, err := n.proxyApp.Query().InfoSync(proxy.RequestInfo)
+	if err != nil {
+		return fmt.Errorf("ABCI Info request failed: %w", err)
+	}
+	if res.LastBlockHeight < 0 {
+		return fmt.Errorf("application reported invalid height (%d)", res.LastBlockHeight)
+	}
+	state := n.blockManager.GetLastState()
+	appHeight, height := uint64(res.LastBlockHeight), state.LastBlockHeight
+	// application reports height 0 until the first block is committed, regardless of initial height
+	if height < uint64(n.genesis.InitialHeight) {
+		height = 0
+	}
+	if appHeight > height {
+		return fmt.Errorf("application height (%d) is above node height (%d); application committed state without "+
+			"the node - restore application data or reset the node", appHeight, height)
+	}
+	if appHeight < height {
+		n.Logger.Info("ABCI application is behind the node, replaying blocks", "appHeight", appHeight, "height", height)
+		if err := n.blockManager.ReplayBlocks(ctx, appHeight); err != nil {
+			return fmt.Errorf("failed to replay blocks: %w", err)
+		}
+		if res, err = n.proxyApp.Query().InfoSync(proxy.RequestInfo); err != nil {
+			return fmt.Errorf("ABCI Info request failed: %w", err)
+		}
+		if res.LastBlockHeight < 0 || uint64(res.LastBlockHeight) != height {
+			return fmt.Errorf("application height (%d) doesn't match node height (%d) after replaying blocks",
+				res.LastBlockHeight, height)
+		}
+	}
+	if height == 0 {
+		return nil
+	}
+	if !bytes.Equal(res.LastBlockAppHash, state.AppHash) {
+		return fmt.Errorf("application hash (%X) doesn't match node app hash (%X) at height %d",
+			res.LastBlockAppHash, state.AppHash, height)
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+package node
+
+import (
+	"context"
+	"crypto/rand"
+	"testing"
+
+	abci "github.com/cometbft/cometbft/abci/types"
+	"github.com/cometbft/cometbft/libs/log"
+	"github.com/cometbft/cometbft/proxy"
+	cmtypes "github.com/cometbft/cometbft/types"
+	"github.com/libp2p/go-libp2p/core/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rollkit/rollkit/config"
+	"github.com/rollkit/rollkit/store"
+	"github.com/rollkit/rollkit/test/mocks"
+	"github.com/rollkit/rollkit/types"
+)
+
+func TestAppHandshake(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	ctx := context.Background()
+
+	app := &mocks.Application{}
+	app.On(InitChain, mock.Anything).Return(abci.ResponseInitChain{})
+	call := app.On(Info, mock.Anything).Return(abci.ResponseInfo{LastBlockHeight: 0})
+	key, _, _ := crypto.GenerateEd25519Key(rand.Reader)
+	genesisValidators, signingKey := types.GetGenesisValidatorSetWithSigner()
+	genesis := &cmtypes.GenesisDoc{ChainID: "test", Validators: genesisValidators}
+	node, err := newFullNode(ctx, config.NodeConfig{DALayer: "newda"}, key, signingKey, proxy.NewLocalClientCreator(app), genesis, log.TestingLogger())
+	require.NoError(err)
+
+	// fresh node and application
+	assert.NoError(node.appHandshake(ctx))
+
+	// application is ahead of the node
+	call.Return(abci.ResponseInfo{LastBlockHeight: 5})
+	assert.ErrorContains(node.appHandshake(ctx), "is above node height")
+}
+
+func TestAppHandshakeReplay(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	ctx := context.Background()
+
+	genesisValidators, signingKey := types.GetGenesisValidatorSetWithSigner()
+	genesis := &cmtypes.GenesisDoc{ChainID: "test", InitialHeight: 1, Validators: genesisValidators}
+
+	// node committed 2 blocks before restart
+	conf := config.NodeConfig{RootDir: t.TempDir(), DBPath: "data", DALayer: "newda"}
+	kv, err := store.NewDefaultKVStore(conf.RootDir, conf.DBPath, "rollkit")
+	require.NoError(err)
+	s := store.New(ctx, newPrefixKV(kv, mainPrefix))
+	valSet := cmtypes.NewValidatorSet([]*cmtypes.Validator{
+		cmtypes.NewValidator(genesisValidators[0].PubKey, genesisValidators[0].Power),
+	})
+	appHash := types.Hash{1, 2, 3}
+	for h := uint64(1); h <= 2; h++ {
+		// block hash is empty without validator hash, so the block couldn't be indexed
+		block := types.GetRandomBlock(h, 1)
+		block.SignedHeader.BaseHeader.ChainID = genesis.ChainID
+		block.SignedHeader.Validators = valSet
+		block.SignedHeader.ValidatorHash = valSet.Hash()
+		block.SignedHeader.ProposerAddress = valSet.Proposer.Address
+		require.NoError(s.SaveBlock(block, &types.Commit{}))
+	}
+	state, err := types.NewFromGenesisDoc(genesis)
+	require.NoError(err)
+	state.LastBlockHeight = 2
+	state.AppHash = appHash
+	require.NoError(s.UpdateState(state))
+	require.NoError(kv.Close())
+
+	app := &mocks.Application{}
+	app.On(BeginBlock, mock.Anything).Return(abci.ResponseBeginBlock{})
+	app.On(DeliverTx, mock.Anything).Return(abci.ResponseDeliverTx{})
+	app.On(EndBlock, mock.Anything).Return(abci.ResponseEndBlock{})
+	app.On(Commit, mock.Anything).Return(abci.ResponseCommit{Data: appHash})
+	key, _, _ := crypto.GenerateEd25519Key(rand.Reader)
+	node, err := newFullNode(ctx, conf, key, signingKey, proxy.NewLocalClientCreator(app), genesis, log.TestingLogger())
+	require.NoError(err)
+
+	// heights match, app hashes differ - node refuses to start
+	call := app.On(Info, mock.Anything).Return(abci.ResponseInfo{LastBlockHeight: 2, LastBlockAppHash: []byte{3, 2, 1}})
+	assert.ErrorContains(node.Start(), "doesn't match node app hash")
+	app.AssertNotCalled(t, EndBlock, mock.Anything)
+
+	// application lost the last block, it's replayed on start
+	call.Return(abci.ResponseInfo{LastBlockHeight: 1, LastBlockAppHash: []byte{3, 2, 1}}).Once()
+	app.On(Info, mock.Anything).Return(abci.ResponseInfo{LastBlockHeight: 2, LastBlockAppHash: appHash})
+	require.NoError(node.Start())
+	defer func() {
+		assert.NoError(node.Stop())
+	}()
+	app.AssertCalled(t, EndBlock, abci.RequestEndBlock{Height: 2})
+	app.AssertNumberOfCalls(t, Commit, 1)
+}
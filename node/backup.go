@@ -0,0 +1,99 @@
+package node
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/cometbft/cometbft/libs/log"
+	ds "github.com/ipfs/go-datastore"
+	dsq "github.com/ipfs/go-datastore/query"
+	"go.uber.org/multierr"
+
+	"github.com/rollkit/rollkit/config"
+	"github.com/rollkit/rollkit/store"
+)
+
+// ErrRestoreNotEmpty is returned when backup is restored into datastore that already contains data.
+var ErrRestoreNotEmpty = errors.New("datastore is not empty, reset node data before restoring backup")
+
+// Backup writes consistent snapshot of all node data (blocks, state, DA, indexer, sync and P2P data) to w.
+// Node may be running during backup.
+func (n *FullNode) Backup(w io.Writer) error {
+	return store.Backup(n.baseKV, w)
+}
+
+// Backup writes consistent snapshot of all light node data to w. Node may be running during backup.
+func (ln *LightNode) Backup(w io.Writer) error {
+	return store.Backup(ln.datastore, w)
+}
+
+// Restore loads node data from backup created with Backup into the datastore configured in conf.
+//
+// Datastore has to be empty and node using it can't be running. Restored data of full node is validated (see
+// store.Verify); if blocks and state are inconsistent, restored data is removed, so node can't start from it.
+func Restore(ctx context.Context, conf config.NodeConfig, r io.Reader, logger log.Logger) error {
+	if conf.RootDir == "" && conf.DBPath == "" {
+		return errors.New("node works in in-memory mode, backup can't be restored")
+	}
+	dbName := "rollkit"
+	if conf.Light {
+		dbName = "rollkit-light"
+	}
+	kv, err := store.NewKVStore(conf.RootDir, conf.DBPath, dbName, conf.DBConfig)
+	if err != nil {
+		return fmt.Errorf("failed to open datastore: %w", err)
+	}
+	err = restoreDatastore(ctx, kv, r, !conf.Light)
+	if closeErr := kv.Close(); closeErr != nil {
+		err = multierr.Append(err, fmt.Errorf("failed to close datastore: %w", closeErr))
+	}
+	if err != nil {
+		return err
+	}
+	logger.Info("node data restored", "datastore", dbName)
+	return nil
+}
+
+func restoreDatastore(ctx context.Context, kv ds.TxnDatastore, r io.Reader, validate bool) error {
+	results, err := kv.Query(ctx, dsq.Query{KeysOnly: true, Limit: 1})
+	if err != nil {
+		return fmt.Errorf("failed to query datastore: %w", err)
+	}
+	entries, err := results.Rest()
+	if err != nil {
+		return fmt.Errorf("failed to query datastore: %w", err)
+	}
+	if len(entries) > 0 {
+		return ErrRestoreNotEmpty
+	}
+
+	if err := store.Restore(kv, r); err != nil {
+		return fmt.Errorf("failed to restore backup: %w", err)
+	}
+	if !validate {
+		return nil
+	}
+	if err := validateRestoredData(ctx, kv); err != nil {
+		if _, resetErr := ResetDatastore(ctx, kv, ResetOptions{}); resetErr != nil {
+			return multierr.Append(fmt.Errorf("restored data is inconsistent: %w", err), resetErr)
+		}
+		return fmt.Errorf("restored data is inconsistent and was removed: %w", err)
+	}
+	return nil
+}
+
+// validateRestoredData checks consistency of restored blocks and state with store.Verify. Index-only problems
+// are not repaired, restored data has to match the backup.
+func validateRestoredData(ctx context.Context, kv ds.TxnDatastore) error {
+	report, err := store.Verify(ctx, newPrefixKV(kv, mainPrefix), store.VerifyOptions{})
+	if err != nil {
+		return err
+	}
+	if report.OK() {
+		return nil
+	}
+	p := report.Problems[0]
+	return fmt.Errorf("%d problems found, first at height %d: %s: %s", len(report.Problems), p.Height, p.Kind, p.Message)
+}
@@ -0,0 +1,108 @@
+package node
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	cmbytes "github.com/cometbft/cometbft/libs/bytes"
+	"github.com/cometbft/cometbft/libs/log"
+	cmstate "github.com/cometbft/cometbft/proto/tendermint/state"
+	cmtypes "github.com/cometbft/cometbft/types"
+	ds "github.com/ipfs/go-datastore"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rollkit/rollkit/config"
+	"github.com/rollkit/rollkit/store"
+	"github.com/rollkit/rollkit/types"
+)
+
+// fillNodeDatastore saves signed block at height 1 with matching state, and data of other node components.
+func fillNodeDatastore(t *testing.T, kv ds.TxnDatastore, stateHash []byte) {
+	ctx := context.Background()
+	s := store.New(ctx, newPrefixKV(kv, mainPrefix))
+	valSet, privKey := types.GetRandomValidatorSetWithPrivKey()
+	block := types.GetRandomBlock(1, 10)
+	block.SignedHeader.Validators = valSet
+	block.SignedHeader.ValidatorHash = valSet.Hash()
+	block.SignedHeader.ProposerAddress = valSet.Proposer.Address
+	dataHash, err := block.Data.Hash()
+	require.NoError(t, err)
+	block.SignedHeader.DataHash = dataHash
+	signature, err := privKey.Sign(block.SignedHeader.Header.MakeCometBFTVote())
+	require.NoError(t, err)
+	block.SignedHeader.Commit = types.Commit{Signatures: []types.Signature{signature}}
+	require.NoError(t, s.SaveBlock(block, &block.SignedHeader.Commit))
+	require.NoError(t, s.SaveBlockResponses(1, &cmstate.ABCIResponses{}))
+	if stateHash == nil {
+		stateHash = block.Hash()
+	}
+	require.NoError(t, s.UpdateState(types.State{
+		ChainID:         block.SignedHeader.ChainID(),
+		InitialHeight:   1,
+		LastBlockHeight: 1,
+		LastBlockID:     cmtypes.BlockID{Hash: cmbytes.HexBytes(stateHash)},
+	}))
+	for _, k := range resetTestKeys[1:] { // resetTestKeys[0] is the state key
+		require.NoError(t, kv.Put(ctx, ds.NewKey(k), []byte(k)))
+	}
+}
+
+func TestBackupRestore(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	ctx := context.Background()
+
+	src, err := store.NewDefaultInMemoryKVStore()
+	require.NoError(err)
+	fillNodeDatastore(t, src, nil)
+
+	var backup bytes.Buffer
+	require.NoError(store.Backup(src, &backup))
+
+	conf := config.NodeConfig{RootDir: t.TempDir(), DBPath: "data"}
+	require.NoError(Restore(ctx, conf, bytes.NewReader(backup.Bytes()), log.TestingLogger()))
+
+	dst, err := store.NewDefaultKVStore(conf.RootDir, conf.DBPath, "rollkit")
+	require.NoError(err)
+	defer func() {
+		assert.NoError(dst.Close())
+	}()
+	assert.Equal(resetTestKeys[1:], remainingKeys(t, dst))
+	state, err := store.New(ctx, newPrefixKV(dst, mainPrefix)).GetState()
+	require.NoError(err)
+	assert.Equal(uint64(1), state.LastBlockHeight)
+
+	// datastore is not empty anymore
+	err = restoreDatastore(ctx, dst, bytes.NewReader(backup.Bytes()), true)
+	assert.ErrorIs(err, ErrRestoreNotEmpty)
+}
+
+func TestRestoreInconsistentData(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	ctx := context.Background()
+
+	src, err := store.NewDefaultInMemoryKVStore()
+	require.NoError(err)
+	fillNodeDatastore(t, src, []byte("not a hash of the latest block"))
+	var backup bytes.Buffer
+	require.NoError(store.Backup(src, &backup))
+
+	dst, err := store.NewDefaultInMemoryKVStore()
+	require.NoError(err)
+	err = restoreDatastore(ctx, dst, &backup, true)
+	assert.Error(err)
+	assert.Empty(remainingKeys(t, dst))
+}
+
+func TestBackupNotSupported(t *testing.T) {
+	kv, err := store.NewKVStore(t.TempDir(), "data", "rollkit", config.DBConfig{DBBackend: config.DBBackendLevelDB})
+	require.NoError(t, err)
+	defer func() {
+		assert.NoError(t, kv.Close())
+	}()
+	assert.ErrorIs(t, store.Backup(kv, &bytes.Buffer{}), store.ErrBackupNotSupported)
+	assert.ErrorIs(t, store.Restore(kv, &bytes.Buffer{}), store.ErrBackupNotSupported)
+}
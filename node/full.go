@@ -2,10 +2,12 @@ package node
 
 import (
 	"context"
-	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	ds "github.com/ipfs/go-datastore"
 	ktds "github.com/ipfs/go-datastore/keytransform"
@@ -21,18 +23,22 @@ import (
 	rpcclient "github.com/cometbft/cometbft/rpc/client"
 	cmtypes "github.com/cometbft/cometbft/types"
 
+	"github.com/rollkit/rollkit/appconn"
 	"github.com/rollkit/rollkit/block"
 	"github.com/rollkit/rollkit/config"
 	"github.com/rollkit/rollkit/da"
 	"github.com/rollkit/rollkit/da/registry"
+	"github.com/rollkit/rollkit/execution"
 	"github.com/rollkit/rollkit/mempool"
 	mempoolv1 "github.com/rollkit/rollkit/mempool/v1"
 	"github.com/rollkit/rollkit/p2p"
+	"github.com/rollkit/rollkit/state"
 	"github.com/rollkit/rollkit/state/indexer"
 	blockidxkv "github.com/rollkit/rollkit/state/indexer/block/kv"
 	"github.com/rollkit/rollkit/state/txindex"
 	"github.com/rollkit/rollkit/state/txindex/kv"
 	"github.com/rollkit/rollkit/store"
+	rollkitlog "github.com/rollkit/rollkit/third_party/log"
 )
 
 // prefixes used in KV store to separate main node data from DALC data
@@ -43,6 +49,9 @@ var (
 )
 
 const (
+	// defaultShutdownTimeout is used only if ShutdownTimeout is not configured
+	defaultShutdownTimeout = 30 * time.Second
+
 	// genesisChunkSize is the maximum size, in bytes, of each
 	// chunk in the genesis structure for the chunked API
 	genesisChunkSize = 16 * 1024 * 1024 // 16 MiB
@@ -50,16 +59,29 @@ const (
 
 var _ Node = &FullNode{}
 
+// ErrNodeStopping is returned when transaction is submitted to the node that is shutting down.
+var ErrNodeStopping = errors.New("node is shutting down, transactions are not accepted")
+
 // FullNode represents a client node in Rollkit network.
 // It connects all the components and orchestrates their work.
 type FullNode struct {
 	service.BaseService
 
 	genesis *cmtypes.GenesisDoc
-	// cache of chunked genesis data.
-	genChunks []string
+	// genesisFile is the path of genesis file, if node was started from file (see NewNodeFromGenesisFile).
+	genesisFile string
+	// genesisExt is the genesis extension applied to configuration when the node was created, if any.
+	genesisExt *GenesisExtension
+	// chunked genesis, served from genesis file or serialized genesis doc.
+	genChunks *genesisChunks
 
 	nodeConfig config.NodeConfig
+	// reloadable holds current values of options that can be changed at runtime (see Reload)
+	reloadable   config.ReloadableConfig
+	confMtx      sync.Mutex
+	reloadHooks  []func(config.NodeConfig)
+	configLoader func() (config.NodeConfig, error)
+	logFilter    *rollkitlog.LevelFilter
 
 	proxyApp     proxy.AppConns
 	eventBus     *cmtypes.EventBus
@@ -71,6 +93,7 @@ type FullNode struct {
 	Mempool      mempool.Mempool
 	mempoolIDs   *mempoolIDs
 	Store        store.Store
+	executor     *state.BlockExecutor
 	blockManager *block.Manager
 	client       rpcclient.Client
 
@@ -79,10 +102,18 @@ type FullNode struct {
 	BlockIndexer   indexer.BlockIndexer
 	IndexerService *txindex.IndexerService
 
+	baseKV ds.TxnDatastore
+
 	// keep context here only because of API compatibility
 	// - it's used in `OnStart` (defined in service.Service interface)
 	ctx    context.Context
 	cancel context.CancelFunc
+
+	// aggCancel stops block production; aggWg is used to wait for aggregator goroutines to finish
+	aggCancel context.CancelFunc
+	aggWg     sync.WaitGroup
+	// stopping is set when node is shutting down and should not accept new transactions
+	stopping atomic.Bool
 }
 
 // newFullNode creates a new Rollkit full node.
@@ -95,31 +126,67 @@ func newFullNode(
 	genesis *cmtypes.GenesisDoc,
 	logger log.Logger,
 ) (*FullNode, error) {
-	proxyApp, err := initProxyApp(clientCreator, logger)
+	return newFullNodeWithResources(ctx, nodeConfig, p2pKey, signingKey, clientCreator, genesis, "", nil, logger)
+}
+
+// newFullNodeWithResources creates a new Rollkit full node. If shared is not nil, node uses datastore, DA layer
+// client and libp2p host shared with other chains (see Host), instead of creating its own. If genesisFile is not
+// empty, genesis is the document read from the file without app state; app state is read from the file only when
+// it's needed.
+func newFullNodeWithResources(
+	ctx context.Context,
+	nodeConfig config.NodeConfig,
+	p2pKey crypto.PrivKey,
+	signingKey crypto.PrivKey,
+	clientCreator proxy.ClientCreator,
+	genesis *cmtypes.GenesisDoc,
+	genesisFile string,
+	shared *sharedResources,
+	logger log.Logger,
+) (*FullNode, error) {
+	if nodeConfig.LogSampleInterval > 0 {
+		logger = rollkitlog.NewSampler(logger, nodeConfig.LogSampleInterval)
+	}
+	logFilter, err := rollkitlog.NewLevelFilter(logger, nodeConfig.LogLevel)
 	if err != nil {
 		return nil, err
 	}
+	logger = logFilter
 
-	eventBus, err := initEventBus(logger)
+	proxyApp, err := initProxyApp(clientCreator, logger)
 	if err != nil {
 		return nil, err
 	}
 
-	baseKV, err := initBaseKV(nodeConfig, logger)
+	eventBus, err := initEventBus(logger)
 	if err != nil {
 		return nil, err
 	}
 
-	dalcKV := newPrefixKV(baseKV, dalcPrefix)
-	dalc, err := initDALC(nodeConfig, dalcKV, logger)
-	if err != nil {
-		return nil, err
+	var (
+		baseKV ds.TxnDatastore
+		dalc   da.DataAvailabilityLayerClient
+	)
+	if shared != nil {
+		baseKV = shared.chainKV(genesis.ChainID)
+		dalc = shared.dalc.ForNamespace(nodeConfig.Namespace)
+	} else {
+		if baseKV, err = initBaseKV(nodeConfig, logger); err != nil {
+			return nil, err
+		}
+		dalcKV := newPrefixKV(baseKV, dalcPrefix)
+		if dalc, err = initDALC(nodeConfig, dalcKV, logger); err != nil {
+			return nil, err
+		}
 	}
 
-	p2pClient, err := p2p.NewClient(nodeConfig.P2P, p2pKey, genesis.ChainID, baseKV, logger.With("module", "p2p"))
+	p2pClient, err := p2p.NewClient(nodeConfig.P2P, p2pKey, genesis.ChainID, nodeConfig.Namespace, baseKV, logger.With("module", "p2p"))
 	if err != nil {
 		return nil, err
 	}
+	if shared != nil {
+		p2pClient.SetSharedHost(shared.p2p)
+	}
 
 	mainKV := newPrefixKV(baseKV, mainPrefix)
 	headerSyncService, err := initHeaderSyncService(ctx, mainKV, nodeConfig, genesis, p2pClient, logger)
@@ -134,8 +201,12 @@ func newFullNode(
 
 	mempool := initMempool(logger, proxyApp)
 
-	store := store.New(ctx, mainKV)
-	blockManager, err := initBlockManager(signingKey, nodeConfig, genesis, store, mempool, proxyApp, dalc, eventBus, logger, blockSyncService)
+	store, err := initStore(ctx, mainKV, nodeConfig)
+	if err != nil {
+		return nil, err
+	}
+	executor := initBlockExecutor(genesis, genesisFile, store, mempool, proxyApp, eventBus, logger)
+	blockManager, err := initBlockManager(signingKey, nodeConfig, genesis, store, executor, dalc, logger, blockSyncService)
 	if err != nil {
 		return nil, err
 	}
@@ -146,14 +217,24 @@ func newFullNode(
 		return nil, err
 	}
 
+	genChunks := newGenesisChunks(genesis)
+	if genesisFile != "" {
+		genChunks = newFileGenesisChunks(genesisFile)
+	}
+
 	ctx, cancel := context.WithCancel(ctx)
 
 	node := &FullNode{
 		proxyApp:       proxyApp,
 		eventBus:       eventBus,
 		genesis:        genesis,
+		genesisFile:    genesisFile,
+		genChunks:      genChunks,
 		nodeConfig:     nodeConfig,
+		reloadable:     nodeConfig.Reloadable(),
+		logFilter:      logFilter,
 		p2pClient:      p2pClient,
+		executor:       executor,
 		blockManager:   blockManager,
 		dalc:           dalc,
 		Mempool:        mempool,
@@ -164,19 +245,37 @@ func newFullNode(
 		BlockIndexer:   blockIndexer,
 		hSyncService:   headerSyncService,
 		bSyncService:   blockSyncService,
+		baseKV:         baseKV,
 		ctx:            ctx,
 		cancel:         cancel,
 	}
 
 	node.BaseService = *service.NewBaseService(logger, "Node", node)
+	blockManager.OnReorg(node.rollbackReorged)
 	node.p2pClient.SetTxValidator(node.newTxValidator())
+	proxyApp.OnDisconnect(node.onAppDisconnect)
+	proxyApp.OnReconnect(node.onAppReconnect)
 	node.client = NewFullClient(node)
 
 	return node, nil
 }
 
-func initProxyApp(clientCreator proxy.ClientCreator, logger log.Logger) (proxy.AppConns, error) {
-	proxyApp := proxy.NewAppConns(clientCreator, proxy.NopMetrics())
+// rollbackReorged removes soft blocks rolled back by block manager from P2P header and block stores, and from
+// indexers. Indexer service is flushed first, so reverted blocks still queued in it are not indexed afterwards.
+func (n *FullNode) rollbackReorged(ctx context.Context, reorg execution.Reorg) error {
+	err := n.hSyncService.Rollback(ctx, reorg.Height)
+	err = multierr.Append(err, n.bSyncService.Rollback(ctx, reorg.Height))
+	if flushErr := n.IndexerService.Flush(ctx); flushErr != nil {
+		return multierr.Append(err, fmt.Errorf("failed to flush indexer service: %w", flushErr))
+	}
+	from, to := int64(reorg.Height)+1, int64(reorg.Height)+int64(len(reorg.Reverted))
+	err = multierr.Append(err, n.TxIndexer.DeleteHeights(from, to))
+	err = multierr.Append(err, n.BlockIndexer.DeleteHeights(from, to))
+	return err
+}
+
+func initProxyApp(clientCreator proxy.ClientCreator, logger log.Logger) (*appconn.AppConns, error) {
+	proxyApp := appconn.NewAppConns(clientCreator, proxy.NopMetrics())
 	proxyApp.SetLogger(logger.With("module", "proxy"))
 	if err := proxyApp.Start(); err != nil {
 		return nil, fmt.Errorf("error while starting proxy app connections: %v", err)
@@ -196,10 +295,23 @@ func initEventBus(logger log.Logger) (*cmtypes.EventBus, error) {
 // initBaseKV initializes the base key-value store.
 func initBaseKV(nodeConfig config.NodeConfig, logger log.Logger) (ds.TxnDatastore, error) {
 	if nodeConfig.RootDir == "" && nodeConfig.DBPath == "" { // this is used for testing
-		logger.Info("WARNING: working in in-memory mode")
-		return store.NewDefaultInMemoryKVStore()
+		rollkitlog.Warn(logger, "working in in-memory mode")
+		return store.NewInMemoryKVStore(nodeConfig.Badger)
 	}
-	return store.NewDefaultKVStore(nodeConfig.RootDir, nodeConfig.DBPath, "rollkit")
+	return store.NewKVStore(nodeConfig.RootDir, nodeConfig.DBPath, "rollkit", nodeConfig.DBConfig)
+}
+
+// initStore creates the main store, wrapped with cache if it's enabled in configuration.
+func initStore(ctx context.Context, mainKV ds.TxnDatastore, nodeConfig config.NodeConfig) (store.Store, error) {
+	s := store.New(ctx, mainKV)
+	if nodeConfig.StoreCacheSize <= 0 {
+		return s, nil
+	}
+	cached, err := store.NewCachedStore(s, nodeConfig.StoreCacheSize)
+	if err != nil {
+		return nil, fmt.Errorf("error while initializing store cache: %w", err)
+	}
+	return cached, nil
 }
 
 func initDALC(nodeConfig config.NodeConfig, dalcKV ds.TxnDatastore, logger log.Logger) (da.DataAvailabilityLayerClient, error) {
@@ -207,7 +319,7 @@ func initDALC(nodeConfig config.NodeConfig, dalcKV ds.TxnDatastore, logger log.L
 	if dalc == nil {
 		return nil, fmt.Errorf("errror while getting data availability client named '%s'", nodeConfig.DALayer)
 	}
-	err := dalc.Init(nodeConfig.NamespaceID, []byte(nodeConfig.DAConfig), dalcKV, logger.With("module", "da_client"))
+	err := dalc.Init(nodeConfig.Namespace, []byte(nodeConfig.DAConfig), dalcKV, logger.With("module", "da_client"))
 	if err != nil {
 		return nil, fmt.Errorf("error while initializing data availability layer client: %w", err)
 	}
@@ -236,41 +348,22 @@ func initBlockSyncService(ctx context.Context, mainKV ds.TxnDatastore, nodeConfi
 	return blockSyncService, nil
 }
 
-func initBlockManager(signingKey crypto.PrivKey, nodeConfig config.NodeConfig, genesis *cmtypes.GenesisDoc, store store.Store, mempool mempool.Mempool, proxyApp proxy.AppConns, dalc da.DataAvailabilityLayerClient, eventBus *cmtypes.EventBus, logger log.Logger, blockSyncService *block.BlockSyncService) (*block.Manager, error) {
-	blockManager, err := block.NewManager(signingKey, nodeConfig.BlockManagerConfig, genesis, store, mempool, proxyApp.Consensus(), dalc, eventBus, logger.With("module", "BlockManager"), blockSyncService.BlockStore())
-	if err != nil {
-		return nil, fmt.Errorf("error while initializing BlockManager: %w", err)
+func initBlockExecutor(genesis *cmtypes.GenesisDoc, genesisFile string, store store.Store, mempool mempool.Mempool, proxyApp proxy.AppConns, eventBus *cmtypes.EventBus, logger log.Logger) *state.BlockExecutor {
+	exec := state.NewBlockExecutor(genesis.ChainID, mempool, proxyApp.Consensus(), proxyApp.Query(), store, eventBus, logger.With("module", "BlockExecutor"))
+	if genesisFile != "" {
+		exec.SetAppStateLoader(func() (json.RawMessage, error) {
+			return readGenesisAppState(genesisFile)
+		})
 	}
-	return blockManager, nil
+	return exec
 }
 
-// initGenesisChunks creates a chunked format of the genesis document to make it easier to
-// iterate through larger genesis structures.
-func (n *FullNode) initGenesisChunks() error {
-	if n.genChunks != nil {
-		return nil
-	}
-
-	if n.genesis == nil {
-		return nil
-	}
-
-	data, err := json.Marshal(n.genesis)
+func initBlockManager(signingKey crypto.PrivKey, nodeConfig config.NodeConfig, genesis *cmtypes.GenesisDoc, store store.Store, exec *state.BlockExecutor, dalc da.DataAvailabilityLayerClient, logger log.Logger, blockSyncService *block.BlockSyncService) (*block.Manager, error) {
+	blockManager, err := block.NewManager(signingKey, nodeConfig.BlockManagerConfig, genesis, store, exec, dalc, logger.With("module", "BlockManager"), blockSyncService)
 	if err != nil {
-		return err
-	}
-
-	for i := 0; i < len(data); i += genesisChunkSize {
-		end := i + genesisChunkSize
-
-		if end > len(data) {
-			end = len(data)
-		}
-
-		n.genChunks = append(n.genChunks, base64.StdEncoding.EncodeToString(data[i:end]))
+		return nil, fmt.Errorf("error while initializing BlockManager: %w", err)
 	}
-
-	return nil
+	return blockManager, nil
 }
 
 func (n *FullNode) headerPublishLoop(ctx context.Context) {
@@ -305,6 +398,36 @@ func (n *FullNode) blockPublishLoop(ctx context.Context) {
 	}
 }
 
+// genesisExtensionLoop writes genesis extension, once DA height of the first block of the chain is recorded by
+// block manager. Existing genesis extension is not replaced.
+func (n *FullNode) genesisExtensionLoop(ctx context.Context) {
+	daStartHeight := n.blockManager.DAStatus().DAStartHeight
+	if daStartHeight == 0 {
+		select {
+		case daStartHeight = <-n.blockManager.DAStartHeightCh:
+		case <-ctx.Done():
+			return
+		}
+	}
+	ext, err := LoadGenesisExtension(n.genesisFile)
+	if err != nil {
+		n.Logger.Error("failed to load genesis extension", "error", err)
+		return
+	}
+	if ext != nil {
+		if ext.DAStartHeight != daStartHeight {
+			n.Logger.Error("DA height of the first block doesn't match genesis extension", "daHeight", daStartHeight, "extension", ext.DAStartHeight)
+		}
+		return
+	}
+	ext = &GenesisExtension{ChainID: n.genesis.ChainID, DAStartHeight: daStartHeight}
+	if err := saveGenesisExtension(n.genesisFile, ext); err != nil {
+		n.Logger.Error("failed to save genesis extension", "error", err)
+		return
+	}
+	n.Logger.Info("genesis extension saved", "path", GenesisExtensionPath(n.genesisFile), "daStartHeight", daStartHeight)
+}
+
 // GetClient returns the RPC client for the full node.
 func (n *FullNode) GetClient() rpcclient.Client {
 	return n.client
@@ -317,6 +440,11 @@ func (n *FullNode) Cancel() {
 
 // OnStart is a part of Service interface.
 func (n *FullNode) OnStart() error {
+	// application may have lost recently committed blocks before the node was restarted
+	if err := n.appHandshake(n.ctx); err != nil {
+		return fmt.Errorf("ABCI application state is inconsistent with node state: %w", err)
+	}
+
 	n.Logger.Info("starting P2P client")
 	err := n.p2pClient.Start(n.ctx)
 	if err != nil {
@@ -337,46 +465,90 @@ func (n *FullNode) OnStart() error {
 
 	if n.nodeConfig.Aggregator {
 		n.Logger.Info("working in aggregator mode", "block time", n.nodeConfig.BlockTime)
-		go n.blockManager.AggregationLoop(n.ctx, n.nodeConfig.LazyAggregator)
-		go n.blockManager.BlockSubmissionLoop(n.ctx)
+		var aggCtx context.Context
+		aggCtx, n.aggCancel = context.WithCancel(n.ctx)
+		n.aggWg.Add(2)
+		go func() {
+			defer n.aggWg.Done()
+			n.blockManager.AggregationLoop(aggCtx, n.nodeConfig.LazyAggregator)
+		}()
+		go func() {
+			defer n.aggWg.Done()
+			n.blockManager.BlockSubmissionLoop(aggCtx)
+		}()
 		go n.headerPublishLoop(n.ctx)
 		go n.blockPublishLoop(n.ctx)
 	}
 	go n.blockManager.RetrieveLoop(n.ctx)
 	go n.blockManager.BlockStoreRetrieveLoop(n.ctx)
 	go n.blockManager.SyncLoop(n.ctx, n.cancel)
+	if n.genesisFile != "" {
+		go n.genesisExtensionLoop(n.ctx)
+	}
 	return nil
 }
 
-// GetGenesis returns entire genesis doc.
-func (n *FullNode) GetGenesis() *cmtypes.GenesisDoc {
-	return n.genesis
+// SetRollbackHook registers rollback hook of ABCI application (see state.RollbackHook). Without it, the node can't
+// roll back soft blocks diverging from DA layer, and doesn't sync past them. It has to be called before the node is
+// started.
+func (n *FullNode) SetRollbackHook(rollback state.RollbackHook) {
+	n.executor.SetRollbackHook(rollback)
 }
 
-// GetGenesisChunks returns chunked version of genesis.
-func (n *FullNode) GetGenesisChunks() ([]string, error) {
-	err := n.initGenesisChunks()
-	if err != nil {
-		return nil, err
-	}
-	return n.genChunks, err
+// GetGenesis returns genesis doc. If node was started from genesis file, app state is not included
+// (it's not kept in memory) - use Genesis RPC method to get entire genesis.
+func (n *FullNode) GetGenesis() *cmtypes.GenesisDoc {
+	return n.genesis
 }
 
 // OnStop is a part of Service interface.
+//
+// Node is stopped in phases: new transactions are rejected, block production is stopped, pending
+// blocks are submitted to DA layer (within ShutdownTimeout), then sync services, P2P client,
+// indexer and datastore are closed.
 func (n *FullNode) OnStop() {
 	n.Logger.Info("halting full node...")
+	n.Logger.Info("shutdown: rejecting new transactions")
+	n.stopping.Store(true)
+
+	var err error
+	if n.aggCancel != nil {
+		n.Logger.Info("shutdown: stopping aggregator")
+		n.aggCancel()
+		n.aggWg.Wait()
+
+		timeout := n.nodeConfig.ShutdownTimeout
+		if timeout == 0 {
+			timeout = defaultShutdownTimeout
+		}
+		n.Logger.Info("shutdown: submitting pending blocks to DA layer", "timeout", timeout)
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		if flushErr := n.blockManager.FlushPendingBlocks(ctx); flushErr != nil {
+			err = multierr.Append(err, fmt.Errorf("failed to submit pending blocks: %w", flushErr))
+		}
+		cancel()
+	}
 	n.cancel()
-	err := n.dalc.Stop()
-	err = multierr.Append(err, n.p2pClient.Close())
+
+	n.Logger.Info("shutdown: stopping data availability layer client")
+	err = multierr.Append(err, n.dalc.Stop())
+	n.Logger.Info("shutdown: stopping sync services")
 	err = multierr.Append(err, n.hSyncService.Stop())
 	err = multierr.Append(err, n.bSyncService.Stop())
+	n.Logger.Info("shutdown: stopping P2P client")
+	err = multierr.Append(err, n.p2pClient.Close())
+	n.Logger.Info("shutdown: stopping indexer")
 	err = multierr.Append(err, n.IndexerService.Stop())
-	n.Logger.Error("errors while stopping node:", "errors", err)
-}
+	n.Logger.Info("shutdown: closing ABCI application connections")
+	err = multierr.Append(err, n.proxyApp.Stop())
+	n.Logger.Info("shutdown: closing datastore")
+	err = multierr.Append(err, n.baseKV.Close())
 
-// OnReset is a part of Service interface.
-func (n *FullNode) OnReset() error {
-	panic("OnReset - not implemented!")
+	if err != nil {
+		n.Logger.Error("errors while stopping node", "errors", err)
+		return
+	}
+	n.Logger.Info("full node stopped")
 }
 
 // SetLogger sets the logger used by node.
@@ -399,11 +571,30 @@ func (n *FullNode) AppClient() proxy.AppConns {
 	return n.proxyApp
 }
 
+// P2PClient returns the P2P client of the node.
+func (n *FullNode) P2PClient() *p2p.Client {
+	return n.p2pClient
+}
+
+// SetDALC replaces the data availability layer client of the node. It has to be called before Start.
+// It's used to share single (mock) DA layer between many nodes running in one process.
+func (n *FullNode) SetDALC(dalc da.DataAvailabilityLayerClient) {
+	n.dalc = dalc
+	n.blockManager.SetDALC(dalc)
+}
+
+func (n *FullNode) isStopping() bool {
+	return n.stopping.Load()
+}
+
 // newTxValidator creates a pubsub validator that uses the node's mempool to check the
 // transaction. If the transaction is valid, then it is added to the mempool
 func (n *FullNode) newTxValidator() p2p.GossipValidator {
 	return func(m *p2p.GossipMessage) bool {
 		n.Logger.Debug("transaction received", "bytes", len(m.Data))
+		if n.isStopping() {
+			return false
+		}
 		checkTxResCh := make(chan *abci.Response, 1)
 		err := n.Mempool.CheckTx(m.Data, func(resp *abci.Response) {
 			checkTxResCh <- resp
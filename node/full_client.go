@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"sort"
 	"time"
 
@@ -20,8 +21,11 @@ import (
 	cmtypes "github.com/cometbft/cometbft/types"
 	"github.com/cometbft/cometbft/version"
 
+	"github.com/rollkit/rollkit/block"
 	rconfig "github.com/rollkit/rollkit/config"
+	"github.com/rollkit/rollkit/execution"
 	"github.com/rollkit/rollkit/mempool"
+	"github.com/rollkit/rollkit/store"
 	"github.com/rollkit/rollkit/types"
 	abciconv "github.com/rollkit/rollkit/types/abci"
 )
@@ -91,6 +95,9 @@ func (c *FullClient) ABCIQueryWithOptions(ctx context.Context, path string, data
 // BroadcastTxCommit returns with the responses from CheckTx and DeliverTx.
 // More: https://docs.tendermint.com/master/rpc/#/Tx/broadcast_tx_commit
 func (c *FullClient) BroadcastTxCommit(ctx context.Context, tx cmtypes.Tx) (*ctypes.ResultBroadcastTxCommit, error) {
+	if c.node.isStopping() {
+		return nil, ErrNodeStopping
+	}
 	// This implementation corresponds to Tendermints implementation from rpc/core/mempool.go.
 	// ctx.RemoteAddr godoc: If neither HTTPReq nor WSConn is set, an empty string is returned.
 	// This code is a local client, so we can assume that subscriber is ""
@@ -182,6 +189,9 @@ func (c *FullClient) BroadcastTxCommit(ctx context.Context, tx cmtypes.Tx) (*cty
 // CheckTx nor DeliverTx results.
 // More: https://docs.tendermint.com/master/rpc/#/Tx/broadcast_tx_async
 func (c *FullClient) BroadcastTxAsync(ctx context.Context, tx cmtypes.Tx) (*ctypes.ResultBroadcastTx, error) {
+	if c.node.isStopping() {
+		return nil, ErrNodeStopping
+	}
 	err := c.node.Mempool.CheckTx(tx, nil, mempool.TxInfo{})
 	if err != nil {
 		return nil, err
@@ -198,6 +208,9 @@ func (c *FullClient) BroadcastTxAsync(ctx context.Context, tx cmtypes.Tx) (*ctyp
 // DeliverTx result.
 // More: https://docs.tendermint.com/master/rpc/#/Tx/broadcast_tx_sync
 func (c *FullClient) BroadcastTxSync(ctx context.Context, tx cmtypes.Tx) (*ctypes.ResultBroadcastTx, error) {
+	if c.node.isStopping() {
+		return nil, ErrNodeStopping
+	}
 	resCh := make(chan *abci.Response, 1)
 	err := c.node.Mempool.CheckTx(tx, func(res *abci.Response) {
 		resCh <- res
@@ -271,35 +284,56 @@ func (c *FullClient) Unsubscribe(ctx context.Context, subscriber, query string)
 
 // Genesis returns entire genesis.
 func (c *FullClient) Genesis(_ context.Context) (*ctypes.ResultGenesis, error) {
-	return &ctypes.ResultGenesis{Genesis: c.node.GetGenesis()}, nil
+	if c.node.genesisFile == "" {
+		return &ctypes.ResultGenesis{Genesis: c.node.GetGenesis()}, nil
+	}
+	// app state of genesis file is not kept in memory
+	appState, err := readGenesisAppState(c.node.genesisFile)
+	if err != nil {
+		return nil, err
+	}
+	genesis := *c.node.GetGenesis()
+	genesis.AppState = appState
+	return &ctypes.ResultGenesis{Genesis: &genesis}, nil
 }
 
 // GenesisChunked returns given chunk of genesis.
 func (c *FullClient) GenesisChunked(context context.Context, id uint) (*ctypes.ResultGenesisChunk, error) {
-	genChunks, err := c.node.GetGenesisChunks()
+	total, err := c.node.genChunks.Total()
 	if err != nil {
 		return nil, fmt.Errorf("error while creating chunks of the genesis document: %w", err)
 	}
-	if genChunks == nil {
-		return nil, fmt.Errorf("service configuration error, genesis chunks are not initialized")
-	}
-
-	chunkLen := len(genChunks)
-	if chunkLen == 0 {
+	if total == 0 {
 		return nil, fmt.Errorf("service configuration error, there are no chunks")
 	}
 
-	if int(id) > chunkLen-1 {
-		return nil, fmt.Errorf("there are %d chunks, %d is invalid", chunkLen-1, id)
+	chunk, err := c.node.genChunks.Chunk(int(id))
+	if err != nil {
+		return nil, err
 	}
 
 	return &ctypes.ResultGenesisChunk{
-		TotalChunks: chunkLen,
+		TotalChunks: total,
 		ChunkNumber: int(id),
-		Data:        genChunks[id],
+		Data:        chunk,
 	}, nil
 }
 
+// GenesisChecksum returns the number of genesis chunks, along with size and SHA-256 checksum of entire genesis.
+func (c *FullClient) GenesisChecksum(_ context.Context) (*ResultGenesisChecksum, error) {
+	return c.node.genChunks.Checksum()
+}
+
+// GenesisExtension returns Rollkit genesis extension of the chain, once DA height of the first block is recorded by
+// the node. It's served by all full nodes, so the extension doesn't have to be copied from the node that wrote it.
+func (c *FullClient) GenesisExtension(_ context.Context) (*GenesisExtension, error) {
+	daStartHeight := c.node.blockManager.DAStatus().DAStartHeight
+	if daStartHeight == 0 {
+		return nil, errors.New("DA height of the first block is not recorded yet")
+	}
+	return &GenesisExtension{ChainID: c.node.genesis.ChainID, DAStartHeight: daStartHeight}, nil
+}
+
 // BlockchainInfo returns ABCI block meta information for given height range.
 func (c *FullClient) BlockchainInfo(ctx context.Context, minHeight, maxHeight int64) (*ctypes.ResultBlockchainInfo, error) {
 	const limit int64 = 20
@@ -487,16 +521,35 @@ func (c *FullClient) Commit(ctx context.Context, height *int64) (*ctypes.ResultC
 		return nil, err
 	}
 
-	// we only have one centralize sequencer = 1 validators
-	val := b.SignedHeader.Validators.Validators[0].Address
-	commit := com.ToABCICommit(heightValue, b.Hash(), val, b.SignedHeader.Time())
-
-	block, err := abciconv.ToABCIBlock(b)
+	signedHeader := b.SignedHeader
+	signedHeader.Commit = *com
+	abciSignedHeader, err := abciconv.ToABCISignedHeader(&signedHeader)
 	if err != nil {
 		return nil, err
 	}
 
-	return ctypes.NewResultCommit(&block.Header, commit, true), nil
+	return ctypes.NewResultCommit(abciSignedHeader.Header, abciSignedHeader.Commit, true), nil
+}
+
+// LightBlock returns signed header and validator set at given height (or the latest height, if height is nil),
+// in the format verifiable by CometBFT light client.
+//
+// NextValidatorsHash is signed by the header, so validator set change can be verified by adjacent verification only
+// if it was announced by the header preceding the change. Otherwise light clients have to verify headers across the
+// change with non-adjacent verification.
+func (c *FullClient) LightBlock(ctx context.Context, height *int64) (*cmtypes.LightBlock, error) {
+	heightValue := c.normalizeHeight(height)
+	b, err := c.node.Store.GetBlock(heightValue)
+	if err != nil {
+		return nil, err
+	}
+	com, err := c.node.Store.GetCommit(heightValue)
+	if err != nil {
+		return nil, err
+	}
+	signedHeader := b.SignedHeader
+	signedHeader.Commit = *com
+	return abciconv.ToABCILightBlock(&signedHeader)
 }
 
 // Validators returns paginated list of validators at given height.
@@ -839,6 +892,60 @@ func (c *FullClient) HeaderByHash(ctx context.Context, hash cmbytes.HexBytes) (*
 	return &ctypes.ResultHeader{Header: &blockMeta.Header}, nil
 }
 
+// DAStatus returns status of block submission to, and retrieval from data availability layer.
+func (c *FullClient) DAStatus(ctx context.Context) (*block.DAStatus, error) {
+	status := c.node.blockManager.DAStatus()
+	return &status, nil
+}
+
+// ResultBlockFinality is the finality level of a committed block.
+type ResultBlockFinality struct {
+	Height   int64              `json:"height"`
+	Finality execution.Finality `json:"finality"`
+}
+
+// BlockFinality returns finality level of committed block at given height (latest block by default).
+// Block is DA-final if it, and all blocks before it, are included in DA layer.
+func (c *FullClient) BlockFinality(ctx context.Context, height *int64) (*ResultBlockFinality, error) {
+	heightValue := c.normalizeHeight(height)
+	if heightValue == 0 || heightValue > c.node.Store.Height() {
+		return nil, fmt.Errorf("block at height %d not found", heightValue)
+	}
+	return &ResultBlockFinality{
+		Height:   int64(heightValue),
+		Finality: c.node.blockManager.Finality(heightValue),
+	}, nil
+}
+
+// StoreCacheStats returns hit and miss counters of the store cache.
+func (c *FullClient) StoreCacheStats(ctx context.Context) (*store.CacheStats, error) {
+	cached, ok := c.node.Store.(*store.CachedStore)
+	if !ok {
+		return nil, errors.New("store cache is disabled")
+	}
+	stats := cached.Stats()
+	return &stats, nil
+}
+
+// SimulateTxs executes txs in a candidate block built on top of the latest state, without committing anything.
+func (c *FullClient) SimulateTxs(ctx context.Context, txs []cmtypes.Tx) (*execution.SimulationResult, error) {
+	rollkitTxs := make(types.Txs, len(txs))
+	for i := range txs {
+		rollkitTxs[i] = types.Tx(txs[i])
+	}
+	return c.node.blockManager.SimulateTxs(ctx, rollkitTxs)
+}
+
+// Backup writes consistent snapshot of all node data to w.
+func (c *FullClient) Backup(ctx context.Context, w io.Writer) error {
+	return c.node.Backup(w)
+}
+
+// ReloadConfig re-reads node configuration and applies options that can be changed at runtime.
+func (c *FullClient) ReloadConfig(ctx context.Context) error {
+	return c.node.ReloadConfig()
+}
+
 func (c *FullClient) eventsRoutine(sub cmtypes.Subscription, subscriber string, q cmpubsub.Query, outc chan<- ctypes.ResultEvent) {
 	defer close(outc)
 	for {
@@ -3,8 +3,13 @@ package node
 import (
 	"context"
 	crand "crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
 	"encoding/hex"
+	"encoding/json"
 	"fmt"
+	"os"
+	"path/filepath"
 	"sync"
 	"testing"
 	"time"
@@ -19,6 +24,7 @@ import (
 	"github.com/cometbft/cometbft/crypto/ed25519"
 	"github.com/cometbft/cometbft/libs/bytes"
 	"github.com/cometbft/cometbft/libs/log"
+	"github.com/cometbft/cometbft/light"
 	"github.com/cometbft/cometbft/p2p"
 	"github.com/cometbft/cometbft/proxy"
 	cmtypes "github.com/cometbft/cometbft/types"
@@ -41,6 +47,7 @@ var expectedInfo = abci.ResponseInfo{
 
 const (
 	InitChain  = "InitChain"
+	Info       = "Info"
 	CheckTx    = "CheckTx"
 	BeginBlock = "BeginBlock"
 	DeliverTx  = "DeliverTx"
@@ -74,6 +81,7 @@ func getRPC(t *testing.T) (*mocks.Application, *FullClient) {
 	require := require.New(t)
 	app := &mocks.Application{}
 	app.On(InitChain, mock.Anything).Return(abci.ResponseInitChain{})
+	app.On(Info, mock.Anything).Return(expectedInfo).Maybe()
 	key, _, _ := crypto.GenerateEd25519Key(crand.Reader)
 	ctx := context.Background()
 	genesisValidators, signingKey := types.GetGenesisValidatorSetWithSigner()
@@ -150,8 +158,7 @@ func TestConnectionGetter(t *testing.T) {
 func TestInfo(t *testing.T) {
 	assert := assert.New(t)
 
-	mockApp, rpc := getRPC(t)
-	mockApp.On("Info", mock.Anything).Return(expectedInfo)
+	_, rpc := getRPC(t)
 
 	info, err := rpc.ABCIInfo(context.Background())
 	assert.NoError(err)
@@ -186,6 +193,7 @@ func TestGenesisChunked(t *testing.T) {
 
 	mockApp := &mocks.Application{}
 	mockApp.On(InitChain, mock.Anything).Return(abci.ResponseInitChain{})
+	mockApp.On(Info, mock.Anything).Return(abci.ResponseInfo{}).Maybe()
 	privKey, _, _ := crypto.GenerateEd25519Key(crand.Reader)
 	signingKey, _, _ := crypto.GenerateEd25519Key(crand.Reader)
 	n, _ := newFullNode(context.Background(), config.NodeConfig{DALayer: "newda"}, privKey, signingKey, proxy.NewLocalClientCreator(mockApp), genDoc, test.NewFileLogger(t))
@@ -214,6 +222,54 @@ func TestGenesisChunked(t *testing.T) {
 	assert.Nil(gc3)
 }
 
+func TestGenesisFromFile(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	genesisValidators, signingKey := types.GetGenesisValidatorSetWithSigner()
+	appState := json.RawMessage(`{"key":"value"}`)
+	genDoc := &cmtypes.GenesisDoc{
+		ChainID:       "test",
+		InitialHeight: 1,
+		Validators:    genesisValidators,
+		AppState:      appState,
+	}
+	require.NoError(genDoc.ValidateAndComplete())
+	genesisFile := filepath.Join(t.TempDir(), "genesis.json")
+	require.NoError(genDoc.SaveAs(genesisFile))
+
+	mockApp := &mocks.Application{}
+	mockApp.On(InitChain, mock.MatchedBy(func(req abci.RequestInitChain) bool {
+		return string(req.AppStateBytes) == string(appState)
+	})).Return(abci.ResponseInitChain{})
+	mockApp.On(Info, mock.Anything).Return(abci.ResponseInfo{}).Maybe()
+	key, _, _ := crypto.GenerateEd25519Key(crand.Reader)
+	n, err := NewNodeFromGenesisFile(context.Background(), config.NodeConfig{DALayer: "newda"}, key, signingKey, proxy.NewLocalClientCreator(mockApp), genesisFile, test.NewFileLogger(t))
+	require.NoError(err)
+	mockApp.AssertExpectations(t)
+
+	fullNode := n.(*FullNode)
+	assert.Nil(fullNode.GetGenesis().AppState)
+
+	rpc := NewFullClient(fullNode)
+	genesis, err := rpc.Genesis(context.Background())
+	require.NoError(err)
+	assert.Equal(appState, genesis.Genesis.AppState)
+
+	fileData, err := os.ReadFile(genesisFile)
+	require.NoError(err)
+	chunk, err := rpc.GenesisChunked(context.Background(), 0)
+	require.NoError(err)
+	assert.Equal(1, chunk.TotalChunks)
+	assert.Equal(base64.StdEncoding.EncodeToString(fileData), chunk.Data)
+
+	checksum, err := rpc.GenesisChecksum(context.Background())
+	require.NoError(err)
+	expected := sha256.Sum256(fileData)
+	assert.EqualValues(expected[:], checksum.Checksum)
+	assert.Equal(int64(len(fileData)), checksum.Size)
+}
+
 func TestBroadcastTxAsync(t *testing.T) {
 	assert := assert.New(t)
 
@@ -492,6 +548,7 @@ func TestTx(t *testing.T) {
 
 	mockApp := &mocks.Application{}
 	mockApp.On(InitChain, mock.Anything).Return(abci.ResponseInitChain{})
+	mockApp.On(Info, mock.Anything).Return(abci.ResponseInfo{}).Maybe()
 	key, _, _ := crypto.GenerateEd25519Key(crand.Reader)
 	genesisValidators, signingKey := types.GetGenesisValidatorSetWithSigner()
 	ctx, cancel := context.WithCancel(context.Background())
@@ -535,11 +592,9 @@ func TestTx(t *testing.T) {
 	assert.EqualValues(res.Hash, resTx.Hash)
 
 	tx2 := cmtypes.Tx("tx2")
-	assert.Panics(func() {
-		resTx, errTx := rpc.Tx(ctx, tx2.Hash(), true)
-		assert.Nil(resTx)
-		assert.Error(errTx)
-	})
+	resTx, errTx = rpc.Tx(ctx, tx2.Hash(), true)
+	assert.Nil(resTx)
+	assert.ErrorContains(errTx, "not found")
 }
 
 func TestUnconfirmedTxs(t *testing.T) {
@@ -747,6 +802,7 @@ func TestMempool2Nodes(t *testing.T) {
 
 	app := &mocks.Application{}
 	app.On(InitChain, mock.Anything).Return(abci.ResponseInitChain{})
+	app.On(Info, mock.Anything).Return(abci.ResponseInfo{}).Maybe()
 	app.On(CheckTx, abci.RequestCheckTx{Tx: []byte("bad")}).Return(abci.ResponseCheckTx{Code: 1})
 	app.On(CheckTx, abci.RequestCheckTx{Tx: []byte("good")}).Return(abci.ResponseCheckTx{Code: 0})
 	key1, _, _ := crypto.GenerateEd25519Key(crand.Reader)
@@ -833,6 +889,7 @@ func TestStatus(t *testing.T) {
 
 	app := &mocks.Application{}
 	app.On(InitChain, mock.Anything).Return(abci.ResponseInitChain{})
+	app.On(Info, mock.Anything).Return(abci.ResponseInfo{}).Maybe()
 	key, _, _ := crypto.GenerateEd25519Key(crand.Reader)
 	genesisValidators, signingKey := types.GetGenesisValidatorSetWithSigner()
 	pubKey := genesisValidators[0].PubKey
@@ -937,6 +994,7 @@ func TestFutureGenesisTime(t *testing.T) {
 	wg.Add(1)
 	mockApp := &mocks.Application{}
 	mockApp.On(InitChain, mock.Anything).Return(abci.ResponseInitChain{})
+	mockApp.On(Info, mock.Anything).Return(abci.ResponseInfo{}).Maybe()
 	mockApp.On(BeginBlock, mock.Anything).Return(abci.ResponseBeginBlock{}).Run(func(_ mock.Arguments) {
 		beginBlockTime = time.Now()
 		wg.Done()
@@ -979,6 +1037,136 @@ func TestFutureGenesisTime(t *testing.T) {
 	assert.True(beginBlockTime.After(genesisTime))
 }
 
+func TestLightBlock(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	mockApp := &mocks.Application{}
+	mockApp.On(InitChain, mock.Anything).Return(abci.ResponseInitChain{})
+	mockApp.On(Info, mock.Anything).Return(abci.ResponseInfo{}).Maybe()
+	mockApp.On(BeginBlock, mock.Anything).Return(abci.ResponseBeginBlock{})
+	mockApp.On(EndBlock, mock.Anything).Return(abci.ResponseEndBlock{})
+	mockApp.On(Commit, mock.Anything).Return(abci.ResponseCommit{})
+	mockApp.On(CheckTx, mock.Anything).Return(abci.ResponseCheckTx{})
+	key, _, _ := crypto.GenerateEd25519Key(crand.Reader)
+	genesisValidators, signingKey := types.GetGenesisValidatorSetWithSigner()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	node, err := newFullNode(ctx, config.NodeConfig{
+		DALayer:    "newda",
+		Aggregator: true,
+		BlockManagerConfig: config.BlockManagerConfig{
+			BlockTime: 100 * time.Millisecond,
+		}},
+		key, signingKey,
+		proxy.NewLocalClientCreator(mockApp),
+		&cmtypes.GenesisDoc{
+			ChainID:       "test",
+			InitialHeight: 1,
+			Validators:    genesisValidators,
+		},
+		test.NewFileLogger(t))
+	require.NoError(err)
+	require.NotNil(node)
+
+	require.NoError(node.Start())
+	defer func() {
+		require.NoError(node.Stop())
+	}()
+	require.NoError(waitForAtLeastNBlocks(node, 5, Store))
+
+	rpc := NewFullClient(node)
+	lightBlocks := make([]*cmtypes.LightBlock, 5)
+	for i := range lightBlocks {
+		h := int64(i + 1)
+		lightBlock, err := rpc.LightBlock(ctx, &h)
+		require.NoError(err)
+		require.NotNil(lightBlock)
+		assert.Equal(h, lightBlock.Height)
+		assert.Equal("test", lightBlock.ChainID)
+		require.NoError(lightBlock.ValidatorSet.VerifyCommitLight("test", lightBlock.Commit.BlockID, h, lightBlock.Commit))
+		lightBlocks[i] = lightBlock
+	}
+
+	latest, err := rpc.LightBlock(ctx, nil)
+	require.NoError(err)
+	assert.GreaterOrEqual(latest.Height, int64(5))
+
+	// verify headers the same way as CometBFT light client (used by IBC relayers) does
+	const trustingPeriod = time.Hour
+	now := time.Now()
+	trusted := lightBlocks[0]
+	for _, untrusted := range lightBlocks[1:] {
+		err := light.Verify(trusted.SignedHeader, trusted.ValidatorSet, untrusted.SignedHeader, untrusted.ValidatorSet,
+			trustingPeriod, now, time.Second, light.DefaultTrustLevel)
+		assert.NoError(err, "adjacent verification of height %d", untrusted.Height)
+		trusted = untrusted
+	}
+	err = light.Verify(lightBlocks[0].SignedHeader, lightBlocks[0].ValidatorSet, latest.SignedHeader, latest.ValidatorSet,
+		trustingPeriod, now, time.Second, light.DefaultTrustLevel)
+	assert.NoError(err, "non-adjacent verification")
+
+	// tampered header must be rejected
+	tampered := *lightBlocks[2].Header
+	tampered.AppHash = bytes.HexBytes(types.GetRandomBytes(32))
+	err = light.Verify(lightBlocks[1].SignedHeader, lightBlocks[1].ValidatorSet,
+		&cmtypes.SignedHeader{Header: &tampered, Commit: lightBlocks[2].Commit}, lightBlocks[2].ValidatorSet,
+		trustingPeriod, now, time.Second, light.DefaultTrustLevel)
+	assert.Error(err)
+
+	future := latest.Height + 100
+	_, err = rpc.LightBlock(ctx, &future)
+	assert.Error(err)
+
+	// validator set changes at height 3 (announced by header at height 2) and at height 5 (not announced)
+	_, rpc = getRPC(t)
+	vals, keys := make([]*cmtypes.ValidatorSet, 3), make([]ed25519.PrivKey, 3)
+	for i := range vals {
+		keys[i] = ed25519.GenPrivKey()
+		vals[i] = cmtypes.NewValidatorSet([]*cmtypes.Validator{cmtypes.NewValidator(keys[i].PubKey(), 1)})
+	}
+	setAt := map[uint64]int{1: 0, 2: 0, 3: 1, 4: 1, 5: 2}
+	start := now.Add(-time.Minute)
+	var prev *types.Block
+	for h := uint64(1); h <= 5; h++ {
+		set := setAt[h]
+		block := types.GetRandomBlock(h, 1)
+		header := &block.SignedHeader
+		header.BaseHeader.Time = uint64(start.Add(time.Duration(h) * time.Second).UnixNano())
+		header.Validators = vals[set]
+		header.ValidatorHash = vals[set].Hash()
+		header.ProposerAddress = vals[set].Proposer.Address
+		if h == 2 {
+			header.NextValidatorHash = vals[1].Hash()
+		}
+		if prev != nil {
+			header.LastHeaderHash = prev.Hash()
+		}
+		signature, err := keys[set].Sign(header.Header.MakeCometBFTVote())
+		require.NoError(err)
+		header.Commit = types.Commit{Signatures: []types.Signature{signature}}
+		require.NoError(rpc.node.Store.SaveBlock(block, &header.Commit))
+		rpc.node.Store.SetHeight(h)
+		prev = block
+	}
+	changing := make([]*cmtypes.LightBlock, 5)
+	for i := range changing {
+		h := int64(i + 1)
+		changing[i], err = rpc.LightBlock(ctx, &h)
+		require.NoError(err, "light block at height %d", h)
+	}
+	assert.Equal(changing[0].ValidatorsHash, changing[0].NextValidatorsHash)
+	assert.Equal(changing[2].ValidatorsHash, changing[1].NextValidatorsHash)
+	for i := 1; i < 4; i++ {
+		err := light.Verify(changing[i-1].SignedHeader, changing[i-1].ValidatorSet, changing[i].SignedHeader, changing[i].ValidatorSet,
+			trustingPeriod, now, time.Second, light.DefaultTrustLevel)
+		assert.NoError(err, "adjacent verification of height %d", changing[i].Height)
+	}
+	err = light.Verify(changing[3].SignedHeader, changing[3].ValidatorSet, changing[4].SignedHeader, changing[4].ValidatorSet,
+		trustingPeriod, now, time.Second, light.DefaultTrustLevel)
+	assert.Error(err, "change that wasn't announced can't be verified")
+}
+
 func TestHealth(t *testing.T) {
 	assert := assert.New(t)
 	require := require.New(t)
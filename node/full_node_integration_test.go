@@ -23,6 +23,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/rollkit/rollkit/config"
+	"github.com/rollkit/rollkit/da"
 	mockda "github.com/rollkit/rollkit/da/mock"
 	"github.com/rollkit/rollkit/store"
 	test "github.com/rollkit/rollkit/test/log"
@@ -38,6 +39,7 @@ func TestAggregatorMode(t *testing.T) {
 
 	app := &mocks.Application{}
 	app.On(InitChain, mock.Anything).Return(abci.ResponseInitChain{})
+	app.On(Info, mock.Anything).Return(abci.ResponseInfo{}).Maybe()
 	app.On(CheckTx, mock.Anything).Return(abci.ResponseCheckTx{})
 	app.On(BeginBlock, mock.Anything).Return(abci.ResponseBeginBlock{})
 	app.On(DeliverTx, mock.Anything).Return(abci.ResponseDeliverTx{})
@@ -47,8 +49,8 @@ func TestAggregatorMode(t *testing.T) {
 	key, _, _ := crypto.GenerateEd25519Key(rand.Reader)
 	genesisValidators, signingKey := types.GetGenesisValidatorSetWithSigner()
 	blockManagerConfig := config.BlockManagerConfig{
-		BlockTime:   1 * time.Second,
-		NamespaceID: types.NamespaceID{1, 2, 3, 4, 5, 6, 7, 8},
+		BlockTime: 1 * time.Second,
+		Namespace: types.MustNewNamespace(types.NamespaceVersionZero, []byte{1, 2, 3, 4, 5, 6, 7, 8}),
 	}
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -79,6 +81,49 @@ func TestAggregatorMode(t *testing.T) {
 	}()
 }
 
+// TestShutdownFlushesPendingBlocks checks if blocks not yet submitted to DA layer are submitted when aggregator is stopped.
+func TestShutdownFlushesPendingBlocks(t *testing.T) {
+	require := require.New(t)
+
+	dalc := &mockda.DataAvailabilityLayerClient{}
+	ds, _ := store.NewDefaultInMemoryKVStore()
+	_ = dalc.Init(types.Namespace{}, []byte((10 * time.Millisecond).String()), ds, log.TestingLogger())
+	_ = dalc.Start()
+
+	bmConfig := getBMConfig()
+	// block submission loop never fires, blocks are submitted only during shutdown
+	bmConfig.DABlockTime = time.Hour
+	key, _, _ := crypto.GenerateEd25519Key(rand.Reader)
+	node, _ := createNode(context.Background(), 0, true, false, []crypto.PrivKey{key}, bmConfig, t)
+	fullNode := node.(*FullNode)
+	fullNode.dalc = dalc
+	fullNode.blockManager.SetDALC(dalc)
+
+	require.NoError(fullNode.Start())
+	require.NoError(waitForAtLeastNBlocks(fullNode, 3, Store))
+	require.NoError(fullNode.Stop())
+	height := fullNode.Store.Height()
+
+	_, err := fullNode.GetClient().BroadcastTxAsync(context.Background(), []byte("tx"))
+	require.ErrorIs(err, ErrNodeStopping)
+
+	// wait for mock DA layer to produce next block, to be able to retrieve submitted blocks
+	time.Sleep(100 * time.Millisecond)
+	submitted := make(map[uint64]bool)
+	for daHeight := uint64(1); ; daHeight++ {
+		res := dalc.RetrieveBlocks(context.Background(), daHeight)
+		if res.Code != da.StatusSuccess {
+			break
+		}
+		for _, b := range res.Blocks {
+			submitted[uint64(b.Height())] = true
+		}
+	}
+	for h := uint64(1); h <= height; h++ {
+		require.True(submitted[h], "block %d not submitted to DA layer", h)
+	}
+}
+
 // TestTxGossipingAndAggregation setups a network of nodes, with single aggregator and multiple producers.
 // Nodes should gossip transactions and aggregator node should produce blocks.
 func TestTxGossipingAndAggregation(t *testing.T) {
@@ -134,6 +179,7 @@ func TestLazyAggregator(t *testing.T) {
 
 	app := &mocks.Application{}
 	app.On(InitChain, mock.Anything).Return(abci.ResponseInitChain{})
+	app.On(Info, mock.Anything).Return(abci.ResponseInfo{}).Maybe()
 	app.On(CheckTx, mock.Anything).Return(abci.ResponseCheckTx{})
 	app.On(BeginBlock, mock.Anything).Return(abci.ResponseBeginBlock{})
 	app.On(DeliverTx, mock.Anything).Return(abci.ResponseDeliverTx{})
@@ -151,8 +197,8 @@ func TestLazyAggregator(t *testing.T) {
 		// the blocktime too short. in future, we can add a configuration
 		// in go-header syncer initialization to not rely on blocktime, but the
 		// config variable
-		BlockTime:   1 * time.Second,
-		NamespaceID: types.NamespaceID{1, 2, 3, 4, 5, 6, 7, 8},
+		BlockTime: 1 * time.Second,
+		Namespace: types.MustNewNamespace(types.NamespaceVersionZero, []byte{1, 2, 3, 4, 5, 6, 7, 8}),
 	}
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -259,7 +305,7 @@ func TestFastDASync(t *testing.T) {
 			require.FailNow("channel closed")
 		default:
 		}
-		nHeight, err := getNodeHeight(node2, Store)
+		nHeight, err := GetNodeHeight(node2, Store)
 		if err != nil {
 			return err
 		}
@@ -470,7 +516,7 @@ func testSingleAggregatorSingleFullNodeSingleLightNode(t *testing.T) {
 	}
 	dalc := &mockda.DataAvailabilityLayerClient{}
 	ds, _ := store.NewDefaultInMemoryKVStore()
-	_ = dalc.Init([8]byte{}, nil, ds, log.TestingLogger())
+	_ = dalc.Init(types.Namespace{}, nil, ds, log.TestingLogger())
 	_ = dalc.Start()
 	defer func() {
 		require.NoError(dalc.Stop())
@@ -512,11 +558,11 @@ func createAndStartNodes(clientNodes int, t *testing.T) ([]*FullNode, []*mocks.A
 	defer cancel()
 	nodes, apps := createNodes(aggCtx, ctx, clientNodes+1, getBMConfig(), t)
 	startNodes(nodes, apps, t)
-	defer func() {
+	t.Cleanup(func() {
 		for _, n := range nodes {
 			assert.NoError(t, n.Stop())
 		}
-	}()
+	})
 	return nodes, apps
 }
 
@@ -589,7 +635,7 @@ func createNodes(aggCtx, ctx context.Context, num int, bmConfig config.BlockMana
 	apps := make([]*mocks.Application, num)
 	dalc := &mockda.DataAvailabilityLayerClient{}
 	ds, _ := store.NewDefaultInMemoryKVStore()
-	_ = dalc.Init([8]byte{}, nil, ds, test.NewFileLoggerCustom(t, test.TempLogFileName(t, "dalc")))
+	_ = dalc.Init(types.Namespace{}, nil, ds, test.NewFileLoggerCustom(t, test.TempLogFileName(t, "dalc")))
 	_ = dalc.Start()
 	node, app := createNode(aggCtx, 0, true, false, keys, bmConfig, t)
 	apps[0] = app
@@ -629,6 +675,7 @@ func createNode(ctx context.Context, n int, aggregator bool, isLight bool, keys
 
 	app := &mocks.Application{}
 	app.On(InitChain, mock.Anything).Return(abci.ResponseInitChain{})
+	app.On(Info, mock.Anything).Return(abci.ResponseInfo{}).Maybe()
 	app.On(CheckTx, mock.Anything).Return(abci.ResponseCheckTx{})
 	app.On(BeginBlock, mock.Anything).Return(abci.ResponseBeginBlock{})
 	app.On(EndBlock, mock.Anything).Return(abci.ResponseEndBlock{})
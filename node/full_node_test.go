@@ -43,6 +43,7 @@ func TestMempoolDirectly(t *testing.T) {
 func setupMockApplication() *mocks.Application {
 	app := &mocks.Application{}
 	app.On(InitChain, mock.Anything).Return(abci.ResponseInitChain{})
+	app.On(Info, mock.Anything).Return(abci.ResponseInfo{}).Maybe()
 	app.On(CheckTx, mock.Anything).Return(abci.ResponseCheckTx{})
 	return app
 }
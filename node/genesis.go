@@ -0,0 +1,355 @@
+package node
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+
+	cmbytes "github.com/cometbft/cometbft/libs/bytes"
+	"github.com/cometbft/cometbft/libs/log"
+	cmtypes "github.com/cometbft/cometbft/types"
+
+	"github.com/rollkit/rollkit/config"
+)
+
+// GenesisExtensionFile is the name of Rollkit genesis extension file, kept in the directory of genesis file.
+const GenesisExtensionFile = "rollkit_genesis.json"
+
+// GenesisExtension contains Rollkit parameters of the chain, that are not known when genesis is created.
+// It's written by the node that records DA height of the first block of the chain (aggregator, when the block
+// is submitted to DA layer), and should be distributed to full nodes together with genesis file. Full nodes
+// serve it with genesis_extension RPC method, and the result can be saved as genesis extension file as is.
+type GenesisExtension struct {
+	ChainID string `json:"chain_id"`
+	// DAStartHeight is the DA height of the first block of the chain.
+	DAStartHeight uint64 `json:"da_start_height"`
+}
+
+// UnmarshalJSON accepts DA start height encoded as JSON number, or as JSON string (RPC encoding of uint64).
+func (e *GenesisExtension) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		ChainID       string      `json:"chain_id"`
+		DAStartHeight json.Number `json:"da_start_height"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	e.ChainID = raw.ChainID
+	e.DAStartHeight = 0
+	if raw.DAStartHeight == "" {
+		return nil
+	}
+	height, err := strconv.ParseUint(raw.DAStartHeight.String(), 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid DA start height: %w", err)
+	}
+	e.DAStartHeight = height
+	return nil
+}
+
+// GenesisExtensionPath returns the path of genesis extension file of given genesis file.
+func GenesisExtensionPath(genesisFile string) string {
+	return filepath.Join(filepath.Dir(genesisFile), GenesisExtensionFile)
+}
+
+// LoadGenesisExtension reads genesis extension of given genesis file. It returns nil if there is no extension file.
+func LoadGenesisExtension(genesisFile string) (*GenesisExtension, error) {
+	data, err := os.ReadFile(GenesisExtensionPath(genesisFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read genesis extension: %w", err)
+	}
+	var ext GenesisExtension
+	if err := json.Unmarshal(data, &ext); err != nil {
+		return nil, fmt.Errorf("failed to parse genesis extension: %w", err)
+	}
+	return &ext, nil
+}
+
+// saveGenesisExtension writes genesis extension of given genesis file. Existing file is replaced atomically.
+func saveGenesisExtension(genesisFile string, ext *GenesisExtension) error {
+	data, err := json.MarshalIndent(ext, "", "  ")
+	if err != nil {
+		return err
+	}
+	path := GenesisExtensionPath(genesisFile)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil { //nolint:gosec // genesis is public
+		return fmt.Errorf("failed to write genesis extension: %w", err)
+	}
+	return os.Rename(tmp, path)
+}
+
+// applyGenesisExtension derives DA start height of the node from genesis extension, or validates DA start height
+// set in the configuration. DA start height above DA height of the first block would make the node miss blocks;
+// lower DA start height is raised, as there are no blocks of the chain below it.
+func applyGenesisExtension(conf *config.NodeConfig, genesis *cmtypes.GenesisDoc, ext *GenesisExtension, logger log.Logger) error {
+	if ext.ChainID != genesis.ChainID {
+		return fmt.Errorf("genesis extension of chain %s doesn't match genesis of chain %s", ext.ChainID, genesis.ChainID)
+	}
+	if conf.DAStartHeight > ext.DAStartHeight {
+		return fmt.Errorf("DA start height %d is above DA height %d of the first block, blocks would be missed", conf.DAStartHeight, ext.DAStartHeight)
+	}
+	if conf.DAStartHeight < ext.DAStartHeight {
+		logger.Info("using DA start height from genesis extension", "daStartHeight", ext.DAStartHeight, "configured", conf.DAStartHeight)
+		conf.DAStartHeight = ext.DAStartHeight
+	}
+	return nil
+}
+
+// genesisAppStateField is the field of genesis document holding app state.
+const genesisAppStateField = "app_state"
+
+// loadGenesisFile reads genesis document from given file, without app state. File is streamed, app state is
+// skipped without being loaded into memory.
+func loadGenesisFile(path string) (*cmtypes.GenesisDoc, error) {
+	fields := make(map[string]json.RawMessage)
+	err := scanGenesisFile(path, func(key string, dec *json.Decoder) error {
+		if key == genesisAppStateField {
+			return skipJSONValue(dec)
+		}
+		var value json.RawMessage
+		if err := dec.Decode(&value); err != nil {
+			return err
+		}
+		fields[key] = value
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(fields)
+	if err != nil {
+		return nil, err
+	}
+	genesis, err := cmtypes.GenesisDocFromJSON(data)
+	if err != nil {
+		return nil, fmt.Errorf("error reading GenesisDoc at %s: %w", path, err)
+	}
+	return genesis, nil
+}
+
+// readGenesisAppState reads app state from given genesis file. App state is compacted, so application receives
+// the same bytes as it would from genesis document the file was saved from (see cmtypes.GenesisDoc.SaveAs).
+func readGenesisAppState(path string) (json.RawMessage, error) {
+	var appState json.RawMessage
+	err := scanGenesisFile(path, func(key string, dec *json.Decoder) error {
+		if key != genesisAppStateField {
+			return skipJSONValue(dec)
+		}
+		var value json.RawMessage
+		if err := dec.Decode(&value); err != nil {
+			return err
+		}
+		var buf bytes.Buffer
+		buf.Grow(len(value))
+		if err := json.Compact(&buf, value); err != nil {
+			return err
+		}
+		appState = buf.Bytes()
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return appState, nil
+}
+
+// scanGenesisFile calls f for every top-level field of genesis file. f has to consume the value of the field
+// from dec.
+func scanGenesisFile(path string, f func(key string, dec *json.Decoder) error) error {
+	file, err := os.Open(path) //nolint:gosec // genesis path comes from node configuration
+	if err != nil {
+		return fmt.Errorf("couldn't read GenesisDoc file: %w", err)
+	}
+	defer file.Close() //nolint:errcheck // file is read only
+
+	dec := json.NewDecoder(bufio.NewReader(file))
+	if t, err := dec.Token(); err != nil || t != json.Delim('{') {
+		return fmt.Errorf("error reading GenesisDoc at %s: not a JSON object", path)
+	}
+	for dec.More() {
+		t, err := dec.Token()
+		if err != nil {
+			return fmt.Errorf("error reading GenesisDoc at %s: %w", path, err)
+		}
+		key, _ := t.(string)
+		if err := f(key, dec); err != nil {
+			return fmt.Errorf("error reading GenesisDoc at %s: field %s: %w", path, key, err)
+		}
+	}
+	return nil
+}
+
+// skipJSONValue consumes next JSON value from dec, token by token.
+func skipJSONValue(dec *json.Decoder) error {
+	depth := 0
+	for {
+		t, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		switch t {
+		case json.Delim('{'), json.Delim('['):
+			depth++
+		case json.Delim('}'), json.Delim(']'):
+			depth--
+		}
+		if depth == 0 {
+			return nil
+		}
+	}
+}
+
+// ResultGenesisChecksum describes chunked genesis, so that clients can verify genesis reassembled from chunks.
+type ResultGenesisChecksum struct {
+	TotalChunks int `json:"total"`
+	// Size is the size of serialized genesis, in bytes.
+	Size int64 `json:"size"`
+	// Checksum is SHA-256 hash of serialized genesis (concatenation of all decoded chunks).
+	Checksum cmbytes.HexBytes `json:"checksum"`
+}
+
+// genesisChunks serves genesis in base64 encoded chunks (see GenesisChunked RPC method).
+//
+// Chunks are not kept in memory - each chunk is computed from the source on request. Source is either
+// the genesis file the node was started from (chunk is read from the file), or genesis document (document
+// is serialized to JSON for the request, and the chunk is cut from it). Size and checksum of the source are
+// computed lazily, on first request.
+type genesisChunks struct {
+	path      string
+	doc       *cmtypes.GenesisDoc
+	chunkSize int64
+
+	once     sync.Once
+	err      error
+	size     int64
+	checksum []byte
+}
+
+// newGenesisChunks returns chunks of given genesis document.
+func newGenesisChunks(doc *cmtypes.GenesisDoc) *genesisChunks {
+	return &genesisChunks{doc: doc, chunkSize: genesisChunkSize}
+}
+
+// newFileGenesisChunks returns chunks of genesis file. File is streamed, never loaded into memory at once.
+func newFileGenesisChunks(path string) *genesisChunks {
+	return &genesisChunks{path: path, chunkSize: genesisChunkSize}
+}
+
+// Chunk returns base64 encoded chunk with given ID.
+func (g *genesisChunks) Chunk(id int) (string, error) {
+	total, err := g.Total()
+	if err != nil {
+		return "", err
+	}
+	if id < 0 || id >= total {
+		return "", fmt.Errorf("there are %d chunks, %d is invalid", total, id)
+	}
+
+	offset := int64(id) * g.chunkSize
+	size := g.size - offset
+	if size > g.chunkSize {
+		size = g.chunkSize
+	}
+	if g.path == "" {
+		data, err := g.marshalDoc()
+		if err != nil {
+			return "", err
+		}
+		if int64(len(data)) != g.size {
+			return "", errors.New("genesis document was modified after node start")
+		}
+		return base64.StdEncoding.EncodeToString(data[offset : offset+size]), nil
+	}
+
+	buf := make([]byte, size)
+
+	f, err := os.Open(g.path)
+	if err != nil {
+		return "", fmt.Errorf("failed to open genesis file: %w", err)
+	}
+	defer f.Close() //nolint:errcheck // file is read only
+	info, err := f.Stat()
+	if err != nil {
+		return "", fmt.Errorf("failed to read genesis file: %w", err)
+	}
+	if info.Size() != g.size {
+		return "", fmt.Errorf("genesis file %s was modified after node start", g.path)
+	}
+	if _, err := f.ReadAt(buf, offset); err != nil {
+		return "", fmt.Errorf("failed to read genesis file: %w", err)
+	}
+	return base64.StdEncoding.EncodeToString(buf), nil
+}
+
+// Total returns the number of chunks.
+func (g *genesisChunks) Total() (int, error) {
+	if err := g.init(); err != nil {
+		return 0, err
+	}
+	return int((g.size + g.chunkSize - 1) / g.chunkSize), nil
+}
+
+// Checksum returns number of chunks, size and checksum of serialized genesis.
+func (g *genesisChunks) Checksum() (*ResultGenesisChecksum, error) {
+	total, err := g.Total()
+	if err != nil {
+		return nil, err
+	}
+	return &ResultGenesisChecksum{TotalChunks: total, Size: g.size, Checksum: g.checksum}, nil
+}
+
+func (g *genesisChunks) init() error {
+	g.once.Do(func() {
+		g.err = g.load()
+	})
+	return g.err
+}
+
+func (g *genesisChunks) load() error {
+	hash := sha256.New()
+	if g.path == "" {
+		data, err := g.marshalDoc()
+		if err != nil {
+			return err
+		}
+		hash.Write(data) //nolint:errcheck // hash.Hash never returns an error
+		g.size = int64(len(data))
+	} else {
+		f, err := os.Open(g.path)
+		if err != nil {
+			return fmt.Errorf("failed to open genesis file: %w", err)
+		}
+		defer f.Close() //nolint:errcheck // file is read only
+		if g.size, err = io.Copy(hash, f); err != nil {
+			return fmt.Errorf("failed to read genesis file: %w", err)
+		}
+	}
+	g.checksum = hash.Sum(nil)
+	return nil
+}
+
+// marshalDoc serializes genesis document. Serialization is deterministic, so every chunk is cut from the same
+// bytes.
+func (g *genesisChunks) marshalDoc() ([]byte, error) {
+	if g.doc == nil {
+		return nil, errors.New("genesis document is not available")
+	}
+	data, err := json.Marshal(g.doc)
+	if err != nil {
+		return nil, err
+	}
+	return data, nil
+}
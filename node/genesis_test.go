@@ -0,0 +1,251 @@
+package node
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/cometbft/cometbft/abci/example/kvstore"
+	"github.com/cometbft/cometbft/libs/log"
+	"github.com/cometbft/cometbft/proxy"
+	"github.com/libp2p/go-libp2p/core/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	cmtypes "github.com/cometbft/cometbft/types"
+
+	"github.com/rollkit/rollkit/config"
+	test "github.com/rollkit/rollkit/test/log"
+	"github.com/rollkit/rollkit/types"
+)
+
+// readAllChunks reassembles genesis from chunks.
+func readAllChunks(t *testing.T, chunks *genesisChunks) []byte {
+	t.Helper()
+	total, err := chunks.Total()
+	require.NoError(t, err)
+	var data []byte
+	for i := 0; i < total; i++ {
+		chunk, err := chunks.Chunk(i)
+		require.NoError(t, err)
+		decoded, err := base64.StdEncoding.DecodeString(chunk)
+		require.NoError(t, err)
+		data = append(data, decoded...)
+	}
+	return data
+}
+
+func TestGenesisChunks(t *testing.T) {
+	genesisValidators, _ := types.GetGenesisValidatorSetWithSigner()
+	genDoc := &cmtypes.GenesisDoc{
+		ChainID:       "test",
+		InitialHeight: 1,
+		Validators:    genesisValidators,
+		AppState:      json.RawMessage(`{"accounts":["a","b","c"]}`),
+	}
+	require.NoError(t, genDoc.ValidateAndComplete())
+	genesisFile := filepath.Join(t.TempDir(), "genesis.json")
+	require.NoError(t, genDoc.SaveAs(genesisFile))
+	fileData, err := os.ReadFile(genesisFile)
+	require.NoError(t, err)
+	docData, err := json.Marshal(genDoc)
+	require.NoError(t, err)
+
+	cases := []struct {
+		name     string
+		chunks   *genesisChunks
+		expected []byte
+	}{
+		{"document", newGenesisChunks(genDoc), docData},
+		{"file", newFileGenesisChunks(genesisFile), fileData},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+
+			c.chunks.chunkSize = 100
+			total, err := c.chunks.Total()
+			require.NoError(err)
+			assert.Equal((len(c.expected)+99)/100, total)
+			assert.Equal(c.expected, readAllChunks(t, c.chunks))
+
+			checksum, err := c.chunks.Checksum()
+			require.NoError(err)
+			expectedSum := sha256.Sum256(c.expected)
+			assert.Equal(total, checksum.TotalChunks)
+			assert.Equal(int64(len(c.expected)), checksum.Size)
+			assert.EqualValues(expectedSum[:], checksum.Checksum)
+
+			_, err = c.chunks.Chunk(total)
+			assert.Error(err)
+			_, err = c.chunks.Chunk(-1)
+			assert.Error(err)
+		})
+	}
+}
+
+func TestGenesisChunksModifiedFile(t *testing.T) {
+	genesisFile := filepath.Join(t.TempDir(), "genesis.json")
+	require.NoError(t, os.WriteFile(genesisFile, []byte(`{"chain_id":"test"}`), 0600))
+
+	chunks := newFileGenesisChunks(genesisFile)
+	_, err := chunks.Chunk(0)
+	require.NoError(t, err)
+
+	require.NoError(t, os.WriteFile(genesisFile, []byte(`{"chain_id":"modified"}`), 0600))
+	_, err = chunks.Chunk(0)
+	assert.Error(t, err)
+
+	_, err = newFileGenesisChunks(filepath.Join(t.TempDir(), "missing.json")).Total()
+	assert.Error(t, err)
+}
+
+func TestLoadGenesisFile(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	genesisValidators, _ := types.GetGenesisValidatorSetWithSigner()
+	appState := json.RawMessage(`{"accounts":[{"name":"a","coins":[1,2]},{"name":"b \"}"}],"empty":{}}`)
+	genDoc := &cmtypes.GenesisDoc{
+		ChainID:       "test",
+		InitialHeight: 1,
+		Validators:    genesisValidators,
+		AppState:      appState,
+	}
+	require.NoError(genDoc.ValidateAndComplete())
+	genesisFile := filepath.Join(t.TempDir(), "genesis.json")
+	require.NoError(genDoc.SaveAs(genesisFile))
+
+	loaded, err := loadGenesisFile(genesisFile)
+	require.NoError(err)
+	assert.Nil(loaded.AppState)
+	assert.Equal(genDoc.ChainID, loaded.ChainID)
+	assert.True(genDoc.GenesisTime.Equal(loaded.GenesisTime))
+	assert.Equal(genDoc.Validators, loaded.Validators)
+	assert.Equal(genDoc.ConsensusParams, loaded.ConsensusParams)
+
+	loadedAppState, err := readGenesisAppState(genesisFile)
+	require.NoError(err)
+	assert.Equal(appState, loadedAppState)
+
+	// genesis without app state
+	genDoc.AppState = nil
+	require.NoError(genDoc.SaveAs(genesisFile))
+	loadedAppState, err = readGenesisAppState(genesisFile)
+	require.NoError(err)
+	assert.Nil(loadedAppState)
+
+	require.NoError(os.WriteFile(genesisFile, []byte(`["not", "an", "object"]`), 0600))
+	_, err = loadGenesisFile(genesisFile)
+	assert.Error(err)
+	_, err = loadGenesisFile(filepath.Join(t.TempDir(), "missing.json"))
+	assert.Error(err)
+}
+
+func TestGenesisExtension(t *testing.T) {
+	genesisFile := filepath.Join(t.TempDir(), "genesis.json")
+	ext, err := LoadGenesisExtension(genesisFile)
+	require.NoError(t, err)
+	assert.Nil(t, ext)
+
+	require.NoError(t, saveGenesisExtension(genesisFile, &GenesisExtension{ChainID: "test", DAStartHeight: 100}))
+	ext, err = LoadGenesisExtension(genesisFile)
+	require.NoError(t, err)
+	assert.Equal(t, &GenesisExtension{ChainID: "test", DAStartHeight: 100}, ext)
+
+	// result of genesis_extension RPC method saved as is
+	require.NoError(t, os.WriteFile(GenesisExtensionPath(genesisFile), []byte(`{"chain_id":"test","da_start_height":"101"}`), 0o600))
+	ext, err = LoadGenesisExtension(genesisFile)
+	require.NoError(t, err)
+	assert.Equal(t, &GenesisExtension{ChainID: "test", DAStartHeight: 101}, ext)
+	require.NoError(t, os.WriteFile(GenesisExtensionPath(genesisFile), []byte(`{"chain_id":"test","da_start_height":"x"}`), 0o600))
+	_, err = LoadGenesisExtension(genesisFile)
+	assert.Error(t, err)
+
+	genesis := &cmtypes.GenesisDoc{ChainID: "test"}
+	cases := []struct {
+		name          string
+		chainID       string
+		daStartHeight uint64
+		expected      uint64
+		wantErr       bool
+	}{
+		{"derived", "test", 0, 100, false},
+		{"raised", "test", 50, 100, false},
+		{"equal", "test", 100, 100, false},
+		{"too high", "test", 101, 0, true},
+		{"other chain", "other", 0, 0, true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			conf := config.NodeConfig{BlockManagerConfig: config.BlockManagerConfig{DAStartHeight: c.daStartHeight}}
+			err := applyGenesisExtension(&conf, genesis, &GenesisExtension{ChainID: c.chainID, DAStartHeight: 100}, log.NewNopLogger())
+			if c.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, c.expected, conf.DAStartHeight)
+		})
+	}
+}
+
+func TestGenesisExtensionWrittenByAggregator(t *testing.T) {
+	require := require.New(t)
+
+	genesisValidators, signingKey := types.GetGenesisValidatorSetWithSigner()
+	genDoc := &cmtypes.GenesisDoc{ChainID: "test", InitialHeight: 1, Validators: genesisValidators}
+	require.NoError(genDoc.ValidateAndComplete())
+	genesisFile := filepath.Join(t.TempDir(), "genesis.json")
+	require.NoError(genDoc.SaveAs(genesisFile))
+
+	conf := config.NodeConfig{
+		DALayer:    "newda",
+		Aggregator: true,
+		P2P:        config.P2PConfig{ListenAddress: "/ip4/127.0.0.1/tcp/0"},
+		BlockManagerConfig: config.BlockManagerConfig{
+			BlockTime:   100 * time.Millisecond,
+			DABlockTime: 100 * time.Millisecond,
+		},
+	}
+	key, _, _ := crypto.GenerateEd25519Key(rand.Reader)
+	n, err := NewNodeFromGenesisFile(context.Background(), conf, key, signingKey, proxy.NewLocalClientCreator(kvstore.NewApplication()), genesisFile, test.NewFileLogger(t))
+	require.NoError(err)
+	aggregator := n.(*FullNode)
+	require.NoError(aggregator.Start())
+	defer func() {
+		require.NoError(aggregator.Stop())
+	}()
+
+	// aggregator writes genesis extension when the first block is submitted to DA layer
+	var ext *GenesisExtension
+	require.Eventually(func() bool {
+		ext, err = LoadGenesisExtension(genesisFile)
+		return err == nil && ext != nil
+	}, 10*time.Second, 50*time.Millisecond)
+	assert.Equal(t, "test", ext.ChainID)
+	assert.Equal(t, aggregator.blockManager.DAStatus().DAStartHeight, ext.DAStartHeight)
+	assert.NotZero(t, ext.DAStartHeight)
+
+	// and serves it over RPC
+	served, err := NewFullClient(aggregator).GenesisExtension(context.Background())
+	require.NoError(err)
+	assert.Equal(t, ext, served)
+
+	// full node derives DA start height from genesis extension
+	conf.Aggregator = false
+	n, err = NewNodeFromGenesisFile(context.Background(), conf, key, signingKey, proxy.NewLocalClientCreator(kvstore.NewApplication()), genesisFile, test.NewFileLogger(t))
+	require.NoError(err)
+	assert.Equal(t, ext.DAStartHeight, n.(*FullNode).nodeConfig.DAStartHeight)
+
+	conf.DAStartHeight = ext.DAStartHeight + 1
+	_, err = NewNodeFromGenesisFile(context.Background(), conf, key, signingKey, proxy.NewLocalClientCreator(kvstore.NewApplication()), genesisFile, test.NewFileLogger(t))
+	assert.Error(t, err)
+}
@@ -1,6 +1,7 @@
 package node
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"testing"
@@ -49,11 +50,12 @@ func getBMConfig() config.BlockManagerConfig {
 	return config.BlockManagerConfig{
 		DABlockTime: 100 * time.Millisecond,
 		BlockTime:   1 * time.Second, // blocks must be at least 1 sec apart for adjacent headers to get verified correctly
-		NamespaceID: types.NamespaceID{8, 7, 6, 5, 4, 3, 2, 1},
+		Namespace:   types.MustNewNamespace(types.NamespaceVersionZero, []byte{8, 7, 6, 5, 4, 3, 2, 1}),
 	}
 }
 
-func getNodeHeight(node Node, source Source) (uint64, error) {
+// GetNodeHeight returns height of the node, as seen by given source.
+func GetNodeHeight(node Node, source Source) (uint64, error) {
 	switch source {
 	case Header:
 		return getNodeHeightFromHeader(node)
@@ -90,6 +92,17 @@ func getNodeHeightFromStore(node Node) (uint64, error) {
 	return 0, errors.New("not a full node")
 }
 
+// GetNodeHeader returns signed header at given height from the header store of full or light node.
+func GetNodeHeader(ctx context.Context, node Node, height uint64) (*types.SignedHeader, error) {
+	if fn, ok := node.(*FullNode); ok {
+		return fn.hSyncService.HeaderStore().GetByHeight(ctx, height)
+	}
+	if ln, ok := node.(*LightNode); ok {
+		return ln.hSyncService.HeaderStore().GetByHeight(ctx, height)
+	}
+	return nil, errors.New("not a full or light node")
+}
+
 // safeClose closes the channel if it's not closed already
 func safeClose(ch chan struct{}) {
 	select {
@@ -101,11 +114,11 @@ func safeClose(ch chan struct{}) {
 
 func verifyNodesSynced(node1, node2 Node, source Source) error {
 	return testutils.Retry(300, 100*time.Millisecond, func() error {
-		n1Height, err := getNodeHeight(node1, source)
+		n1Height, err := GetNodeHeight(node1, source)
 		if err != nil {
 			return err
 		}
-		n2Height, err := getNodeHeight(node2, source)
+		n2Height, err := GetNodeHeight(node2, source)
 		if err != nil {
 			return err
 		}
@@ -118,7 +131,7 @@ func verifyNodesSynced(node1, node2 Node, source Source) error {
 
 func waitForAtLeastNBlocks(node Node, n int, source Source) error {
 	return testutils.Retry(300, 100*time.Millisecond, func() error {
-		nHeight, err := getNodeHeight(node, source)
+		nHeight, err := GetNodeHeight(node, source)
 		if err != nil {
 			return err
 		}
@@ -14,6 +14,7 @@ import (
 
 	mockda "github.com/rollkit/rollkit/da/mock"
 	"github.com/rollkit/rollkit/store"
+	"github.com/rollkit/rollkit/types"
 )
 
 func TestMockTester(t *testing.T) {
@@ -30,7 +31,7 @@ func TestGetNodeHeight(t *testing.T) {
 	defer cancel()
 	dalc := &mockda.DataAvailabilityLayerClient{}
 	ds, _ := store.NewDefaultInMemoryKVStore()
-	_ = dalc.Init([8]byte{}, nil, ds, log.TestingLogger())
+	_ = dalc.Init(types.Namespace{}, nil, ds, log.TestingLogger())
 	_ = dalc.Start()
 	defer func() {
 		require.NoError(dalc.Stop())
@@ -56,7 +57,7 @@ func TestGetNodeHeight(t *testing.T) {
 	}()
 
 	require.NoError(testutils.Retry(1000, 100*time.Millisecond, func() error {
-		num, err := getNodeHeight(fullNode, Header)
+		num, err := GetNodeHeight(fullNode, Header)
 		if err != nil {
 			return err
 		}
@@ -66,7 +67,7 @@ func TestGetNodeHeight(t *testing.T) {
 		return errors.New("expected height > 0")
 	}))
 	require.NoError(testutils.Retry(1000, 100*time.Millisecond, func() error {
-		num, err := getNodeHeight(fullNode, Block)
+		num, err := GetNodeHeight(fullNode, Block)
 		if err != nil {
 			return err
 		}
@@ -76,7 +77,7 @@ func TestGetNodeHeight(t *testing.T) {
 		return errors.New("expected height > 0")
 	}))
 	require.NoError(testutils.Retry(1000, 100*time.Millisecond, func() error {
-		num, err := getNodeHeight(fullNode, Store)
+		num, err := GetNodeHeight(fullNode, Store)
 		if err != nil {
 			return err
 		}
@@ -86,7 +87,7 @@ func TestGetNodeHeight(t *testing.T) {
 		return errors.New("expected height > 0")
 	}))
 	require.NoError(testutils.Retry(1000, 100*time.Millisecond, func() error {
-		num, err := getNodeHeight(lightNode, Header)
+		num, err := GetNodeHeight(lightNode, Header)
 		if err != nil {
 			return err
 		}
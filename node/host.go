@@ -0,0 +1,193 @@
+package node
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	ds "github.com/ipfs/go-datastore"
+	"github.com/libp2p/go-libp2p/core/crypto"
+	"go.uber.org/multierr"
+
+	"github.com/cometbft/cometbft/libs/log"
+	"github.com/cometbft/cometbft/libs/service"
+	proxy "github.com/cometbft/cometbft/proxy"
+	cmtypes "github.com/cometbft/cometbft/types"
+
+	"github.com/rollkit/rollkit/config"
+	"github.com/rollkit/rollkit/da"
+	"github.com/rollkit/rollkit/da/shared"
+	"github.com/rollkit/rollkit/p2p"
+	"github.com/rollkit/rollkit/store"
+	"github.com/rollkit/rollkit/types"
+)
+
+// chainsPrefix is used in shared datastore for data of chains run by Host.
+const chainsPrefix = "chains"
+
+// defaultDABatchWindow is used only if DABatchWindow is not configured.
+const defaultDABatchWindow = 100 * time.Millisecond
+
+// HostConfig configures resources shared by all chains run by Host.
+type HostConfig struct {
+	// NodeConfig configures shared datastore (RootDir, DBPath, DBConfig), DA layer client (DALayer, DAConfig,
+	// Namespace) and libp2p host (P2P).
+	config.NodeConfig
+
+	// DABatchWindow is the time for which blocks submitted by all chains are collected, before being submitted
+	// to DA layer in single batch.
+	DABatchWindow time.Duration
+}
+
+// sharedResources are resources shared by all chains run by Host.
+type sharedResources struct {
+	baseKV ds.TxnDatastore
+	dalc   *shared.Client
+	p2p    *p2p.SharedHost
+}
+
+// chainKV returns part of shared datastore used by given chain. Chain ID is hex encoded, as it may contain
+// characters with special meaning in datastore keys.
+func (r *sharedResources) chainKV(chainID string) ds.TxnDatastore {
+	return store.NewPrefixKV(r.baseKV, ds.NewKey(chainsPrefix).ChildString(hex.EncodeToString([]byte(chainID))))
+}
+
+// Host runs many chains (full nodes) in one process.
+//
+// Chains share single datastore (each chain uses its own prefix), single DA layer client (each chain uses its own
+// namespace, blocks of all chains are submitted in batches) and single libp2p host (each chain uses its own
+// pubsub topics, discovery namespace and sync protocols). Each chain has its own ABCI application, mempool,
+// block manager and RPC client - to serve RPC of each chain, create separate RPC server for every node returned
+// by AddChain.
+type Host struct {
+	service.BaseService
+
+	shared *sharedResources
+	p2pKey crypto.PrivKey
+
+	chainsMtx  sync.Mutex
+	chains     []*FullNode
+	namespaces map[types.Namespace]string
+
+	ctx context.Context
+}
+
+// NewHost creates Host and its shared resources. libp2p host starts listening immediately.
+func NewHost(ctx context.Context, conf HostConfig, p2pKey crypto.PrivKey, logger log.Logger) (*Host, error) {
+	baseKV, err := initBaseKV(conf.NodeConfig, logger)
+	if err != nil {
+		return nil, err
+	}
+	dalc, err := initDALC(conf.NodeConfig, store.NewPrefixKV(baseKV, ds.NewKey(dalcPrefix)), logger)
+	if err != nil {
+		return nil, multierr.Append(err, baseKV.Close())
+	}
+	h, err := newHost(ctx, conf, p2pKey, baseKV, dalc, logger)
+	if err != nil {
+		return nil, multierr.Append(err, baseKV.Close())
+	}
+	return h, nil
+}
+
+// newHost creates Host using given datastore and initialized DA layer client.
+func newHost(ctx context.Context, conf HostConfig, p2pKey crypto.PrivKey, baseKV ds.TxnDatastore, dalc da.DataAvailabilityLayerClient, logger log.Logger) (*Host, error) {
+	batchWindow := conf.DABatchWindow
+	if batchWindow == 0 {
+		batchWindow = defaultDABatchWindow
+	}
+	sharedDALC, err := shared.New(dalc, batchWindow, logger.With("module", "da_client"))
+	if err != nil {
+		return nil, err
+	}
+	sharedHost, err := p2p.NewSharedHost(ctx, conf.P2P, p2pKey, baseKV, logger.With("module", "p2p"))
+	if err != nil {
+		return nil, err
+	}
+
+	h := &Host{
+		shared: &sharedResources{
+			baseKV: baseKV,
+			dalc:   sharedDALC,
+			p2p:    sharedHost,
+		},
+		p2pKey:     p2pKey,
+		namespaces: make(map[types.Namespace]string),
+		ctx:        ctx,
+	}
+	h.BaseService = *service.NewBaseService(logger, "Host", h)
+	return h, nil
+}
+
+// AddChain creates full node of a chain, using resources shared by Host. Chain ID and namespace have to be
+// unique among chains run by Host. Shared configuration (datastore, DA layer and P2P) in conf is ignored.
+// AddChain has to be called before Start; nodes are started and stopped with Host.
+func (h *Host) AddChain(conf config.NodeConfig, signingKey crypto.PrivKey, clientCreator proxy.ClientCreator, genesis *cmtypes.GenesisDoc, logger log.Logger) (*FullNode, error) {
+	if h.IsRunning() {
+		return nil, errors.New("chains can't be added to running host")
+	}
+	if conf.Light {
+		return nil, errors.New("light nodes can't be run by host")
+	}
+
+	h.chainsMtx.Lock()
+	defer h.chainsMtx.Unlock()
+	for _, n := range h.chains {
+		if n.genesis.ChainID == genesis.ChainID {
+			return nil, fmt.Errorf("chain %s is already added", genesis.ChainID)
+		}
+	}
+	if chainID, ok := h.namespaces[conf.Namespace]; ok {
+		return nil, fmt.Errorf("namespace %s is already used by chain %s", conf.Namespace, chainID)
+	}
+
+	n, err := newFullNodeWithResources(h.ctx, conf, h.p2pKey, signingKey, clientCreator, genesis, "", h.shared, logger.With("chain", genesis.ChainID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create node of chain %s: %w", genesis.ChainID, err)
+	}
+	h.chains = append(h.chains, n)
+	h.namespaces[conf.Namespace] = genesis.ChainID
+	return n, nil
+}
+
+// Chains returns nodes of all chains run by Host.
+func (h *Host) Chains() []*FullNode {
+	h.chainsMtx.Lock()
+	defer h.chainsMtx.Unlock()
+	return append([]*FullNode(nil), h.chains...)
+}
+
+// OnStart is a part of Service interface. Shared DA layer client is started, then nodes of all chains.
+func (h *Host) OnStart() error {
+	if err := h.shared.dalc.Start(); err != nil {
+		return fmt.Errorf("error while starting data availability layer client: %w", err)
+	}
+	for _, n := range h.Chains() {
+		if err := n.Start(); err != nil {
+			return fmt.Errorf("error while starting node of chain %s: %w", n.genesis.ChainID, err)
+		}
+	}
+	return nil
+}
+
+// OnStop is a part of Service interface. Nodes of all chains are stopped (submitting pending blocks to DA layer),
+// then shared DA layer client, libp2p host and datastore are closed.
+func (h *Host) OnStop() {
+	var err error
+	for _, n := range h.Chains() {
+		if n.IsRunning() {
+			err = multierr.Append(err, n.Stop())
+		}
+	}
+	h.Logger.Info("shutdown: stopping shared resources")
+	err = multierr.Append(err, h.shared.dalc.Stop())
+	err = multierr.Append(err, h.shared.p2p.Close())
+	err = multierr.Append(err, h.shared.baseKV.Close())
+	if err != nil {
+		h.Logger.Error("errors while stopping host", "errors", err)
+		return
+	}
+	h.Logger.Info("host stopped")
+}
@@ -0,0 +1,99 @@
+package node
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"testing"
+	"time"
+
+	testutils "github.com/celestiaorg/utils/test"
+	"github.com/cometbft/cometbft/abci/example/kvstore"
+	"github.com/cometbft/cometbft/proxy"
+	cmtypes "github.com/cometbft/cometbft/types"
+	"github.com/libp2p/go-libp2p/core/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rollkit/rollkit/config"
+	mockda "github.com/rollkit/rollkit/da/mock"
+	"github.com/rollkit/rollkit/store"
+	test "github.com/rollkit/rollkit/test/log"
+	"github.com/rollkit/rollkit/types"
+)
+
+func TestHost(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	logger := test.NewFileLogger(t)
+
+	baseKV, err := store.NewDefaultInMemoryKVStore()
+	require.NoError(err)
+	dalc := &mockda.DataAvailabilityLayerClient{}
+	require.NoError(dalc.Init(types.Namespace{}, []byte((100 * time.Millisecond).String()), baseKV, logger))
+	p2pKey, _, _ := crypto.GenerateEd25519Key(rand.Reader)
+	hostConf := HostConfig{
+		NodeConfig:    config.NodeConfig{P2P: config.P2PConfig{ListenAddress: "/ip4/127.0.0.1/tcp/0"}},
+		DABatchWindow: 50 * time.Millisecond,
+	}
+	h, err := newHost(ctx, hostConf, p2pKey, baseKV, dalc, logger)
+	require.NoError(err)
+
+	genesisValidators, signingKey := types.GetGenesisValidatorSetWithSigner()
+	addChain := func(chainID string, namespaceID types.Namespace) (*FullNode, error) {
+		conf := config.NodeConfig{
+			Aggregator: true,
+			BlockManagerConfig: config.BlockManagerConfig{
+				BlockTime:   100 * time.Millisecond,
+				DABlockTime: 100 * time.Millisecond,
+				Namespace:   namespaceID,
+			},
+		}
+		genesis := &cmtypes.GenesisDoc{ChainID: chainID, InitialHeight: 1, Validators: genesisValidators}
+		return h.AddChain(conf, signingKey, proxy.NewLocalClientCreator(kvstore.NewApplication()), genesis, logger)
+	}
+	_, err = addChain("chain1", types.MustNewNamespace(types.NamespaceVersionZero, []byte{1}))
+	require.NoError(err)
+	_, err = addChain("chain2", types.MustNewNamespace(types.NamespaceVersionZero, []byte{2}))
+	require.NoError(err)
+
+	// chain ID and namespace have to be unique
+	_, err = addChain("chain1", types.MustNewNamespace(types.NamespaceVersionZero, []byte{3}))
+	assert.Error(err)
+	_, err = addChain("chain3", types.MustNewNamespace(types.NamespaceVersionZero, []byte{1}))
+	assert.Error(err)
+
+	require.NoError(h.Start())
+	defer func() {
+		assert.NoError(h.Stop())
+	}()
+	_, err = addChain("chain3", types.MustNewNamespace(types.NamespaceVersionZero, []byte{3}))
+	assert.Error(err)
+
+	chains := h.Chains()
+	require.Len(chains, 2)
+	for _, n := range chains {
+		require.NoError(waitForAtLeastNBlocks(n, 3, Store))
+
+		// blocks are submitted to DA layer and retrieved back from chain's namespace
+		block, err := n.Store.GetBlock(1)
+		require.NoError(err)
+		require.NoError(testutils.Retry(300, 100*time.Millisecond, func() error {
+			if !n.blockManager.IsDAIncluded(block.Hash()) {
+				return errors.New("block not included in DA layer yet")
+			}
+			return nil
+		}))
+	}
+
+	// chains share libp2p host, but not the datastore
+	assert.Equal(chains[0].P2PClient().Host().ID(), chains[1].P2PClient().Host().ID())
+	for _, n := range chains {
+		block, err := n.Store.GetBlock(1)
+		require.NoError(err)
+		assert.Equal(n.genesis.ChainID, block.SignedHeader.ChainID())
+	}
+}
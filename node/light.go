@@ -17,6 +17,7 @@ import (
 	"github.com/rollkit/rollkit/config"
 	"github.com/rollkit/rollkit/p2p"
 	"github.com/rollkit/rollkit/store"
+	rollkitlog "github.com/rollkit/rollkit/third_party/log"
 )
 
 var _ Node = &LightNode{}
@@ -27,11 +28,14 @@ type LightNode struct {
 
 	P2P *p2p.Client
 
+	conf config.NodeConfig
+
 	proxyApp proxy.AppConns
 
 	hSyncService *block.HeaderSyncService
 
-	client rpcclient.Client
+	client    rpcclient.Client
+	datastore ds.TxnDatastore
 
 	ctx    context.Context
 	cancel context.CancelFunc
@@ -61,7 +65,7 @@ func newLightNode(
 	if err != nil {
 		return nil, err
 	}
-	client, err := p2p.NewClient(conf.P2P, p2pKey, genesis.ChainID, datastore, logger.With("module", "p2p"))
+	client, err := p2p.NewClient(conf.P2P, p2pKey, genesis.ChainID, conf.Namespace, datastore, logger.With("module", "p2p"))
 	if err != nil {
 		return nil, err
 	}
@@ -75,8 +79,10 @@ func newLightNode(
 
 	node := &LightNode{
 		P2P:          client,
+		conf:         conf,
 		proxyApp:     proxyApp,
 		hSyncService: headerSyncService,
+		datastore:    datastore,
 		cancel:       cancel,
 		ctx:          ctx,
 	}
@@ -92,10 +98,10 @@ func newLightNode(
 
 func openDatastore(conf config.NodeConfig, logger log.Logger) (ds.TxnDatastore, error) {
 	if conf.RootDir == "" && conf.DBPath == "" { // this is used for testing
-		logger.Info("WARNING: working in in-memory mode")
-		return store.NewDefaultInMemoryKVStore()
+		rollkitlog.Warn(logger, "working in in-memory mode")
+		return store.NewInMemoryKVStore(conf.Badger)
 	}
-	return store.NewDefaultKVStore(conf.RootDir, conf.DBPath, "rollkit-light")
+	return store.NewKVStore(conf.RootDir, conf.DBPath, "rollkit-light", conf.DBConfig)
 }
 
 // Cancel calls the underlying context's cancel function.
@@ -120,9 +126,17 @@ func (ln *LightNode) OnStart() error {
 func (ln *LightNode) OnStop() {
 	ln.Logger.Info("halting light node...")
 	ln.cancel()
-	err := ln.P2P.Close()
-	err = multierr.Append(err, ln.hSyncService.Stop())
-	ln.Logger.Error("errors while stopping node:", "errors", err)
+	ln.Logger.Info("shutdown: stopping header sync service")
+	err := ln.hSyncService.Stop()
+	ln.Logger.Info("shutdown: stopping P2P client")
+	err = multierr.Append(err, ln.P2P.Close())
+	ln.Logger.Info("shutdown: closing datastore")
+	err = multierr.Append(err, ln.datastore.Close())
+	if err != nil {
+		ln.Logger.Error("errors while stopping node", "errors", err)
+		return
+	}
+	ln.Logger.Info("light node stopped")
 }
 
 // Dummy validator that always returns a callback function with boolean `false`
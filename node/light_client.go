@@ -2,11 +2,15 @@ package node
 
 import (
 	"context"
+	"fmt"
+	"io"
 
 	cmbytes "github.com/cometbft/cometbft/libs/bytes"
 	rpcclient "github.com/cometbft/cometbft/rpc/client"
 	ctypes "github.com/cometbft/cometbft/rpc/core/types"
 	"github.com/cometbft/cometbft/types"
+
+	abciconv "github.com/rollkit/rollkit/types/abci"
 )
 
 var _ rpcclient.Client = &LightClient{}
@@ -190,3 +194,29 @@ func (c *LightClient) Header(ctx context.Context, height *int64) (*ctypes.Result
 func (c *LightClient) HeaderByHash(ctx context.Context, hash cmbytes.HexBytes) (*ctypes.ResultHeader, error) {
 	panic("Not implemented")
 }
+
+// LightBlock returns signed header and validator set at given height (or the latest height, if height is nil),
+// in the format verifiable by CometBFT light client. Headers are served from the header store of the light node.
+func (c *LightClient) LightBlock(ctx context.Context, height *int64) (*types.LightBlock, error) {
+	headerStore := c.node.hSyncService.HeaderStore()
+	head, err := headerStore.Head(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load head of header store: %w", err)
+	}
+	if height == nil || uint64(*height) == head.Height() {
+		return abciconv.ToABCILightBlock(head)
+	}
+	if *height <= 0 || uint64(*height) > head.Height() {
+		return nil, fmt.Errorf("height %d is not available (latest height: %d)", *height, head.Height())
+	}
+	header, err := headerStore.GetByHeight(ctx, uint64(*height))
+	if err != nil {
+		return nil, err
+	}
+	return abciconv.ToABCILightBlock(header)
+}
+
+// Backup writes consistent snapshot of all light node data to w.
+func (c *LightClient) Backup(ctx context.Context, w io.Writer) error {
+	return c.node.Backup(w)
+}
@@ -53,3 +53,43 @@ func NewNode(
 		)
 	}
 }
+
+// NewNodeFromGenesisFile returns a new Full or Light Node, using genesis stored in given file.
+//
+// Unlike NewNode, app state from genesis is never kept in memory by the node - it's skipped when the file is
+// parsed, read from the file only when it has to be passed to the application on chain initialization, and
+// genesis is served to RPC clients directly from the file.
+func NewNodeFromGenesisFile(
+	ctx context.Context,
+	conf config.NodeConfig,
+	p2pKey crypto.PrivKey,
+	signingKey crypto.PrivKey,
+	appClient proxy.ClientCreator,
+	genesisFile string,
+	logger log.Logger,
+) (Node, error) {
+	genesis, err := loadGenesisFile(genesisFile)
+	if err != nil {
+		return nil, err
+	}
+	if conf.Light {
+		return newLightNode(ctx, conf, p2pKey, appClient, genesis, logger)
+	}
+
+	ext, err := LoadGenesisExtension(genesisFile)
+	if err != nil {
+		return nil, err
+	}
+	if ext != nil {
+		if err := applyGenesisExtension(&conf, genesis, ext, logger); err != nil {
+			return nil, err
+		}
+	}
+
+	n, err := newFullNodeWithResources(ctx, conf, p2pKey, signingKey, appClient, genesis, genesisFile, nil, logger)
+	if err != nil {
+		return nil, err
+	}
+	n.genesisExt = ext
+	return n, nil
+}
@@ -0,0 +1,125 @@
+package node
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/cometbft/cometbft/libs/log"
+
+	"github.com/rollkit/rollkit/config"
+	rollkitlog "github.com/rollkit/rollkit/third_party/log"
+)
+
+// ErrNoConfigLoader is returned when configuration reload is requested, but node doesn't know
+// where to read configuration from.
+var ErrNoConfigLoader = errors.New("configuration loader is not set")
+
+// Config returns current configuration of the node.
+func (n *FullNode) Config() config.NodeConfig {
+	n.confMtx.Lock()
+	defer n.confMtx.Unlock()
+	return n.nodeConfig.WithReloadable(n.reloadable)
+}
+
+// OnConfigReload registers a function called with new configuration after every successful reload.
+func (n *FullNode) OnConfigReload(hook func(config.NodeConfig)) {
+	n.confMtx.Lock()
+	defer n.confMtx.Unlock()
+	n.reloadHooks = append(n.reloadHooks, hook)
+}
+
+// SetConfigLoader sets a function used by ReloadConfig to read configuration (see config.NewConfigLoader).
+func (n *FullNode) SetConfigLoader(loader func() (config.NodeConfig, error)) {
+	n.confMtx.Lock()
+	defer n.confMtx.Unlock()
+	n.configLoader = loader
+}
+
+// ReloadConfig reads configuration using configuration loader and applies it with Reload.
+func (n *FullNode) ReloadConfig() error {
+	n.confMtx.Lock()
+	loader := n.configLoader
+	n.confMtx.Unlock()
+
+	if loader == nil {
+		return ErrNoConfigLoader
+	}
+	conf, err := loader()
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+	// options derived from genesis extension at startup are derived again, so they don't differ from current ones
+	if n.genesisExt != nil {
+		if err := applyGenesisExtension(&conf, n.genesis, n.genesisExt, log.NewNopLogger()); err != nil {
+			return err
+		}
+	}
+	return n.Reload(conf)
+}
+
+// Reload applies new configuration to the running node.
+//
+// Only options listed in config.ReloadableConfig can be changed. If any other option differs from
+// the current configuration, config.ErrNotReloadable is returned and no changes are applied.
+//
+// New configuration is validated before any change is applied. Peer lists are updated first, as it's
+// the only step that can fail at runtime; if it fails, no other option is changed.
+func (n *FullNode) Reload(newConf config.NodeConfig) error {
+	n.confMtx.Lock()
+	defer n.confMtx.Unlock()
+
+	oldConf := n.nodeConfig.WithReloadable(n.reloadable)
+	changes, err := config.Diff(oldConf, newConf)
+	if err != nil {
+		return err
+	}
+	if len(changes) == 0 {
+		n.Logger.Info("configuration reloaded, nothing changed")
+		return nil
+	}
+
+	rc := newConf.Reloadable()
+	levels, err := rollkitlog.ParseLevels(rc.LogLevel)
+	if err != nil {
+		return err
+	}
+	if rc.BlockedPeers != n.reloadable.BlockedPeers || rc.AllowedPeers != n.reloadable.AllowedPeers {
+		if err := n.p2pClient.UpdatePeerLists(rc.BlockedPeers, rc.AllowedPeers); err != nil {
+			return fmt.Errorf("failed to update peer lists: %w", err)
+		}
+	}
+	n.logFilter.SetLevels(levels)
+	n.blockManager.SetLazyBlockTime(rc.LazyBlockTime)
+	n.reloadable = rc
+
+	for _, c := range changes {
+		n.Logger.Info("configuration option changed", "option", c.Option, "old", c.Old, "new", c.New)
+	}
+	for _, hook := range n.reloadHooks {
+		hook(newConf)
+	}
+	return nil
+}
+
+// ReloadOnSignal reloads node configuration every time process receives SIGHUP, until ctx is done.
+func ReloadOnSignal(ctx context.Context, n *FullNode) {
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGHUP)
+	defer signal.Stop(sigCh)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-sigCh:
+			n.Logger.Info("received SIGHUP, reloading configuration")
+			if err := n.ReloadConfig(); err != nil {
+				n.Logger.Error("failed to reload configuration", "error", err)
+			}
+		}
+	}
+}
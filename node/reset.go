@@ -0,0 +1,113 @@
+package node
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	ds "github.com/ipfs/go-datastore"
+	dsq "github.com/ipfs/go-datastore/query"
+
+	"github.com/cometbft/cometbft/libs/log"
+
+	"github.com/rollkit/rollkit/config"
+	"github.com/rollkit/rollkit/store"
+)
+
+// addrBookPrefix is a prefix of keys used by libp2p connection gater to persist blocked peers, addresses and subnets.
+const addrBookPrefix = "/libp2p"
+
+// ErrResetRunningNode is returned when reset of running node is requested.
+var ErrResetRunningNode = errors.New("node data can't be reset while node is running")
+
+// ResetOptions defines which data is removed by node reset.
+//
+// Keys and configuration files are never removed.
+type ResetOptions struct {
+	// KeepAddrBook preserves P2P peer data (connection gater state).
+	KeepAddrBook bool
+	// IndexerOnly removes only transaction and block indexes, all other data is preserved.
+	IndexerOnly bool
+}
+
+// ResetData removes data of stopped node (see Reset).
+func (n *FullNode) ResetData(opts ResetOptions) error {
+	if n.IsRunning() {
+		return ErrResetRunningNode
+	}
+	return Reset(n.nodeConfig, opts, n.Logger)
+}
+
+// OnReset is a part of Service interface.
+//
+// All node data is removed. Node has to be re-created to be started again.
+func (n *FullNode) OnReset() error {
+	return n.ResetData(ResetOptions{})
+}
+
+// OnReset is a part of Service interface.
+//
+// All node data is removed. Node has to be re-created to be started again.
+func (ln *LightNode) OnReset() error {
+	if ln.IsRunning() {
+		return ErrResetRunningNode
+	}
+	return Reset(ln.conf, ResetOptions{}, ln.Logger)
+}
+
+// Reset removes node data from the datastore configured in conf. Node using the datastore can't be running.
+//
+// Nodes working in in-memory mode don't persist any data, so there is nothing to reset.
+func Reset(conf config.NodeConfig, opts ResetOptions, logger log.Logger) error {
+	if conf.RootDir == "" && conf.DBPath == "" {
+		logger.Info("node works in in-memory mode, nothing to reset")
+		return nil
+	}
+	dbName := "rollkit"
+	if conf.Light {
+		dbName = "rollkit-light"
+	}
+	kv, err := store.NewKVStore(conf.RootDir, conf.DBPath, dbName, conf.DBConfig)
+	if err != nil {
+		return fmt.Errorf("failed to open datastore: %w", err)
+	}
+
+	removed, err := ResetDatastore(context.Background(), kv, opts)
+	if closeErr := kv.Close(); err == nil && closeErr != nil {
+		err = fmt.Errorf("failed to close datastore: %w", closeErr)
+	}
+	if err != nil {
+		return err
+	}
+	logger.Info("node data removed", "keys", removed, "keepAddrBook", opts.KeepAddrBook, "indexerOnly", opts.IndexerOnly)
+	return nil
+}
+
+// ResetDatastore removes node data from kv, according to opts. It returns number of removed keys.
+func ResetDatastore(ctx context.Context, kv ds.Datastore, opts ResetOptions) (int, error) {
+	prefix := ""
+	if opts.IndexerOnly {
+		prefix = ds.NewKey(indexerPrefix).String()
+	}
+	results, err := kv.Query(ctx, dsq.Query{Prefix: prefix, KeysOnly: true})
+	if err != nil {
+		return 0, fmt.Errorf("failed to query datastore: %w", err)
+	}
+	entries, err := results.Rest()
+	if err != nil {
+		return 0, fmt.Errorf("failed to query datastore: %w", err)
+	}
+
+	removed := 0
+	for _, e := range entries {
+		if opts.KeepAddrBook && strings.HasPrefix(e.Key, addrBookPrefix+"/") {
+			continue
+		}
+		if err := kv.Delete(ctx, ds.NewKey(e.Key)); err != nil {
+			return removed, fmt.Errorf("failed to remove %s: %w", e.Key, err)
+		}
+		removed++
+	}
+	return removed, kv.Sync(ctx, ds.NewKey(prefix))
+}
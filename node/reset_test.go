@@ -0,0 +1,92 @@
+package node
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cometbft/cometbft/libs/log"
+	ds "github.com/ipfs/go-datastore"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rollkit/rollkit/config"
+	"github.com/rollkit/rollkit/store"
+)
+
+var resetTestKeys = []string{
+	"/0/s",
+	"/0/headerSync/1",
+	"/0/blockSync/1",
+	"/1/da",
+	"/2/tx",
+	"/2/block_events/1",
+	"/libp2p/net/conngater/peer/1",
+}
+
+func fillDatastore(t *testing.T, kv ds.Datastore) {
+	for _, k := range resetTestKeys {
+		require.NoError(t, kv.Put(context.Background(), ds.NewKey(k), []byte(k)))
+	}
+}
+
+func remainingKeys(t *testing.T, kv ds.Datastore) []string {
+	var keys []string
+	for _, k := range resetTestKeys {
+		has, err := kv.Has(context.Background(), ds.NewKey(k))
+		require.NoError(t, err)
+		if has {
+			keys = append(keys, k)
+		}
+	}
+	return keys
+}
+
+func TestResetDatastore(t *testing.T) {
+	cases := []struct {
+		name      string
+		opts      ResetOptions
+		remaining []string
+	}{
+		{"all", ResetOptions{}, nil},
+		{"keep address book", ResetOptions{KeepAddrBook: true}, []string{"/libp2p/net/conngater/peer/1"}},
+		{"indexer only", ResetOptions{IndexerOnly: true}, []string{"/0/s", "/0/headerSync/1", "/0/blockSync/1", "/1/da", "/libp2p/net/conngater/peer/1"}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			kv, err := store.NewDefaultInMemoryKVStore()
+			require.NoError(t, err)
+			fillDatastore(t, kv)
+
+			removed, err := ResetDatastore(context.Background(), kv, c.opts)
+			require.NoError(t, err)
+			assert.Equal(t, len(resetTestKeys)-len(c.remaining), removed)
+			assert.Equal(t, c.remaining, remainingKeys(t, kv))
+		})
+	}
+}
+
+func TestReset(t *testing.T) {
+	require := require.New(t)
+
+	conf := config.NodeConfig{RootDir: t.TempDir(), DBPath: "data"}
+	kv, err := store.NewDefaultKVStore(conf.RootDir, conf.DBPath, "rollkit")
+	require.NoError(err)
+	fillDatastore(t, kv)
+	require.NoError(kv.Close())
+
+	require.NoError(Reset(conf, ResetOptions{KeepAddrBook: true}, log.TestingLogger()))
+
+	kv, err = store.NewDefaultKVStore(conf.RootDir, conf.DBPath, "rollkit")
+	require.NoError(err)
+	defer func() {
+		require.NoError(kv.Close())
+	}()
+	assert.Equal(t, []string{"/libp2p/net/conngater/peer/1"}, remainingKeys(t, kv))
+}
+
+func TestResetRunningNode(t *testing.T) {
+	node := initializeAndStartFullNode(context.Background(), t)
+	defer cleanUpNode(node, t)
+
+	assert.ErrorIs(t, node.ResetData(ResetOptions{}), ErrResetRunningNode)
+}
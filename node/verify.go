@@ -0,0 +1,36 @@
+package node
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/cometbft/cometbft/libs/log"
+
+	"github.com/rollkit/rollkit/config"
+	"github.com/rollkit/rollkit/store"
+)
+
+// VerifyStore checks consistency of the store of stopped full node, using datastore configured in conf
+// (see store.Verify). Index-only problems are fixed if opts.Repair is set.
+func VerifyStore(ctx context.Context, conf config.NodeConfig, opts store.VerifyOptions, logger log.Logger) (*store.VerifyReport, error) {
+	if conf.RootDir == "" && conf.DBPath == "" {
+		return nil, errors.New("node works in in-memory mode, there is no store to verify")
+	}
+	if conf.Light {
+		return nil, errors.New("light node doesn't keep blocks, there is no store to verify")
+	}
+	kv, err := store.NewKVStore(conf.RootDir, conf.DBPath, "rollkit", conf.DBConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open datastore: %w", err)
+	}
+	report, err := store.Verify(ctx, newPrefixKV(kv, mainPrefix), opts)
+	if closeErr := kv.Close(); err == nil && closeErr != nil {
+		err = fmt.Errorf("failed to close datastore: %w", closeErr)
+	}
+	if err != nil {
+		return nil, err
+	}
+	logger.Info("store verified", "checkedBlocks", report.CheckedBlocks, "problems", len(report.Problems), "repair", opts.Repair)
+	return report, nil
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/hex"
 	"fmt"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/cometbft/cometbft/p2p"
@@ -29,6 +30,7 @@ import (
 
 	"github.com/rollkit/rollkit/config"
 	"github.com/rollkit/rollkit/third_party/log"
+	"github.com/rollkit/rollkit/types"
 )
 
 // TODO(tzdybal): refactor to configuration parameters
@@ -49,9 +51,10 @@ const (
 // Those seed nodes serve Kademlia DHT protocol, and are agnostic to ORU chain. Using DHT
 // peer routing and discovery clients find other peers within ORU network.
 type Client struct {
-	conf    config.P2PConfig
-	chainID string
-	privKey crypto.PrivKey
+	conf      config.P2PConfig
+	chainID   string
+	namespace types.Namespace
+	privKey   crypto.PrivKey
 
 	host  host.Host
 	dht   *dht.IpfsDHT
@@ -59,9 +62,17 @@ type Client struct {
 	gater *conngater.BasicConnectionGater
 	ps    *pubsub.PubSub
 
+	// providedHost is used instead of creating new libp2p host in Start, if set (see SetHost)
+	providedHost host.Host
+	// shared is used instead of creating new libp2p host, gossipsub and DHT in Start, if set (see SetSharedHost)
+	shared *SharedHost
+
 	txGossiper  *Gossiper
 	txValidator GossipValidator
 
+	// peerListsMtx guards blocked/allowed peers in conf, as they can be updated at runtime
+	peerListsMtx sync.Mutex
+
 	// cancel is used to cancel context passed to libp2p functions
 	// it's required because of discovery.Advertise call
 	cancel context.CancelFunc
@@ -73,7 +84,7 @@ type Client struct {
 //
 // Basic checks on parameters are done, and default parameters are provided for unset-configuration
 // TODO(tzdybal): consider passing entire config, not just P2P config, to reduce number of arguments
-func NewClient(conf config.P2PConfig, privKey crypto.PrivKey, chainID string, ds datastore.Datastore, logger log.Logger) (*Client, error) {
+func NewClient(conf config.P2PConfig, privKey crypto.PrivKey, chainID string, namespace types.Namespace, ds datastore.Datastore, logger log.Logger) (*Client, error) {
 	if privKey == nil {
 		return nil, errNoPrivKey
 	}
@@ -87,11 +98,12 @@ func NewClient(conf config.P2PConfig, privKey crypto.PrivKey, chainID string, ds
 	}
 
 	return &Client{
-		conf:    conf,
-		gater:   gater,
-		privKey: privKey,
-		chainID: chainID,
-		logger:  logger,
+		conf:      conf,
+		gater:     gater,
+		privKey:   privKey,
+		chainID:   chainID,
+		namespace: namespace,
+		logger:    logger,
 	}, nil
 }
 
@@ -106,6 +118,12 @@ func (c *Client) Start(ctx context.Context) error {
 	// create new, cancelable context
 	ctx, c.cancel = context.WithCancel(ctx)
 	c.logger.Debug("starting P2P client")
+	if c.shared != nil {
+		return c.startWithSharedHost(ctx)
+	}
+	if c.providedHost != nil {
+		return c.startWithHost(ctx, c.providedHost)
+	}
 	host, err := c.listen(ctx)
 	if err != nil {
 		return err
@@ -113,19 +131,41 @@ func (c *Client) Start(ctx context.Context) error {
 	return c.startWithHost(ctx, host)
 }
 
+// SetHost makes Client use the given libp2p host instead of creating a new one in Start.
+// It has to be called before Start. It's used to run nodes on in-process networks (like libp2p mocknet).
+func (c *Client) SetHost(h host.Host) {
+	c.providedHost = h
+}
+
+// SetSharedHost makes Client use libp2p host, gossipsub and DHT shared with clients of other chains, instead of
+// creating them in Start. Client uses its own pubsub topics, discovery namespace and sync protocols (all derived
+// from chain ID). Connection gater (and lists of blocked and allowed peers) and node key are also shared.
+// It has to be called before Start.
+func (c *Client) SetSharedHost(sh *SharedHost) {
+	c.shared = sh
+	c.gater = sh.gater
+	c.privKey = sh.privKey
+}
+
+func (c *Client) startWithSharedHost(ctx context.Context) error {
+	c.host, c.ps, c.dht = c.shared.host, c.shared.ps, c.shared.dht
+
+	c.logger.Debug("setting up gossiping")
+	if err := c.setupTxGossiper(ctx); err != nil {
+		return err
+	}
+
+	c.logger.Debug("setting up active peer discovery")
+	return c.peerDiscovery(ctx)
+}
+
 func (c *Client) startWithHost(ctx context.Context, h host.Host) error {
 	c.host = h
 	for _, a := range c.host.Addrs() {
 		c.logger.Info("listening on", "address", fmt.Sprintf("%s/p2p/%s", a, c.host.ID()))
 	}
 
-	c.logger.Debug("blocking blacklisted peers", "blacklist", c.conf.BlockedPeers)
-	if err := c.setupBlockedPeers(c.parseAddrInfoList(c.conf.BlockedPeers)); err != nil {
-		return err
-	}
-
-	c.logger.Debug("allowing whitelisted peers", "whitelist", c.conf.AllowedPeers)
-	if err := c.setupAllowedPeers(c.parseAddrInfoList(c.conf.AllowedPeers)); err != nil {
+	if err := c.setupPeerLists(); err != nil {
 		return err
 	}
 
@@ -147,10 +187,13 @@ func (c *Client) startWithHost(ctx context.Context, h host.Host) error {
 	return nil
 }
 
-// Close gently stops Client.
+// Close gently stops Client. Shared host is not closed (see SharedHost.Close).
 func (c *Client) Close() error {
 	c.cancel()
 
+	if c.shared != nil {
+		return c.txGossiper.Close()
+	}
 	return multierr.Combine(
 		c.txGossiper.Close(),
 		c.dht.Close(),
@@ -305,6 +348,81 @@ func (c *Client) setupPeerDiscovery(ctx context.Context) error {
 	return nil
 }
 
+// UpdatePeerLists replaces lists of blocked and allowed peers (comma separated multiaddrs).
+//
+// Allowed peers are never blocked, even if they are also on blocked list. Peers that are no longer blocked
+// (removed from blocked list or added to allowed list) are unblocked. Newly blocked peers (including blocked
+// peers removed from allowed list) are blocked and disconnected. If connection gater can't be updated,
+// previous lists are restored.
+func (c *Client) UpdatePeerLists(blocked, allowed string) error {
+	c.peerListsMtx.Lock()
+	defer c.peerListsMtx.Unlock()
+
+	oldBlocked := c.blockedPeerIDs(c.conf.BlockedPeers, c.conf.AllowedPeers)
+	newBlocked := c.blockedPeerIDs(blocked, allowed)
+	if err := c.updateBlockedPeers(oldBlocked, newBlocked); err != nil {
+		if rbErr := c.updateBlockedPeers(newBlocked, oldBlocked); rbErr != nil {
+			c.logger.Error("failed to restore previous peer lists", "error", rbErr)
+		}
+		return err
+	}
+	c.conf.BlockedPeers = blocked
+	c.conf.AllowedPeers = allowed
+
+	if c.host == nil {
+		return nil
+	}
+	var err error
+	for id := range newBlocked {
+		err = multierr.Append(err, c.host.Network().ClosePeer(id))
+	}
+	return err
+}
+
+// blockedPeerIDs returns IDs of peers from blocked list, that are not on allowed list.
+func (c *Client) blockedPeerIDs(blocked, allowed string) map[peer.ID]struct{} {
+	ids := make(map[peer.ID]struct{})
+	for _, p := range c.parseAddrInfoList(blocked) {
+		ids[p.ID] = struct{}{}
+	}
+	for _, p := range c.parseAddrInfoList(allowed) {
+		delete(ids, p.ID)
+	}
+	return ids
+}
+
+// updateBlockedPeers unblocks peers from oldBlocked that are not in newBlocked, and blocks all peers from newBlocked.
+func (c *Client) updateBlockedPeers(oldBlocked, newBlocked map[peer.ID]struct{}) error {
+	for id := range oldBlocked {
+		if _, ok := newBlocked[id]; ok {
+			continue
+		}
+		if err := c.gater.UnblockPeer(id); err != nil {
+			return err
+		}
+	}
+	for id := range newBlocked {
+		if err := c.gater.BlockPeer(id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// setupPeerLists blocks and allows peers from configuration.
+func (c *Client) setupPeerLists() error {
+	c.peerListsMtx.Lock()
+	defer c.peerListsMtx.Unlock()
+
+	c.logger.Debug("blocking blacklisted peers", "blacklist", c.conf.BlockedPeers)
+	if err := c.setupBlockedPeers(c.parseAddrInfoList(c.conf.BlockedPeers)); err != nil {
+		return err
+	}
+
+	c.logger.Debug("allowing whitelisted peers", "whitelist", c.conf.AllowedPeers)
+	return c.setupAllowedPeers(c.parseAddrInfoList(c.conf.AllowedPeers))
+}
+
 func (c *Client) setupBlockedPeers(peers []peer.AddrInfo) error {
 	for _, p := range peers {
 		if err := c.gater.BlockPeer(p.ID); err != nil {
@@ -355,7 +473,11 @@ func (c *Client) setupGossiping(ctx context.Context) error {
 	if err != nil {
 		return err
 	}
+	return c.setupTxGossiper(ctx)
+}
 
+func (c *Client) setupTxGossiper(ctx context.Context) error {
+	var err error
 	c.txGossiper, err = NewGossiper(c.host, c.ps, c.getTxTopic(), c.logger, WithValidator(c.txValidator))
 	if err != nil {
 		return err
@@ -391,9 +513,14 @@ func (c *Client) parseAddrInfoList(addrInfoStr string) []peer.AddrInfo {
 // getNamespace returns unique string identifying ORU network.
 //
 // It is used to advertise/find peers in libp2p DHT.
-// For now, chainID is used.
+// chainID is used, followed by DA namespace of the chain if its version is above 0. Version 0 namespaces (including
+// legacy namespace IDs) are not included, so that nodes of such chains share topics with nodes that don't know
+// about namespace versions.
 func (c *Client) getNamespace() string {
-	return c.chainID
+	if c.namespace.Version() == types.NamespaceVersionZero {
+		return c.chainID
+	}
+	return c.chainID + "/" + c.namespace.String()
 }
 
 func (c *Client) getTxTopic() string {
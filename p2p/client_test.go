@@ -3,7 +3,9 @@ package p2p
 import (
 	"context"
 	"crypto/rand"
+	"fmt"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -18,6 +20,7 @@ import (
 
 	"github.com/rollkit/rollkit/config"
 	test "github.com/rollkit/rollkit/test/log"
+	"github.com/rollkit/rollkit/types"
 )
 
 func TestClientStartup(t *testing.T) {
@@ -44,7 +47,7 @@ func TestClientStartup(t *testing.T) {
 
 	for _, testCase := range testCases {
 		t.Run(testCase.desc, func(t *testing.T) {
-			client, err := NewClient(testCase.p2pconf, privKey, "TestChain",
+			client, err := NewClient(testCase.p2pconf, privKey, "TestChain", types.Namespace{},
 				dssync.MutexWrap(datastore.NewMapDatastore()), test.NewFileLoggerCustom(t, test.TempLogFileName(t, testCase.desc)))
 			assert.NoError(err)
 			assert.NotNil(client)
@@ -193,7 +196,7 @@ func TestSeedStringParsing(t *testing.T) {
 			assert := assert.New(t)
 			require := require.New(t)
 			logger := &test.MockLogger{}
-			client, err := NewClient(config.P2PConfig{}, privKey, "TestNetwork",
+			client, err := NewClient(config.P2PConfig{}, privKey, "TestNetwork", types.Namespace{},
 				dssync.MutexWrap(datastore.NewMapDatastore()), logger)
 			require.NoError(err)
 			require.NotNil(client)
@@ -205,3 +208,134 @@ func TestSeedStringParsing(t *testing.T) {
 		})
 	}
 }
+
+func TestSharedHost(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	logger := test.NewFileLogger(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	newSharedHost := func(seeds string) *SharedHost {
+		privKey, _, _ := crypto.GenerateEd25519Key(rand.Reader)
+		conf := config.P2PConfig{ListenAddress: "/ip4/127.0.0.1/tcp/0", Seeds: seeds}
+		sh, err := NewSharedHost(ctx, conf, privKey, dssync.MutexWrap(datastore.NewMapDatastore()), logger)
+		require.NoError(err)
+		return sh
+	}
+	sh1 := newSharedHost("")
+	sh2 := newSharedHost(fmt.Sprintf("%s/p2p/%s", sh1.Host().Addrs()[0], sh1.Host().ID()))
+
+	// two chains on each host
+	chains := []string{"chainA", "chainB"}
+	var received [2][2]atomic.Int32
+	var clients [2][2]*Client
+	for i, sh := range []*SharedHost{sh1, sh2} {
+		for j, chainID := range chains {
+			privKey, _, _ := crypto.GenerateEd25519Key(rand.Reader)
+			client, err := NewClient(config.P2PConfig{}, privKey, chainID, types.Namespace{}, dssync.MutexWrap(datastore.NewMapDatastore()), logger)
+			require.NoError(err)
+			client.SetSharedHost(sh)
+			counter := &received[i][j]
+			client.SetTxValidator(func(*GossipMessage) bool {
+				counter.Add(1)
+				return true
+			})
+			require.NoError(client.Start(ctx))
+			clients[i][j] = client
+		}
+	}
+	assert.Equal(sh1.Host().ID(), clients[0][0].Host().ID())
+	assert.Equal(sh1.Host().ID(), clients[0][1].Host().ID())
+	id0, _, _, err := clients[0][0].Info()
+	require.NoError(err)
+	id1, _, _, err := clients[0][1].Info()
+	require.NoError(err)
+	assert.Equal(id0, id1)
+
+	// this sleep is required for pubsub to "propagate" subscription information
+	time.Sleep(1 * time.Second)
+
+	require.NoError(clients[1][0].GossipTx(ctx, []byte("foobar")))
+	require.Eventually(func() bool { return received[0][0].Load() == 1 }, 5*time.Second, 10*time.Millisecond)
+	// transactions of one chain are not delivered to other chains
+	assert.Zero(received[0][1].Load())
+	assert.Zero(received[1][1].Load())
+
+	// closing clients doesn't close shared host
+	for i := range clients {
+		for j := range clients[i] {
+			assert.NoError(clients[i][j].Close())
+		}
+	}
+	assert.NotEmpty(sh1.Host().Network().Peers())
+	assert.NoError(sh1.Close())
+	assert.NoError(sh2.Close())
+}
+
+func TestGetNamespace(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	privKey, _, _ := crypto.GenerateEd25519Key(rand.Reader)
+	newClient := func(namespace types.Namespace) *Client {
+		client, err := NewClient(config.P2PConfig{}, privKey, "TestChain", namespace, dssync.MutexWrap(datastore.NewMapDatastore()), test.NewFileLogger(t))
+		require.NoError(err)
+		return client
+	}
+
+	// without DA namespace, only chain ID is used
+	client := newClient(types.Namespace{})
+	assert.Equal("TestChain", client.getNamespace())
+	assert.Equal("TestChain-tx", client.getTxTopic())
+
+	// version 0 namespaces keep topics used before namespaces were versioned
+	client = newClient(types.MustNewNamespace(types.NamespaceVersionZero, []byte{1}))
+	assert.Equal("TestChain", client.getNamespace())
+
+	// chains with the same chain ID, but namespaces of higher versions, use different topics
+	client1 := newClient(types.MustNewNamespace(1, []byte{1}))
+	client2 := newClient(types.MustNewNamespace(1, []byte{2}))
+	assert.Equal("TestChain/1:01", client1.getNamespace())
+	assert.NotEqual(client1.getTxTopic(), client2.getTxTopic())
+}
+
+func TestUpdatePeerLists(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	addrs := make([]string, 2)
+	ids := make([]peer.ID, 2)
+	for i := range addrs {
+		privKey, _, err := crypto.GenerateEd25519Key(rand.Reader)
+		require.NoError(err)
+		ids[i], err = peer.IDFromPrivateKey(privKey)
+		require.NoError(err)
+		addrs[i] = "/ip4/127.0.0.1/tcp/7676/p2p/" + ids[i].String()
+	}
+	blocked := addrs[0] + "," + addrs[1]
+
+	privKey, _, _ := crypto.GenerateEd25519Key(rand.Reader)
+	client, err := NewClient(config.P2PConfig{BlockedPeers: blocked, AllowedPeers: addrs[1]}, privKey, "TestChain", types.Namespace{},
+		dssync.MutexWrap(datastore.NewMapDatastore()), test.NewFileLogger(t))
+	require.NoError(err)
+	require.NoError(client.setupPeerLists())
+	assert.False(client.gater.InterceptPeerDial(ids[0]))
+	assert.True(client.gater.InterceptPeerDial(ids[1]))
+
+	// peer removed from allowed list is blocked
+	require.NoError(client.UpdatePeerLists(blocked, ""))
+	assert.False(client.gater.InterceptPeerDial(ids[0]))
+	assert.False(client.gater.InterceptPeerDial(ids[1]))
+
+	// peer removed from blocked list is unblocked
+	require.NoError(client.UpdatePeerLists(addrs[1], ""))
+	assert.True(client.gater.InterceptPeerDial(ids[0]))
+	assert.False(client.gater.InterceptPeerDial(ids[1]))
+
+	// peer added to allowed list is unblocked
+	require.NoError(client.UpdatePeerLists(addrs[1], addrs[1]))
+	assert.True(client.gater.InterceptPeerDial(ids[1]))
+	assert.Equal(addrs[1], client.conf.AllowedPeers)
+}
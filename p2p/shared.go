@@ -0,0 +1,86 @@
+package p2p
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ipfs/go-datastore"
+	dht "github.com/libp2p/go-libp2p-kad-dht"
+	pubsub "github.com/libp2p/go-libp2p-pubsub"
+	"github.com/libp2p/go-libp2p/core/crypto"
+	"github.com/libp2p/go-libp2p/core/host"
+	"github.com/libp2p/go-libp2p/p2p/net/conngater"
+	"go.uber.org/multierr"
+
+	"github.com/rollkit/rollkit/config"
+	"github.com/rollkit/rollkit/third_party/log"
+	"github.com/rollkit/rollkit/types"
+)
+
+// SharedHost is a libp2p host, together with gossipsub router, DHT and connection gater, shared by P2P clients
+// of many chains running in one process (see Client.SetSharedHost).
+type SharedHost struct {
+	host  host.Host
+	dht   *dht.IpfsDHT
+	ps    *pubsub.PubSub
+	gater *conngater.BasicConnectionGater
+
+	privKey crypto.PrivKey
+	cancel  context.CancelFunc
+}
+
+// NewSharedHost creates libp2p host listening on conf.ListenAddress, connects to seed nodes and sets up
+// gossipsub and DHT. Blocked and allowed peers from conf apply to all chains.
+func NewSharedHost(ctx context.Context, conf config.P2PConfig, privKey crypto.PrivKey, ds datastore.Datastore, logger log.Logger) (*SharedHost, error) {
+	// client without chain is used to setup host, in the same way as for single chain
+	c, err := NewClient(conf, privKey, "", types.Namespace{}, ds, logger)
+	if err != nil {
+		return nil, err
+	}
+	ctx, c.cancel = context.WithCancel(ctx)
+	sh, err := c.newSharedHost(ctx)
+	if err != nil {
+		c.cancel()
+		return nil, err
+	}
+	return sh, nil
+}
+
+func (c *Client) newSharedHost(ctx context.Context) (sh *SharedHost, err error) {
+	if c.host, err = c.listen(ctx); err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			err = multierr.Append(err, c.host.Close())
+		}
+	}()
+	for _, a := range c.host.Addrs() {
+		c.logger.Info("listening on", "address", fmt.Sprintf("%s/p2p/%s", a, c.host.ID()))
+	}
+
+	if err = c.setupPeerLists(); err != nil {
+		return nil, err
+	}
+	if c.ps, err = pubsub.NewGossipSub(ctx, c.host); err != nil {
+		return nil, err
+	}
+	if err = c.setupDHT(ctx); err != nil {
+		return nil, err
+	}
+	return &SharedHost{host: c.host, dht: c.dht, ps: c.ps, gater: c.gater, privKey: c.privKey, cancel: c.cancel}, nil
+}
+
+// Host returns the shared libp2p host.
+func (sh *SharedHost) Host() host.Host {
+	return sh.host
+}
+
+// Close stops the shared host. It has to be called after P2P clients of all chains are closed.
+func (sh *SharedHost) Close() error {
+	sh.cancel()
+	return multierr.Combine(
+		sh.dht.Close(),
+		sh.host.Close(),
+	)
+}
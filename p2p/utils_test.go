@@ -19,6 +19,7 @@ import (
 
 	"github.com/rollkit/rollkit/config"
 	"github.com/rollkit/rollkit/third_party/log"
+	"github.com/rollkit/rollkit/types"
 )
 
 type testNet []*Client
@@ -105,7 +106,7 @@ func startTestNetwork(ctx context.Context, t *testing.T, n int, conf map[int]hos
 	for i := 0; i < n; i++ {
 		client, err := NewClient(config.P2PConfig{Seeds: seeds[i]},
 			mnet.Hosts()[i].Peerstore().PrivKey(mnet.Hosts()[i].ID()),
-			conf[i].chainID, sync.MutexWrap(datastore.NewMapDatastore()), logger)
+			conf[i].chainID, types.Namespace{}, sync.MutexWrap(datastore.NewMapDatastore()), logger)
 		require.NoError(err)
 		require.NotNil(client)
 
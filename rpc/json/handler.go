@@ -1,10 +1,12 @@
 package json
 
 import (
+	"context"
 	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"net/url"
 	"reflect"
@@ -40,6 +42,9 @@ func newHandler(s *service, codec rpc.Codec, logger log.Logger) *handler {
 		logger.Debug("registering method", "name", name)
 		mux.HandleFunc("/"+name, h.newHandler(method))
 	}
+	if b, ok := s.client.(backupProvider); ok && s.unsafe {
+		mux.HandleFunc("/unsafe_backup", h.backupHandler(b))
+	}
 
 	return h
 }
@@ -69,7 +74,7 @@ func (h *handler) serveJSONRPCforWS(w http.ResponseWriter, r *http.Request, wsCo
 	}
 	methodSpec, ok := h.srv.methods[method]
 	if !ok {
-		codecReq.WriteError(w, int(json2.E_NO_METHOD), err)
+		codecReq.WriteError(w, int(json2.E_NO_METHOD), &json2.Error{Code: json2.E_NO_METHOD, Message: "method not found: " + method})
 		return
 	}
 
@@ -171,6 +176,45 @@ func (h *handler) newHandler(methodSpec *method) func(http.ResponseWriter, *http
 	}
 }
 
+// backupProvider is implemented by clients of nodes supporting online backup.
+type backupProvider interface {
+	Backup(ctx context.Context, w io.Writer) error
+}
+
+// backupHandler streams backup of node data as HTTP response body.
+//
+// If backup fails after streaming started, connection is aborted, so client doesn't receive truncated backup
+// as a valid response.
+func (h *handler) backupHandler(b backupProvider) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		w.Header().Set("Content-Type", "application/octet-stream")
+		cw := &countingWriter{w: w}
+		if err := b.Backup(r.Context(), cw); err != nil {
+			h.logger.Error("failed to write backup", "error", err)
+			if cw.n == 0 {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			panic(http.ErrAbortHandler)
+		}
+	}
+}
+
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (cw *countingWriter) Write(p []byte) (int, error) {
+	n, err := cw.w.Write(p)
+	cw.n += int64(n)
+	return n, err
+}
+
 func (h *handler) encodeAndWriteResponse(w http.ResponseWriter, result interface{}, errResult error, statusCode int) {
 	// Prevents Internet Explorer from MIME-sniffing a response away
 	// from the declared content-type
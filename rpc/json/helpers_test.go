@@ -45,6 +45,8 @@ func getRPC(t *testing.T) (*mocks.Application, rpcclient.Client) {
 		GasWanted: 1000,
 		GasUsed:   1000,
 	})
+	// handshake performed on node start
+	app.On("Info", mock.Anything).Return(abci.ResponseInfo{}).Once()
 	app.On("Info", mock.Anything).Return(abci.ResponseInfo{
 		Data:             "mock",
 		Version:          "mock",
@@ -3,6 +3,7 @@ package json
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"reflect"
@@ -10,14 +11,27 @@ import (
 
 	rpcclient "github.com/cometbft/cometbft/rpc/client"
 	ctypes "github.com/cometbft/cometbft/rpc/core/types"
+	"github.com/cometbft/cometbft/types"
 	"github.com/gorilla/rpc/v2/json2"
 
+	"github.com/rollkit/rollkit/block"
+	"github.com/rollkit/rollkit/execution"
+	"github.com/rollkit/rollkit/node"
+	"github.com/rollkit/rollkit/store"
 	"github.com/rollkit/rollkit/third_party/log"
 )
 
 // GetHTTPHandler returns handler configured to serve Tendermint-compatible RPC.
+//
+// Unsafe methods are not served; see GetUnsafeHTTPHandler.
 func GetHTTPHandler(l rpcclient.Client, logger log.Logger) (http.Handler, error) {
-	return newHandler(newService(l, logger), json2.NewCodec(), logger), nil
+	return newHandler(newService(l, logger, false), json2.NewCodec(), logger), nil
+}
+
+// GetUnsafeHTTPHandler returns handler configured to serve Tendermint-compatible RPC, including unsafe methods
+// (e.g. unsafe_reload_config). It should be used only if unsafe RPC was explicitly enabled by node operator.
+func GetUnsafeHTTPHandler(l rpcclient.Client, logger log.Logger) (http.Handler, error) {
+	return newHandler(newService(l, logger, true), json2.NewCodec(), logger), nil
 }
 
 type method struct {
@@ -42,12 +56,15 @@ type service struct {
 	client  rpcclient.Client
 	methods map[string]*method
 	logger  log.Logger
+	// unsafe enables methods that change node state or expose node data (names prefixed with "unsafe_")
+	unsafe bool
 }
 
-func newService(c rpcclient.Client, l log.Logger) *service {
+func newService(c rpcclient.Client, l log.Logger, unsafe bool) *service {
 	s := service{
 		client: c,
 		logger: l,
+		unsafe: unsafe,
 	}
 	s.methods = map[string]*method{
 		"subscribe":            newMethod(s.Subscribe),
@@ -80,9 +97,73 @@ func newService(c rpcclient.Client, l log.Logger) *service {
 		"abci_info":            newMethod(s.ABCIInfo),
 		"broadcast_evidence":   newMethod(s.BroadcastEvidence),
 	}
+	if _, ok := c.(configReloader); ok && unsafe {
+		s.methods["unsafe_reload_config"] = newMethod(s.UnsafeReloadConfig)
+	}
+	if _, ok := c.(txSimulator); ok {
+		s.methods["simulate_txs"] = newMethod(s.SimulateTxs)
+	}
+	if _, ok := c.(daStatusProvider); ok {
+		s.methods["da_status"] = newMethod(s.DAStatus)
+	}
+	if _, ok := c.(blockFinalityProvider); ok {
+		s.methods["block_finality"] = newMethod(s.BlockFinality)
+	}
+	if _, ok := c.(storeCacheStatsProvider); ok {
+		s.methods["store_cache_stats"] = newMethod(s.StoreCacheStats)
+	}
+	if _, ok := c.(genesisChecksumProvider); ok {
+		s.methods["genesis_checksum"] = newMethod(s.GenesisChecksum)
+	}
+	if _, ok := c.(genesisExtensionProvider); ok {
+		s.methods["genesis_extension"] = newMethod(s.GenesisExtension)
+	}
+	if _, ok := c.(lightBlockProvider); ok {
+		s.methods["light_block"] = newMethod(s.LightBlock)
+	}
 	return &s
 }
 
+// configReloader is implemented by clients of nodes supporting runtime configuration changes.
+type configReloader interface {
+	ReloadConfig(ctx context.Context) error
+}
+
+// txSimulator is implemented by clients of nodes supporting dry-run block execution.
+type txSimulator interface {
+	SimulateTxs(ctx context.Context, txs []types.Tx) (*execution.SimulationResult, error)
+}
+
+// daStatusProvider is implemented by clients of nodes reporting progress of DA submission and retrieval.
+type daStatusProvider interface {
+	DAStatus(ctx context.Context) (*block.DAStatus, error)
+}
+
+// blockFinalityProvider is implemented by clients of nodes tracking DA finality of blocks.
+type blockFinalityProvider interface {
+	BlockFinality(ctx context.Context, height *int64) (*node.ResultBlockFinality, error)
+}
+
+// storeCacheStatsProvider is implemented by clients of nodes with cached store.
+type storeCacheStatsProvider interface {
+	StoreCacheStats(ctx context.Context) (*store.CacheStats, error)
+}
+
+// genesisChecksumProvider is implemented by clients serving chunked genesis with its checksum.
+type genesisChecksumProvider interface {
+	GenesisChecksum(ctx context.Context) (*node.ResultGenesisChecksum, error)
+}
+
+// genesisExtensionProvider is implemented by clients serving Rollkit genesis extension.
+type genesisExtensionProvider interface {
+	GenesisExtension(ctx context.Context) (*node.GenesisExtension, error)
+}
+
+// lightBlockProvider is implemented by clients serving headers in the format of CometBFT light client (used by IBC relayers).
+type lightBlockProvider interface {
+	LightBlock(ctx context.Context, height *int64) (*types.LightBlock, error)
+}
+
 func (s *service) Subscribe(req *http.Request, args *subscribeArgs, wsConn *wsConn) (*ctypes.ResultSubscribe, error) {
 	// TODO(tzdybal): pass config and check subscriptions limits
 	// TODO(tzdybal): extract consts or configs
@@ -147,6 +228,14 @@ func (s *service) NetInfo(req *http.Request, args *netInfoArgs) (*ctypes.ResultN
 	return s.client.NetInfo(req.Context())
 }
 
+func (s *service) GenesisChecksum(req *http.Request, args *genesisChecksumArgs) (*node.ResultGenesisChecksum, error) {
+	return s.client.(genesisChecksumProvider).GenesisChecksum(req.Context())
+}
+
+func (s *service) GenesisExtension(req *http.Request, args *genesisExtensionArgs) (*node.GenesisExtension, error) {
+	return s.client.(genesisExtensionProvider).GenesisExtension(req.Context())
+}
+
 func (s *service) BlockchainInfo(req *http.Request, args *blockchainInfoArgs) (*ctypes.ResultBlockchainInfo, error) {
 	return s.client.BlockchainInfo(req.Context(), int64(args.MinHeight), int64(args.MaxHeight))
 }
@@ -175,6 +264,15 @@ func (s *service) Commit(req *http.Request, args *commitArgs) (*ctypes.ResultCom
 	return s.client.Commit(req.Context(), (*int64)(&args.Height))
 }
 
+func (s *service) LightBlock(req *http.Request, args *lightBlockArgs) (*types.LightBlock, error) {
+	// height is optional, latest light block is returned by default
+	var height *int64
+	if args.Height > 0 {
+		height = (*int64)(&args.Height)
+	}
+	return s.client.(lightBlockProvider).LightBlock(req.Context(), height)
+}
+
 func (s *service) CheckTx(req *http.Request, args *checkTxArgs) (*ctypes.ResultCheckTx, error) {
 	return s.client.CheckTx(req.Context(), args.Tx)
 }
@@ -244,3 +342,37 @@ func (s *service) ABCIInfo(req *http.Request, args *ABCIInfoArgs) (*ctypes.Resul
 func (s *service) BroadcastEvidence(req *http.Request, args *broadcastEvidenceArgs) (*ctypes.ResultBroadcastEvidence, error) {
 	return s.client.BroadcastEvidence(req.Context(), args.Evidence)
 }
+
+func (s *service) SimulateTxs(req *http.Request, args *simulateTxsArgs) (*execution.SimulationResult, error) {
+	if len(args.Txs) == 0 {
+		return nil, errors.New("no transactions to simulate")
+	}
+	return s.client.(txSimulator).SimulateTxs(req.Context(), args.Txs)
+}
+
+// DA API
+func (s *service) DAStatus(req *http.Request, args *daStatusArgs) (*block.DAStatus, error) {
+	return s.client.(daStatusProvider).DAStatus(req.Context())
+}
+
+func (s *service) BlockFinality(req *http.Request, args *blockFinalityArgs) (*node.ResultBlockFinality, error) {
+	// height is optional, finality of the latest block is returned by default
+	var height *int64
+	if args.Height > 0 {
+		height = (*int64)(&args.Height)
+	}
+	return s.client.(blockFinalityProvider).BlockFinality(req.Context(), height)
+}
+
+func (s *service) StoreCacheStats(req *http.Request, args *storeCacheStatsArgs) (*store.CacheStats, error) {
+	return s.client.(storeCacheStatsProvider).StoreCacheStats(req.Context())
+}
+
+// unsafe API
+func (s *service) UnsafeReloadConfig(req *http.Request, args *unsafeReloadConfigArgs) (*emptyResult, error) {
+	s.logger.Info("reloading configuration", "remote", req.RemoteAddr)
+	if err := s.client.(configReloader).ReloadConfig(req.Context()); err != nil {
+		return nil, fmt.Errorf("failed to reload configuration: %w", err)
+	}
+	return &emptyResult{}, nil
+}
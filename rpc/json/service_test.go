@@ -35,6 +35,46 @@ func TestHandlerMapping(t *testing.T) {
 	assert.Equal(http.StatusOK, resp.Code)
 }
 
+func TestUnsafeMethods(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	_, local := getRPC(t)
+	jsonReq, err := json2.EncodeClientRequest("unsafe_reload_config", &unsafeReloadConfigArgs{})
+	require.NoError(err)
+	call := func(handler http.Handler) *response {
+		req := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(jsonReq))
+		resp := httptest.NewRecorder()
+		handler.ServeHTTP(resp, req)
+		var jsonResp response
+		require.NoError(json.Unmarshal(resp.Body.Bytes(), &jsonResp))
+		return &jsonResp
+	}
+
+	// backup is streamed by dedicated handler, otherwise request is served by JSON-RPC handler
+	backupServed := func(handler http.Handler) bool {
+		resp := httptest.NewRecorder()
+		handler.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/unsafe_backup", nil))
+		return resp.Header().Get("Content-Type") == "application/octet-stream"
+	}
+
+	// unsafe methods are not served by default
+	handler, err := GetHTTPHandler(local, log.TestingLogger())
+	require.NoError(err)
+	jsonResp := call(handler)
+	require.NotNil(jsonResp.Error)
+	assert.EqualValues(json2.E_NO_METHOD, jsonResp.Error.Code)
+	assert.False(backupServed(handler))
+
+	// node doesn't have configuration loader, but method is served
+	handler, err = GetUnsafeHTTPHandler(local, log.TestingLogger())
+	require.NoError(err)
+	jsonResp = call(handler)
+	require.NotNil(jsonResp.Error)
+	assert.Contains(jsonResp.Error.Message, "configuration loader is not set")
+	assert.True(backupServed(handler))
+}
+
 func TestREST(t *testing.T) {
 	assert := assert.New(t)
 	require := require.New(t)
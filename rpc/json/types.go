@@ -35,6 +35,10 @@ type genesisArgs struct {
 type genesisChunkedArgs struct {
 	ID StrInt `json:"chunk"`
 }
+type genesisChecksumArgs struct {
+}
+type genesisExtensionArgs struct {
+}
 type blockArgs struct {
 	Height StrInt64 `json:"height"`
 }
@@ -47,6 +51,9 @@ type blockResultsArgs struct {
 type commitArgs struct {
 	Height StrInt64 `json:"height"`
 }
+type lightBlockArgs struct {
+	Height StrInt64 `json:"height"`
+}
 type checkTxArgs struct {
 	Tx types.Tx `json:"tx"`
 }
@@ -110,12 +117,35 @@ type ABCIQueryArgs struct {
 type ABCIInfoArgs struct {
 }
 
+// DA API
+
+type daStatusArgs struct {
+}
+
+type blockFinalityArgs struct {
+	Height StrInt64 `json:"height"`
+}
+
+// store API
+
+type storeCacheStatsArgs struct {
+}
+
 // evidence API
 
 type broadcastEvidenceArgs struct {
 	Evidence types.Evidence `json:"evidence"`
 }
 
+// unsafe API
+
+type simulateTxsArgs struct {
+	Txs []types.Tx `json:"txs"`
+}
+
+type unsafeReloadConfigArgs struct {
+}
+
 type emptyResult struct{}
 
 // JSON-deserialization specific types
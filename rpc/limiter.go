@@ -0,0 +1,82 @@
+package rpc
+
+import (
+	"math"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// rateLimiter is a token bucket limiting the number of requests served per second.
+//
+// Limit and burst can be changed at runtime. Zero limit disables rate limiting.
+type rateLimiter struct {
+	mtx    sync.Mutex
+	limit  float64
+	burst  float64
+	tokens float64
+	last   time.Time
+	now    func() time.Time
+}
+
+func newRateLimiter(limit float64, burst int) *rateLimiter {
+	l := &rateLimiter{now: time.Now}
+	l.SetLimit(limit, burst)
+	return l
+}
+
+// SetLimit changes the limit (requests per second) and the burst of the limiter.
+//
+// Tokens collected so far are kept (up to the new burst), so that changing the limit doesn't let clients send
+// another burst of requests. The bucket is filled only if rate limiting was disabled.
+func (l *rateLimiter) SetLimit(limit float64, burst int) {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	now := l.now()
+	if l.limit > 0 {
+		l.tokens += now.Sub(l.last).Seconds() * l.limit
+	}
+	// at least one request has to be allowed
+	l.burst = math.Max(float64(burst), 1)
+	if l.limit <= 0 {
+		l.tokens = l.burst
+	}
+	l.tokens = math.Min(l.tokens, l.burst)
+	l.limit = limit
+	l.last = now
+}
+
+// Allow returns true if request can be served now.
+func (l *rateLimiter) Allow() bool {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	if l.limit <= 0 {
+		return true
+	}
+
+	now := l.now()
+	l.tokens += now.Sub(l.last).Seconds() * l.limit
+	if l.tokens > l.burst {
+		l.tokens = l.burst
+	}
+	l.last = now
+
+	if l.tokens < 1 {
+		return false
+	}
+	l.tokens--
+	return true
+}
+
+// Handler wraps next with a handler rejecting requests over the limit with HTTP 429 status.
+func (l *rateLimiter) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !l.Allow() {
+			http.Error(w, "rate limit exceeded", http.StatusTooManyRequests)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
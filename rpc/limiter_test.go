@@ -0,0 +1,72 @@
+package rpc
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimiter(t *testing.T) {
+	assert := assert.New(t)
+
+	now := time.Now()
+	l := &rateLimiter{now: func() time.Time { return now }}
+
+	// unlimited
+	l.SetLimit(0, 0)
+	for i := 0; i < 100; i++ {
+		assert.True(l.Allow())
+	}
+
+	l.SetLimit(2, 3)
+	for i := 0; i < 3; i++ {
+		assert.True(l.Allow())
+	}
+	assert.False(l.Allow())
+
+	now = now.Add(500 * time.Millisecond)
+	assert.True(l.Allow())
+	assert.False(l.Allow())
+
+	// tokens never exceed burst
+	now = now.Add(time.Hour)
+	for i := 0; i < 3; i++ {
+		assert.True(l.Allow())
+	}
+	assert.False(l.Allow())
+
+	// reload with the same limit doesn't refill the bucket
+	l.SetLimit(2, 3)
+	assert.False(l.Allow())
+
+	// tokens are capped at the new burst
+	now = now.Add(time.Hour)
+	l.SetLimit(2, 1)
+	assert.True(l.Allow())
+	assert.False(l.Allow())
+	l.SetLimit(2, 3)
+	assert.False(l.Allow())
+}
+
+func TestRateLimiterHandler(t *testing.T) {
+	assert := assert.New(t)
+
+	l := newRateLimiter(0.001, 1)
+	h := l.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/health", nil))
+	assert.Equal(http.StatusOK, rec.Code)
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/health", nil))
+	assert.Equal(http.StatusTooManyRequests, rec.Code)
+
+	l.SetLimit(0, 0)
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/health", nil))
+	assert.Equal(http.StatusOK, rec.Code)
+}
@@ -15,6 +15,7 @@ import (
 	"github.com/rs/cors"
 	"golang.org/x/net/netutil"
 
+	rollconf "github.com/rollkit/rollkit/config"
 	"github.com/rollkit/rollkit/node"
 	"github.com/rollkit/rollkit/rpc/json"
 )
@@ -23,8 +24,9 @@ import (
 type Server struct {
 	*service.BaseService
 
-	config *config.RPCConfig
-	client rpcclient.Client
+	config  *config.RPCConfig
+	client  rpcclient.Client
+	limiter *rateLimiter
 
 	server http.Server
 }
@@ -32,13 +34,27 @@ type Server struct {
 // NewServer creates new instance of Server with given configuration.
 func NewServer(node node.Node, config *config.RPCConfig, logger log.Logger) *Server {
 	srv := &Server{
-		config: config,
-		client: node.GetClient(),
+		config:  config,
+		client:  node.GetClient(),
+		limiter: newRateLimiter(0, 0),
+	}
+	if r, ok := node.(reloadableNode); ok {
+		conf := r.Config()
+		srv.limiter.SetLimit(conf.RPC.RateLimit, conf.RPC.RateBurst)
+		r.OnConfigReload(func(conf rollconf.NodeConfig) {
+			srv.limiter.SetLimit(conf.RPC.RateLimit, conf.RPC.RateBurst)
+		})
 	}
 	srv.BaseService = service.NewBaseService(logger, "RPC", srv)
 	return srv
 }
 
+// reloadableNode is implemented by nodes supporting runtime configuration changes.
+type reloadableNode interface {
+	Config() rollconf.NodeConfig
+	OnConfigReload(func(rollconf.NodeConfig))
+}
+
 // Client returns a Tendermint-compatible rpc Client instance.
 //
 // This method is called in cosmos-sdk.
@@ -82,7 +98,12 @@ func (s *Server) startRPC() error {
 		listener = netutil.LimitListener(listener, s.config.MaxOpenConnections)
 	}
 
-	handler, err := json.GetHTTPHandler(s.client, s.Logger)
+	getHandler := json.GetHTTPHandler
+	if s.config.Unsafe {
+		s.Logger.Info("unsafe RPC methods are enabled")
+		getHandler = json.GetUnsafeHTTPHandler
+	}
+	handler, err := getHandler(s.client, s.Logger)
 	if err != nil {
 		return err
 	}
@@ -101,6 +122,8 @@ func (s *Server) startRPC() error {
 		handler = c.Handler(handler)
 	}
 
+	handler = s.limiter.Handler(handler)
+
 	go func() {
 		err := s.serve(listener, handler)
 		if err != http.ErrServerClosed {
@@ -1,14 +1,15 @@
 package state
 
 import (
-	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
-	"time"
+	"sync"
 
 	abci "github.com/cometbft/cometbft/abci/types"
 	cryptoenc "github.com/cometbft/cometbft/crypto/encoding"
+	"github.com/cometbft/cometbft/crypto/merkle"
 	cmbytes "github.com/cometbft/cometbft/libs/bytes"
 	cmstate "github.com/cometbft/cometbft/proto/tendermint/state"
 	cmproto "github.com/cometbft/cometbft/proto/tendermint/types"
@@ -16,7 +17,9 @@ import (
 	cmtypes "github.com/cometbft/cometbft/types"
 	"go.uber.org/multierr"
 
+	"github.com/rollkit/rollkit/execution"
 	"github.com/rollkit/rollkit/mempool"
+	"github.com/rollkit/rollkit/store"
 	"github.com/rollkit/rollkit/third_party/log"
 	"github.com/rollkit/rollkit/types"
 	abciconv "github.com/rollkit/rollkit/types/abci"
@@ -28,43 +31,103 @@ var ErrEmptyValSetGenerated = errors.New("applying the validator changes would r
 // ErrAddingValidatorToBased is returned when trying to add a validator to an empty validator set.
 var ErrAddingValidatorToBased = errors.New("cannot add validators to empty validator set")
 
-// BlockExecutor creates and applies blocks and maintains state.
+// SimulatePath is the ABCI query path of the block simulation hook.
+//
+// Applications supporting block simulation should handle queries with this path. Query data is
+// a protobuf encoded CometBFT Block (tendermint.types.Block), that has to be executed on top of the
+// latest committed state in a throwaway branch. Response value has to be a protobuf encoded
+// ABCIResponses (tendermint.state.ABCIResponses).
+const SimulatePath = "/rollkit/simulate"
+
+// FinalityEventType and FinalityEventKey identify the event attribute labelling block and transaction events
+// with finality level of the block (e.g. query "rollkit.finality='da'"). Events are labelled only if block
+// manager sets finality level (see execution.WithFinality).
+const (
+	FinalityEventType = "rollkit"
+	FinalityEventKey  = "finality"
+)
+
+// BlockExecutor is an execution.Executor backed by ABCI application.
+//
+// Transactions are reaped from the mempool, blocks are executed using consensus connection to the app,
+// and ABCI responses are persisted in the store and published as events after the block is committed.
 type BlockExecutor struct {
-	proposerAddress []byte
-	namespaceID     types.NamespaceID
-	chainID         string
-	proxyApp        proxy.AppConnConsensus
-	mempool         mempool.Mempool
+	chainID    string
+	proxyApp   proxy.AppConnConsensus
+	proxyQuery proxy.AppConnQuery
+	mempool    mempool.Mempool
+	store      store.Store
 
 	eventBus *cmtypes.EventBus
 
 	logger log.Logger
+
+	// loadAppState loads genesis app state on chain initialization, if it's not kept in genesis document
+	loadAppState func() (json.RawMessage, error)
+	// rollback reverts the app to the state committed at given height, if the app supports it
+	rollback RollbackHook
+
+	// pending holds responses of the block executed by ExecuteTxs, until it's committed
+	pendingMtx sync.Mutex
+	pending    *cmstate.ABCIResponses
+	pendingFor uint64
+}
+
+var _ execution.Executor = &BlockExecutor{}
+var _ execution.TxNotifier = &BlockExecutor{}
+var _ execution.Simulator = &BlockExecutor{}
+var _ execution.PendingTxsReporter = &BlockExecutor{}
+var _ execution.FinalityNotifier = &BlockExecutor{}
+
+// gasWantedReporter is implemented by mempools tracking total gas wanted by transactions.
+type gasWantedReporter interface {
+	GasWanted() int64
 }
 
 // NewBlockExecutor creates new instance of BlockExecutor.
-// Proposer address and namespace ID will be used in all newly created blocks.
-func NewBlockExecutor(proposerAddress []byte, namespaceID [8]byte, chainID string, mempool mempool.Mempool, proxyApp proxy.AppConnConsensus, eventBus *cmtypes.EventBus, logger log.Logger) *BlockExecutor {
+// ABCI responses of committed blocks are saved in the store. Query connection is used only for block simulation
+// and to verify the app after rollback, and can be nil.
+func NewBlockExecutor(chainID string, mempool mempool.Mempool, proxyApp proxy.AppConnConsensus, proxyQuery proxy.AppConnQuery, store store.Store, eventBus *cmtypes.EventBus, logger log.Logger) *BlockExecutor {
 	return &BlockExecutor{
-		proposerAddress: proposerAddress,
-		namespaceID:     namespaceID,
-		chainID:         chainID,
-		proxyApp:        proxyApp,
-		mempool:         mempool,
-		eventBus:        eventBus,
-		logger:          logger,
+		chainID:    chainID,
+		proxyApp:   proxyApp,
+		proxyQuery: proxyQuery,
+		mempool:    mempool,
+		store:      store,
+		eventBus:   eventBus,
+		logger:     logger,
 	}
 }
 
-// InitChain calls InitChainSync using consensus connection to app.
-func (e *BlockExecutor) InitChain(genesis *cmtypes.GenesisDoc) (*abci.ResponseInitChain, error) {
+// SetAppStateLoader sets a function loading genesis app state, used by InitChain instead of app state of genesis
+// document. It allows to keep large app state out of memory, until the chain is initialized.
+func (e *BlockExecutor) SetAppStateLoader(load func() (json.RawMessage, error)) {
+	e.loadAppState = load
+}
+
+// SetRollbackHook registers rollback hook of the app, used by Rollback. Without the hook, committed soft blocks
+// diverging from DA layer can't be rolled back. It has to be set before blocks are synced.
+func (e *BlockExecutor) SetRollbackHook(rollback RollbackHook) {
+	e.rollback = rollback
+}
+
+// InitChain calls InitChainSync using consensus connection to app, and updates state with the response.
+func (e *BlockExecutor) InitChain(ctx context.Context, genesis *cmtypes.GenesisDoc, state types.State) (types.State, error) {
 	params := genesis.ConsensusParams
+	appState := genesis.AppState
+	if e.loadAppState != nil {
+		var err error
+		if appState, err = e.loadAppState(); err != nil {
+			return types.State{}, fmt.Errorf("failed to load genesis app state: %w", err)
+		}
+	}
 
 	validators := make([]*cmtypes.Validator, len(genesis.Validators))
 	for i, v := range genesis.Validators {
 		validators[i] = cmtypes.NewValidator(v.PubKey, v.Power)
 	}
 
-	return e.proxyApp.InitChainSync(abci.RequestInitChain{
+	res, err := e.proxyApp.InitChainSync(abci.RequestInitChain{
 		Time:    genesis.GenesisTime,
 		ChainId: genesis.ChainID,
 		ConsensusParams: &cmproto.ConsensusParams{
@@ -85,75 +148,64 @@ func (e *BlockExecutor) InitChain(genesis *cmtypes.GenesisDoc) (*abci.ResponseIn
 			},
 		},
 		Validators:    cmtypes.TM2PB.ValidatorUpdates(cmtypes.NewValidatorSet(validators)),
-		AppStateBytes: genesis.AppState,
+		AppStateBytes: appState,
 		InitialHeight: genesis.InitialHeight,
 	})
+	if err != nil {
+		return types.State{}, err
+	}
+
+	updateStateFromInitChain(&state, res)
+	return state, nil
 }
 
-// CreateBlock reaps transactions from mempool and builds a block.
-func (e *BlockExecutor) CreateBlock(height uint64, lastCommit *types.Commit, lastHeaderHash types.Hash, state types.State) *types.Block {
+// GetTxs reaps transactions from mempool.
+func (e *BlockExecutor) GetTxs(ctx context.Context, state types.State) (types.Txs, error) {
 	maxBytes := state.ConsensusParams.Block.MaxBytes
 	maxGas := state.ConsensusParams.Block.MaxGas
 
-	mempoolTxs := e.mempool.ReapMaxBytesMaxGas(maxBytes, maxGas)
-
-	block := &types.Block{
-		SignedHeader: types.SignedHeader{
-			Header: types.Header{
-				Version: types.Version{
-					Block: state.Version.Consensus.Block,
-					App:   state.Version.Consensus.App,
-				},
-				BaseHeader: types.BaseHeader{
-					ChainID: e.chainID,
-					Height:  height,
-					Time:    uint64(time.Now().UnixNano()),
-				},
-				//LastHeaderHash: lastHeaderHash,
-				//LastCommitHash:  lastCommitHash,
-				DataHash:        make(types.Hash, 32),
-				ConsensusHash:   make(types.Hash, 32),
-				AppHash:         state.AppHash,
-				LastResultsHash: state.LastResultsHash,
-				ProposerAddress: e.proposerAddress,
-			},
-			Commit: *lastCommit,
-		},
-		Data: types.Data{
-			Txs:                    toRollkitTxs(mempoolTxs),
-			IntermediateStateRoots: types.IntermediateStateRoots{RawRootsList: nil},
-			// Note: Temporarily remove Evidence #896
-			// Evidence:               types.EvidenceData{Evidence: nil},
-		},
-	}
-	block.SignedHeader.LastCommitHash = lastCommit.GetCommitHash(&block.SignedHeader.Header, e.proposerAddress)
-	block.SignedHeader.LastHeaderHash = lastHeaderHash
+	return toRollkitTxs(e.mempool.ReapMaxBytesMaxGas(maxBytes, maxGas)), nil
+}
 
-	return block
+// TxsAvailable returns a channel signalled by mempool when transactions are available.
+func (e *BlockExecutor) TxsAvailable() <-chan struct{} {
+	if e.mempool == nil {
+		return nil
+	}
+	return e.mempool.TxsAvailable()
 }
 
-// ApplyBlock validates and executes the block.
-func (e *BlockExecutor) ApplyBlock(ctx context.Context, state types.State, block *types.Block) (types.State, *cmstate.ABCIResponses, error) {
-	err := e.Validate(state, block)
-	if err != nil {
-		return types.State{}, nil, err
+// PendingTxs returns total size and gas wanted of transactions in mempool. Gas is reported only by mempools
+// tracking it (like mempool v1).
+func (e *BlockExecutor) PendingTxs() (int64, int64) {
+	if e.mempool == nil {
+		return 0, 0
+	}
+	var gas int64
+	if gm, ok := e.mempool.(gasWantedReporter); ok {
+		gas = gm.GasWanted()
 	}
+	return e.mempool.SizeBytes(), gas
+}
+
+// ExecuteTxs executes the block using app, and returns updated state.
+func (e *BlockExecutor) ExecuteTxs(ctx context.Context, state types.State, block *types.Block) (types.State, error) {
 	// This makes calls to the AppClient
 	resp, err := e.execute(ctx, state, block)
 	if err != nil {
-		return types.State{}, nil, err
+		return types.State{}, err
 	}
 
 	abciValUpdates := resp.EndBlock.ValidatorUpdates
 
 	err = validateValidatorUpdates(abciValUpdates, state.ConsensusParams.Validator)
 	if err != nil {
-		return state, nil, fmt.Errorf("error in validator updates: %v", err)
+		return state, fmt.Errorf("error in validator updates: %v", err)
 	}
 
 	validatorUpdates, err := cmtypes.PB2TM.ValidatorUpdates(abciValUpdates)
 	if err != nil {
-		return state, nil, err
+		return state, err
 	}
 	if len(validatorUpdates) > 0 {
 		e.logger.Debug("updates to validators", "updates", cmtypes.ValidatorListString(validatorUpdates))
@@ -164,27 +216,118 @@ func (e *BlockExecutor) ApplyBlock(ctx context.Context, state types.State, block
 
 	state, err = e.updateState(state, block, resp, validatorUpdates)
 	if err != nil {
-		return types.State{}, nil, err
+		return types.State{}, err
 	}
 
-	return state, resp, nil
+	e.pendingMtx.Lock()
+	e.pending = resp
+	e.pendingFor = block.Height()
+	e.pendingMtx.Unlock()
+
+	return state, nil
 }
 
-// Commit commits the block
-func (e *BlockExecutor) Commit(ctx context.Context, state types.State, block *types.Block, resp *cmstate.ABCIResponses) ([]byte, uint64, error) {
-	appHash, retainHeight, err := e.commit(ctx, state, block, resp.DeliverTxs)
+// Commit commits the block executed by ExecuteTxs, saves ABCI responses and publishes events.
+func (e *BlockExecutor) Commit(ctx context.Context, state types.State, block *types.Block) ([]byte, error) {
+	e.pendingMtx.Lock()
+	resp := e.pending
+	if resp == nil || e.pendingFor != block.Height() {
+		e.pendingMtx.Unlock()
+		return nil, fmt.Errorf("block at height %d was not executed", block.Height())
+	}
+	e.pending = nil
+	e.pendingMtx.Unlock()
+
+	appHash, err := e.commit(ctx, state, block, resp.DeliverTxs)
 	if err != nil {
-		return []byte{}, 0, err
+		return []byte{}, err
 	}
 
 	state.AppHash = appHash
 
-	err = e.publishEvents(resp, block, state)
+	if err := e.store.SaveBlockResponses(block.Height(), resp); err != nil {
+		return nil, fmt.Errorf("failed to save block responses: %w", err)
+	}
+
+	finality, _ := execution.FinalityFromContext(ctx)
+	err = e.publishEvents(resp, block, finality)
 	if err != nil {
 		e.logger.Error("failed to fire block events", "error", err)
 	}
 
-	return appHash, retainHeight, nil
+	return appHash, nil
+}
+
+// BlockFinalized publishes NewBlockHeader event of already committed block again, labelled with DA finality.
+func (e *BlockExecutor) BlockFinalized(ctx context.Context, height uint64) error {
+	if e.eventBus == nil {
+		return nil
+	}
+	block, err := e.store.GetBlock(height)
+	if err != nil {
+		return fmt.Errorf("failed to load block: %w", err)
+	}
+	resp, err := e.store.GetBlockResponses(height)
+	if err != nil {
+		return fmt.Errorf("failed to load block responses: %w", err)
+	}
+	abciBlock, err := abciconv.ToABCIBlock(block)
+	if err != nil {
+		return err
+	}
+	abciBlock.Header.ValidatorsHash = block.SignedHeader.Validators.Hash()
+	return e.eventBus.PublishEventNewBlockHeader(cmtypes.EventDataNewBlockHeader{
+		Header:           abciBlock.Header,
+		NumTxs:           int64(len(abciBlock.Txs)),
+		ResultBeginBlock: *resp.BeginBlock,
+		ResultEndBlock:   withFinalityEvent(*resp.EndBlock, execution.FinalityDA),
+	})
+}
+
+// SimulateTxs executes the block using simulation hook of the app (see SimulatePath), without committing anything.
+func (e *BlockExecutor) SimulateTxs(ctx context.Context, state types.State, block *types.Block) ([]execution.TxResult, error) {
+	if e.proxyQuery == nil {
+		return nil, execution.ErrSimulationNotSupported
+	}
+	abciBlock, err := abciconv.ToABCIBlock(block)
+	if err != nil {
+		return nil, err
+	}
+	abciBlock.Header.ChainID = e.chainID
+	pbBlock, err := abciBlock.ToProto()
+	if err != nil {
+		return nil, err
+	}
+	data, err := pbBlock.Marshal()
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := e.proxyQuery.QuerySync(abci.RequestQuery{
+		Path:   SimulatePath,
+		Data:   data,
+		Height: int64(state.LastBlockHeight),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if res.Code != abci.CodeTypeOK {
+		return nil, fmt.Errorf("%w: query %s failed with code %d: %s", execution.ErrSimulationNotSupported, SimulatePath, res.Code, res.Log)
+	}
+
+	var resp cmstate.ABCIResponses
+	if err := resp.Unmarshal(res.Value); err != nil {
+		return nil, fmt.Errorf("invalid response of block simulation: %w", err)
+	}
+	if len(resp.DeliverTxs) != len(block.Data.Txs) {
+		return nil, fmt.Errorf("invalid response of block simulation: expected %d results, got %d", len(block.Data.Txs), len(resp.DeliverTxs))
+	}
+
+	results := make([]execution.TxResult, len(resp.DeliverTxs))
+	for i, r := range resp.DeliverTxs {
+		results[i] = toTxResult(r)
+	}
+	return results, nil
 }
 
 func (e *BlockExecutor) updateState(state types.State, block *types.Block, abciResponses *cmstate.ABCIResponses, validatorUpdates []*cmtypes.Validator) (types.State, error) {
@@ -208,55 +351,28 @@ func (e *BlockExecutor) updateState(state types.State, block *types.Block, abciR
 	return s, nil
 }
 
-func (e *BlockExecutor) commit(ctx context.Context, state types.State, block *types.Block, deliverTxs []*abci.ResponseDeliverTx) ([]byte, uint64, error) {
+func (e *BlockExecutor) commit(ctx context.Context, state types.State, block *types.Block, deliverTxs []*abci.ResponseDeliverTx) ([]byte, error) {
 	e.mempool.Lock()
 	defer e.mempool.Unlock()
 
 	err := e.mempool.FlushAppConn()
 	if err != nil {
-		return nil, 0, err
+		return nil, err
 	}
 
 	resp, err := e.proxyApp.CommitSync()
 	if err != nil {
-		return nil, 0, err
+		return nil, err
 	}
 
 	maxBytes := state.ConsensusParams.Block.MaxBytes
 	maxGas := state.ConsensusParams.Block.MaxGas
 	err = e.mempool.Update(block.Height(), fromRollkitTxs(block.Data.Txs), deliverTxs, mempool.PreCheckMaxBytes(maxBytes), mempool.PostCheckMaxGas(maxGas))
 	if err != nil {
-		return nil, 0, err
-	}
-
-	return resp.Data, uint64(resp.RetainHeight), err
-}
-
-// Validate validates the state and the block for the executor
-func (e *BlockExecutor) Validate(state types.State, block *types.Block) error {
-	err := block.ValidateBasic()
-	if err != nil {
-		return err
-	}
-	if block.SignedHeader.Version.App != state.Version.Consensus.App ||
-		block.SignedHeader.Version.Block != state.Version.Consensus.Block {
-		return errors.New("block version mismatch")
-	}
-	if state.LastBlockHeight <= 0 && block.Height() != state.InitialHeight {
-		return errors.New("initial block height mismatch")
-	}
-	if state.LastBlockHeight > 0 && block.Height() != state.LastBlockHeight+1 {
-		return errors.New("block height mismatch")
-	}
-	if !bytes.Equal(block.SignedHeader.AppHash[:], state.AppHash[:]) {
-		return errors.New("AppHash mismatch")
-	}
-
-	if !bytes.Equal(block.SignedHeader.LastResultsHash[:], state.LastResultsHash[:]) {
-		return errors.New("LastResultsHash mismatch")
+		return nil, err
 	}
 
-	return nil
+	return resp.Data, nil
 }
 
 func (e *BlockExecutor) execute(ctx context.Context, state types.State, block *types.Block) (*cmstate.ABCIResponses, error) {
@@ -318,7 +434,8 @@ func (e *BlockExecutor) execute(ctx context.Context, state types.State, block *t
 	return abciResponses, nil
 }
 
-func (e *BlockExecutor) publishEvents(resp *cmstate.ABCIResponses, block *types.Block, state types.State) error {
+// publishEvents publishes events of committed block. If finality is not empty, events are labelled with it.
+func (e *BlockExecutor) publishEvents(resp *cmstate.ABCIResponses, block *types.Block, finality execution.Finality) error {
 	if e.eventBus == nil {
 		return nil
 	}
@@ -327,17 +444,18 @@ func (e *BlockExecutor) publishEvents(resp *cmstate.ABCIResponses, block *types.
 		return err
 	}
 	abciBlock.Header.ValidatorsHash = block.SignedHeader.Validators.Hash()
+	endBlock := withFinalityEvent(*resp.EndBlock, finality)
 
 	err = multierr.Append(err, e.eventBus.PublishEventNewBlock(cmtypes.EventDataNewBlock{
 		Block:            abciBlock,
 		ResultBeginBlock: *resp.BeginBlock,
-		ResultEndBlock:   *resp.EndBlock,
+		ResultEndBlock:   endBlock,
 	}))
 	err = multierr.Append(err, e.eventBus.PublishEventNewBlockHeader(cmtypes.EventDataNewBlockHeader{
 		Header:           abciBlock.Header,
 		NumTxs:           int64(len(abciBlock.Txs)),
 		ResultBeginBlock: *resp.BeginBlock,
-		ResultEndBlock:   *resp.EndBlock,
+		ResultEndBlock:   endBlock,
 	}))
 	for _, ev := range abciBlock.Evidence.Evidence {
 		err = multierr.Append(err, e.eventBus.PublishEventNewEvidence(cmtypes.EventDataNewEvidence{
@@ -351,13 +469,57 @@ func (e *BlockExecutor) publishEvents(resp *cmstate.ABCIResponses, block *types.
 				Height: int64(block.Height()),
 				Index:  uint32(i),
 				Tx:     abciBlock.Data.Txs[i],
-				Result: *dtx,
+				Result: withFinalityEventTx(*dtx, finality),
 			},
 		}))
 	}
 	return err
 }
 
+// withFinalityEvent returns copy of EndBlock response with finality event appended. Saved responses are not modified.
+func withFinalityEvent(r abci.ResponseEndBlock, finality execution.Finality) abci.ResponseEndBlock {
+	r.Events = appendFinalityEvent(r.Events, finality)
+	return r
+}
+
+// withFinalityEventTx returns copy of DeliverTx response with finality event appended.
+func withFinalityEventTx(r abci.ResponseDeliverTx, finality execution.Finality) abci.ResponseDeliverTx {
+	r.Events = appendFinalityEvent(r.Events, finality)
+	return r
+}
+
+func appendFinalityEvent(events []abci.Event, finality execution.Finality) []abci.Event {
+	if finality == "" {
+		return events
+	}
+	// full slice expression forces a copy, so the underlying array of saved responses is not modified
+	return append(events[:len(events):len(events)], abci.Event{
+		Type:       FinalityEventType,
+		Attributes: []abci.EventAttribute{{Key: FinalityEventKey, Value: string(finality), Index: true}},
+	})
+}
+
+func toTxResult(r *abci.ResponseDeliverTx) execution.TxResult {
+	events := make([]execution.Event, len(r.Events))
+	for i, ev := range r.Events {
+		attrs := make([]execution.EventAttribute, len(ev.Attributes))
+		for j, a := range ev.Attributes {
+			attrs[j] = execution.EventAttribute{Key: a.Key, Value: a.Value, Index: a.Index}
+		}
+		events[i] = execution.Event{Type: ev.Type, Attributes: attrs}
+	}
+	return execution.TxResult{
+		Code:      r.Code,
+		Data:      r.Data,
+		Log:       r.Log,
+		Info:      r.Info,
+		GasWanted: r.GasWanted,
+		GasUsed:   r.GasUsed,
+		Events:    events,
+		Codespace: r.Codespace,
+	}
+}
+
 func toRollkitTxs(txs cmtypes.Txs) types.Txs {
 	rollkitTxs := make(types.Txs, len(txs))
 	for i := range txs {
@@ -397,3 +559,37 @@ func validateValidatorUpdates(abciUpdates []abci.ValidatorUpdate, params *cmprot
 	}
 	return nil
 }
+
+// updateStateFromInitChain updates the state using ABCI InitChain response.
+func updateStateFromInitChain(s *types.State, res *abci.ResponseInitChain) {
+	// If the app did not return an app hash, we keep the one set from the genesis doc in
+	// the state. We don't set appHash since we don't want the genesis doc app hash
+	// recorded in the genesis block. We should probably just remove GenesisDoc.AppHash.
+	if len(res.AppHash) > 0 {
+		s.AppHash = res.AppHash
+	}
+
+	if res.ConsensusParams != nil {
+		params := res.ConsensusParams
+		if params.Block != nil {
+			s.ConsensusParams.Block.MaxBytes = params.Block.MaxBytes
+			s.ConsensusParams.Block.MaxGas = params.Block.MaxGas
+		}
+		if params.Evidence != nil {
+			s.ConsensusParams.Evidence.MaxAgeNumBlocks = params.Evidence.MaxAgeNumBlocks
+			s.ConsensusParams.Evidence.MaxAgeDuration = params.Evidence.MaxAgeDuration
+			s.ConsensusParams.Evidence.MaxBytes = params.Evidence.MaxBytes
+		}
+		if params.Validator != nil {
+			// Copy params.Validator.PubkeyTypes, and set result's value to the copy.
+			// This avoids having to initialize the slice to 0 values, and then write to it again.
+			s.ConsensusParams.Validator.PubKeyTypes = append([]string{}, params.Validator.PubKeyTypes...)
+		}
+		if params.Version != nil {
+			s.ConsensusParams.Version.App = params.Version.App
+		}
+		s.Version.Consensus.App = s.ConsensusParams.Version.App
+	}
+	// We update the last results hash with the empty hash, to conform with RFC-6962.
+	s.LastResultsHash = merkle.HashFromByteSlices(nil)
+}
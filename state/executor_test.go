@@ -3,6 +3,7 @@ package state
 import (
 	"context"
 	"crypto/rand"
+	"errors"
 	"fmt"
 	"testing"
 	"time"
@@ -14,14 +15,19 @@ import (
 	abci "github.com/cometbft/cometbft/abci/types"
 	cfg "github.com/cometbft/cometbft/config"
 	"github.com/cometbft/cometbft/crypto/ed25519"
+	cmbytes "github.com/cometbft/cometbft/libs/bytes"
+	cmjson "github.com/cometbft/cometbft/libs/json"
 	"github.com/cometbft/cometbft/libs/log"
 	"github.com/cometbft/cometbft/libs/pubsub/query"
+	cmstate "github.com/cometbft/cometbft/proto/tendermint/state"
 	cmproto "github.com/cometbft/cometbft/proto/tendermint/types"
 	"github.com/cometbft/cometbft/proxy"
 	cmtypes "github.com/cometbft/cometbft/types"
 
+	"github.com/rollkit/rollkit/execution"
 	"github.com/rollkit/rollkit/mempool"
 	mempoolv1 "github.com/rollkit/rollkit/mempool/v1"
+	"github.com/rollkit/rollkit/store"
 	"github.com/rollkit/rollkit/test/mocks"
 	"github.com/rollkit/rollkit/types"
 )
@@ -34,7 +40,7 @@ const (
 	Commit     = "Commit"
 )
 
-func doTestCreateBlock(t *testing.T) {
+func doTestGetTxs(t *testing.T) {
 	assert := assert.New(t)
 	require := require.New(t)
 
@@ -49,11 +55,9 @@ func doTestCreateBlock(t *testing.T) {
 	require.NoError(err)
 	require.NotNil(client)
 
-	nsID := [8]byte{1, 2, 3, 4, 5, 6, 7, 8}
-	fmt.Println("Made NID")
 	mpool := mempoolv1.NewTxMempool(logger, cfg.DefaultMempoolConfig(), proxy.NewAppConnMempool(client, proxy.NopMetrics()), 0)
 	fmt.Println("Made a NewTxMempool")
-	executor := NewBlockExecutor([]byte("test address"), nsID, "test", mpool, proxy.NewAppConnConsensus(client, proxy.NopMetrics()), nil, logger)
+	executor := NewBlockExecutor("test", mpool, proxy.NewAppConnConsensus(client, proxy.NopMetrics()), nil, nil, nil, logger)
 	fmt.Println("Made a New Block Executor")
 
 	state := types.State{}
@@ -63,31 +67,52 @@ func doTestCreateBlock(t *testing.T) {
 	state.ConsensusParams.Block.MaxGas = 100000
 
 	// empty block
-	block := executor.CreateBlock(1, &types.Commit{}, []byte{}, state)
-	require.NotNil(block)
-	assert.Empty(block.Data.Txs)
-	assert.Equal(uint64(1), block.Height())
+	txs, err := executor.GetTxs(context.Background(), state)
+	require.NoError(err)
+	assert.Empty(txs)
 
 	// one small Tx
 	err = mpool.CheckTx([]byte{1, 2, 3, 4}, func(r *abci.Response) {}, mempool.TxInfo{})
 	require.NoError(err)
-	block = executor.CreateBlock(2, &types.Commit{}, []byte{}, state)
-	require.NotNil(block)
-	assert.Equal(uint64(2), block.Height())
-	assert.Len(block.Data.Txs, 1)
+	txs, err = executor.GetTxs(context.Background(), state)
+	require.NoError(err)
+	assert.Len(txs, 1)
 
 	// now there are 3 Txs, and only two can fit into single block
 	err = mpool.CheckTx([]byte{4, 5, 6, 7}, func(r *abci.Response) {}, mempool.TxInfo{})
 	require.NoError(err)
 	err = mpool.CheckTx(make([]byte, 100), func(r *abci.Response) {}, mempool.TxInfo{})
 	require.NoError(err)
-	block = executor.CreateBlock(3, &types.Commit{}, []byte{}, state)
-	require.NotNil(block)
-	assert.Len(block.Data.Txs, 2)
+	txs, err = executor.GetTxs(context.Background(), state)
+	require.NoError(err)
+	assert.Len(txs, 2)
+}
+
+func TestGetTxsWithFraudProofsDisabled(t *testing.T) {
+	doTestGetTxs(t)
 }
 
-func TestCreateBlockWithFraudProofsDisabled(t *testing.T) {
-	doTestCreateBlock(t)
+func createBlock(t *testing.T, executor *BlockExecutor, height uint64, state types.State) *types.Block {
+	txs, err := executor.GetTxs(context.Background(), state)
+	require.NoError(t, err)
+	block := &types.Block{
+		SignedHeader: types.SignedHeader{
+			Header: types.Header{
+				BaseHeader: types.BaseHeader{
+					ChainID: "test",
+					Height:  height,
+					Time:    uint64(time.Now().UnixNano()),
+				},
+				AppHash:         state.AppHash,
+				LastResultsHash: state.LastResultsHash,
+				ProposerAddress: []byte("test address"),
+			},
+		},
+		Data: types.Data{
+			Txs: txs,
+		},
+	}
+	return block
 }
 
 func doTestApplyBlock(t *testing.T) {
@@ -112,13 +137,15 @@ func doTestApplyBlock(t *testing.T) {
 	require.NoError(err)
 	require.NotNil(client)
 
-	nsID := [8]byte{1, 2, 3, 4, 5, 6, 7, 8}
 	chainID := "test"
 
 	mpool := mempoolv1.NewTxMempool(logger, cfg.DefaultMempoolConfig(), proxy.NewAppConnMempool(client, proxy.NopMetrics()), 0)
 	eventBus := cmtypes.NewEventBus()
 	require.NoError(eventBus.Start())
-	executor := NewBlockExecutor([]byte("test address"), nsID, chainID, mpool, proxy.NewAppConnConsensus(client, proxy.NopMetrics()), eventBus, logger)
+	kv, err := store.NewDefaultInMemoryKVStore()
+	require.NoError(err)
+	blockStore := store.New(context.Background(), kv)
+	executor := NewBlockExecutor(chainID, mpool, proxy.NewAppConnConsensus(client, proxy.NopMetrics()), proxy.NewAppConnQuery(client, proxy.NopMetrics()), blockStore, eventBus, logger)
 
 	txQuery, err := query.New("tm.event='Tx'")
 	require.NoError(err)
@@ -150,8 +177,7 @@ func doTestApplyBlock(t *testing.T) {
 
 	_ = mpool.CheckTx([]byte{1, 2, 3, 4}, func(r *abci.Response) {}, mempool.TxInfo{})
 	require.NoError(err)
-	block := executor.CreateBlock(1, &types.Commit{Signatures: []types.Signature{types.Signature([]byte{1, 1, 1})}}, []byte{}, state)
-	require.NotNil(block)
+	block := createBlock(t, executor, 1, state)
 	assert.Equal(uint64(1), block.Height())
 	assert.Len(block.Data.Txs, 1)
 	dataHash, err := block.Data.Hash()
@@ -166,21 +192,22 @@ func doTestApplyBlock(t *testing.T) {
 	}
 	block.SignedHeader.Validators = cmtypes.NewValidatorSet(validators)
 
-	newState, resp, err := executor.ApplyBlock(context.Background(), state, block)
+	newState, err := executor.ExecuteTxs(context.Background(), state, block)
 	require.NoError(err)
 	require.NotNil(newState)
-	require.NotNil(resp)
 	assert.Equal(uint64(1), newState.LastBlockHeight)
-	appHash, _, err := executor.Commit(context.Background(), newState, block, resp)
+	appHash, err := executor.Commit(context.Background(), newState, block)
 	require.NoError(err)
 	assert.Equal(mockAppHash, appHash)
+	resp, err := blockStore.GetBlockResponses(1)
+	require.NoError(err)
+	assert.Len(resp.DeliverTxs, 1)
 
 	require.NoError(mpool.CheckTx([]byte{0, 1, 2, 3, 4}, func(r *abci.Response) {}, mempool.TxInfo{}))
 	require.NoError(mpool.CheckTx([]byte{5, 6, 7, 8, 9}, func(r *abci.Response) {}, mempool.TxInfo{}))
 	require.NoError(mpool.CheckTx([]byte{1, 2, 3, 4, 5}, func(r *abci.Response) {}, mempool.TxInfo{}))
 	require.NoError(mpool.CheckTx(make([]byte, 90), func(r *abci.Response) {}, mempool.TxInfo{}))
-	block = executor.CreateBlock(2, &types.Commit{Signatures: []types.Signature{types.Signature([]byte{1, 1, 1})}}, []byte{}, newState)
-	require.NotNil(block)
+	block = createBlock(t, executor, 2, newState)
 	assert.Equal(uint64(2), block.Height())
 	assert.Len(block.Data.Txs, 3)
 	dataHash, err = block.Data.Hash()
@@ -194,14 +221,17 @@ func doTestApplyBlock(t *testing.T) {
 	}
 	block.SignedHeader.Validators = cmtypes.NewValidatorSet(validators)
 
-	newState, resp, err = executor.ApplyBlock(context.Background(), newState, block)
+	newState, err = executor.ExecuteTxs(context.Background(), newState, block)
 	require.NoError(err)
 	require.NotNil(newState)
-	require.NotNil(resp)
 	assert.Equal(uint64(2), newState.LastBlockHeight)
-	_, _, err = executor.Commit(context.Background(), newState, block, resp)
+	_, err = executor.Commit(context.Background(), newState, block)
 	require.NoError(err)
 
+	// block can be committed only once
+	_, err = executor.Commit(context.Background(), newState, block)
+	assert.Error(err)
+
 	// wait for at least 4 Tx events, for up to 3 second.
 	// 3 seconds is a fail-scenario only
 	timer := time.NewTimer(3 * time.Second)
@@ -237,3 +267,146 @@ func doTestApplyBlock(t *testing.T) {
 func TestApplyBlockWithFraudProofsDisabled(t *testing.T) {
 	doTestApplyBlock(t)
 }
+
+func TestSimulateTxs(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	logger := log.TestingLogger()
+
+	app := &mocks.Application{}
+	client, err := proxy.NewLocalClientCreator(app).NewABCIClient()
+	require.NoError(err)
+
+	executor := NewBlockExecutor("test", nil, proxy.NewAppConnConsensus(client, proxy.NopMetrics()), proxy.NewAppConnQuery(client, proxy.NopMetrics()), nil, nil, logger)
+
+	vKey := ed25519.GenPrivKey()
+	block := &types.Block{
+		SignedHeader: types.SignedHeader{
+			Header: types.Header{
+				BaseHeader: types.BaseHeader{ChainID: "test", Height: 1},
+			},
+			Validators: cmtypes.NewValidatorSet([]*cmtypes.Validator{cmtypes.NewValidator(vKey.PubKey(), 100)}),
+		},
+		Data: types.Data{Txs: types.Txs{types.Tx("tx1"), types.Tx("tx2")}},
+	}
+
+	responses := cmstate.ABCIResponses{
+		DeliverTxs: []*abci.ResponseDeliverTx{
+			{Code: 0, GasUsed: 10, Events: []abci.Event{{Type: "transfer", Attributes: []abci.EventAttribute{{Key: "amount", Value: "5"}}}}},
+			{Code: 3, Log: "insufficient funds"},
+		},
+		BeginBlock: &abci.ResponseBeginBlock{},
+		EndBlock:   &abci.ResponseEndBlock{},
+	}
+	value, err := responses.Marshal()
+	require.NoError(err)
+
+	call := app.On("Query", mock.MatchedBy(func(req abci.RequestQuery) bool {
+		if req.Path != SimulatePath {
+			return false
+		}
+		var pbBlock cmproto.Block
+		return pbBlock.Unmarshal(req.Data) == nil && len(pbBlock.Data.Txs) == 2
+	})).Return(abci.ResponseQuery{Value: value})
+
+	results, err := executor.SimulateTxs(context.Background(), types.State{}, block)
+	require.NoError(err)
+	require.Len(results, 2)
+	assert.Equal(uint32(0), results[0].Code)
+	assert.Equal(int64(10), results[0].GasUsed)
+	require.Len(results[0].Events, 1)
+	assert.Equal("transfer", results[0].Events[0].Type)
+	assert.Equal("amount", results[0].Events[0].Attributes[0].Key)
+	assert.Equal(uint32(3), results[1].Code)
+	assert.Equal("insufficient funds", results[1].Log)
+
+	// application without simulation hook
+	call.Return(abci.ResponseQuery{Code: 1, Log: "unknown path"})
+	_, err = executor.SimulateTxs(context.Background(), types.State{}, block)
+	assert.ErrorIs(err, execution.ErrSimulationNotSupported)
+
+	// executor without query connection
+	executor = NewBlockExecutor("test", nil, proxy.NewAppConnConsensus(client, proxy.NopMetrics()), nil, nil, nil, logger)
+	_, err = executor.SimulateTxs(context.Background(), types.State{}, block)
+	assert.ErrorIs(err, execution.ErrSimulationNotSupported)
+}
+
+func TestRollback(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	logger := log.TestingLogger()
+	ctx := context.Background()
+
+	app := &mocks.Application{}
+	client, err := proxy.NewLocalClientCreator(app).NewABCIClient()
+	require.NoError(err)
+	kv, err := store.NewDefaultInMemoryKVStore()
+	require.NoError(err)
+	s := store.New(ctx, kv)
+	require.NoError(s.SaveSoftState(2, types.State{LastBlockHeight: 2, AppHash: types.Hash{1, 2}}))
+
+	executor := NewBlockExecutor("test", nil, proxy.NewAppConnConsensus(client, proxy.NopMetrics()), proxy.NewAppConnQuery(client, proxy.NopMetrics()), s, nil, logger)
+
+	// application without rollback hook
+	assert.ErrorIs(executor.Rollback(ctx, 2), execution.ErrRollbackNotSupported)
+
+	var rolledBack uint64
+	var hookErr error
+	executor.SetRollbackHook(func(height uint64) error {
+		rolledBack = height
+		return hookErr
+	})
+	info := app.On("Info", mock.Anything).Return(abci.ResponseInfo{LastBlockHeight: 2, LastBlockAppHash: []byte{1, 2}})
+	assert.NoError(executor.Rollback(ctx, 2))
+	assert.Equal(uint64(2), rolledBack)
+
+	// app reverted to different state
+	info.Return(abci.ResponseInfo{LastBlockHeight: 2, LastBlockAppHash: []byte{2, 1}})
+	assert.ErrorContains(executor.Rollback(ctx, 2), "doesn't match app hash")
+	info.Return(abci.ResponseInfo{LastBlockHeight: 3})
+	assert.ErrorContains(executor.Rollback(ctx, 2), "last block height 3")
+
+	// application failed to roll back
+	hookErr = errors.New("state pruned")
+	assert.ErrorContains(executor.Rollback(ctx, 2), "state pruned")
+
+	// executor without query connection doesn't verify the app
+	hookErr = nil
+	executor = NewBlockExecutor("test", nil, proxy.NewAppConnConsensus(client, proxy.NopMetrics()), nil, s, nil, logger)
+	executor.SetRollbackHook(func(height uint64) error { return nil })
+	assert.NoError(executor.Rollback(ctx, 2))
+}
+
+func TestBlocksReorged(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	ctx := context.Background()
+
+	eventBus := cmtypes.NewEventBus()
+	require.NoError(eventBus.Start())
+	defer func() {
+		assert.NoError(eventBus.Stop())
+	}()
+	sub, err := eventBus.Subscribe(ctx, "test", EventQueryReorg)
+	require.NoError(err)
+
+	executor := NewBlockExecutor("test", nil, nil, nil, nil, eventBus, log.TestingLogger())
+	reorg := execution.Reorg{Height: 4, Reverted: []types.Hash{{1}, {2}}, Canonical: types.Hash{3}, DAHeight: 7}
+	require.NoError(executor.BlocksReorged(ctx, reorg))
+
+	select {
+	case msg := <-sub.Out():
+		data, ok := msg.Data().(EventDataReorg)
+		require.True(ok)
+		assert.Equal(int64(4), data.Height)
+		assert.Equal([]cmbytes.HexBytes{{1}, {2}}, data.Reverted)
+		assert.Equal(cmbytes.HexBytes{3}, data.Canonical)
+		assert.Equal(uint64(7), data.DAHeight)
+		_, err := cmjson.Marshal(msg.Data())
+		assert.NoError(err)
+	case <-time.After(time.Second):
+		t.Fatal("reorg event was not published")
+	}
+}
@@ -19,4 +19,8 @@ type BlockIndexer interface {
 	// Search performs a query for block heights that match a given BeginBlock
 	// and Endblock event search criteria.
 	Search(ctx context.Context, q *query.Query) ([]int64, error)
+
+	// DeleteHeights removes blocks at heights from..to (inclusive) from the index, when the blocks are rolled
+	// back.
+	DeleteHeights(from, to int64) error
 }
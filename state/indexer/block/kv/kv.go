@@ -2,6 +2,7 @@ package kv
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"math/big"
@@ -53,6 +54,7 @@ func (idx *BlockerIndexer) Has(height int64) (bool, error) {
 // primary key: encode(block.height | height) => encode(height)
 // BeginBlock events: encode(eventType.eventAttr|eventValue|height|begin_block) => encode(height)
 // EndBlock events: encode(eventType.eventAttr|eventValue|height|end_block) => encode(height)
+// event keys: encode(block_event_keys | height) => JSON list of event keys of the block, used to delete them
 func (idx *BlockerIndexer) Index(bh types.EventDataNewBlockHeader) error {
 	batch, err := idx.store.NewTransaction(idx.ctx, false)
 	if err != nil {
@@ -68,15 +70,21 @@ func (idx *BlockerIndexer) Index(bh types.EventDataNewBlockHeader) error {
 	}
 
 	// 2. index BeginBlock events
-	if err := idx.indexEvents(batch, bh.ResultBeginBlock.Events, "begin_block", height); err != nil {
+	keys, err := idx.indexEvents(batch, bh.ResultBeginBlock.Events, "begin_block", height, nil)
+	if err != nil {
 		return fmt.Errorf("failed to index BeginBlock events: %w", err)
 	}
 
 	// 3. index EndBlock events
-	if err := idx.indexEvents(batch, bh.ResultEndBlock.Events, "end_block", height); err != nil {
+	if keys, err = idx.indexEvents(batch, bh.ResultEndBlock.Events, "end_block", height, keys); err != nil {
 		return fmt.Errorf("failed to index EndBlock events: %w", err)
 	}
 
+	// 4. record event keys; block can be indexed many times (e.g. when it becomes DA-final)
+	if err := idx.addEventKeys(batch, height, keys); err != nil {
+		return fmt.Errorf("failed to record event keys: %w", err)
+	}
+
 	return batch.Commit(idx.ctx)
 }
 
@@ -449,7 +457,8 @@ func (idx *BlockerIndexer) match(
 	return filteredHeights, nil
 }
 
-func (idx *BlockerIndexer) indexEvents(batch ds.Txn, events []abci.Event, typ string, height int64) error {
+// indexEvents indexes events of the block, and appends their keys to keys.
+func (idx *BlockerIndexer) indexEvents(batch ds.Txn, events []abci.Event, typ string, height int64, keys []string) ([]string, error) {
 	heightBz := int64ToBytes(height)
 
 	for _, event := range events {
@@ -466,18 +475,87 @@ func (idx *BlockerIndexer) indexEvents(batch ds.Txn, events []abci.Event, typ st
 			// index iff the event specified index:true and it's not a reserved event
 			compositeKey := fmt.Sprintf("%s.%s", event.Type, string(attr.Key))
 			if compositeKey == types.BlockHeightKey {
-				return fmt.Errorf("event type and attribute key \"%s\" is reserved; please use a different key", compositeKey)
+				return nil, fmt.Errorf("event type and attribute key \"%s\" is reserved; please use a different key", compositeKey)
 			}
 
 			if attr.GetIndex() {
 				key := eventKey(compositeKey, typ, string(attr.Value), height)
 
 				if err := batch.Put(idx.ctx, ds.NewKey(key), heightBz); err != nil {
-					return err
+					return nil, err
 				}
+				keys = append(keys, key)
 			}
 		}
 	}
 
-	return nil
+	return keys, nil
+}
+
+func (idx *BlockerIndexer) addEventKeys(batch ds.Txn, height int64, keys []string) error {
+	recorded, err := idx.getEventKeys(batch, height)
+	if err != nil {
+		return err
+	}
+	seen := make(map[string]bool, len(recorded))
+	for _, key := range recorded {
+		seen[key] = true
+	}
+	for _, key := range keys {
+		if !seen[key] {
+			seen[key] = true
+			recorded = append(recorded, key)
+		}
+	}
+	bz, err := json.Marshal(recorded)
+	if err != nil {
+		return err
+	}
+	return batch.Put(idx.ctx, ds.NewKey(eventKeysKey(height)), bz)
+}
+
+func (idx *BlockerIndexer) getEventKeys(batch ds.Txn, height int64) ([]string, error) {
+	bz, err := batch.Get(idx.ctx, ds.NewKey(eventKeysKey(height)))
+	if errors.Is(err, ds.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var keys []string
+	if err := json.Unmarshal(bz, &keys); err != nil {
+		return nil, fmt.Errorf("failed to decode event keys: %w", err)
+	}
+	return keys, nil
+}
+
+// DeleteHeights removes blocks at heights from..to (inclusive) from the index, when the blocks are rolled back.
+// Events of blocks indexed before event keys were recorded are not removed, but they don't match any query until
+// the block at their height is indexed again.
+func (idx *BlockerIndexer) DeleteHeights(from, to int64) error {
+	batch, err := idx.store.NewTransaction(idx.ctx, false)
+	if err != nil {
+		return fmt.Errorf("failed to create a new batch for transaction: %w", err)
+	}
+	defer batch.Discard(idx.ctx)
+
+	for height := from; height <= to; height++ {
+		keys, err := idx.getEventKeys(batch, height)
+		if err != nil {
+			return err
+		}
+		for _, key := range keys {
+			if err := batch.Delete(idx.ctx, ds.NewKey(key)); err != nil {
+				return err
+			}
+		}
+		if err := batch.Delete(idx.ctx, ds.NewKey(eventKeysKey(height))); err != nil {
+			return err
+		}
+		if err := batch.Delete(idx.ctx, ds.NewKey(heightKey(height))); err != nil {
+			return err
+		}
+	}
+
+	return batch.Commit(idx.ctx)
 }
@@ -144,3 +144,42 @@ func TestBlockIndexer(t *testing.T) {
 		})
 	}
 }
+
+func TestBlockIndexerDeleteHeights(t *testing.T) {
+	kvStore, err := store.NewDefaultInMemoryKVStore()
+	require.NoError(t, err)
+	indexer := blockidxkv.New(context.Background(), kvStore)
+
+	index := func(height int64, events ...abci.Event) {
+		require.NoError(t, indexer.Index(types.EventDataNewBlockHeader{
+			Header:         types.Header{Height: height},
+			ResultEndBlock: abci.ResponseEndBlock{Events: events},
+		}))
+	}
+	fooEvent := func(value string) abci.Event {
+		return abci.Event{Type: "end_event", Attributes: []abci.EventAttribute{{Key: "foo", Value: value, Index: true}}}
+	}
+	search := func(q string) []int64 {
+		results, err := indexer.Search(context.Background(), query.MustParse(q))
+		require.NoError(t, err)
+		return results
+	}
+
+	for h := int64(1); h <= 3; h++ {
+		index(h, fooEvent(fmt.Sprint(h)))
+	}
+	// block indexed again with more events
+	index(2, fooEvent("2"), abci.Event{Type: "rollkit", Attributes: []abci.EventAttribute{{Key: "finality", Value: "da", Index: true}}})
+
+	require.NoError(t, indexer.DeleteHeights(2, 3))
+	ok, err := indexer.Has(3)
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	// canonical block at rolled back height
+	index(2, fooEvent("20"))
+	require.Equal(t, []int64{1}, search("end_event.foo = 1"))
+	require.Empty(t, search("end_event.foo = 2"))
+	require.Equal(t, []int64{2}, search("end_event.foo = 20"))
+	require.Empty(t, search("rollkit.finality = 'da'"))
+}
@@ -38,6 +38,12 @@ func heightKey(height int64) string {
 	return store.GenerateKey([]interface{}{types.BlockHeightKey, height})
 }
 
+// eventKeysKey is the key of event keys of the block at given height. Composite keys of events contain a dot, so
+// it doesn't collide with event keys.
+func eventKeysKey(height int64) string {
+	return store.GenerateKey([]interface{}{"block_event_keys", height})
+}
+
 func eventKey(compositeKey, typ, eventValue string, height int64) string {
 	return store.GenerateKey([]interface{}{compositeKey, eventValue, height, typ})
 }
@@ -0,0 +1,95 @@
+package state
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	cmbytes "github.com/cometbft/cometbft/libs/bytes"
+	cmjson "github.com/cometbft/cometbft/libs/json"
+	"github.com/cometbft/cometbft/proxy"
+	cmtypes "github.com/cometbft/cometbft/types"
+
+	"github.com/rollkit/rollkit/execution"
+)
+
+// RollbackHook reverts ABCI application to the state committed at given height, so that Info reports it as the
+// last block, with its app hash. Blocks above this height are executed again afterwards.
+//
+// ABCI has no rollback request, and the query connection is read-only and not ordered with block execution, so
+// applications supporting rollback of soft blocks register the hook with the block executor (see
+// BlockExecutor.SetRollbackHook). The hook is called directly, while block execution is paused: the block manager
+// rolls back blocks from the sync loop, between blocks, and no request is sent on the consensus connection until
+// the hook returns.
+type RollbackHook func(height uint64) error
+
+// EventReorg is the type of event published after soft blocks diverging from DA layer are rolled back
+// (query "tm.event='Reorg'").
+const EventReorg = "Reorg"
+
+// EventQueryReorg is the query matching reorg events.
+var EventQueryReorg = cmtypes.QueryForEvent(EventReorg)
+
+// EventDataReorg is the data of reorg event (see execution.Reorg).
+type EventDataReorg struct {
+	Height    int64              `json:"height"`
+	Reverted  []cmbytes.HexBytes `json:"reverted"`
+	Canonical cmbytes.HexBytes   `json:"canonical"`
+	DAHeight  uint64             `json:"da_height"`
+}
+
+func init() {
+	cmjson.RegisterType(EventDataReorg{}, "rollkit/event/Reorg")
+}
+
+var _ execution.Rollbacker = &BlockExecutor{}
+var _ execution.ReorgNotifier = &BlockExecutor{}
+
+// Rollback reverts the app to the state committed at given height, using rollback hook of the app (see
+// RollbackHook). execution.ErrRollbackNotSupported is returned if the hook isn't registered.
+//
+// Height and app hash reported by the app afterwards are verified, if query connection is available.
+func (e *BlockExecutor) Rollback(ctx context.Context, height uint64) error {
+	if e.rollback == nil {
+		return execution.ErrRollbackNotSupported
+	}
+	if err := e.rollback(height); err != nil {
+		return fmt.Errorf("application failed to roll back to height %d: %w", height, err)
+	}
+
+	e.pendingMtx.Lock()
+	e.pending = nil
+	e.pendingMtx.Unlock()
+
+	if e.proxyQuery == nil {
+		return nil
+	}
+	info, err := e.proxyQuery.InfoSync(proxy.RequestInfo)
+	if err != nil {
+		return fmt.Errorf("failed to query app info: %w", err)
+	}
+	if info.LastBlockHeight != int64(height) {
+		return fmt.Errorf("app reports last block height %d after rollback to height %d", info.LastBlockHeight, height)
+	}
+	if state, err := e.store.GetSoftState(height); err == nil && !bytes.Equal(info.LastBlockAppHash, state.AppHash) {
+		return fmt.Errorf("app hash %X after rollback doesn't match app hash %X at height %d", info.LastBlockAppHash, state.AppHash, height)
+	}
+	return nil
+}
+
+// BlocksReorged publishes reorg event.
+func (e *BlockExecutor) BlocksReorged(ctx context.Context, reorg execution.Reorg) error {
+	if e.eventBus == nil {
+		return nil
+	}
+	reverted := make([]cmbytes.HexBytes, len(reorg.Reverted))
+	for i, hash := range reorg.Reverted {
+		reverted[i] = cmbytes.HexBytes(hash)
+	}
+	return e.eventBus.Publish(EventReorg, EventDataReorg{
+		Height:    int64(reorg.Height),
+		Reverted:  reverted,
+		Canonical: cmbytes.HexBytes(reorg.Canonical),
+		DAHeight:  reorg.DAHeight,
+	})
+}
@@ -24,6 +24,10 @@ type TxIndexer interface {
 
 	// Search allows you to query for transactions.
 	Search(ctx context.Context, q *query.Query) ([]*abci.TxResult, error)
+
+	// DeleteHeights removes transactions included in blocks at heights from..to (inclusive), when the blocks are
+	// rolled back.
+	DeleteHeights(from, to int64) error
 }
 
 // Batch groups together multiple Index operations to be performed at the same time.
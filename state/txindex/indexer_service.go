@@ -3,6 +3,7 @@ package txindex
 import (
 	"context"
 
+	cmpubsub "github.com/cometbft/cometbft/libs/pubsub"
 	"github.com/cometbft/cometbft/libs/service"
 	"github.com/cometbft/cometbft/types"
 
@@ -25,6 +26,10 @@ type IndexerService struct {
 	txIdxr    TxIndexer
 	blockIdxr indexer.BlockIndexer
 	eventBus  *types.EventBus
+
+	// flushCh passes Flush requests to indexing goroutine; done is closed when the goroutine exits
+	flushCh chan chan struct{}
+	done    chan struct{}
 }
 
 // NewIndexerService returns a new service instance.
@@ -35,7 +40,14 @@ func NewIndexerService(
 	eventBus *types.EventBus,
 ) *IndexerService {
 
-	is := &IndexerService{ctx: ctx, txIdxr: txIdxr, blockIdxr: blockIdxr, eventBus: eventBus}
+	is := &IndexerService{
+		ctx:       ctx,
+		txIdxr:    txIdxr,
+		blockIdxr: blockIdxr,
+		eventBus:  eventBus,
+		flushCh:   make(chan chan struct{}),
+		done:      make(chan struct{}),
+	}
 	is.BaseService = *service.NewBaseService(nil, "IndexerService", is)
 	return is
 }
@@ -60,6 +72,7 @@ func (is *IndexerService) OnStart() error {
 	}
 
 	go func() {
+		defer close(is.done)
 		for {
 			select {
 			case <-is.ctx.Done():
@@ -67,48 +80,88 @@ func (is *IndexerService) OnStart() error {
 			case <-blockHeadersSub.Cancelled():
 				return
 			case msg := <-blockHeadersSub.Out():
-				eventDataHeader := msg.Data().(types.EventDataNewBlockHeader)
-				height := eventDataHeader.Header.Height
-				batch := NewBatch(eventDataHeader.NumTxs)
-
-				for i := int64(0); i < eventDataHeader.NumTxs; i++ {
-					select {
-					case <-is.ctx.Done():
-						return
-					case <-txsSub.Cancelled():
+				if !is.indexBlock(msg, txsSub) {
+					return
+				}
+			case flushed := <-is.flushCh:
+				for len(blockHeadersSub.Out()) > 0 {
+					if !is.indexBlock(<-blockHeadersSub.Out(), txsSub) {
 						return
-					case msg2 := <-txsSub.Out():
-						txResult := msg2.Data().(types.EventDataTx).TxResult
-
-						if err = batch.Add(&txResult); err != nil {
-							is.Logger.Error(
-								"failed to add tx to batch",
-								"height", height,
-								"index", txResult.Index,
-								"err", err,
-							)
-						}
 					}
 				}
-
-				if err := is.blockIdxr.Index(eventDataHeader); err != nil {
-					is.Logger.Error("failed to index block", "height", height, "err", err)
-				} else {
-					is.Logger.Info("indexed block", "height", height)
-				}
-
-				if err = is.txIdxr.AddBatch(batch); err != nil {
-					is.Logger.Error("failed to index block txs", "height", height, "err", err)
-				} else {
-					is.Logger.Debug("indexed block txs", "height", height, "num_txs", eventDataHeader.NumTxs)
-				}
-
+				close(flushed)
 			}
 		}
 	}()
 	return nil
 }
 
+// indexBlock indexes block from header event, and its transactions received from txsSub. It returns false if
+// the service is stopped.
+func (is *IndexerService) indexBlock(msg cmpubsub.Message, txsSub types.Subscription) bool {
+	eventDataHeader := msg.Data().(types.EventDataNewBlockHeader)
+	height := eventDataHeader.Header.Height
+	batch := NewBatch(eventDataHeader.NumTxs)
+
+	for i := int64(0); i < eventDataHeader.NumTxs; i++ {
+		select {
+		case <-is.ctx.Done():
+			return false
+		case <-txsSub.Cancelled():
+			return false
+		case msg2 := <-txsSub.Out():
+			txResult := msg2.Data().(types.EventDataTx).TxResult
+
+			if err := batch.Add(&txResult); err != nil {
+				is.Logger.Error(
+					"failed to add tx to batch",
+					"height", height,
+					"index", txResult.Index,
+					"err", err,
+				)
+			}
+		}
+	}
+
+	if err := is.blockIdxr.Index(eventDataHeader); err != nil {
+		is.Logger.Error("failed to index block", "height", height, "err", err)
+	} else {
+		is.Logger.Info("indexed block", "height", height)
+	}
+
+	if err := is.txIdxr.AddBatch(batch); err != nil {
+		is.Logger.Error("failed to index block txs", "height", height, "err", err)
+	} else {
+		is.Logger.Debug("indexed block txs", "height", height, "num_txs", eventDataHeader.NumTxs)
+	}
+	return true
+}
+
+// Flush blocks until blocks published before it was called are indexed. It's called before indexed blocks are
+// removed (see TxIndexer.DeleteHeights), so that events of removed blocks still queued in the service are not
+// indexed afterwards.
+func (is *IndexerService) Flush(ctx context.Context) error {
+	if !is.IsRunning() {
+		return nil
+	}
+	flushed := make(chan struct{})
+	select {
+	case is.flushCh <- flushed:
+	case <-is.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	select {
+	case <-flushed:
+		return nil
+	case <-is.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
 // OnStop implements service.Service by unsubscribing from all transactions.
 func (is *IndexerService) OnStop() {
 	if is.eventBus.IsRunning() {
@@ -85,3 +85,60 @@ func TestIndexerServiceIndexesBlocks(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, txResult2, res)
 }
+
+func TestIndexerServiceFlush(t *testing.T) {
+	eventBus := types.NewEventBus()
+	eventBus.SetLogger(log.TestingLogger())
+	require.NoError(t, eventBus.Start())
+	t.Cleanup(func() {
+		if err := eventBus.Stop(); err != nil {
+			t.Error(err)
+		}
+	})
+
+	kvStore, _ := store.NewDefaultInMemoryKVStore()
+	txIndexer := kv.NewTxIndex(context.Background(), kvStore)
+	prefixStore := (ktds.Wrap(kvStore, ktds.PrefixTransform{Prefix: ds.NewKey("block_events")}).Children()[0]).(ds.TxnDatastore)
+	blockIndexer := blockidxkv.New(context.Background(), prefixStore)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	service := txindex.NewIndexerService(ctx, txIndexer, blockIndexer, eventBus)
+	service.SetLogger(log.TestingLogger())
+
+	// service that isn't running has nothing to flush
+	require.NoError(t, service.Flush(ctx))
+
+	require.NoError(t, service.Start())
+	t.Cleanup(func() {
+		if err := service.Stop(); err != nil {
+			t.Error(err)
+		}
+	})
+
+	for height := int64(1); height <= 5; height++ {
+		require.NoError(t, eventBus.PublishEventNewBlockHeader(types.EventDataNewBlockHeader{
+			Header: types.Header{Height: height},
+			NumTxs: int64(1),
+		}))
+		require.NoError(t, eventBus.PublishEventTx(types.EventDataTx{TxResult: abci.TxResult{
+			Height: height,
+			Tx:     types.Tx{byte(height)},
+		}}))
+	}
+
+	// blocks published before Flush are indexed when it returns
+	require.NoError(t, service.Flush(ctx))
+	for height := int64(1); height <= 5; height++ {
+		ok, err := blockIndexer.Has(height)
+		require.NoError(t, err)
+		require.True(t, ok)
+		res, err := txIndexer.Get(types.Tx{byte(height)}.Hash())
+		require.NoError(t, err)
+		require.NotNil(t, res)
+	}
+
+	// flush returns immediately after service context is cancelled
+	cancel()
+	require.NoError(t, service.Flush(context.Background()))
+}
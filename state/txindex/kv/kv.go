@@ -3,6 +3,7 @@ package kv
 import (
 	"context"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"math/big"
 	"strconv"
@@ -49,6 +50,9 @@ func (txi *TxIndex) Get(hash []byte) (*abci.TxResult, error) {
 	}
 
 	rawBytes, err := txi.store.Get(txi.ctx, ds.NewKey(hex.EncodeToString(hash)))
+	if errors.Is(err, ds.ErrNotFound) {
+		return nil, nil
+	}
 	if err != nil {
 		panic(err)
 	}
@@ -143,6 +147,73 @@ func (txi *TxIndex) Index(result *abci.TxResult) error {
 	return b.Commit(txi.ctx)
 }
 
+// DeleteHeights removes transactions included in blocks at heights from..to (inclusive) from the index, when the
+// blocks are rolled back. Transactions indexed again at other heights are kept.
+func (txi *TxIndex) DeleteHeights(from, to int64) error {
+	b, err := txi.store.NewTransaction(txi.ctx, false)
+	if err != nil {
+		return fmt.Errorf("failed to create a new batch for transaction: %w", err)
+	}
+	defer b.Discard(txi.ctx)
+
+	for height := from; height <= to; height++ {
+		results, err := store.PrefixEntries(txi.ctx, txi.store, startKey(types.TxHeightKey, height, height))
+		if err != nil {
+			return err
+		}
+		entries, err := results.Rest()
+		if err != nil {
+			return err
+		}
+		for _, entry := range entries {
+			if err := b.Delete(txi.ctx, ds.NewKey(entry.Key)); err != nil {
+				return err
+			}
+			hashKey := ds.NewKey(hex.EncodeToString(entry.Value))
+			rawBytes, err := txi.store.Get(txi.ctx, hashKey)
+			if errors.Is(err, ds.ErrNotFound) {
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			result := new(abci.TxResult)
+			if err := proto.Unmarshal(rawBytes, result); err != nil {
+				return fmt.Errorf("error reading TxResult: %v", err)
+			}
+			if result.Height != height {
+				continue
+			}
+			if err := txi.deleteEvents(result, b); err != nil {
+				return err
+			}
+			if err := b.Delete(txi.ctx, hashKey); err != nil {
+				return err
+			}
+		}
+	}
+
+	return b.Commit(txi.ctx)
+}
+
+func (txi *TxIndex) deleteEvents(result *abci.TxResult, store ds.Txn) error {
+	for _, event := range result.Result.Events {
+		if len(event.Type) == 0 {
+			continue
+		}
+		for _, attr := range event.Attributes {
+			if len(attr.Key) == 0 || !attr.GetIndex() {
+				continue
+			}
+			compositeTag := fmt.Sprintf("%s.%s", event.Type, string(attr.Key))
+			if err := store.Delete(txi.ctx, ds.NewKey(keyForEvent(compositeTag, attr.Value, result))); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
 func (txi *TxIndex) indexEvents(result *abci.TxResult, hash []byte, store ds.Txn) error {
 	for _, event := range result.Result.Events {
 		// only index events with a non-empty type
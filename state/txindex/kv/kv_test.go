@@ -69,6 +69,47 @@ func TestTxIndex(t *testing.T) {
 	assert.True(t, proto.Equal(txResult2, loadedTxResult2))
 }
 
+func TestTxIndexDeleteHeights(t *testing.T) {
+	kvStore, _ := store.NewDefaultInMemoryKVStore()
+	ctx := context.Background()
+	indexer := NewTxIndex(ctx, kvStore)
+
+	events := func(n string) []abci.Event {
+		return []abci.Event{{Type: "account", Attributes: []abci.EventAttribute{{Key: "number", Value: n, Index: true}}}}
+	}
+	txResult1 := txResultWithEvents(events("1"))
+	txResult2 := txResultWithEvents(events("2"))
+	txResult2.Tx = types.Tx("HELLO AGAIN")
+	txResult2.Height = 2
+	// transaction indexed again at lower height
+	txResult3 := txResultWithEvents(events("3"))
+	txResult3.Tx = types.Tx("HELLO TWICE")
+	txResult3.Height = 3
+	for _, r := range []*abci.TxResult{txResult1, txResult2, txResult3} {
+		require.NoError(t, indexer.Index(r))
+	}
+	txResult3.Height = 1
+	txResult3.Index = 1
+	require.NoError(t, indexer.Index(txResult3))
+
+	require.NoError(t, indexer.DeleteHeights(2, 3))
+
+	loaded, err := indexer.Get(types.Tx(txResult2.Tx).Hash())
+	require.NoError(t, err)
+	assert.Nil(t, loaded)
+	for n, expected := range map[string]int{"1": 1, "2": 0, "3": 1} {
+		results, err := indexer.Search(ctx, query.MustParse("account.number = "+n))
+		require.NoError(t, err)
+		assert.Len(t, results, expected, n)
+	}
+	results, err := indexer.Search(ctx, query.MustParse("tx.height >= 2"))
+	require.NoError(t, err)
+	assert.Empty(t, results)
+	loaded, err = indexer.Get(types.Tx(txResult3.Tx).Hash())
+	require.NoError(t, err)
+	assert.True(t, proto.Equal(txResult3, loaded))
+}
+
 func TestTxSearch(t *testing.T) {
 	kvStore, _ := store.NewDefaultInMemoryKVStore()
 	indexer := NewTxIndex(context.Background(), kvStore)
@@ -33,3 +33,8 @@ func (txi *TxIndex) Index(result *abci.TxResult) error {
 func (txi *TxIndex) Search(ctx context.Context, q *query.Query) ([]*abci.TxResult, error) {
 	return []*abci.TxResult{}, nil
 }
+
+// DeleteHeights is a noop and always returns nil.
+func (txi *TxIndex) DeleteHeights(from, to int64) error {
+	return nil
+}
@@ -0,0 +1,38 @@
+package store
+
+import (
+	"errors"
+	"io"
+
+	ds "github.com/ipfs/go-datastore"
+	badger3 "github.com/ipfs/go-ds-badger3"
+)
+
+// restoreMaxPendingWrites is the number of pending writes during restore (see badger.DB.Load).
+const restoreMaxPendingWrites = 256
+
+// ErrBackupNotSupported is returned when datastore doesn't support online backup and restore.
+var ErrBackupNotSupported = errors.New("backup and restore are supported only by badger datastore")
+
+// Backup writes consistent snapshot of all entries of kv to w, using badger backup format.
+//
+// Backup can be created while datastore is used; writes made after backup starts are not included.
+func Backup(kv ds.Datastore, w io.Writer) error {
+	db, ok := kv.(*badger3.Datastore)
+	if !ok {
+		return ErrBackupNotSupported
+	}
+	_, err := db.DB.Backup(w, 0)
+	return err
+}
+
+// Restore loads entries from backup created with Backup into kv.
+//
+// Existing entries with the same keys are overwritten, so kv should be empty.
+func Restore(kv ds.Datastore, r io.Reader) error {
+	db, ok := kv.(*badger3.Datastore)
+	if !ok {
+		return ErrBackupNotSupported
+	}
+	return db.DB.Load(r, restoreMaxPendingWrites)
+}
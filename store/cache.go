@@ -0,0 +1,356 @@
+package store
+
+import (
+	"sync"
+	"sync/atomic"
+
+	cmstate "github.com/cometbft/cometbft/proto/tendermint/state"
+	"github.com/gogo/protobuf/proto"
+	lru "github.com/hashicorp/golang-lru/v2"
+
+	"github.com/rollkit/rollkit/types"
+)
+
+// CachedStore is a Store with read-through LRU cache of decoded blocks, commits and block responses.
+//
+// Values are copied when they are cached and when they are returned from cache, so callers can modify them
+// (e.g. block manager sets data hash and commit of published block) without affecting cached values.
+type CachedStore struct {
+	Store
+
+	blocks    *lru.Cache[uint64, *types.Block]
+	commits   *lru.Cache[uint64, *types.Commit]
+	responses *lru.Cache[uint64, *cmstate.ABCIResponses]
+	// hashes maps block hashes to heights, to serve lookups by hash from caches indexed by height.
+	hashes *lru.Cache[string, uint64]
+
+	// gen is incremented on every invalidation; values loaded before invalidation are not cached.
+	mtx sync.Mutex
+	gen uint64
+
+	blockStats     cacheCounters
+	commitStats    cacheCounters
+	responsesStats cacheCounters
+}
+
+var _ Store = &CachedStore{}
+
+// CacheStats contains hit and miss counters of CachedStore.
+type CacheStats struct {
+	Blocks    CacheCounters `json:"blocks"`
+	Commits   CacheCounters `json:"commits"`
+	Responses CacheCounters `json:"responses"`
+}
+
+// CacheCounters contains hit and miss counters of single cache.
+type CacheCounters struct {
+	Hits    uint64  `json:"hits"`
+	Misses  uint64  `json:"misses"`
+	HitRate float64 `json:"hit_rate"`
+}
+
+type cacheCounters struct {
+	hits   atomic.Uint64
+	misses atomic.Uint64
+}
+
+func (c *cacheCounters) hit(ok bool) {
+	if ok {
+		c.hits.Add(1)
+	} else {
+		c.misses.Add(1)
+	}
+}
+
+func (c *cacheCounters) stats() CacheCounters {
+	s := CacheCounters{Hits: c.hits.Load(), Misses: c.misses.Load()}
+	if total := s.Hits + s.Misses; total > 0 {
+		s.HitRate = float64(s.Hits) / float64(total)
+	}
+	return s
+}
+
+// NewCachedStore returns Store caching up to size blocks, commits and block responses (each) read from s.
+func NewCachedStore(s Store, size int) (*CachedStore, error) {
+	blocks, err := lru.New[uint64, *types.Block](size)
+	if err != nil {
+		return nil, err
+	}
+	commits, err := lru.New[uint64, *types.Commit](size)
+	if err != nil {
+		return nil, err
+	}
+	responses, err := lru.New[uint64, *cmstate.ABCIResponses](size)
+	if err != nil {
+		return nil, err
+	}
+	hashes, err := lru.New[string, uint64](size)
+	if err != nil {
+		return nil, err
+	}
+	return &CachedStore{
+		Store:     s,
+		blocks:    blocks,
+		commits:   commits,
+		responses: responses,
+		hashes:    hashes,
+	}, nil
+}
+
+// SaveBlock saves block along with its commit, and replaces cached values at block height.
+func (c *CachedStore) SaveBlock(block *types.Block, commit *types.Commit) error {
+	if err := c.Store.SaveBlock(block, commit); err != nil {
+		return err
+	}
+	height := block.Height()
+	hash := string(block.Hash())
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	c.gen++
+	// different block can be saved at the same height only after rollback, which invalidates cached values above
+	// rollback height; hash of the replaced block is dropped in case it's still cached
+	if old, ok := c.blocks.Peek(height); ok {
+		if oldHash := string(old.Hash()); oldHash != hash {
+			c.hashes.Remove(oldHash)
+		}
+	}
+	c.responses.Remove(height)
+	c.blocks.Add(height, copyBlock(block))
+	c.commits.Add(height, copyCommit(commit))
+	c.hashes.Add(hash, height)
+	return nil
+}
+
+// GetBlock returns block at given height, or error if it's not found in Store.
+func (c *CachedStore) GetBlock(height uint64) (*types.Block, error) {
+	if block, ok := c.blocks.Get(height); ok {
+		c.blockStats.hit(true)
+		return copyBlock(block), nil
+	}
+	c.blockStats.hit(false)
+	gen := c.generation()
+	block, err := c.Store.GetBlock(height)
+	if err != nil {
+		return nil, err
+	}
+	c.add(gen, func() {
+		c.blocks.Add(height, copyBlock(block))
+		c.hashes.Add(string(block.Hash()), height)
+	})
+	return block, nil
+}
+
+// GetBlockByHash returns block with given block header hash, or error if it's not found in Store.
+func (c *CachedStore) GetBlockByHash(hash types.Hash) (*types.Block, error) {
+	if height, ok := c.hashes.Get(string(hash)); ok {
+		if block, ok := c.blocks.Get(height); ok {
+			c.blockStats.hit(true)
+			return copyBlock(block), nil
+		}
+	}
+	c.blockStats.hit(false)
+	gen := c.generation()
+	block, err := c.Store.GetBlockByHash(hash)
+	if err != nil {
+		return nil, err
+	}
+	c.add(gen, func() {
+		c.blocks.Add(block.Height(), copyBlock(block))
+		c.hashes.Add(string(hash), block.Height())
+	})
+	return block, nil
+}
+
+// SaveBlockResponses saves block responses in Store, and replaces cached responses at given height.
+func (c *CachedStore) SaveBlockResponses(height uint64, responses *cmstate.ABCIResponses) error {
+	if err := c.Store.SaveBlockResponses(height, responses); err != nil {
+		return err
+	}
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	c.gen++
+	c.responses.Add(height, copyResponses(responses))
+	return nil
+}
+
+// GetBlockResponses returns block results at given height, or error if it's not found in Store.
+func (c *CachedStore) GetBlockResponses(height uint64) (*cmstate.ABCIResponses, error) {
+	if responses, ok := c.responses.Get(height); ok {
+		c.responsesStats.hit(true)
+		return copyResponses(responses), nil
+	}
+	c.responsesStats.hit(false)
+	gen := c.generation()
+	responses, err := c.Store.GetBlockResponses(height)
+	if err != nil {
+		return nil, err
+	}
+	c.add(gen, func() { c.responses.Add(height, copyResponses(responses)) })
+	return responses, nil
+}
+
+// GetCommit returns commit for a block at given height, or error if it's not found in Store.
+func (c *CachedStore) GetCommit(height uint64) (*types.Commit, error) {
+	if commit, ok := c.commits.Get(height); ok {
+		c.commitStats.hit(true)
+		return copyCommit(commit), nil
+	}
+	c.commitStats.hit(false)
+	gen := c.generation()
+	commit, err := c.Store.GetCommit(height)
+	if err != nil {
+		return nil, err
+	}
+	c.add(gen, func() { c.commits.Add(height, copyCommit(commit)) })
+	return commit, nil
+}
+
+// GetCommitByHash returns commit for a block with given block header hash, or error if it's not found in Store.
+//
+// Commits are cached by height, so commit is cached only if height of the block is known.
+func (c *CachedStore) GetCommitByHash(hash types.Hash) (*types.Commit, error) {
+	height, known := c.hashes.Get(string(hash))
+	if known {
+		if commit, ok := c.commits.Get(height); ok {
+			c.commitStats.hit(true)
+			return copyCommit(commit), nil
+		}
+	}
+	c.commitStats.hit(false)
+	gen := c.generation()
+	commit, err := c.Store.GetCommitByHash(hash)
+	if err != nil {
+		return nil, err
+	}
+	if known {
+		c.add(gen, func() { c.commits.Add(height, copyCommit(commit)) })
+	}
+	return commit, nil
+}
+
+// Rollback removes blocks above given height from the underlying store, together with their cached values.
+func (c *CachedStore) Rollback(height uint64) error {
+	if err := c.Store.Rollback(height); err != nil {
+		return err
+	}
+	c.InvalidateFrom(height + 1)
+	return nil
+}
+
+// InvalidateFrom removes cached values at given height and above. It has to be called after blocks are rolled back.
+func (c *CachedStore) InvalidateFrom(height uint64) {
+	c.invalidate(func(h uint64) bool { return h >= height })
+}
+
+// InvalidateBelow removes cached values below given height. It has to be called after blocks are pruned.
+func (c *CachedStore) InvalidateBelow(height uint64) {
+	c.invalidate(func(h uint64) bool { return h < height })
+}
+
+// Stats returns hit and miss counters of the caches.
+func (c *CachedStore) Stats() CacheStats {
+	return CacheStats{
+		Blocks:    c.blockStats.stats(),
+		Commits:   c.commitStats.stats(),
+		Responses: c.responsesStats.stats(),
+	}
+}
+
+func (c *CachedStore) generation() uint64 {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	return c.gen
+}
+
+// add calls fn to populate caches, unless caches were invalidated after gen was obtained.
+func (c *CachedStore) add(gen uint64, fn func()) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	if c.gen == gen {
+		fn()
+	}
+}
+
+// invalidate removes cached values at heights matching given predicate, and returns new generation.
+func (c *CachedStore) invalidate(match func(height uint64) bool) uint64 {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	c.gen++
+	for _, h := range c.blocks.Keys() {
+		if match(h) {
+			c.blocks.Remove(h)
+		}
+	}
+	for _, h := range c.commits.Keys() {
+		if match(h) {
+			c.commits.Remove(h)
+		}
+	}
+	for _, h := range c.responses.Keys() {
+		if match(h) {
+			c.responses.Remove(h)
+		}
+	}
+	for _, hash := range c.hashes.Keys() {
+		if h, ok := c.hashes.Peek(hash); ok && match(h) {
+			c.hashes.Remove(hash)
+		}
+	}
+	return c.gen
+}
+
+// copyBlock returns deep copy of block.
+func copyBlock(block *types.Block) *types.Block {
+	c := *block
+	h := &c.SignedHeader
+	h.LastHeaderHash = copyBytes(h.LastHeaderHash)
+	h.LastCommitHash = copyBytes(h.LastCommitHash)
+	h.DataHash = copyBytes(h.DataHash)
+	h.ConsensusHash = copyBytes(h.ConsensusHash)
+	h.AppHash = copyBytes(h.AppHash)
+	h.ValidatorHash = copyBytes(h.ValidatorHash)
+	h.NextValidatorHash = copyBytes(h.NextValidatorHash)
+	h.LastResultsHash = copyBytes(h.LastResultsHash)
+	h.ProposerAddress = copyBytes(h.ProposerAddress)
+	h.Commit = *copyCommit(&h.Commit)
+	if h.Validators != nil {
+		h.Validators = h.Validators.Copy()
+	}
+	if block.Data.Txs != nil {
+		c.Data.Txs = make(types.Txs, len(block.Data.Txs))
+		for i, tx := range block.Data.Txs {
+			c.Data.Txs[i] = copyBytes(tx)
+		}
+	}
+	if roots := block.Data.IntermediateStateRoots.RawRootsList; roots != nil {
+		c.Data.IntermediateStateRoots.RawRootsList = make([][]byte, len(roots))
+		for i, root := range roots {
+			c.Data.IntermediateStateRoots.RawRootsList[i] = copyBytes(root)
+		}
+	}
+	return &c
+}
+
+// copyCommit returns deep copy of commit.
+func copyCommit(commit *types.Commit) *types.Commit {
+	c := &types.Commit{}
+	if commit.Signatures != nil {
+		c.Signatures = make([]types.Signature, len(commit.Signatures))
+		for i, sig := range commit.Signatures {
+			c.Signatures[i] = copyBytes(sig)
+		}
+	}
+	return c
+}
+
+// copyResponses returns deep copy of block responses.
+func copyResponses(responses *cmstate.ABCIResponses) *cmstate.ABCIResponses {
+	return proto.Clone(responses).(*cmstate.ABCIResponses)
+}
+
+func copyBytes[T ~[]byte](b T) T {
+	if b == nil {
+		return nil
+	}
+	return append(T{}, b...)
+}
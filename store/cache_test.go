@@ -0,0 +1,198 @@
+package store
+
+import (
+	"context"
+	"testing"
+
+	abcitypes "github.com/cometbft/cometbft/abci/types"
+	cmstate "github.com/cometbft/cometbft/proto/tendermint/state"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rollkit/rollkit/types"
+)
+
+func newTestCachedStore(t *testing.T, size int) (Store, *CachedStore) {
+	kv, err := NewDefaultInMemoryKVStore()
+	require.NoError(t, err)
+	s := New(context.Background(), kv)
+	cached, err := NewCachedStore(s, size)
+	require.NoError(t, err)
+	return s, cached
+}
+
+// getTestBlock returns random block with validator hash set, as block hash is empty without it.
+func getTestBlock(height uint64, nTxs int) *types.Block {
+	block := types.GetRandomBlock(height, nTxs)
+	block.SignedHeader.ValidatorHash = types.GetRandomBytes(32)
+	return block
+}
+
+func TestCachedStoreReadThrough(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	s, cached := newTestCachedStore(t, 10)
+	for h := uint64(1); h <= 3; h++ {
+		block := getTestBlock(h, 1)
+		require.NoError(s.SaveBlock(block, &types.Commit{}))
+		require.NoError(s.SaveBlockResponses(h, &cmstate.ABCIResponses{}))
+	}
+
+	block, err := cached.GetBlock(2)
+	require.NoError(err)
+	again, err := cached.GetBlock(2)
+	require.NoError(err)
+	assert.Equal(block, again)
+	byHash, err := cached.GetBlockByHash(block.Hash())
+	require.NoError(err)
+	assert.Equal(block, byHash)
+
+	_, err = cached.GetCommit(1)
+	require.NoError(err)
+	_, err = cached.GetCommit(1)
+	require.NoError(err)
+	_, err = cached.GetCommitByHash(block.Hash())
+	require.NoError(err)
+
+	_, err = cached.GetBlockResponses(3)
+	require.NoError(err)
+	_, err = cached.GetBlockResponses(3)
+	require.NoError(err)
+
+	_, err = cached.GetBlock(4)
+	assert.Error(err)
+
+	stats := cached.Stats()
+	assert.Equal(CacheCounters{Hits: 2, Misses: 2, HitRate: 0.5}, stats.Blocks)
+	assert.Equal(CacheCounters{Hits: 1, Misses: 2, HitRate: 1.0 / 3}, stats.Commits)
+	assert.Equal(CacheCounters{Hits: 1, Misses: 1, HitRate: 0.5}, stats.Responses)
+}
+
+func TestCachedStoreSave(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	_, cached := newTestCachedStore(t, 10)
+	block := getTestBlock(1, 1)
+	commit := &types.Commit{Signatures: []types.Signature{types.GetRandomBytes(64)}}
+	require.NoError(cached.SaveBlock(block, commit))
+
+	loaded, err := cached.GetBlock(1)
+	require.NoError(err)
+	assert.Equal(block, loaded)
+	loadedCommit, err := cached.GetCommit(1)
+	require.NoError(err)
+	assert.Equal(commit, loadedCommit)
+
+	// block at the same height replaces cached block (and its hash)
+	replacement := getTestBlock(1, 2)
+	require.NoError(cached.SaveBlock(replacement, &types.Commit{}))
+	loaded, err = cached.GetBlock(1)
+	require.NoError(err)
+	assert.Equal(replacement, loaded)
+	loaded, err = cached.GetBlockByHash(block.Hash())
+	require.NoError(err)
+	assert.Equal(block.Hash(), loaded.Hash())
+	assert.NotSame(block, loaded)
+
+	responses := &cmstate.ABCIResponses{DeliverTxs: []*abcitypes.ResponseDeliverTx{{Code: 1}}}
+	require.NoError(cached.SaveBlockResponses(1, responses))
+	loadedResponses, err := cached.GetBlockResponses(1)
+	require.NoError(err)
+	assert.Equal(responses, loadedResponses)
+}
+
+func TestCachedStoreCopies(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	_, cached := newTestCachedStore(t, 10)
+	block := getTestBlock(1, 2)
+	block.SignedHeader.Validators = types.GetRandomValidatorSet()
+	commit := &types.Commit{Signatures: []types.Signature{types.GetRandomBytes(64)}}
+	responses := &cmstate.ABCIResponses{DeliverTxs: []*abcitypes.ResponseDeliverTx{{Code: 1}}}
+	require.NoError(cached.SaveBlock(block, commit))
+	require.NoError(cached.SaveBlockResponses(1, responses))
+	expectedBlock := copyBlock(block)
+	expectedCommit := copyCommit(commit)
+	expectedResponses := copyResponses(responses)
+
+	// values passed to Save methods are not cached
+	block.SignedHeader.AppHash[0]++
+	commit.Signatures[0][0]++
+	responses.DeliverTxs[0].Code++
+
+	// values returned by Get methods are not cached
+	loaded, err := cached.GetBlock(1)
+	require.NoError(err)
+	assert.Equal(expectedBlock, loaded)
+	loaded.SignedHeader.DataHash = types.GetRandomBytes(32)
+	loaded.SignedHeader.Commit = types.Commit{Signatures: []types.Signature{types.GetRandomBytes(64)}}
+	loaded.SignedHeader.Validators.Validators[0].VotingPower++
+	loaded.Data.Txs[0][0]++
+	loadedCommit, err := cached.GetCommit(1)
+	require.NoError(err)
+	loadedCommit.Signatures[0][0]++
+	loadedResponses, err := cached.GetBlockResponses(1)
+	require.NoError(err)
+	loadedResponses.DeliverTxs[0].Code++
+
+	loaded, err = cached.GetBlockByHash(expectedBlock.Hash())
+	require.NoError(err)
+	assert.Equal(expectedBlock, loaded)
+	loadedCommit, err = cached.GetCommit(1)
+	require.NoError(err)
+	assert.Equal(expectedCommit, loadedCommit)
+	loadedResponses, err = cached.GetBlockResponses(1)
+	require.NoError(err)
+	assert.Equal(expectedResponses, loadedResponses)
+	assert.Equal(CacheCounters{Hits: 2, HitRate: 1}, cached.Stats().Blocks)
+}
+
+func TestCachedStoreInvalidate(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	_, cached := newTestCachedStore(t, 10)
+	for h := uint64(1); h <= 5; h++ {
+		require.NoError(cached.SaveBlock(getTestBlock(h, 1), &types.Commit{}))
+		require.NoError(cached.SaveBlockResponses(h, &cmstate.ABCIResponses{}))
+	}
+	assert.Equal(5, cached.blocks.Len())
+
+	cached.InvalidateFrom(4)
+	assert.ElementsMatch([]uint64{1, 2, 3}, cached.blocks.Keys())
+	assert.ElementsMatch([]uint64{1, 2, 3}, cached.commits.Keys())
+	assert.ElementsMatch([]uint64{1, 2, 3}, cached.responses.Keys())
+	assert.Equal(3, cached.hashes.Len())
+
+	cached.InvalidateBelow(3)
+	assert.ElementsMatch([]uint64{3}, cached.blocks.Keys())
+	assert.ElementsMatch([]uint64{3}, cached.commits.Keys())
+	assert.ElementsMatch([]uint64{3}, cached.responses.Keys())
+	assert.Equal(1, cached.hashes.Len())
+
+	// invalidated values are loaded from underlying store
+	block, err := cached.GetBlock(5)
+	require.NoError(err)
+	assert.Equal(uint64(5), block.Height())
+	assert.Equal(CacheCounters{Misses: 1}, cached.Stats().Blocks)
+}
+
+func TestCachedStoreRollback(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	s, cached := newTestCachedStore(t, 10)
+	for h := uint64(1); h <= 5; h++ {
+		require.NoError(cached.SaveBlock(getTestBlock(h, 1), &types.Commit{}))
+		cached.SetHeight(h)
+	}
+
+	require.NoError(cached.Rollback(3))
+	assert.Equal(uint64(3), s.Height())
+	assert.ElementsMatch([]uint64{1, 2, 3}, cached.blocks.Keys())
+	_, err := cached.GetBlock(4)
+	assert.Error(err)
+}
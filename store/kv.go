@@ -6,29 +6,84 @@ import (
 	"fmt"
 	"path"
 	"path/filepath"
-	"time"
 
-	"github.com/dgraph-io/badger/v3"
+	"github.com/dgraph-io/badger/v3/options"
 	ds "github.com/ipfs/go-datastore"
 	dsq "github.com/ipfs/go-datastore/query"
 	badger3 "github.com/ipfs/go-ds-badger3"
+	leveldb "github.com/ipfs/go-ds-leveldb"
+
+	"github.com/rollkit/rollkit/config"
 )
 
 // NewDefaultInMemoryKVStore builds KVStore that works in-memory (without accessing disk).
 func NewDefaultInMemoryKVStore() (ds.TxnDatastore, error) {
-	inMemoryOptions := &badger3.Options{
-		GcDiscardRatio: 0.2,
-		GcInterval:     15 * time.Minute,
-		GcSleep:        10 * time.Second,
-		Options:        badger.DefaultOptions("").WithInMemory(true),
+	return NewInMemoryKVStore(config.BadgerConfig{})
+}
+
+// NewInMemoryKVStore builds badger based KVStore that works in-memory (without accessing disk), tuned with conf.
+func NewInMemoryKVStore(conf config.BadgerConfig) (ds.TxnDatastore, error) {
+	opts, err := badgerOptions(conf)
+	if err != nil {
+		return nil, err
 	}
-	return badger3.NewDatastore("", inMemoryOptions)
+	opts.Options = opts.Options.WithInMemory(true)
+	return badger3.NewDatastore("", opts)
 }
 
 // NewDefaultKVStore creates instance of default key-value store.
 func NewDefaultKVStore(rootDir, dbPath, dbName string) (ds.TxnDatastore, error) {
+	return NewKVStore(rootDir, dbPath, dbName, config.DBConfig{})
+}
+
+// NewKVStore creates instance of key-value store, using backend and options from conf.
+func NewKVStore(rootDir, dbPath, dbName string, conf config.DBConfig) (ds.TxnDatastore, error) {
 	path := filepath.Join(rootify(rootDir, dbPath), dbName)
-	return badger3.NewDatastore(path, nil)
+	switch conf.DBBackend {
+	case "", config.DBBackendBadger:
+		opts, err := badgerOptions(conf.Badger)
+		if err != nil {
+			return nil, err
+		}
+		return badger3.NewDatastore(path, opts)
+	case config.DBBackendLevelDB:
+		return leveldb.NewDatastore(path, nil)
+	default:
+		return nil, fmt.Errorf("unknown datastore backend: %q", conf.DBBackend)
+	}
+}
+
+// badgerOptions translates conf into badger datastore options. Zero values are replaced with defaults.
+func badgerOptions(conf config.BadgerConfig) (*badger3.Options, error) {
+	opts := badger3.DefaultOptions
+	if conf.ValueLogFileSize > 0 {
+		opts.Options = opts.Options.WithValueLogFileSize(conf.ValueLogFileSize)
+	}
+	if conf.BlockCacheSize > 0 {
+		opts.Options = opts.Options.WithBlockCacheSize(conf.BlockCacheSize)
+	}
+	if conf.IndexCacheSize > 0 {
+		opts.Options = opts.Options.WithIndexCacheSize(conf.IndexCacheSize)
+	}
+	switch conf.Compression {
+	case "":
+	case config.BadgerCompressionNone:
+		opts.Options = opts.Options.WithCompression(options.None)
+	case config.BadgerCompressionSnappy:
+		opts.Options = opts.Options.WithCompression(options.Snappy)
+	case config.BadgerCompressionZSTD:
+		opts.Options = opts.Options.WithCompression(options.ZSTD)
+	default:
+		return nil, fmt.Errorf("unknown badger compression: %q", conf.Compression)
+	}
+	opts.Options = opts.Options.WithSyncWrites(conf.SyncWrites)
+	if conf.GCInterval > 0 {
+		opts.GcInterval = conf.GCInterval
+	}
+	if conf.GCDiscardRatio > 0 {
+		opts.GcDiscardRatio = conf.GCDiscardRatio
+	}
+	return &opts, nil
 }
 
 // PrefixEntries retrieves all entries in the datastore whose keys have the supplied prefix
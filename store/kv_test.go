@@ -0,0 +1,119 @@
+package store
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	ds "github.com/ipfs/go-datastore"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rollkit/rollkit/config"
+	"github.com/rollkit/rollkit/types"
+)
+
+func TestNewKVStore(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name string
+		conf config.DBConfig
+		err  bool
+	}{
+		{"default", config.DBConfig{}, false},
+		{"badger", config.DBConfig{DBBackend: config.DBBackendBadger}, false},
+		{"tuned badger", config.DBConfig{
+			DBBackend: config.DBBackendBadger,
+			Badger: config.BadgerConfig{
+				ValueLogFileSize: 16 << 20,
+				BlockCacheSize:   8 << 20,
+				IndexCacheSize:   8 << 20,
+				Compression:      config.BadgerCompressionZSTD,
+				SyncWrites:       true,
+				GCInterval:       time.Minute,
+				GCDiscardRatio:   0.5,
+			},
+		}, false},
+		{"leveldb", config.DBConfig{DBBackend: config.DBBackendLevelDB}, false},
+		{"unknown backend", config.DBConfig{DBBackend: "rocksdb"}, true},
+		{"unknown compression", config.DBConfig{Badger: config.BadgerConfig{Compression: "lz4"}}, true},
+	}
+
+	for _, c := range cases {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+
+			kv, err := NewKVStore(t.TempDir(), "db", "test", c.conf)
+			if c.err {
+				assert.Error(err)
+				return
+			}
+			require.NoError(err)
+			ctx := context.Background()
+			key := ds.NewKey("/key")
+			require.NoError(kv.Put(ctx, key, []byte("value")))
+			value, err := kv.Get(ctx, key)
+			require.NoError(err)
+			assert.Equal([]byte("value"), value)
+			assert.NoError(kv.Close())
+		})
+	}
+}
+
+func TestMigrate(t *testing.T) {
+	t.Parallel()
+	assert := assert.New(t)
+	require := require.New(t)
+
+	ctx := context.Background()
+	rootDir := t.TempDir()
+	src, err := NewKVStore(rootDir, "db", "src", config.DBConfig{DBBackend: config.DBBackendBadger})
+	require.NoError(err)
+	dst, err := NewKVStore(rootDir, "db", "dst", config.DBConfig{DBBackend: config.DBBackendLevelDB})
+	require.NoError(err)
+
+	srcStore := New(ctx, src)
+	block := types.GetRandomBlock(1, 10)
+	block.SignedHeader.Validators = types.GetRandomValidatorSet()
+	block.SignedHeader.ValidatorHash = block.SignedHeader.Validators.Hash()
+	require.NoError(srcStore.SaveBlock(block, &types.Commit{}))
+	require.NoError(srcStore.UpdateState(types.State{LastBlockHeight: 1}))
+
+	// more entries than single batch
+	extra := 2*migrateBatchSize + 7
+	for i := 0; i < extra; i++ {
+		require.NoError(src.Put(ctx, ds.NewKey(fmt.Sprintf("/extra/%d", i)), []byte{byte(i)}))
+	}
+	all, err := PrefixEntries(ctx, src, "")
+	require.NoError(err)
+	entries, err := all.Rest()
+	require.NoError(err)
+
+	copied, err := Migrate(ctx, src, dst)
+	require.NoError(err)
+	assert.Equal(len(entries), copied)
+	assert.Greater(copied, extra)
+
+	for _, e := range entries {
+		value, err := dst.Get(ctx, ds.NewKey(e.Key))
+		require.NoError(err)
+		// backends differ in representation of empty values (nil or empty slice)
+		assert.True(bytes.Equal(e.Value, value), e.Key)
+	}
+
+	dstStore := New(ctx, dst)
+	migrated, err := dstStore.GetBlock(1)
+	require.NoError(err)
+	assert.Equal(block, migrated)
+	state, err := dstStore.GetState()
+	require.NoError(err)
+	assert.Equal(uint64(1), state.LastBlockHeight)
+
+	assert.NoError(src.Close())
+	assert.NoError(dst.Close())
+}
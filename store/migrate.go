@@ -0,0 +1,65 @@
+package store
+
+import (
+	"context"
+	"fmt"
+
+	ds "github.com/ipfs/go-datastore"
+	dsq "github.com/ipfs/go-datastore/query"
+)
+
+// migrateBatchSize is the number of entries written in single batch during migration.
+const migrateBatchSize = 1000
+
+// Migrate copies all entries from src to dst and returns the number of copied entries.
+//
+// Existing entries of dst with the same keys are overwritten, other entries are left untouched.
+// Writes are batched, if dst supports batching.
+func Migrate(ctx context.Context, src, dst ds.Datastore) (int, error) {
+	results, err := src.Query(ctx, dsq.Query{})
+	if err != nil {
+		return 0, fmt.Errorf("failed to query source datastore: %w", err)
+	}
+	defer results.Close() //nolint:errcheck
+
+	var (
+		writer  ds.Write = dst
+		batch   ds.Batch
+		pending int
+		copied  int
+	)
+	if batching, ok := dst.(ds.Batching); ok {
+		if batch, err = batching.Batch(ctx); err != nil {
+			return 0, fmt.Errorf("failed to create batch: %w", err)
+		}
+		writer = batch
+	}
+
+	for res := range results.Next() {
+		if res.Error != nil {
+			return copied, fmt.Errorf("failed to read source datastore: %w", res.Error)
+		}
+		if err := writer.Put(ctx, ds.NewKey(res.Key), res.Value); err != nil {
+			return copied, fmt.Errorf("failed to write %s: %w", res.Key, err)
+		}
+		pending++
+		if batch != nil && pending == migrateBatchSize {
+			if err := batch.Commit(ctx); err != nil {
+				return copied, fmt.Errorf("failed to commit batch: %w", err)
+			}
+			copied += pending
+			pending = 0
+			if batch, err = dst.(ds.Batching).Batch(ctx); err != nil {
+				return copied, fmt.Errorf("failed to create batch: %w", err)
+			}
+			writer = batch
+		}
+	}
+	if batch != nil {
+		if err := batch.Commit(ctx); err != nil {
+			return copied, fmt.Errorf("failed to commit batch: %w", err)
+		}
+	}
+	copied += pending
+	return copied, dst.Sync(ctx, ds.NewKey("/"))
+}
@@ -0,0 +1,71 @@
+package store
+
+import (
+	"context"
+
+	ds "github.com/ipfs/go-datastore"
+	ktds "github.com/ipfs/go-datastore/keytransform"
+)
+
+// prefixKV is a view of transactional datastore, with all keys prefixed. It's used to share single datastore
+// between many chains run in one process.
+type prefixKV struct {
+	*ktds.Datastore
+	base      ds.TxnDatastore
+	transform ktds.PrefixTransform
+}
+
+var _ ds.TxnDatastore = &prefixKV{}
+var _ ds.Batching = &prefixKV{}
+
+// NewPrefixKV returns a view of base datastore with all keys (including keys used in transactions) prefixed
+// with prefix. Closing the view doesn't close base datastore.
+func NewPrefixKV(base ds.TxnDatastore, prefix ds.Key) ds.TxnDatastore {
+	transform := ktds.PrefixTransform{Prefix: prefix}
+	return &prefixKV{
+		Datastore: ktds.Wrap(base, transform),
+		base:      base,
+		transform: transform,
+	}
+}
+
+// NewTransaction starts transaction in base datastore. Keys used in transaction are prefixed.
+func (p *prefixKV) NewTransaction(ctx context.Context, readOnly bool) (ds.Txn, error) {
+	txn, err := p.base.NewTransaction(ctx, readOnly)
+	if err != nil {
+		return nil, err
+	}
+	return &prefixTxn{Datastore: ktds.Wrap(txnDatastore{txn}, p.transform), txn: txn}, nil
+}
+
+// Close is no-op, base datastore is closed by its owner.
+func (p *prefixKV) Close() error {
+	return nil
+}
+
+// prefixTxn is a transaction with all keys prefixed.
+type prefixTxn struct {
+	*ktds.Datastore
+	txn ds.Txn
+}
+
+func (t *prefixTxn) Commit(ctx context.Context) error {
+	return t.txn.Commit(ctx)
+}
+
+func (t *prefixTxn) Discard(ctx context.Context) {
+	t.txn.Discard(ctx)
+}
+
+// txnDatastore adapts transaction to Datastore interface, so it can be wrapped with key transform.
+type txnDatastore struct {
+	ds.Txn
+}
+
+func (txnDatastore) Sync(context.Context, ds.Key) error {
+	return nil
+}
+
+func (txnDatastore) Close() error {
+	return nil
+}
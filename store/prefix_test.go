@@ -6,6 +6,7 @@ import (
 
 	ds "github.com/ipfs/go-datastore"
 	ktds "github.com/ipfs/go-datastore/keytransform"
+	dsq "github.com/ipfs/go-datastore/query"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -113,3 +114,60 @@ func TestPrefixKVBatch(t *testing.T) {
 	require.NoError(err)
 
 }
+
+func TestPrefixKVTransaction(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+	require := require.New(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	base, _ := NewDefaultInMemoryKVStore()
+	p1 := NewPrefixKV(base, ds.NewKey("chain1"))
+	p2 := NewPrefixKV(base, ds.NewKey("chain2"))
+
+	key := ds.NewKey("key")
+	txn1, err := p1.NewTransaction(ctx, false)
+	require.NoError(err)
+	require.NoError(txn1.Put(ctx, key, []byte("val1")))
+	txn2, err := p2.NewTransaction(ctx, false)
+	require.NoError(err)
+	require.NoError(txn2.Put(ctx, key, []byte("val2")))
+
+	// not visible before commit
+	_, err = p1.Get(ctx, key)
+	assert.ErrorIs(err, ds.ErrNotFound)
+
+	require.NoError(txn1.Commit(ctx))
+	require.NoError(txn2.Commit(ctx))
+
+	v, err := p1.Get(ctx, key)
+	require.NoError(err)
+	assert.Equal([]byte("val1"), v)
+	v, err = p2.Get(ctx, key)
+	require.NoError(err)
+	assert.Equal([]byte("val2"), v)
+	v, err = base.Get(ctx, ds.NewKey("chain1/key"))
+	require.NoError(err)
+	assert.Equal([]byte("val1"), v)
+
+	// keys read in transaction are also prefixed
+	txn, err := p2.NewTransaction(ctx, true)
+	require.NoError(err)
+	v, err = txn.Get(ctx, key)
+	require.NoError(err)
+	assert.Equal([]byte("val2"), v)
+	results, err := txn.Query(ctx, dsq.Query{})
+	require.NoError(err)
+	entries, err := results.Rest()
+	require.NoError(err)
+	require.Len(entries, 1)
+	assert.Equal(key.String(), entries[0].Key)
+	txn.Discard(ctx)
+
+	// closing view doesn't close base datastore
+	require.NoError(p1.Close())
+	_, err = base.Get(ctx, ds.NewKey("chain2/key"))
+	assert.NoError(err)
+}
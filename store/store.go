@@ -2,7 +2,9 @@ package store
 
 import (
 	"context"
+	"encoding/binary"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"sync/atomic"
 
@@ -25,6 +27,11 @@ var (
 	statePrefix      = "s"
 	responsesPrefix  = "r"
 	validatorsPrefix = "v"
+	daHeightPrefix   = "d"
+	daStartPrefix    = "g"
+	softStatePrefix  = "t"
+	finalizedPrefix  = "f"
+	softBasePrefix   = "p"
 )
 
 // DefaultStore is a default store implmementation.
@@ -169,6 +176,87 @@ func (s *DefaultStore) GetCommitByHash(hash types.Hash) (*types.Commit, error) {
 // UpdateState updates state saved in Store. Only one State is stored.
 // If there is no State in Store, state will be saved.
 func (s *DefaultStore) UpdateState(state types.State) error {
+	return s.putState(getStateKey(), state)
+}
+
+// GetState returns last state saved with UpdateState.
+func (s *DefaultStore) GetState() (types.State, error) {
+	state, err := s.getState(getStateKey())
+	if err != nil {
+		return types.State{}, err
+	}
+	atomic.StoreUint64(&s.height, uint64(state.LastBlockHeight))
+	return state, nil
+}
+
+// SaveSoftState saves state after committing block at given height. States below the height soft states were
+// pruned to (see PruneSoftStates) are not saved, as blocks below DA-final height are never rolled back.
+func (s *DefaultStore) SaveSoftState(height uint64, state types.State) error {
+	base, err := s.getUint64(getSoftBaseKey())
+	if errors.Is(err, ds.ErrNotFound) {
+		// no state was saved before, pruning starts from the first one
+		if err := s.setUint64(getSoftBaseKey(), height); err != nil {
+			return err
+		}
+	} else if err != nil {
+		return fmt.Errorf("failed to retrieve soft states base height: %w", err)
+	} else if height < base {
+		return nil
+	}
+	return s.putState(getSoftStateKey(height), state)
+}
+
+// GetSoftState returns state saved with SaveSoftState.
+func (s *DefaultStore) GetSoftState(height uint64) (types.State, error) {
+	return s.getState(getSoftStateKey(height))
+}
+
+// PruneSoftStates removes states saved with SaveSoftState below given height. States are removed by height, from
+// the height of the previous pruning, in single transaction.
+func (s *DefaultStore) PruneSoftStates(height uint64) error {
+	base, err := s.getUint64(getSoftBaseKey())
+	if errors.Is(err, ds.ErrNotFound) {
+		base = height
+	} else if err != nil {
+		return fmt.Errorf("failed to retrieve soft states base height: %w", err)
+	}
+	if height < base {
+		return nil
+	}
+
+	bb, err := s.db.NewTransaction(s.ctx, false)
+	if err != nil {
+		return fmt.Errorf("failed to create a new batch for transaction: %w", err)
+	}
+	for h := base; h < height; h++ {
+		err = multierr.Append(err, bb.Delete(s.ctx, ds.NewKey(getSoftStateKey(h))))
+	}
+	err = multierr.Append(err, bb.Put(s.ctx, ds.NewKey(getSoftBaseKey()), binary.BigEndian.AppendUint64(nil, height)))
+	if err != nil {
+		bb.Discard(s.ctx)
+		return err
+	}
+	if err = bb.Commit(s.ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// SetFinalizedHeight saves DA-final height.
+func (s *DefaultStore) SetFinalizedHeight(height uint64) error {
+	return s.setUint64(getFinalizedHeightKey(), height)
+}
+
+// GetFinalizedHeight returns height saved with SetFinalizedHeight.
+func (s *DefaultStore) GetFinalizedHeight() (uint64, error) {
+	height, err := s.getUint64(getFinalizedHeightKey())
+	if err != nil {
+		return 0, fmt.Errorf("failed to retrieve DA-final height: %w", err)
+	}
+	return height, nil
+}
+
+func (s *DefaultStore) putState(key string, state types.State) error {
 	pbState, err := state.ToProto()
 	if err != nil {
 		return fmt.Errorf("failed to marshal state to JSON: %w", err)
@@ -177,12 +265,11 @@ func (s *DefaultStore) UpdateState(state types.State) error {
 	if err != nil {
 		return err
 	}
-	return s.db.Put(s.ctx, ds.NewKey(getStateKey()), data)
+	return s.db.Put(s.ctx, ds.NewKey(key), data)
 }
 
-// GetState returns last state saved with UpdateState.
-func (s *DefaultStore) GetState() (types.State, error) {
-	blob, err := s.db.Get(s.ctx, ds.NewKey(getStateKey()))
+func (s *DefaultStore) getState(key string) (types.State, error) {
+	blob, err := s.db.Get(s.ctx, ds.NewKey(key))
 	if err != nil {
 		return types.State{}, fmt.Errorf("failed to retrieve state: %w", err)
 	}
@@ -194,10 +281,52 @@ func (s *DefaultStore) GetState() (types.State, error) {
 
 	var state types.State
 	err = state.FromProto(&pbState)
-	atomic.StoreUint64(&s.height, uint64(state.LastBlockHeight))
 	return state, err
 }
 
+// SetDAHeight saves the next DA height to retrieve blocks from.
+func (s *DefaultStore) SetDAHeight(daHeight uint64) error {
+	return s.setUint64(getDAHeightKey(), daHeight)
+}
+
+// GetDAHeight returns DA height saved with SetDAHeight.
+func (s *DefaultStore) GetDAHeight() (uint64, error) {
+	daHeight, err := s.getUint64(getDAHeightKey())
+	if err != nil {
+		return 0, fmt.Errorf("failed to retrieve DA height: %w", err)
+	}
+	return daHeight, nil
+}
+
+// SetDAStartHeight saves DA height of the first block of the chain.
+func (s *DefaultStore) SetDAStartHeight(daHeight uint64) error {
+	return s.setUint64(getDAStartHeightKey(), daHeight)
+}
+
+// GetDAStartHeight returns DA height saved with SetDAStartHeight.
+func (s *DefaultStore) GetDAStartHeight() (uint64, error) {
+	daHeight, err := s.getUint64(getDAStartHeightKey())
+	if err != nil {
+		return 0, fmt.Errorf("failed to retrieve DA start height: %w", err)
+	}
+	return daHeight, nil
+}
+
+func (s *DefaultStore) setUint64(key string, value uint64) error {
+	return s.db.Put(s.ctx, ds.NewKey(key), binary.BigEndian.AppendUint64(nil, value))
+}
+
+func (s *DefaultStore) getUint64(key string) (uint64, error) {
+	blob, err := s.db.Get(s.ctx, ds.NewKey(key))
+	if err != nil {
+		return 0, err
+	}
+	if len(blob) != 8 {
+		return 0, fmt.Errorf("invalid value length: %d", len(blob))
+	}
+	return binary.BigEndian.Uint64(blob), nil
+}
+
 // SaveValidators stores validator set for given block height in store.
 func (s *DefaultStore) SaveValidators(height uint64, validatorSet *cmtypes.ValidatorSet) error {
 	pbValSet, err := validatorSet.ToProto()
@@ -233,6 +362,43 @@ func (s *DefaultStore) GetValidators(height uint64) (*cmtypes.ValidatorSet, erro
 	return cmtypes.ValidatorSetFromProto(&pbValSet)
 }
 
+// Rollback removes blocks (with their commits, height index entries, block responses and validator sets) above
+// given height in single transaction, and sets store height to it.
+func (s *DefaultStore) Rollback(height uint64) error {
+	current := s.Height()
+	if height >= current {
+		return nil
+	}
+
+	bb, err := s.db.NewTransaction(s.ctx, false)
+	if err != nil {
+		return fmt.Errorf("failed to create a new batch for transaction: %w", err)
+	}
+	for h := height + 1; h <= current; h++ {
+		hash, err := s.loadHashFromIndex(h)
+		if err != nil {
+			bb.Discard(s.ctx)
+			return err
+		}
+		err = multierr.Append(err, bb.Delete(s.ctx, ds.NewKey(getBlockKey(hash))))
+		err = multierr.Append(err, bb.Delete(s.ctx, ds.NewKey(getCommitKey(hash))))
+		err = multierr.Append(err, bb.Delete(s.ctx, ds.NewKey(getIndexKey(h))))
+		err = multierr.Append(err, bb.Delete(s.ctx, ds.NewKey(getResponsesKey(h))))
+		err = multierr.Append(err, bb.Delete(s.ctx, ds.NewKey(getValidatorsKey(h))))
+		err = multierr.Append(err, bb.Delete(s.ctx, ds.NewKey(getSoftStateKey(h))))
+		if err != nil {
+			bb.Discard(s.ctx)
+			return err
+		}
+	}
+	if err = bb.Commit(s.ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	atomic.StoreUint64(&s.height, height)
+	return nil
+}
+
 // loadHashFromIndex returns the hash of a block given its height
 func (s *DefaultStore) loadHashFromIndex(height uint64) (header.Hash, error) {
 	blob, err := s.db.Get(s.ctx, ds.NewKey(getIndexKey(height)))
@@ -262,6 +428,26 @@ func getStateKey() string {
 	return statePrefix
 }
 
+func getSoftStateKey(height uint64) string {
+	return GenerateKey([]interface{}{softStatePrefix, height})
+}
+
+func getFinalizedHeightKey() string {
+	return finalizedPrefix
+}
+
+func getSoftBaseKey() string {
+	return softBasePrefix
+}
+
+func getDAHeightKey() string {
+	return daHeightPrefix
+}
+
+func getDAStartHeightKey() string {
+	return daStartPrefix
+}
+
 func getResponsesKey(height uint64) string {
 	return GenerateKey([]interface{}{responsesPrefix, height})
 }
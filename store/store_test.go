@@ -8,11 +8,13 @@ import (
 	abcitypes "github.com/cometbft/cometbft/abci/types"
 	cmstate "github.com/cometbft/cometbft/proto/tendermint/state"
 	cmproto "github.com/cometbft/cometbft/proto/tendermint/types"
+	cmtypes "github.com/cometbft/cometbft/types"
 	ds "github.com/ipfs/go-datastore"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/rollkit/rollkit/config"
 	"github.com/rollkit/rollkit/types"
 )
 
@@ -91,9 +93,10 @@ func TestStoreLoad(t *testing.T) {
 
 	mKV, _ := NewDefaultInMemoryKVStore()
 	dKV, _ := NewDefaultKVStore(tmpDir, "db", "test")
+	lKV, _ := NewKVStore(tmpDir, "db", "test-leveldb", config.DBConfig{DBBackend: config.DBBackendLevelDB})
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
-	for _, kv := range []ds.TxnDatastore{mKV, dKV} {
+	for _, kv := range []ds.TxnDatastore{mKV, dKV, lKV} {
 		for _, c := range cases {
 			t.Run(c.name, func(t *testing.T) {
 				assert := assert.New(t)
@@ -148,6 +151,33 @@ func TestRestart(t *testing.T) {
 	assert.Equal(expectedHeight, s2.Height())
 }
 
+func TestDAHeight(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+	require := require.New(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	kv, _ := NewDefaultInMemoryKVStore()
+	s1 := New(ctx, kv)
+	_, err := s1.GetDAHeight()
+	assert.Error(err)
+
+	_, err = s1.GetDAStartHeight()
+	assert.Error(err)
+
+	require.NoError(s1.SetDAHeight(1234))
+	require.NoError(s1.SetDAStartHeight(1000))
+	s2 := New(ctx, kv)
+	daHeight, err := s2.GetDAHeight()
+	require.NoError(err)
+	assert.Equal(uint64(1234), daHeight)
+	daStartHeight, err := s2.GetDAStartHeight()
+	require.NoError(err)
+	assert.Equal(uint64(1000), daStartHeight)
+}
+
 func TestBlockResponses(t *testing.T) {
 	t.Parallel()
 	assert := assert.New(t)
@@ -192,3 +222,109 @@ func TestBlockResponses(t *testing.T) {
 	assert.NotNil(resp)
 	assert.Equal(expected, resp)
 }
+
+func TestRollback(t *testing.T) {
+	t.Parallel()
+	assert := assert.New(t)
+	require := require.New(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	kv, _ := NewDefaultInMemoryKVStore()
+	s := New(ctx, kv)
+
+	blocks := make([]*types.Block, 5)
+	for i := range blocks {
+		h := uint64(i + 1)
+		blocks[i] = getTestBlock(h, 1)
+		require.NoError(s.SaveBlock(blocks[i], &types.Commit{}))
+		require.NoError(s.SaveBlockResponses(h, &cmstate.ABCIResponses{}))
+		require.NoError(s.SaveValidators(h, &cmtypes.ValidatorSet{}))
+		require.NoError(s.SaveSoftState(h, types.State{LastBlockHeight: h}))
+		s.SetHeight(h)
+	}
+
+	require.NoError(s.Rollback(3))
+	assert.Equal(uint64(3), s.Height())
+	for h := uint64(1); h <= 3; h++ {
+		_, err := s.GetBlock(h)
+		assert.NoError(err)
+	}
+	for h := uint64(4); h <= 5; h++ {
+		_, err := s.GetBlock(h)
+		assert.Error(err)
+		_, err = s.GetBlockByHash(blocks[h-1].Hash())
+		assert.Error(err)
+		_, err = s.GetCommitByHash(blocks[h-1].Hash())
+		assert.Error(err)
+		_, err = s.GetBlockResponses(h)
+		assert.Error(err)
+		_, err = s.GetValidators(h)
+		assert.Error(err)
+		_, err = s.GetSoftState(h)
+		assert.Error(err)
+	}
+	state, err := s.GetSoftState(3)
+	require.NoError(err)
+	assert.Equal(uint64(3), state.LastBlockHeight)
+
+	// rolling back to current or higher height is no-op
+	require.NoError(s.Rollback(3))
+	require.NoError(s.Rollback(10))
+	assert.Equal(uint64(3), s.Height())
+
+	// new blocks can be saved at rolled back heights
+	block := getTestBlock(4, 2)
+	require.NoError(s.SaveBlock(block, &types.Commit{}))
+	s.SetHeight(4)
+	loaded, err := s.GetBlock(4)
+	require.NoError(err)
+	assert.Equal(block.Hash(), loaded.Hash())
+}
+
+func TestSoftStates(t *testing.T) {
+	t.Parallel()
+	assert := assert.New(t)
+	require := require.New(t)
+
+	kv, _ := NewDefaultInMemoryKVStore()
+	s := New(context.Background(), kv)
+
+	_, err := s.GetFinalizedHeight()
+	assert.ErrorIs(err, ds.ErrNotFound)
+	require.NoError(s.SetFinalizedHeight(3))
+	finalized, err := s.GetFinalizedHeight()
+	require.NoError(err)
+	assert.Equal(uint64(3), finalized)
+
+	for h := uint64(1); h <= 12; h++ {
+		require.NoError(s.SaveSoftState(h, types.State{ChainID: "test", LastBlockHeight: h}))
+	}
+	// soft states don't replace the last state
+	_, err = s.GetState()
+	assert.Error(err)
+
+	require.NoError(s.PruneSoftStates(10))
+	for h := uint64(1); h <= 12; h++ {
+		state, err := s.GetSoftState(h)
+		if h < 10 {
+			assert.Error(err)
+			continue
+		}
+		require.NoError(err)
+		assert.Equal(types.State{ChainID: "test", LastBlockHeight: h}, state)
+	}
+
+	// states below pruned height are not saved
+	require.NoError(s.SaveSoftState(5, types.State{ChainID: "test", LastBlockHeight: 5}))
+	_, err = s.GetSoftState(5)
+	assert.Error(err)
+
+	// pruning continues from the previous height, pruning to lower height is no-op
+	require.NoError(s.PruneSoftStates(12))
+	require.NoError(s.PruneSoftStates(3))
+	for h := uint64(10); h <= 12; h++ {
+		_, err := s.GetSoftState(h)
+		assert.Equal(h < 12, err != nil)
+	}
+}
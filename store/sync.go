@@ -0,0 +1,76 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	ds "github.com/ipfs/go-datastore"
+	"github.com/ipfs/go-datastore/namespace"
+
+	"github.com/celestiaorg/go-header"
+	goheaderstore "github.com/celestiaorg/go-header/store"
+)
+
+// RollbackSyncStore removes blocks (or headers) above given height from go-header store kept in kv under given prefix
+// (see HeaderSyncPrefix and BlockSyncPrefix), and makes the one at given height the head of the store. If there is
+// nothing at given height (store was initialized above it), the store is emptied. Store with given prefix can't be
+// used while it's rolled back, and has to be opened again afterwards, as go-header store caches its head.
+//
+// go-header store doesn't support removing headers, so keys are removed directly. It relies on the layout of
+// go-header v0.4.1 store: hash of the head under "head", hashes indexed by height, and headers keyed by hash.
+func RollbackSyncStore[H header.Header[H]](ctx context.Context, kv ds.Batching, prefix string, height uint64) error {
+	ss, err := goheaderstore.NewStore[H](kv, goheaderstore.WithStorePrefix(prefix))
+	if err != nil {
+		return fmt.Errorf("failed to open %s store: %w", prefix, err)
+	}
+	head, err := ss.Head(ctx)
+	if errors.Is(err, header.ErrNoHead) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to load head of %s store: %w", prefix, err)
+	}
+	if head.Height() <= height {
+		return nil
+	}
+
+	nkv := namespace.Wrap(kv, ds.NewKey(prefix))
+	batch, err := nkv.Batch(ctx)
+	if err != nil {
+		return err
+	}
+	for h := height + 1; h <= head.Height(); h++ {
+		heightKey := ds.NewKey(strconv.FormatUint(h, 10))
+		hash, err := nkv.Get(ctx, heightKey)
+		if errors.Is(err, ds.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to load hash at height %d from %s store: %w", h, prefix, err)
+		}
+		if err := batch.Delete(ctx, heightKey); err != nil {
+			return err
+		}
+		if err := batch.Delete(ctx, ds.NewKey(header.Hash(hash).String())); err != nil {
+			return err
+		}
+	}
+
+	headKey := ds.NewKey("head")
+	hash, err := nkv.Get(ctx, ds.NewKey(strconv.FormatUint(height, 10)))
+	switch {
+	case errors.Is(err, ds.ErrNotFound):
+		err = batch.Delete(ctx, headKey)
+	case err == nil:
+		var headHash []byte
+		if headHash, err = header.Hash(hash).MarshalJSON(); err == nil {
+			err = batch.Put(ctx, headKey, headHash)
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update head of %s store: %w", prefix, err)
+	}
+	return batch.Commit(ctx)
+}
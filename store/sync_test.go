@@ -0,0 +1,71 @@
+package store
+
+import (
+	"context"
+	"testing"
+
+	ds "github.com/ipfs/go-datastore"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/celestiaorg/go-header"
+	"github.com/celestiaorg/go-header/headertest"
+	goheaderstore "github.com/celestiaorg/go-header/store"
+)
+
+func TestRollbackSyncStore(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	ctx := context.Background()
+
+	kv, err := NewDefaultInMemoryKVStore()
+	require.NoError(err)
+	batching := kv.(ds.Batching)
+	open := func() *goheaderstore.Store[*headertest.DummyHeader] {
+		ss, err := goheaderstore.NewStore[*headertest.DummyHeader](batching, goheaderstore.WithStorePrefix(BlockSyncPrefix))
+		require.NoError(err)
+		require.NoError(ss.Start(ctx))
+		return ss
+	}
+
+	// empty store
+	require.NoError(RollbackSyncStore[*headertest.DummyHeader](ctx, batching, BlockSyncPrefix, 0))
+
+	blocks := headertest.NewTestSuite(t).GenDummyHeaders(5)
+	ss := open()
+	require.NoError(ss.Init(ctx, blocks[0]))
+	require.NoError(ss.Append(ctx, blocks[1:]...))
+	require.NoError(ss.Stop(ctx))
+
+	require.NoError(RollbackSyncStore[*headertest.DummyHeader](ctx, batching, BlockSyncPrefix, 3))
+	ss = open()
+	head, err := ss.Head(ctx)
+	require.NoError(err)
+	assert.Equal(blocks[2].Hash(), head.Hash())
+	assert.Equal(uint64(3), ss.Height())
+	has, err := ss.Has(ctx, blocks[3].Hash())
+	require.NoError(err)
+	assert.False(has)
+
+	// store can be extended from the new head
+	require.NoError(ss.Append(ctx, blocks[3]))
+	require.NoError(ss.Stop(ctx))
+	require.NoError(RollbackSyncStore[*headertest.DummyHeader](ctx, batching, BlockSyncPrefix, 3))
+
+	// rolling back to current or higher height is no-op
+	require.NoError(RollbackSyncStore[*headertest.DummyHeader](ctx, batching, BlockSyncPrefix, 3))
+	require.NoError(RollbackSyncStore[*headertest.DummyHeader](ctx, batching, BlockSyncPrefix, 10))
+	ss = open()
+	head, err = ss.Head(ctx)
+	require.NoError(err)
+	assert.Equal(uint64(3), head.Height())
+	require.NoError(ss.Stop(ctx))
+
+	// nothing is kept below the first block of the store
+	require.NoError(RollbackSyncStore[*headertest.DummyHeader](ctx, batching, BlockSyncPrefix, 0))
+	ss = open()
+	_, err = ss.Head(ctx)
+	assert.ErrorIs(err, header.ErrNoHead)
+	require.NoError(ss.Init(ctx, blocks[0]))
+	require.NoError(ss.Stop(ctx))
+}
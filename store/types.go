@@ -43,4 +43,34 @@ type Store interface {
 	SaveValidators(height uint64, validatorSet *cmtypes.ValidatorSet) error
 
 	GetValidators(height uint64) (*cmtypes.ValidatorSet, error)
+
+	// SetDAHeight saves the next DA height to retrieve blocks from.
+	SetDAHeight(daHeight uint64) error
+	// GetDAHeight returns DA height saved with SetDAHeight, or error if it's not found in Store.
+	GetDAHeight() (uint64, error)
+
+	// SetDAStartHeight saves DA height of the first block of the chain.
+	SetDAStartHeight(daHeight uint64) error
+	// GetDAStartHeight returns DA height saved with SetDAStartHeight, or error if it's not found in Store.
+	GetDAStartHeight() (uint64, error)
+
+	// SaveSoftState saves state after committing block at given height, so that blocks above it can be rolled back.
+	// Saved states are removed by Rollback (above its height) and PruneSoftStates. States below the height of
+	// the last pruning are not saved.
+	SaveSoftState(height uint64, state types.State) error
+	// GetSoftState returns state saved with SaveSoftState, or error if it's not found in Store.
+	GetSoftState(height uint64) (types.State, error)
+	// PruneSoftStates removes states saved with SaveSoftState below given height, down to the height of the
+	// previous pruning.
+	PruneSoftStates(height uint64) error
+
+	// SetFinalizedHeight saves DA-final height: the height of the last block, such that all blocks up to it are
+	// included in DA layer.
+	SetFinalizedHeight(height uint64) error
+	// GetFinalizedHeight returns height saved with SetFinalizedHeight, or error if it's not found in Store.
+	GetFinalizedHeight() (uint64, error)
+
+	// Rollback removes all blocks above given height, with their commits, block responses, validator sets and
+	// soft states, and sets store height to it. State has to be updated separately.
+	Rollback(height uint64) error
 }
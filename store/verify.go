@@ -0,0 +1,426 @@
+package store
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+
+	ds "github.com/ipfs/go-datastore"
+	dsq "github.com/ipfs/go-datastore/query"
+
+	"github.com/celestiaorg/go-header"
+	goheaderstore "github.com/celestiaorg/go-header/store"
+
+	"github.com/rollkit/rollkit/types"
+)
+
+// ProblemKind identifies the type of inconsistency found by Verify.
+type ProblemKind string
+
+// Kinds of problems reported by Verify.
+const (
+	// ProblemInvalidState means that state can't be loaded.
+	ProblemInvalidState ProblemKind = "invalid_state"
+	// ProblemStateHeight means that state height differs from the height of the highest block.
+	ProblemStateHeight ProblemKind = "state_height"
+	// ProblemStateBlock means that last block ID or chain ID in state doesn't match the block at state height.
+	ProblemStateBlock ProblemKind = "state_block"
+	// ProblemCorruptBlock means that block can't be decoded, or it's stored under a hash of different block.
+	ProblemCorruptBlock ProblemKind = "corrupt_block"
+	// ProblemMissingBlock means that there is no block at given height, or index points to missing block.
+	ProblemMissingBlock ProblemKind = "missing_block"
+	// ProblemMissingIndex means that block is stored, but it's not indexed by height. It can be repaired.
+	ProblemMissingIndex ProblemKind = "missing_index"
+	// ProblemInvalidIndex means that index entry is malformed, or it points to block with different height.
+	// It can be repaired if block at given height is stored.
+	ProblemInvalidIndex ProblemKind = "invalid_index"
+	// ProblemMissingCommit means that block is stored without its commit.
+	ProblemMissingCommit ProblemKind = "missing_commit"
+	// ProblemOrphanCommit means that commit is stored without its block.
+	ProblemOrphanCommit ProblemKind = "orphan_commit"
+	// ProblemInvalidBlock means that block fails basic validation (including signature verification).
+	ProblemInvalidBlock ProblemKind = "invalid_block"
+	// ProblemBrokenLinkage means that LastHeaderHash of block doesn't match hash of the previous block.
+	ProblemBrokenLinkage ProblemKind = "broken_linkage"
+	// ProblemMissingResponses means that responses of applied block are not stored.
+	ProblemMissingResponses ProblemKind = "missing_responses"
+	// ProblemMissingValidators means that validator set of applied block is not stored.
+	ProblemMissingValidators ProblemKind = "missing_validators"
+	// ProblemSyncStoreMismatch means that header or block sync store contains different block than the main store.
+	ProblemSyncStoreMismatch ProblemKind = "sync_store_mismatch"
+)
+
+// Prefixes of header and block sync stores, within the main node datastore.
+const (
+	HeaderSyncPrefix = "headerSync"
+	BlockSyncPrefix  = "blockSync"
+)
+
+// Problem describes single inconsistency found in the store.
+type Problem struct {
+	Kind ProblemKind `json:"kind"`
+	// Height of the affected block, 0 if height is unknown.
+	Height  uint64 `json:"height,omitempty"`
+	Message string `json:"message"`
+	// Repaired is true if problem was fixed by Verify running in repair mode.
+	Repaired bool `json:"repaired,omitempty"`
+}
+
+// VerifyOptions configures Verify.
+type VerifyOptions struct {
+	// Repair enables fixing of index-only problems: missing and invalid height index entries of stored blocks.
+	Repair bool
+}
+
+// VerifyReport is the result of Verify.
+type VerifyReport struct {
+	StateHeight   uint64    `json:"state_height"`
+	HighestBlock  uint64    `json:"highest_block"`
+	CheckedBlocks int       `json:"checked_blocks"`
+	Problems      []Problem `json:"problems"`
+}
+
+// OK returns true if no problems were found, or all of them were repaired.
+func (r *VerifyReport) OK() bool {
+	for _, p := range r.Problems {
+		if !p.Repaired {
+			return false
+		}
+	}
+	return true
+}
+
+// verifier holds the state of single Verify run.
+type verifier struct {
+	ctx    context.Context
+	kv     ds.TxnDatastore
+	opts   VerifyOptions
+	report *VerifyReport
+
+	// heights maps hashes of correctly stored blocks to their heights; blocks are loaded when their height is checked
+	heights  map[string]uint64
+	byHeight map[uint64][]string
+	resolved map[uint64]types.Hash
+}
+
+// Verify checks consistency of the store kept in kv (main node datastore).
+//
+// All heights from initial height to the highest known height are checked: index entries, presence and hashes
+// of blocks and commits, linkage of consecutive blocks, block signatures, presence of block responses and
+// validator sets (if validator sets are stored at all). Blocks are checked height by height, so only hashes and
+// heights of all blocks are kept in memory. State height is compared with the highest block, and
+// header and block sync stores are compared with the main store. Block at state height has to match the last
+// block ID and chain ID in state.
+func Verify(ctx context.Context, kv ds.TxnDatastore, opts VerifyOptions) (*VerifyReport, error) {
+	v := &verifier{
+		ctx:      ctx,
+		kv:       kv,
+		opts:     opts,
+		report:   &VerifyReport{},
+		heights:  make(map[string]uint64),
+		byHeight: make(map[uint64][]string),
+		resolved: make(map[uint64]types.Hash),
+	}
+
+	s := New(ctx, kv)
+	initialHeight := uint64(1)
+	state, err := s.GetState()
+	hasState := err == nil
+	switch {
+	case errors.Is(err, ds.ErrNotFound):
+	case err != nil:
+		v.problem(ProblemInvalidState, 0, err.Error())
+	default:
+		v.report.StateHeight = state.LastBlockHeight
+		if state.InitialHeight > 0 {
+			initialHeight = state.InitialHeight
+		}
+	}
+
+	if err := v.scanBlocks(); err != nil {
+		return nil, err
+	}
+	if err := v.scanCommits(); err != nil {
+		return nil, err
+	}
+	maxIndexed, err := v.maxIndexedHeight()
+	if err != nil {
+		return nil, err
+	}
+	maxHeight := v.report.StateHeight
+	for h := range v.byHeight {
+		if h > maxHeight {
+			maxHeight = h
+		}
+		if h > v.report.HighestBlock {
+			v.report.HighestBlock = h
+		}
+	}
+	if maxIndexed > maxHeight {
+		maxHeight = maxIndexed
+	}
+
+	hasValidators, err := v.hasPrefix(validatorsPrefix)
+	if err != nil {
+		return nil, err
+	}
+	var prev *types.Block
+	for height := initialHeight; height <= maxHeight; height++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		block, err := v.checkIndex(height)
+		if err != nil {
+			return nil, err
+		}
+		if block != nil {
+			v.report.CheckedBlocks++
+			v.checkBlock(height, block, prev)
+		}
+		if height <= v.report.StateHeight {
+			v.checkApplied(s, height, hasValidators)
+		}
+		if hasState && height == v.report.StateHeight && block != nil {
+			v.checkState(state, block)
+		}
+		prev = block
+	}
+
+	if v.report.StateHeight != v.report.HighestBlock {
+		v.problem(ProblemStateHeight, v.report.StateHeight, fmt.Sprintf("state height %d differs from height of the highest block %d", v.report.StateHeight, v.report.HighestBlock))
+	}
+
+	if err := verifySyncStore[*types.SignedHeader](ctx, v, HeaderSyncPrefix); err != nil {
+		return nil, err
+	}
+	if err := verifySyncStore[*types.Block](ctx, v, BlockSyncPrefix); err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(v.report.Problems, func(i, j int) bool {
+		return v.report.Problems[i].Height < v.report.Problems[j].Height
+	})
+	return v.report, nil
+}
+
+func (v *verifier) problem(kind ProblemKind, height uint64, msg string) *Problem {
+	v.report.Problems = append(v.report.Problems, Problem{Kind: kind, Height: height, Message: msg})
+	return &v.report.Problems[len(v.report.Problems)-1]
+}
+
+// scanBlocks decodes all stored blocks one by one, and groups their hashes by height.
+func (v *verifier) scanBlocks() error {
+	return v.forEach(blockPrefix, false, func(e dsq.Entry) {
+		keyHash := path.Base(e.Key)
+		block := new(types.Block)
+		if err := block.UnmarshalBinary(e.Value); err != nil {
+			v.problem(ProblemCorruptBlock, 0, fmt.Sprintf("failed to decode block %s: %s", keyHash, err))
+			return
+		}
+		if hash := hex.EncodeToString(block.Hash()); hash != keyHash {
+			v.problem(ProblemCorruptBlock, block.Height(), fmt.Sprintf("block with hash %s is stored as %s", hash, keyHash))
+			return
+		}
+		v.heights[keyHash] = block.Height()
+		v.byHeight[block.Height()] = append(v.byHeight[block.Height()], keyHash)
+	})
+}
+
+// scanCommits finds commits stored without blocks.
+func (v *verifier) scanCommits() error {
+	return v.forEach(commitPrefix, true, func(e dsq.Entry) {
+		hash := path.Base(e.Key)
+		if _, ok := v.heights[hash]; !ok {
+			v.problem(ProblemOrphanCommit, 0, fmt.Sprintf("commit %s is stored without block", hash))
+		}
+	})
+}
+
+func (v *verifier) maxIndexedHeight() (uint64, error) {
+	var highest uint64
+	err := v.forEach(indexPrefix, true, func(e dsq.Entry) {
+		var height uint64
+		if _, err := fmt.Sscanf(path.Base(e.Key), "%d", &height); err == nil && height > highest {
+			highest = height
+		}
+	})
+	return highest, err
+}
+
+func (v *verifier) hasPrefix(prefix string) (bool, error) {
+	results, err := v.kv.Query(v.ctx, dsq.Query{Prefix: GenerateKey([]interface{}{prefix}), KeysOnly: true, Limit: 1})
+	if err != nil {
+		return false, err
+	}
+	entries, err := results.Rest()
+	return len(entries) > 0, err
+}
+
+// forEach calls f for every entry with given prefix. Entries are read one by one, not all at once.
+func (v *verifier) forEach(prefix string, keysOnly bool, f func(dsq.Entry)) error {
+	results, err := v.kv.Query(v.ctx, dsq.Query{Prefix: GenerateKey([]interface{}{prefix}), KeysOnly: keysOnly})
+	if err != nil {
+		return fmt.Errorf("failed to query datastore: %w", err)
+	}
+	defer results.Close() //nolint:errcheck
+	for res := range results.Next() {
+		if res.Error != nil {
+			return fmt.Errorf("failed to query datastore: %w", res.Error)
+		}
+		f(res.Entry)
+	}
+	return nil
+}
+
+// loadBlock loads block with given hash, found by scanBlocks.
+func (v *verifier) loadBlock(hash string) (*types.Block, error) {
+	key, _ := hex.DecodeString(hash)
+	value, err := v.kv.Get(v.ctx, ds.NewKey(getBlockKey(key)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load block %s: %w", hash, err)
+	}
+	block := new(types.Block)
+	if err := block.UnmarshalBinary(value); err != nil {
+		return nil, fmt.Errorf("failed to decode block %s: %w", hash, err)
+	}
+	return block, nil
+}
+
+// checkIndex verifies height index entry, and returns block at given height (nil, if it's not available).
+func (v *verifier) checkIndex(height uint64) (*types.Block, error) {
+	candidates := v.byHeight[height]
+	key := ds.NewKey(getIndexKey(height))
+	value, err := v.kv.Get(v.ctx, key)
+	if err != nil && !errors.Is(err, ds.ErrNotFound) {
+		return nil, fmt.Errorf("failed to read index at height %d: %w", height, err)
+	}
+
+	var (
+		kind ProblemKind
+		msg  string
+	)
+	switch {
+	case errors.Is(err, ds.ErrNotFound) && len(candidates) == 0:
+		v.problem(ProblemMissingBlock, height, "no block at height")
+		return nil, nil
+	case errors.Is(err, ds.ErrNotFound):
+		kind, msg = ProblemMissingIndex, "block is not indexed by height"
+	case len(value) != 32:
+		kind, msg = ProblemInvalidIndex, fmt.Sprintf("invalid hash length %d in index", len(value))
+	default:
+		indexed := hex.EncodeToString(value)
+		blockHeight, ok := v.heights[indexed]
+		if ok && blockHeight == height {
+			v.resolved[height] = value
+			return v.loadBlock(indexed)
+		}
+		if ok {
+			kind, msg = ProblemInvalidIndex, fmt.Sprintf("index points to block at height %d", blockHeight)
+		} else if len(candidates) == 0 {
+			v.problem(ProblemMissingBlock, height, fmt.Sprintf("index points to missing block %X", value))
+			return nil, nil
+		} else {
+			kind, msg = ProblemInvalidIndex, fmt.Sprintf("index points to missing block %X", value)
+		}
+	}
+
+	p := v.problem(kind, height, msg)
+	if len(candidates) != 1 {
+		if len(candidates) > 1 {
+			p.Message += fmt.Sprintf(" (%d blocks stored at height, can't repair)", len(candidates))
+		}
+		return nil, nil
+	}
+	hash, _ := hex.DecodeString(candidates[0])
+	if v.opts.Repair {
+		if err := v.kv.Put(v.ctx, key, hash); err != nil {
+			return nil, fmt.Errorf("failed to repair index at height %d: %w", height, err)
+		}
+		p.Repaired = true
+	}
+	v.resolved[height] = hash
+	return v.loadBlock(candidates[0])
+}
+
+// checkBlock verifies block at given height: commit presence, validity (signature) and linkage to previous block.
+func (v *verifier) checkBlock(height uint64, block *types.Block, prev *types.Block) {
+	hash := v.resolved[height]
+	has, err := v.kv.Has(v.ctx, ds.NewKey(getCommitKey(hash)))
+	if err != nil || !has {
+		v.problem(ProblemMissingCommit, height, fmt.Sprintf("commit of block %X is not stored", hash))
+	}
+	if err := block.ValidateBasic(); err != nil {
+		v.problem(ProblemInvalidBlock, height, err.Error())
+	}
+	if prev != nil && !bytes.Equal(block.SignedHeader.LastHeaderHash[:], prev.Hash()) {
+		v.problem(ProblemBrokenLinkage, height, fmt.Sprintf("last header hash %X doesn't match hash of previous block %X", block.SignedHeader.LastHeaderHash[:], prev.Hash()))
+	}
+}
+
+// checkState verifies that state references given block (stored at state height).
+func (v *verifier) checkState(state types.State, block *types.Block) {
+	height := block.Height()
+	if block.SignedHeader.ChainID() != state.ChainID {
+		v.problem(ProblemStateBlock, height, fmt.Sprintf("chain ID of block (%s) doesn't match state (%s)", block.SignedHeader.ChainID(), state.ChainID))
+	}
+	if hash := v.resolved[height]; !bytes.Equal(hash, state.LastBlockID.Hash) {
+		v.problem(ProblemStateBlock, height, fmt.Sprintf("hash of block %X doesn't match last block ID in state %X", hash, state.LastBlockID.Hash))
+	}
+}
+
+// checkApplied verifies data saved when block is applied.
+func (v *verifier) checkApplied(s Store, height uint64, hasValidators bool) {
+	if _, err := s.GetBlockResponses(height); err != nil {
+		v.problem(ProblemMissingResponses, height, err.Error())
+	}
+	if hasValidators {
+		if _, err := s.GetValidators(height); err != nil {
+			v.problem(ProblemMissingValidators, height, err.Error())
+		}
+	}
+}
+
+// verifySyncStore compares blocks (or headers) kept in go-header store with given prefix with the main store.
+func verifySyncStore[H header.Header[H]](ctx context.Context, v *verifier, prefix string) error {
+	batching, ok := v.kv.(ds.Batching)
+	if !ok {
+		return nil
+	}
+	ss, err := goheaderstore.NewStore[H](batching, goheaderstore.WithStorePrefix(prefix))
+	if err != nil {
+		return fmt.Errorf("failed to open %s store: %w", prefix, err)
+	}
+	head, err := ss.Head(ctx)
+	if errors.Is(err, header.ErrNoHead) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to load head of %s store: %w", prefix, err)
+	}
+	last := head.Height()
+	if last > v.report.HighestBlock {
+		last = v.report.HighestBlock
+	}
+	for height := uint64(1); height <= last; height++ {
+		expected, ok := v.resolved[height]
+		if !ok {
+			continue
+		}
+		h, err := ss.GetByHeight(ctx, height)
+		if errors.Is(err, header.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			v.problem(ProblemSyncStoreMismatch, height, fmt.Sprintf("failed to load from %s store: %s", prefix, err))
+			continue
+		}
+		if !bytes.Equal(h.Hash(), expected) {
+			v.problem(ProblemSyncStoreMismatch, height, fmt.Sprintf("%s store contains %X, main store contains %X", prefix, h.Hash(), expected))
+		}
+	}
+	return nil
+}
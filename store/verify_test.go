@@ -0,0 +1,177 @@
+package store
+
+import (
+	"context"
+	"testing"
+
+	cmbytes "github.com/cometbft/cometbft/libs/bytes"
+	cmstate "github.com/cometbft/cometbft/proto/tendermint/state"
+	cmtypes "github.com/cometbft/cometbft/types"
+	ds "github.com/ipfs/go-datastore"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	goheaderstore "github.com/celestiaorg/go-header/store"
+
+	"github.com/rollkit/rollkit/types"
+)
+
+const verifyTestHeight = 5
+
+// getTestChain returns n valid, signed and linked blocks, starting at height 1.
+func getTestChain(t *testing.T, n int) []*types.Block {
+	valSet, privKey := types.GetRandomValidatorSetWithPrivKey()
+	blocks := make([]*types.Block, 0, n)
+	for h := 1; h <= n; h++ {
+		block := types.GetRandomBlock(uint64(h), 2)
+		block.SignedHeader.Validators = valSet
+		block.SignedHeader.ValidatorHash = valSet.Hash()
+		block.SignedHeader.ProposerAddress = valSet.Proposer.Address
+		if h > 1 {
+			block.SignedHeader.LastHeaderHash = blocks[h-2].Hash()
+		}
+		dataHash, err := block.Data.Hash()
+		require.NoError(t, err)
+		block.SignedHeader.DataHash = dataHash
+		signature, err := privKey.Sign(block.SignedHeader.Header.MakeCometBFTVote())
+		require.NoError(t, err)
+		block.SignedHeader.Commit = types.Commit{Signatures: []types.Signature{signature}}
+		blocks = append(blocks, block)
+	}
+	return blocks
+}
+
+// newVerifyTestStore saves blocks with commits, responses and state, like the block manager does.
+func newVerifyTestStore(t *testing.T) (ds.TxnDatastore, Store, []*types.Block) {
+	kv, err := NewDefaultInMemoryKVStore()
+	require.NoError(t, err)
+	s := New(context.Background(), kv)
+	blocks := getTestChain(t, verifyTestHeight)
+	for _, block := range blocks {
+		require.NoError(t, s.SaveBlock(block, &block.SignedHeader.Commit))
+		require.NoError(t, s.SaveBlockResponses(block.Height(), &cmstate.ABCIResponses{}))
+	}
+	last := blocks[len(blocks)-1]
+	require.NoError(t, s.UpdateState(types.State{
+		ChainID:         last.SignedHeader.ChainID(),
+		InitialHeight:   1,
+		LastBlockHeight: last.Height(),
+		LastBlockID:     cmtypes.BlockID{Hash: cmbytes.HexBytes(last.Hash())},
+	}))
+	return kv, s, blocks
+}
+
+func problemKinds(report *VerifyReport) []ProblemKind {
+	var kinds []ProblemKind
+	for _, p := range report.Problems {
+		kinds = append(kinds, p.Kind)
+	}
+	return kinds
+}
+
+func TestVerifyConsistentStore(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	kv, _, _ := newVerifyTestStore(t)
+	report, err := Verify(context.Background(), kv, VerifyOptions{})
+	require.NoError(err)
+	assert.True(report.OK())
+	assert.Empty(report.Problems)
+	assert.Equal(verifyTestHeight, report.CheckedBlocks)
+	assert.Equal(uint64(verifyTestHeight), report.StateHeight)
+	assert.Equal(uint64(verifyTestHeight), report.HighestBlock)
+}
+
+func TestVerifyProblems(t *testing.T) {
+	ctx := context.Background()
+	cases := []struct {
+		name     string
+		corrupt  func(t *testing.T, kv ds.TxnDatastore, s Store, blocks []*types.Block)
+		expected []ProblemKind
+	}{
+		{"missing block", func(t *testing.T, kv ds.TxnDatastore, s Store, blocks []*types.Block) {
+			require.NoError(t, kv.Delete(ctx, ds.NewKey(getBlockKey(blocks[2].Hash()))))
+		}, []ProblemKind{ProblemOrphanCommit, ProblemMissingBlock}},
+		{"missing commit", func(t *testing.T, kv ds.TxnDatastore, s Store, blocks []*types.Block) {
+			require.NoError(t, kv.Delete(ctx, ds.NewKey(getCommitKey(blocks[1].Hash()))))
+		}, []ProblemKind{ProblemMissingCommit}},
+		{"missing responses", func(t *testing.T, kv ds.TxnDatastore, s Store, blocks []*types.Block) {
+			require.NoError(t, kv.Delete(ctx, ds.NewKey(getResponsesKey(4))))
+		}, []ProblemKind{ProblemMissingResponses}},
+		{"state behind blocks", func(t *testing.T, kv ds.TxnDatastore, s Store, blocks []*types.Block) {
+			block := blocks[verifyTestHeight-2]
+			require.NoError(t, s.UpdateState(types.State{
+				ChainID:         block.SignedHeader.ChainID(),
+				InitialHeight:   1,
+				LastBlockHeight: block.Height(),
+				LastBlockID:     cmtypes.BlockID{Hash: cmbytes.HexBytes(block.Hash())},
+			}))
+		}, []ProblemKind{ProblemStateHeight}},
+		{"state references other block", func(t *testing.T, kv ds.TxnDatastore, s Store, blocks []*types.Block) {
+			require.NoError(t, s.UpdateState(types.State{
+				ChainID:         "other",
+				InitialHeight:   1,
+				LastBlockHeight: verifyTestHeight,
+				LastBlockID:     cmtypes.BlockID{Hash: cmbytes.HexBytes(blocks[0].Hash())},
+			}))
+		}, []ProblemKind{ProblemStateBlock, ProblemStateBlock}},
+		{"invalid signature", func(t *testing.T, kv ds.TxnDatastore, s Store, blocks []*types.Block) {
+			block := blocks[3]
+			block.SignedHeader.Commit.Signatures[0] = types.GetRandomBytes(64)
+			require.NoError(t, s.SaveBlock(block, &block.SignedHeader.Commit))
+		}, []ProblemKind{ProblemInvalidBlock}},
+		{"broken linkage", func(t *testing.T, kv ds.TxnDatastore, s Store, blocks []*types.Block) {
+			other := getTestChain(t, 3)[2]
+			require.NoError(t, kv.Delete(ctx, ds.NewKey(getBlockKey(blocks[2].Hash()))))
+			require.NoError(t, kv.Delete(ctx, ds.NewKey(getCommitKey(blocks[2].Hash()))))
+			require.NoError(t, s.SaveBlock(other, &other.SignedHeader.Commit))
+		}, []ProblemKind{ProblemBrokenLinkage, ProblemBrokenLinkage}},
+		{"sync store mismatch", func(t *testing.T, kv ds.TxnDatastore, s Store, blocks []*types.Block) {
+			ss, err := goheaderstore.NewStore[*types.Block](kv.(ds.Batching), goheaderstore.WithStorePrefix(BlockSyncPrefix))
+			require.NoError(t, err)
+			require.NoError(t, ss.Init(ctx, getTestChain(t, 2)[1]))
+		}, []ProblemKind{ProblemSyncStoreMismatch}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			kv, s, blocks := newVerifyTestStore(t)
+			c.corrupt(t, kv, s, blocks)
+			report, err := Verify(ctx, kv, VerifyOptions{Repair: true})
+			require.NoError(t, err)
+			assert.False(t, report.OK())
+			assert.ElementsMatch(t, c.expected, problemKinds(report))
+		})
+	}
+}
+
+func TestVerifyRepairIndex(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	ctx := context.Background()
+
+	kv, _, blocks := newVerifyTestStore(t)
+	require.NoError(kv.Delete(ctx, ds.NewKey(getIndexKey(2))))
+	require.NoError(kv.Put(ctx, ds.NewKey(getIndexKey(4)), blocks[0].Hash()))
+
+	report, err := Verify(ctx, kv, VerifyOptions{})
+	require.NoError(err)
+	assert.False(report.OK())
+	assert.ElementsMatch([]ProblemKind{ProblemMissingIndex, ProblemInvalidIndex}, problemKinds(report))
+	for _, p := range report.Problems {
+		assert.False(p.Repaired)
+	}
+
+	report, err = Verify(ctx, kv, VerifyOptions{Repair: true})
+	require.NoError(err)
+	assert.True(report.OK())
+	assert.Len(report.Problems, 2)
+	for _, p := range report.Problems {
+		assert.True(p.Repaired)
+	}
+
+	report, err = Verify(ctx, kv, VerifyOptions{})
+	require.NoError(err)
+	assert.Empty(report.Problems)
+}
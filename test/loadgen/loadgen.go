@@ -0,0 +1,336 @@
+// Package loadgen generates transaction load against Rollkit node and measures its throughput and latency.
+//
+// Transactions are sent at configured rate, with sizes drawn uniformly from configured range. Generator
+// observes the node while sending, and reports time to soft confirmation (inclusion in a block), time to
+// DA inclusion, block fullness and mempool / DA submission backlog over time. Report is JSON-serializable.
+package loadgen
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	cmtypes "github.com/cometbft/cometbft/types"
+)
+
+const (
+	defaultSampleInterval = 100 * time.Millisecond
+	defaultDrainTimeout   = 30 * time.Second
+	minTxSize             = 16
+)
+
+// Config describes the generated load.
+type Config struct {
+	// Rate is the number of transactions sent per second.
+	Rate float64 `json:"rate"`
+	// Duration is the time of sending transactions.
+	Duration time.Duration `json:"duration"`
+	// MinTxSize and MaxTxSize define the range of transaction sizes in bytes.
+	MinTxSize int `json:"min_tx_size"`
+	MaxTxSize int `json:"max_tx_size"`
+	// DrainTimeout is the maximum time of waiting for inclusion of sent transactions after sending stops.
+	DrainTimeout time.Duration `json:"drain_timeout"`
+	// SampleInterval is the interval of observing the node.
+	SampleInterval time.Duration `json:"sample_interval"`
+	// Seed of transaction contents and sizes.
+	Seed int64 `json:"seed"`
+}
+
+// Validate checks if configuration is correct, and sets defaults of optional fields.
+func (c *Config) Validate() error {
+	if c.Rate <= 0 {
+		return errors.New("rate must be positive")
+	}
+	if c.Duration <= 0 {
+		return errors.New("duration must be positive")
+	}
+	if c.MinTxSize < minTxSize {
+		return fmt.Errorf("minimum transaction size is %d bytes", minTxSize)
+	}
+	if c.MaxTxSize < c.MinTxSize {
+		return errors.New("maximum transaction size is lower than minimum transaction size")
+	}
+	if c.DrainTimeout == 0 {
+		c.DrainTimeout = defaultDrainTimeout
+	}
+	if c.SampleInterval == 0 {
+		c.SampleInterval = defaultSampleInterval
+	}
+	return nil
+}
+
+type txRecord struct {
+	sent        time.Time
+	height      uint64
+	softLatency time.Duration
+	daLatency   time.Duration
+	daIncluded  bool
+}
+
+// generator holds the state of single load generation run.
+type generator struct {
+	conf   Config
+	target Target
+	rng    *rand.Rand
+	start  time.Time
+
+	mtx      sync.Mutex
+	txs      map[string]*txRecord
+	byHeight map[uint64][]*txRecord
+	sent     int
+	failed   int
+
+	lastHeight    uint64
+	maxBlockBytes int64
+	daSupported   bool
+	blocks        []BlockStats
+	samples       []Sample
+}
+
+// Run sends transactions to the target and observes it, according to configuration.
+//
+// After sending stops, Run waits for all transactions to be included on DA (or in blocks, if target doesn't
+// report DA status), at most for DrainTimeout.
+func Run(ctx context.Context, target Target, conf Config) (*Report, error) {
+	if err := conf.Validate(); err != nil {
+		return nil, err
+	}
+	g := &generator{
+		conf:        conf,
+		target:      target,
+		rng:         rand.New(rand.NewSource(conf.Seed)), //nolint:gosec
+		txs:         make(map[string]*txRecord),
+		byHeight:    make(map[uint64][]*txRecord),
+		daSupported: true,
+	}
+
+	var err error
+	if g.lastHeight, err = target.Height(ctx); err != nil {
+		return nil, fmt.Errorf("failed to get height of the node: %w", err)
+	}
+	startHeight := g.lastHeight
+	if g.maxBlockBytes, err = target.MaxBlockBytes(ctx); err != nil {
+		return nil, fmt.Errorf("failed to get maximum block size: %w", err)
+	}
+	g.start = time.Now()
+
+	observeCtx, stopObserving := context.WithCancel(ctx)
+	observeErr := make(chan error, 1)
+	go func() {
+		observeErr <- g.observeLoop(observeCtx)
+	}()
+
+	g.sendLoop(ctx)
+	g.drain(ctx)
+
+	stopObserving()
+	if err := <-observeErr; err != nil {
+		return nil, err
+	}
+	if err := g.observe(ctx); err != nil {
+		return nil, err
+	}
+	return g.report(startHeight), nil
+}
+
+func (g *generator) sendLoop(ctx context.Context) {
+	interval := time.Duration(float64(time.Second) / g.conf.Rate)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	deadline := time.NewTimer(g.conf.Duration)
+	defer deadline.Stop()
+
+	for i := 0; ; i++ {
+		tx := g.newTx(i)
+		rec := &txRecord{sent: time.Now()}
+		g.mtx.Lock()
+		g.txs[string(tx.Hash())] = rec
+		g.sent++
+		g.mtx.Unlock()
+
+		if err := g.target.SendTx(ctx, tx); err != nil {
+			g.mtx.Lock()
+			delete(g.txs, string(tx.Hash()))
+			g.failed++
+			g.mtx.Unlock()
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-deadline.C:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// newTx creates unique transaction of random size, in "key=value" format.
+func (g *generator) newTx(i int) cmtypes.Tx {
+	size := g.conf.MinTxSize
+	if g.conf.MaxTxSize > g.conf.MinTxSize {
+		size += g.rng.Intn(g.conf.MaxTxSize - g.conf.MinTxSize + 1)
+	}
+	tx := make([]byte, size)
+	prefix := fmt.Sprintf("lg%d-%d=", g.conf.Seed, i)
+	n := copy(tx, prefix)
+	const letters = "abcdefghijklmnopqrstuvwxyz"
+	for ; n < size; n++ {
+		tx[n] = letters[g.rng.Intn(len(letters))]
+	}
+	return tx
+}
+
+// drain waits until all sent transactions are included, or DrainTimeout elapses.
+func (g *generator) drain(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, g.conf.DrainTimeout)
+	defer cancel()
+	ticker := time.NewTicker(g.conf.SampleInterval)
+	defer ticker.Stop()
+	for !g.done() {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (g *generator) done() bool {
+	g.mtx.Lock()
+	defer g.mtx.Unlock()
+	for _, rec := range g.txs {
+		if rec.height == 0 || (g.daSupported && !rec.daIncluded) {
+			return false
+		}
+	}
+	return true
+}
+
+func (g *generator) observeLoop(ctx context.Context) error {
+	ticker := time.NewTicker(g.conf.SampleInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+		if err := g.observe(ctx); err != nil && ctx.Err() == nil {
+			return err
+		}
+	}
+}
+
+// observe processes new blocks, DA status and mempool size of the target.
+func (g *generator) observe(ctx context.Context) error {
+	height, err := g.target.Height(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get height of the node: %w", err)
+	}
+	now := time.Now()
+	for h := g.lastHeight + 1; h <= height; h++ {
+		txs, err := g.target.BlockTxs(ctx, h)
+		if err != nil {
+			return fmt.Errorf("failed to get block %d: %w", h, err)
+		}
+		g.processBlock(h, txs, now)
+		g.lastHeight = h
+	}
+
+	sample := Sample{Elapsed: now.Sub(g.start).Seconds(), Height: height}
+	if sample.MempoolTxs, sample.MempoolBytes, err = g.target.MempoolSize(ctx); err != nil {
+		return fmt.Errorf("failed to get mempool size: %w", err)
+	}
+	status, err := g.target.DAStatus(ctx)
+	switch {
+	case errors.Is(err, ErrDAStatusNotSupported):
+		g.mtx.Lock()
+		g.daSupported = false
+		g.mtx.Unlock()
+	case err != nil:
+		return fmt.Errorf("failed to get DA status: %w", err)
+	default:
+		sample.DAIncludedHeight = status.DAIncludedHeight
+		sample.PendingDABlocks = status.PendingBlocks
+		g.processDAInclusion(status.DAIncludedHeight, now)
+	}
+
+	g.mtx.Lock()
+	g.samples = append(g.samples, sample)
+	g.mtx.Unlock()
+	return nil
+}
+
+func (g *generator) processBlock(height uint64, txs cmtypes.Txs, now time.Time) {
+	g.mtx.Lock()
+	defer g.mtx.Unlock()
+
+	stats := BlockStats{Height: height, Txs: len(txs)}
+	for _, tx := range txs {
+		stats.Bytes += int64(len(tx))
+		rec, ok := g.txs[string(tx.Hash())]
+		if !ok || rec.height != 0 {
+			continue
+		}
+		rec.height = height
+		rec.softLatency = now.Sub(rec.sent)
+		g.byHeight[height] = append(g.byHeight[height], rec)
+	}
+	if g.maxBlockBytes > 0 {
+		stats.Fullness = float64(stats.Bytes) / float64(g.maxBlockBytes)
+	}
+	g.blocks = append(g.blocks, stats)
+}
+
+func (g *generator) processDAInclusion(daIncludedHeight uint64, now time.Time) {
+	g.mtx.Lock()
+	defer g.mtx.Unlock()
+
+	for height, recs := range g.byHeight {
+		if height > daIncludedHeight {
+			continue
+		}
+		for _, rec := range recs {
+			rec.daIncluded = true
+			rec.daLatency = now.Sub(rec.sent)
+		}
+		delete(g.byHeight, height)
+	}
+}
+
+func (g *generator) report(startHeight uint64) *Report {
+	g.mtx.Lock()
+	defer g.mtx.Unlock()
+
+	elapsed := time.Since(g.start)
+	r := &Report{
+		Config:         g.conf,
+		Elapsed:        elapsed.Seconds(),
+		StartHeight:    startHeight,
+		EndHeight:      g.lastHeight,
+		Sent:           g.sent,
+		Failed:         g.failed,
+		DAStatusActive: g.daSupported,
+		Blocks:         g.blocks,
+		Samples:        g.samples,
+	}
+	var soft, da []time.Duration
+	for _, rec := range g.txs {
+		if rec.height != 0 {
+			soft = append(soft, rec.softLatency)
+		}
+		if rec.daIncluded {
+			da = append(da, rec.daLatency)
+		}
+	}
+	r.SoftConfirmed = len(soft)
+	r.DAIncluded = len(da)
+	r.Throughput = float64(r.SoftConfirmed) / elapsed.Seconds()
+	r.SoftConfirmation = newLatencyStats(soft)
+	r.DAInclusion = newLatencyStats(da)
+	return r
+}
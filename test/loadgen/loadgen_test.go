@@ -0,0 +1,127 @@
+package loadgen
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	cmtypes "github.com/cometbft/cometbft/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rollkit/rollkit/block"
+)
+
+// fakeTarget produces a block with all mempool transactions every time height is queried.
+// Blocks are DA included with a delay of one block.
+type fakeTarget struct {
+	mtx     sync.Mutex
+	mempool cmtypes.Txs
+	blocks  []cmtypes.Txs
+}
+
+func (f *fakeTarget) SendTx(_ context.Context, tx cmtypes.Tx) error {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	f.mempool = append(f.mempool, tx)
+	return nil
+}
+
+func (f *fakeTarget) Height(_ context.Context) (uint64, error) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	f.blocks = append(f.blocks, f.mempool)
+	f.mempool = nil
+	return uint64(len(f.blocks)), nil
+}
+
+func (f *fakeTarget) BlockTxs(_ context.Context, height uint64) (cmtypes.Txs, error) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	return f.blocks[height-1], nil
+}
+
+func (f *fakeTarget) MaxBlockBytes(_ context.Context) (int64, error) {
+	return 1024 * 1024, nil
+}
+
+func (f *fakeTarget) MempoolSize(_ context.Context) (int, int64, error) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	var size int64
+	for _, tx := range f.mempool {
+		size += int64(len(tx))
+	}
+	return len(f.mempool), size, nil
+}
+
+func (f *fakeTarget) DAStatus(_ context.Context) (*block.DAStatus, error) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	return &block.DAStatus{DAIncludedHeight: uint64(len(f.blocks) - 1)}, nil
+}
+
+func TestRun(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	conf := Config{
+		Rate:           200,
+		Duration:       500 * time.Millisecond,
+		MinTxSize:      32,
+		MaxTxSize:      64,
+		SampleInterval: 20 * time.Millisecond,
+		DrainTimeout:   5 * time.Second,
+	}
+	target := &fakeTarget{}
+	report, err := Run(context.Background(), target, conf)
+	require.NoError(err)
+
+	assert.Greater(report.Sent, 0)
+	assert.Zero(report.Failed)
+	assert.Equal(report.Sent, report.SoftConfirmed)
+	assert.Equal(report.Sent, report.DAIncluded)
+	assert.True(report.DAStatusActive)
+	assert.Equal(report.Sent, report.SoftConfirmation.Count)
+	assert.LessOrEqual(report.SoftConfirmation.Min, report.SoftConfirmation.P50)
+	assert.LessOrEqual(report.SoftConfirmation.P50, report.SoftConfirmation.Max)
+	assert.GreaterOrEqual(report.DAInclusion.Mean, report.SoftConfirmation.Mean)
+	assert.NotEmpty(report.Samples)
+
+	txs := 0
+	for _, b := range report.Blocks {
+		txs += b.Txs
+		assert.LessOrEqual(b.Fullness, 1.0)
+		if b.Txs > 0 {
+			assert.GreaterOrEqual(b.Bytes, int64(b.Txs*conf.MinTxSize))
+			assert.LessOrEqual(b.Bytes, int64(b.Txs*conf.MaxTxSize))
+		}
+	}
+	assert.Equal(report.Sent, txs)
+
+	var buf bytes.Buffer
+	require.NoError(report.WriteJSON(&buf))
+	var decoded Report
+	require.NoError(json.Unmarshal(buf.Bytes(), &decoded))
+	assert.Equal(report.Sent, decoded.Sent)
+	assert.Equal(len(report.Blocks), len(decoded.Blocks))
+}
+
+func TestConfigValidate(t *testing.T) {
+	valid := Config{Rate: 1, Duration: time.Second, MinTxSize: 16, MaxTxSize: 16}
+	assert.NoError(t, valid.Validate())
+	assert.Equal(t, defaultDrainTimeout, valid.DrainTimeout)
+	assert.Equal(t, defaultSampleInterval, valid.SampleInterval)
+
+	for _, c := range []Config{
+		{Rate: 0, Duration: time.Second, MinTxSize: 16, MaxTxSize: 16},
+		{Rate: 1, Duration: 0, MinTxSize: 16, MaxTxSize: 16},
+		{Rate: 1, Duration: time.Second, MinTxSize: 1, MaxTxSize: 16},
+		{Rate: 1, Duration: time.Second, MinTxSize: 32, MaxTxSize: 16},
+	} {
+		assert.Error(t, c.Validate())
+	}
+}
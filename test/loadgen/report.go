@@ -0,0 +1,98 @@
+package loadgen
+
+import (
+	"encoding/json"
+	"io"
+	"sort"
+	"time"
+)
+
+// Report contains results of load generation. All times are in seconds, latencies in milliseconds.
+type Report struct {
+	Config Config `json:"config"`
+	// Elapsed is the total time of the run, including draining.
+	Elapsed     float64 `json:"elapsed"`
+	StartHeight uint64  `json:"start_height"`
+	EndHeight   uint64  `json:"end_height"`
+
+	Sent          int `json:"sent"`
+	Failed        int `json:"failed"`
+	SoftConfirmed int `json:"soft_confirmed"`
+	DAIncluded    int `json:"da_included"`
+	// DAStatusActive is false if target doesn't report DA status; DA metrics are empty in such case.
+	DAStatusActive bool `json:"da_status_active"`
+	// Throughput is the number of soft confirmed transactions per second.
+	Throughput float64 `json:"throughput"`
+
+	SoftConfirmation LatencyStats `json:"soft_confirmation_latency"`
+	DAInclusion      LatencyStats `json:"da_inclusion_latency"`
+
+	Blocks  []BlockStats `json:"blocks"`
+	Samples []Sample     `json:"samples"`
+}
+
+// LatencyStats summarizes latencies, in milliseconds.
+type LatencyStats struct {
+	Count int     `json:"count"`
+	Min   float64 `json:"min"`
+	Mean  float64 `json:"mean"`
+	P50   float64 `json:"p50"`
+	P90   float64 `json:"p90"`
+	P99   float64 `json:"p99"`
+	Max   float64 `json:"max"`
+}
+
+// BlockStats describes single block produced during the run.
+type BlockStats struct {
+	Height uint64 `json:"height"`
+	Txs    int    `json:"txs"`
+	Bytes  int64  `json:"bytes"`
+	// Fullness is the size of transactions relative to maximum block size.
+	Fullness float64 `json:"fullness"`
+}
+
+// Sample is the state of the node observed during the run.
+type Sample struct {
+	// Elapsed is the time since start of the run.
+	Elapsed          float64 `json:"elapsed"`
+	Height           uint64  `json:"height"`
+	DAIncludedHeight uint64  `json:"da_included_height"`
+	MempoolTxs       int     `json:"mempool_txs"`
+	MempoolBytes     int64   `json:"mempool_bytes"`
+	// PendingDABlocks is the number of blocks waiting for submission to DA layer.
+	PendingDABlocks int `json:"pending_da_blocks"`
+}
+
+// WriteJSON writes indented JSON representation of the report.
+func (r *Report) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
+
+func newLatencyStats(latencies []time.Duration) LatencyStats {
+	if len(latencies) == 0 {
+		return LatencyStats{}
+	}
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+	ms := func(d time.Duration) float64 {
+		return float64(d) / float64(time.Millisecond)
+	}
+	percentile := func(p float64) float64 {
+		return ms(latencies[int(p*float64(len(latencies)-1))])
+	}
+
+	var sum time.Duration
+	for _, l := range latencies {
+		sum += l
+	}
+	return LatencyStats{
+		Count: len(latencies),
+		Min:   ms(latencies[0]),
+		Mean:  ms(sum) / float64(len(latencies)),
+		P50:   percentile(0.5),
+		P90:   percentile(0.9),
+		P99:   percentile(0.99),
+		Max:   ms(latencies[len(latencies)-1]),
+	}
+}
@@ -0,0 +1,183 @@
+package loadgen
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	rpcclient "github.com/cometbft/cometbft/rpc/client"
+	rpchttp "github.com/cometbft/cometbft/rpc/client/http"
+	jsonrpcclient "github.com/cometbft/cometbft/rpc/jsonrpc/client"
+	cmtypes "github.com/cometbft/cometbft/types"
+
+	"github.com/rollkit/rollkit/block"
+	"github.com/rollkit/rollkit/mempool"
+	"github.com/rollkit/rollkit/node"
+)
+
+// ErrDAStatusNotSupported is returned by targets unable to report DA status of the node.
+var ErrDAStatusNotSupported = errors.New("DA status is not supported by target")
+
+// Target is the node under load.
+type Target interface {
+	// SendTx submits transaction to the node.
+	SendTx(ctx context.Context, tx cmtypes.Tx) error
+	// Height returns the latest height of the node.
+	Height(ctx context.Context) (uint64, error)
+	// BlockTxs returns transactions included in the block at given height.
+	BlockTxs(ctx context.Context, height uint64) (cmtypes.Txs, error)
+	// MaxBlockBytes returns the maximum size of the block.
+	MaxBlockBytes(ctx context.Context) (int64, error)
+	// MempoolSize returns the number and total size of transactions in mempool.
+	MempoolSize(ctx context.Context) (int, int64, error)
+	// DAStatus returns status of DA submission and retrieval. ErrDAStatusNotSupported is returned if
+	// status is not available.
+	DAStatus(ctx context.Context) (*block.DAStatus, error)
+}
+
+// BroadcastMode defines how transactions are submitted to the node.
+type BroadcastMode string
+
+const (
+	// BroadcastAsync uses broadcast_tx_async.
+	BroadcastAsync BroadcastMode = "async"
+	// BroadcastSync uses broadcast_tx_sync.
+	BroadcastSync BroadcastMode = "sync"
+	// BroadcastMempool injects transactions directly to mempool of in-process node.
+	BroadcastMempool BroadcastMode = "mempool"
+)
+
+// daStatusProvider is implemented by clients of Rollkit full nodes.
+type daStatusProvider interface {
+	DAStatus(ctx context.Context) (*block.DAStatus, error)
+}
+
+// ClientTarget is a Target using RPC client.
+type ClientTarget struct {
+	client   rpcclient.Client
+	send     func(ctx context.Context, tx cmtypes.Tx) error
+	daStatus func(ctx context.Context) (*block.DAStatus, error)
+}
+
+var _ Target = &ClientTarget{}
+
+// NewClientTarget creates a Target sending transactions with broadcast_tx_async or broadcast_tx_sync.
+// DA status is available if client is a client of in-process Rollkit node.
+func NewClientTarget(client rpcclient.Client, mode BroadcastMode) (*ClientTarget, error) {
+	t := &ClientTarget{client: client}
+	switch mode {
+	case BroadcastAsync:
+		t.send = func(ctx context.Context, tx cmtypes.Tx) error {
+			_, err := client.BroadcastTxAsync(ctx, tx)
+			return err
+		}
+	case BroadcastSync:
+		t.send = func(ctx context.Context, tx cmtypes.Tx) error {
+			res, err := client.BroadcastTxSync(ctx, tx)
+			if err != nil {
+				return err
+			}
+			if res.Code != 0 {
+				return fmt.Errorf("transaction rejected with code %d: %s", res.Code, res.Log)
+			}
+			return nil
+		}
+	default:
+		return nil, fmt.Errorf("broadcast mode %q is not supported by RPC client", mode)
+	}
+	if p, ok := client.(daStatusProvider); ok {
+		t.daStatus = p.DAStatus
+	}
+	return t, nil
+}
+
+// NewRemoteTarget creates a Target for node listening for RPC requests on given address.
+// DA status is retrieved using da_status method.
+func NewRemoteTarget(addr string, mode BroadcastMode) (*ClientTarget, error) {
+	client, err := rpchttp.New(addr, "/websocket")
+	if err != nil {
+		return nil, err
+	}
+	t, err := NewClientTarget(client, mode)
+	if err != nil {
+		return nil, err
+	}
+	rpc, err := jsonrpcclient.New(addr)
+	if err != nil {
+		return nil, err
+	}
+	t.daStatus = func(ctx context.Context) (*block.DAStatus, error) {
+		status := &block.DAStatus{}
+		if _, err := rpc.Call(ctx, "da_status", map[string]interface{}{}, status); err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrDAStatusNotSupported, err)
+		}
+		return status, nil
+	}
+	return t, nil
+}
+
+// NewNodeTarget creates a Target for in-process full node. Transactions are injected directly into mempool
+// if mode is BroadcastMempool.
+func NewNodeTarget(n *node.FullNode, mode BroadcastMode) (*ClientTarget, error) {
+	if mode != BroadcastMempool {
+		return NewClientTarget(n.GetClient(), mode)
+	}
+	t, err := NewClientTarget(n.GetClient(), BroadcastAsync)
+	if err != nil {
+		return nil, err
+	}
+	t.send = func(ctx context.Context, tx cmtypes.Tx) error {
+		return n.Mempool.CheckTx(tx, nil, mempool.TxInfo{})
+	}
+	return t, nil
+}
+
+// SendTx implements Target.
+func (t *ClientTarget) SendTx(ctx context.Context, tx cmtypes.Tx) error {
+	return t.send(ctx, tx)
+}
+
+// Height implements Target.
+func (t *ClientTarget) Height(ctx context.Context) (uint64, error) {
+	status, err := t.client.Status(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return uint64(status.SyncInfo.LatestBlockHeight), nil
+}
+
+// BlockTxs implements Target.
+func (t *ClientTarget) BlockTxs(ctx context.Context, height uint64) (cmtypes.Txs, error) {
+	h := int64(height)
+	res, err := t.client.Block(ctx, &h)
+	if err != nil {
+		return nil, err
+	}
+	return res.Block.Txs, nil
+}
+
+// MaxBlockBytes implements Target.
+func (t *ClientTarget) MaxBlockBytes(ctx context.Context) (int64, error) {
+	res, err := t.client.ConsensusParams(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	return res.ConsensusParams.Block.MaxBytes, nil
+}
+
+// MempoolSize implements Target.
+func (t *ClientTarget) MempoolSize(ctx context.Context) (int, int64, error) {
+	res, err := t.client.NumUnconfirmedTxs(ctx)
+	if err != nil {
+		return 0, 0, err
+	}
+	return res.Total, res.TotalBytes, nil
+}
+
+// DAStatus implements Target.
+func (t *ClientTarget) DAStatus(ctx context.Context) (*block.DAStatus, error) {
+	if t.daStatus == nil {
+		return nil, ErrDAStatusNotSupported
+	}
+	return t.daStatus(ctx)
+}
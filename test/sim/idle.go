@@ -0,0 +1,77 @@
+package sim
+
+import (
+	"bytes"
+	"fmt"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// idleTimeout is the (real) time after which waitIdle gives up, when goroutines keep running, e.g. because a loop
+// is spinning without waiting for the clock.
+const idleTimeout = time.Minute
+
+// blockedStates are the states of goroutines that can't make progress until another goroutine wakes them up.
+var blockedStates = []string{
+	"chan receive",
+	"chan send",
+	"select",
+	"sync.",
+	"semacquire",
+	"IO wait",
+	"finalizer wait",
+}
+
+// waitIdle waits until all goroutines of the process, except the calling one, are blocked (see blockedStates).
+//
+// Block managers measure time only with the virtual clock, so once they are all blocked, they wait for the clock
+// to be advanced (or for goroutines they depend on, e.g. the ones of the datastore, which are blocked as well). The
+// decision doesn't depend on timing: goroutine woken up by a channel operation or a fired timer is runnable until
+// it blocks again. All goroutines are checked, not only the ones of the nodes, as nodes wait for goroutines they
+// didn't start.
+func waitIdle() {
+	deadline := time.Now().Add(idleTimeout)
+	buf := make([]byte, 64*1024)
+	for {
+		n := runtime.Stack(buf, true)
+		if n == len(buf) {
+			buf = make([]byte, 2*len(buf))
+			continue
+		}
+		busy := busyGoroutine(buf[:n])
+		if busy == "" {
+			return
+		}
+		if time.Now().After(deadline) {
+			panic(fmt.Sprintf("simulation did not become idle within %s, running goroutine:\n%s", idleTimeout, busy))
+		}
+		runtime.Gosched()
+	}
+}
+
+// busyGoroutine returns the first goroutine in stack dump of all goroutines that isn't blocked, or empty string
+// if all of them are. The first goroutine of the dump (the calling one) is skipped.
+func busyGoroutine(dump []byte) string {
+	goroutines := bytes.Split(dump, []byte("\n\n"))
+	for _, g := range goroutines[1:] {
+		header, _, _ := strings.Cut(string(g), "\n")
+		_, state, ok := strings.Cut(header, "[")
+		if !ok {
+			continue
+		}
+		if !isBlocked(state) {
+			return string(g)
+		}
+	}
+	return ""
+}
+
+func isBlocked(state string) bool {
+	for _, s := range blockedStates {
+		if strings.HasPrefix(state, s) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,333 @@
+// Package sim runs simulations of Rollkit networks on a virtual clock.
+//
+// Simulation consists of a sequencer and full nodes, each driven by the real block manager loops, sharing
+// single mock DA layer. All loops, timers and block timestamps use the same clock.Mock, so hours of network
+// time pass in (real) seconds. All random decisions (transactions, DA block heights) are derived from the
+// seed, so scenarios like "sequencer offline for an hour while DA reorgs twice" are reproducible.
+//
+// Clock is advanced in steps (see Config.Step). After each step, simulation waits until the nodes processed all the
+// events due at current time: until none of the goroutines of the process can make progress without the clock being
+// advanced again (see waitIdle). Events due within single step are processed concurrently.
+package sim
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/cometbft/cometbft/libs/log"
+	cmtypes "github.com/cometbft/cometbft/types"
+	"github.com/libp2p/go-libp2p/core/crypto"
+
+	"github.com/rollkit/rollkit/block"
+	"github.com/rollkit/rollkit/config"
+	mockda "github.com/rollkit/rollkit/da/mock"
+	"github.com/rollkit/rollkit/execution/kv"
+	"github.com/rollkit/rollkit/store"
+	"github.com/rollkit/rollkit/types"
+)
+
+// Config describes the simulation.
+type Config struct {
+	// Seed of all random decisions made by simulation.
+	Seed int64
+	// FullNodes is the number of full nodes, syncing from DA layer.
+	FullNodes int
+	// BlockManagerConfig is used by all nodes. DefaultBlockManagerConfig is used if BlockTime is not set.
+	BlockManagerConfig config.BlockManagerConfig
+	// Logger is used by all nodes (with "node" key). Nop logger is used by default.
+	Logger log.Logger
+	// Step is the interval the virtual clock is advanced by at once. DefaultStep is used if it's not set.
+	Step time.Duration
+}
+
+// DefaultStep is the default interval the virtual clock is advanced by at once; it's the block time of
+// DefaultBlockManagerConfig. Timers due within single step are fired in order, but goroutines woken up by them
+// are waited for only at the end of the step, so timers shorter than the step (e.g. retries of DA retrieval)
+// are effectively rounded up to the step.
+const DefaultStep = 10 * time.Second
+
+// DefaultBlockManagerConfig returns block manager configuration used by simulations.
+func DefaultBlockManagerConfig() config.BlockManagerConfig {
+	return config.BlockManagerConfig{
+		BlockTime:     10 * time.Second,
+		LazyBlockTime: 10 * time.Second,
+		DABlockTime:   30 * time.Second,
+		Namespace:     types.MustNewNamespace(types.NamespaceVersionZero, []byte{1, 2, 3, 4, 5, 6, 7, 8}),
+	}
+}
+
+// Node is a single node of the simulated network.
+type Node struct {
+	Manager  *block.Manager
+	Executor *kv.Executor
+	Store    store.Store
+
+	sequencer bool
+	cancel    context.CancelFunc
+	wg        sync.WaitGroup
+}
+
+// Start starts loops of the node: block production and submission for sequencer, DA retrieval and
+// syncing for full nodes. Sequencer produces blocks every block time, also when there are no transactions.
+func (n *Node) Start(ctx context.Context) {
+	if n.cancel != nil {
+		return
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	n.cancel = cancel
+	if n.sequencer {
+		n.run(func() { n.Manager.AggregationLoop(ctx, false) })
+		n.run(func() { n.Manager.BlockSubmissionLoop(ctx) })
+		n.run(func() { drain(ctx, n.Manager) })
+		return
+	}
+	n.run(func() { n.Manager.RetrieveLoop(ctx) })
+	n.run(func() { n.Manager.SyncLoop(ctx, cancel) })
+}
+
+// Stop stops all loops of the node and waits for them to finish.
+func (n *Node) Stop() {
+	if n.cancel == nil {
+		return
+	}
+	n.cancel()
+	n.wg.Wait()
+	n.cancel = nil
+}
+
+// IsRunning returns true if node was started and not stopped.
+func (n *Node) IsRunning() bool {
+	return n.cancel != nil
+}
+
+func (n *Node) run(f func()) {
+	n.wg.Add(1)
+	go func() {
+		defer n.wg.Done()
+		f()
+	}()
+}
+
+// drain consumes headers and blocks published by sequencer; there is no P2P network in simulation.
+func drain(ctx context.Context, m *block.Manager) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-m.HeaderCh:
+		case <-m.BlockCh:
+		}
+	}
+}
+
+// Simulation is a network of nodes running on a virtual clock.
+type Simulation struct {
+	Clock     *clock.Mock
+	Rand      *rand.Rand
+	DA        *mockda.DataAvailabilityLayerClient
+	Sequencer *Node
+	FullNodes []*Node
+
+	step time.Duration
+	txs  []types.Tx
+}
+
+// New creates the simulation. Virtual clock is set to genesis time; nodes are not started.
+func New(conf Config) (*Simulation, error) {
+	if conf.BlockManagerConfig.BlockTime == 0 {
+		conf.BlockManagerConfig = DefaultBlockManagerConfig()
+	}
+	if conf.Logger == nil {
+		conf.Logger = log.NewNopLogger()
+	}
+	if conf.Step == 0 {
+		conf.Step = DefaultStep
+	}
+
+	sim := &Simulation{
+		Clock: clock.NewMock(),
+		Rand:  rand.New(rand.NewSource(conf.Seed)), //nolint:gosec
+		step:  conf.Step,
+	}
+	genesisTime := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	sim.Clock.Set(genesisTime)
+
+	sim.DA = &mockda.DataAvailabilityLayerClient{}
+	sim.DA.SetClock(sim.Clock)
+	sim.DA.SetRand(rand.New(rand.NewSource(sim.Rand.Int63()))) //nolint:gosec
+	dalcKV, err := store.NewDefaultInMemoryKVStore()
+	if err != nil {
+		return nil, err
+	}
+	daConfig := []byte(conf.BlockManagerConfig.DABlockTime.String())
+	if err := sim.DA.Init(conf.BlockManagerConfig.Namespace, daConfig, dalcKV, conf.Logger.With("module", "da_client")); err != nil {
+		return nil, err
+	}
+
+	validators, signingKey := types.GetGenesisValidatorSetWithSigner()
+	genesis := &cmtypes.GenesisDoc{
+		ChainID:       "sim",
+		GenesisTime:   genesisTime,
+		InitialHeight: 1,
+		Validators:    validators,
+	}
+	for i := 0; i <= conf.FullNodes; i++ {
+		n, err := sim.newNode(signingKey, conf, genesis, conf.Logger.With("node", i))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create node %d: %w", i, err)
+		}
+		if i == 0 {
+			n.sequencer = true
+			sim.Sequencer = n
+		} else {
+			sim.FullNodes = append(sim.FullNodes, n)
+		}
+	}
+	return sim, nil
+}
+
+func (s *Simulation) newNode(signingKey crypto.PrivKey, conf Config, genesis *cmtypes.GenesisDoc, logger log.Logger) (*Node, error) {
+	kvStore, err := store.NewDefaultInMemoryKVStore()
+	if err != nil {
+		return nil, err
+	}
+	st := store.New(context.Background(), kvStore)
+	exec := kv.NewExecutor()
+	m, err := block.NewManager(signingKey, conf.BlockManagerConfig, genesis, st, exec, s.DA, logger, nil)
+	if err != nil {
+		return nil, err
+	}
+	m.SetClock(s.Clock)
+	return &Node{Manager: m, Executor: exec, Store: st}, nil
+}
+
+// Nodes returns all nodes, sequencer first.
+func (s *Simulation) Nodes() []*Node {
+	return append([]*Node{s.Sequencer}, s.FullNodes...)
+}
+
+// Start starts the DA layer and all the nodes.
+func (s *Simulation) Start(ctx context.Context) error {
+	if err := s.DA.Start(); err != nil {
+		return err
+	}
+	for _, n := range s.Nodes() {
+		n.Start(ctx)
+	}
+	waitIdle()
+	return nil
+}
+
+// Stop stops all the nodes.
+func (s *Simulation) Stop() error {
+	for _, n := range s.Nodes() {
+		n.Stop()
+	}
+	return s.DA.Stop()
+}
+
+// Run advances virtual clock by d, letting the nodes process all events.
+func (s *Simulation) Run(d time.Duration) {
+	s.runUntil(s.Clock.Now().Add(d), func() bool { return false })
+}
+
+// InjectTxs creates n random, unique transactions and passes them to the sequencer.
+func (s *Simulation) InjectTxs(n int) []types.Tx {
+	txs := make([]types.Tx, n)
+	for i := range txs {
+		txs[i] = types.Tx(fmt.Sprintf("key%d=%d", len(s.txs), s.Rand.Int63()))
+		s.txs = append(s.txs, txs[i])
+		s.Sequencer.Executor.InjectTx(txs[i])
+	}
+	return txs
+}
+
+// Txs returns all transactions injected during simulation.
+func (s *Simulation) Txs() []types.Tx {
+	return s.txs
+}
+
+// ReorgDA reorganizes last depth DA blocks (see mockda.DataAvailabilityLayerClient.Reorg).
+func (s *Simulation) ReorgDA(ctx context.Context, depth uint64) (int, error) {
+	return s.DA.Reorg(ctx, depth)
+}
+
+// WaitForSync advances virtual clock until all full nodes reach the height of the sequencer at the time of
+// the call, or timeout elapses.
+func (s *Simulation) WaitForSync(timeout time.Duration) error {
+	target := s.Sequencer.Store.Height()
+	synced := func() bool {
+		for _, n := range s.FullNodes {
+			if n.Store.Height() < target {
+				return false
+			}
+		}
+		return true
+	}
+	if !s.runUntil(s.Clock.Now().Add(timeout), synced) {
+		return fmt.Errorf("full nodes did not reach sequencer height %d", target)
+	}
+	return nil
+}
+
+// Converged verifies that full nodes committed the same blocks as the sequencer, and have the same application
+// state. Sequencer keeps producing blocks, so every full node is compared with the sequencer at its own height.
+func (s *Simulation) Converged() error {
+	seqHeight := s.Sequencer.Store.Height()
+	for i, n := range s.FullNodes {
+		height := n.Store.Height()
+		if height > seqHeight {
+			return fmt.Errorf("full node %d: height %d, sequencer height %d", i, height, seqHeight)
+		}
+		for h := uint64(1); h <= height; h++ {
+			expected, err := s.Sequencer.Store.GetBlock(h)
+			if err != nil {
+				return err
+			}
+			block, err := n.Store.GetBlock(h)
+			if err != nil {
+				return fmt.Errorf("full node %d: %w", i, err)
+			}
+			if !bytes.Equal(block.Hash(), expected.Hash()) {
+				return fmt.Errorf("full node %d: block %d is %X, sequencer block is %X", i, h, block.Hash(), expected.Hash())
+			}
+		}
+		// app hash after executing block h is included in the header of block h+1
+		appHash := s.Sequencer.Executor.AppHash()
+		if height < seqHeight {
+			next, err := s.Sequencer.Store.GetBlock(height + 1)
+			if err != nil {
+				return err
+			}
+			appHash = next.SignedHeader.AppHash
+		}
+		if h := n.Executor.AppHash(); !bytes.Equal(h, appHash) {
+			return fmt.Errorf("full node %d: app hash %X at height %d, sequencer app hash %X", i, h, height, appHash)
+		}
+	}
+	return nil
+}
+
+// runUntil advances virtual clock step by step, until done returns true, or the clock reaches end. It returns the
+// last result of done.
+func (s *Simulation) runUntil(end time.Time, done func() bool) bool {
+	waitIdle()
+	for !done() {
+		now := s.Clock.Now()
+		if !now.Before(end) {
+			return false
+		}
+		next := now.Add(s.step)
+		if next.After(end) {
+			next = end
+		}
+		s.Clock.Set(next)
+		waitIdle()
+	}
+	return true
+}
@@ -0,0 +1,77 @@
+package sim
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// runSequencerOfflineScenario runs the network for 10 minutes, takes the sequencer offline for an hour
+// (DA reorgs twice in the meantime), and runs it again for 10 minutes. It returns app hash of the sequencer.
+func runSequencerOfflineScenario(t *testing.T, seed int64) []byte {
+	require := require.New(t)
+	ctx := context.Background()
+
+	sim, err := New(Config{Seed: seed, FullNodes: 2})
+	require.NoError(err)
+	require.NoError(sim.Start(ctx))
+	defer func() {
+		require.NoError(sim.Stop())
+	}()
+
+	blockTime := DefaultBlockManagerConfig().BlockTime
+	runWithTxs := func(d time.Duration) {
+		for elapsed := time.Duration(0); elapsed < d; elapsed += blockTime {
+			sim.InjectTxs(sim.Rand.Intn(5))
+			sim.Run(blockTime)
+		}
+	}
+
+	runWithTxs(10 * time.Minute)
+	require.NoError(sim.WaitForSync(10 * time.Minute))
+	require.NoError(sim.Converged())
+	heightBefore := sim.Sequencer.Store.Height()
+	require.Greater(heightBefore, uint64(0))
+
+	sim.Sequencer.Stop()
+	for i := 0; i < 2; i++ {
+		sim.InjectTxs(sim.Rand.Intn(10))
+		sim.Run(20 * time.Minute)
+		_, err := sim.ReorgDA(ctx, uint64(sim.Rand.Intn(5)+1))
+		require.NoError(err)
+	}
+	sim.Run(20 * time.Minute)
+	assert.Equal(t, heightBefore, sim.Sequencer.Store.Height())
+
+	sim.Sequencer.Start(ctx)
+	runWithTxs(10 * time.Minute)
+	require.NoError(sim.WaitForSync(10 * time.Minute))
+	require.NoError(sim.Converged())
+
+	// all transactions are applied on full nodes
+	for _, n := range sim.FullNodes {
+		for _, tx := range sim.Txs() {
+			key, value, _ := bytes.Cut(tx, []byte("="))
+			v, ok := n.Executor.Get(string(key))
+			if assert.True(t, ok, "missing key %s", key) {
+				assert.Equal(t, string(value), v)
+			}
+		}
+	}
+	return sim.Sequencer.Executor.AppHash()
+}
+
+func TestSequencerOfflineWithDAReorgs(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping simulation in short mode")
+	}
+	const seed = 42
+
+	appHash := runSequencerOfflineScenario(t, seed)
+	// same seed gives the same result
+	assert.Equal(t, appHash, runSequencerOfflineScenario(t, seed))
+}
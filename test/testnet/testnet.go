@@ -0,0 +1,362 @@
+// Package testnet runs in-process networks of Rollkit nodes, for use in integration tests.
+//
+// Network consists of single aggregator, any number of full and light nodes. All nodes share single mock
+// data availability layer, and communicate over libp2p mocknet, so no ports or other OS resources are used.
+// Helpers allow waiting for nodes to reach given height, injecting transactions, partitioning and healing
+// the P2P network and asserting that all nodes converged to the same chain.
+package testnet
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	testutils "github.com/celestiaorg/utils/test"
+	"github.com/cometbft/cometbft/abci/example/kvstore"
+	abci "github.com/cometbft/cometbft/abci/types"
+	"github.com/cometbft/cometbft/libs/log"
+	"github.com/cometbft/cometbft/proxy"
+	cmtypes "github.com/cometbft/cometbft/types"
+	"github.com/libp2p/go-libp2p/core/host"
+	"github.com/libp2p/go-libp2p/core/peer"
+	mocknet "github.com/libp2p/go-libp2p/p2p/net/mock"
+	"go.uber.org/multierr"
+
+	"github.com/rollkit/rollkit/config"
+	mockda "github.com/rollkit/rollkit/da/mock"
+	"github.com/rollkit/rollkit/node"
+	"github.com/rollkit/rollkit/store"
+	"github.com/rollkit/rollkit/types"
+)
+
+const (
+	retryAttempts = 300
+	retryInterval = 100 * time.Millisecond
+)
+
+// Config describes the network.
+type Config struct {
+	// FullNodes is the number of full nodes, in addition to the aggregator.
+	FullNodes int
+	// LightNodes is the number of light nodes.
+	LightNodes int
+	// BlockManagerConfig is used by all nodes. DefaultBlockManagerConfig is used if BlockTime is not set.
+	BlockManagerConfig config.BlockManagerConfig
+	// NewApp creates ABCI application for i-th node (aggregator is 0, light nodes are last).
+	// CometBFT kvstore is used by default.
+	NewApp func(i int) abci.Application
+	// Logger is used by all nodes (with "node" key). Testing logger is used by default.
+	Logger log.Logger
+}
+
+// DefaultBlockManagerConfig returns block manager configuration suitable for tests.
+func DefaultBlockManagerConfig() config.BlockManagerConfig {
+	return config.BlockManagerConfig{
+		DABlockTime: 100 * time.Millisecond,
+		BlockTime:   1 * time.Second, // blocks must be at least 1 sec apart for adjacent headers to get verified correctly
+		Namespace:   types.MustNewNamespace(types.NamespaceVersionZero, []byte{8, 7, 6, 5, 4, 3, 2, 1}),
+	}
+}
+
+// Network is an in-process network of Rollkit nodes.
+type Network struct {
+	Aggregator *node.FullNode
+	FullNodes  []*node.FullNode
+	LightNodes []*node.LightNode
+
+	// DALC is the data availability layer client shared by all full nodes.
+	DALC    *mockda.DataAvailabilityLayerClient
+	Genesis *cmtypes.GenesisDoc
+
+	mnet  mocknet.Mocknet
+	peers map[node.Node]peer.ID
+
+	// cut contains pairs of peers unlinked by Partition
+	cutMtx sync.Mutex
+	cut    map[[2]peer.ID]struct{}
+}
+
+// New creates all nodes of the network, without starting them. Network is stopped when test finishes.
+func New(t testing.TB, conf Config) (*Network, error) {
+	if conf.BlockManagerConfig.BlockTime == 0 {
+		conf.BlockManagerConfig = DefaultBlockManagerConfig()
+	}
+	if conf.NewApp == nil {
+		conf.NewApp = func(int) abci.Application { return kvstore.NewApplication() }
+	}
+	if conf.Logger == nil {
+		conf.Logger = log.TestingLogger()
+	}
+
+	validators, signingKey := types.GetGenesisValidatorSetWithSigner()
+	n := &Network{
+		Genesis: &cmtypes.GenesisDoc{ChainID: "testnet", InitialHeight: 1, Validators: validators},
+		mnet:    mocknet.New(),
+		peers:   make(map[node.Node]peer.ID),
+		cut:     make(map[[2]peer.ID]struct{}),
+	}
+	t.Cleanup(func() {
+		if err := n.Stop(); err != nil {
+			t.Error(err)
+		}
+	})
+
+	n.DALC = &mockda.DataAvailabilityLayerClient{}
+	dalcKV, err := store.NewDefaultInMemoryKVStore()
+	if err != nil {
+		return nil, err
+	}
+	daConfig := []byte(conf.BlockManagerConfig.DABlockTime.String())
+	if err := n.DALC.Init(conf.BlockManagerConfig.Namespace, daConfig, dalcKV, conf.Logger.With("module", "da_client")); err != nil {
+		return nil, fmt.Errorf("failed to initialize mock DA: %w", err)
+	}
+
+	// hosts are created upfront, because all full nodes are seeds for other nodes
+	total := 1 + conf.FullNodes + conf.LightNodes
+	hosts := make([]host.Host, total)
+	seeds := make([]string, 0, 1+conf.FullNodes)
+	for i := range hosts {
+		if hosts[i], err = n.mnet.GenPeer(); err != nil {
+			return nil, fmt.Errorf("failed to create libp2p host: %w", err)
+		}
+		if i <= conf.FullNodes {
+			seeds = append(seeds, fmt.Sprintf("%s/p2p/%s", hosts[i].Addrs()[0], hosts[i].ID()))
+		}
+	}
+	if err := n.mnet.LinkAll(); err != nil {
+		return nil, err
+	}
+
+	ctx := context.Background()
+	for i, h := range hosts {
+		// aggregator has no seeds - mocknet hosts are reachable before nodes are started, and aggregator
+		// would try to fetch the genesis header from them
+		p2pConf := config.P2PConfig{}
+		if i > 0 {
+			p2pConf.Seeds = strings.Join(seeds, ",")
+		}
+		nodeConf := config.NodeConfig{
+			P2P:                p2pConf,
+			DALayer:            "newda",
+			Aggregator:         i == 0,
+			Light:              i > conf.FullNodes,
+			BlockManagerConfig: conf.BlockManagerConfig,
+		}
+		nd, err := node.NewNode(
+			ctx,
+			nodeConf,
+			h.Peerstore().PrivKey(h.ID()),
+			signingKey,
+			proxy.NewLocalClientCreator(conf.NewApp(i)),
+			n.Genesis,
+			conf.Logger.With("node", i),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create node %d: %w", i, err)
+		}
+		n.peers[nd] = h.ID()
+
+		switch nd := nd.(type) {
+		case *node.FullNode:
+			nd.P2PClient().SetHost(h)
+			nd.SetDALC(n.DALC)
+			if i == 0 {
+				n.Aggregator = nd
+			} else {
+				n.FullNodes = append(n.FullNodes, nd)
+			}
+		case *node.LightNode:
+			nd.P2P.SetHost(h)
+			n.LightNodes = append(n.LightNodes, nd)
+		}
+	}
+
+	return n, nil
+}
+
+// Nodes returns all nodes of the network: aggregator, full nodes and light nodes.
+func (n *Network) Nodes() []node.Node {
+	var nodes []node.Node
+	if n.Aggregator != nil {
+		nodes = append(nodes, n.Aggregator)
+	}
+	for _, fn := range n.FullNodes {
+		nodes = append(nodes, fn)
+	}
+	for _, ln := range n.LightNodes {
+		nodes = append(nodes, ln)
+	}
+	return nodes
+}
+
+// Start starts the aggregator, waits for the first block and then starts all other nodes. It returns once all
+// nodes synced the second block, so transactions can be gossiped.
+func (n *Network) Start() error {
+	if err := n.DALC.Start(); err != nil {
+		return err
+	}
+	if err := n.Aggregator.Start(); err != nil {
+		return fmt.Errorf("failed to start aggregator: %w", err)
+	}
+	// header exchange service of other nodes is initialized with the first block
+	if err := n.WaitForHeight(n.Aggregator, 1, node.Header); err != nil {
+		return err
+	}
+	for i, nd := range n.Nodes()[1:] {
+		if err := nd.Start(); err != nil {
+			return fmt.Errorf("failed to start node %d: %w", i+1, err)
+		}
+	}
+	// transactions published before pubsub mesh is built are lost; waiting for the next block gives gossipsub
+	// a few heartbeats
+	return n.WaitForHeightAll(2)
+}
+
+// Stop stops all running nodes, in reverse order. It's safe to call Stop many times.
+func (n *Network) Stop() error {
+	var err error
+	nodes := n.Nodes()
+	for i := len(nodes) - 1; i >= 0; i-- {
+		if nodes[i].IsRunning() {
+			err = multierr.Append(err, nodes[i].Stop())
+		}
+	}
+	return multierr.Append(err, n.mnet.Close())
+}
+
+// WaitForHeight waits until the node reaches at least given height, as seen by given source.
+func (n *Network) WaitForHeight(nd node.Node, height uint64, source node.Source) error {
+	return testutils.Retry(retryAttempts, retryInterval, func() error {
+		h, err := node.GetNodeHeight(nd, source)
+		if err != nil {
+			return err
+		}
+		if h < height {
+			return fmt.Errorf("expected height >= %d, got %d", height, h)
+		}
+		return nil
+	})
+}
+
+// WaitForHeightAll waits until all nodes reach at least given height. Full nodes have to apply the blocks,
+// light nodes have to sync the headers.
+func (n *Network) WaitForHeightAll(height uint64) error {
+	for _, nd := range n.Nodes() {
+		if err := n.WaitForHeight(nd, height, heightSource(nd)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// InjectTx submits the transaction to the mempool of given full node. Transaction is gossiped to the aggregator.
+func (n *Network) InjectTx(ctx context.Context, nd *node.FullNode, tx cmtypes.Tx) error {
+	res, err := nd.GetClient().BroadcastTxSync(ctx, tx)
+	if err != nil {
+		return err
+	}
+	if res.Code != abci.CodeTypeOK {
+		return fmt.Errorf("transaction rejected with code %d: %s", res.Code, res.Log)
+	}
+	return nil
+}
+
+// Partition splits the P2P network into groups; nodes from different groups can't communicate.
+// Nodes that are not part of any group remain connected to all the nodes. Data availability layer is
+// still shared by all full nodes.
+func (n *Network) Partition(groups ...[]node.Node) error {
+	n.cutMtx.Lock()
+	defer n.cutMtx.Unlock()
+
+	for i := range groups {
+		for j := i + 1; j < len(groups); j++ {
+			for _, a := range groups[i] {
+				for _, b := range groups[j] {
+					pa, pb := n.peers[a], n.peers[b]
+					if _, ok := n.cut[[2]peer.ID{pa, pb}]; ok {
+						continue
+					}
+					if err := n.mnet.UnlinkPeers(pa, pb); err != nil {
+						return err
+					}
+					// closing connections is best-effort, peers may not be connected
+					_ = n.mnet.DisconnectPeers(pa, pb)
+					_ = n.mnet.DisconnectPeers(pb, pa)
+					n.cut[[2]peer.ID{pa, pb}] = struct{}{}
+					n.cut[[2]peer.ID{pb, pa}] = struct{}{}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// Heal restores all links removed by Partition and reconnects the peers.
+func (n *Network) Heal() error {
+	n.cutMtx.Lock()
+	defer n.cutMtx.Unlock()
+
+	for pair := range n.cut {
+		if pair[0] > pair[1] {
+			continue
+		}
+		if _, err := n.mnet.LinkPeers(pair[0], pair[1]); err != nil {
+			return err
+		}
+		if _, err := n.mnet.ConnectPeers(pair[0], pair[1]); err != nil {
+			return err
+		}
+	}
+	n.cut = make(map[[2]peer.ID]struct{})
+	return nil
+}
+
+// WaitForConvergence waits until all nodes reach current height of the aggregator and verifies that they
+// agree on the chain: headers at that height have to be identical on all nodes, and full nodes must have
+// applied the same block.
+func (n *Network) WaitForConvergence(ctx context.Context) error {
+	height, err := node.GetNodeHeight(n.Aggregator, node.Store)
+	if err != nil {
+		return err
+	}
+	if err := n.WaitForHeightAll(height); err != nil {
+		return err
+	}
+
+	expected, err := node.GetNodeHeader(ctx, n.Aggregator, height)
+	if err != nil {
+		return err
+	}
+	for i, nd := range n.Nodes()[1:] {
+		header, err := node.GetNodeHeader(ctx, nd, height)
+		if err != nil {
+			return fmt.Errorf("node %d: %w", i+1, err)
+		}
+		if !bytes.Equal(header.Hash(), expected.Hash()) {
+			return fmt.Errorf("node %d: header hash at height %d is %s, expected %s", i+1, height, header.Hash(), expected.Hash())
+		}
+		fn, ok := nd.(*node.FullNode)
+		if !ok {
+			continue
+		}
+		block, err := fn.Store.GetBlock(height)
+		if err != nil {
+			return fmt.Errorf("node %d: %w", i+1, err)
+		}
+		if !bytes.Equal(block.Hash(), expected.Hash()) {
+			return fmt.Errorf("node %d: block hash at height %d is %s, expected %s", i+1, height, block.Hash(), expected.Hash())
+		}
+	}
+	return nil
+}
+
+func heightSource(nd node.Node) node.Source {
+	if _, ok := nd.(*node.LightNode); ok {
+		return node.Header
+	}
+	return node.Store
+}
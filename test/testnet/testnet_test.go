@@ -0,0 +1,61 @@
+package testnet
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	testutils "github.com/celestiaorg/utils/test"
+	cmtypes "github.com/cometbft/cometbft/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rollkit/rollkit/node"
+)
+
+func TestNetwork(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping in-process network test in short mode")
+	}
+	assert := assert.New(t)
+	require := require.New(t)
+	ctx := context.Background()
+
+	net, err := New(t, Config{FullNodes: 2, LightNodes: 1})
+	require.NoError(err)
+	require.Len(net.Nodes(), 4)
+	require.NoError(net.Start())
+
+	// transaction submitted to full node is gossiped to the aggregator
+	require.NoError(net.InjectTx(ctx, net.FullNodes[0], cmtypes.Tx("key=value")))
+	require.NoError(net.WaitForHeightAll(3))
+	require.NoError(testutils.Retry(50, 100*time.Millisecond, func() error {
+		res, err := net.FullNodes[1].GetClient().ABCIQuery(ctx, "", []byte("key"))
+		if err != nil {
+			return err
+		}
+		if string(res.Response.Value) != "value" {
+			return fmt.Errorf("unexpected value: %q", res.Response.Value)
+		}
+		return nil
+	}))
+
+	// nodes in the same group as aggregator keep syncing
+	require.NoError(net.Partition(
+		[]node.Node{net.Aggregator, net.FullNodes[0]},
+		[]node.Node{net.FullNodes[1], net.LightNodes[0]},
+	))
+	aggHeight, err := node.GetNodeHeight(net.Aggregator, node.Store)
+	require.NoError(err)
+	require.NoError(net.WaitForHeight(net.Aggregator, aggHeight+2, node.Store))
+	require.NoError(net.WaitForHeight(net.FullNodes[0], aggHeight+2, node.Store))
+
+	require.NoError(net.Heal())
+	require.NoError(net.WaitForConvergence(ctx))
+
+	require.NoError(net.Stop())
+	for _, nd := range net.Nodes() {
+		assert.False(nd.IsRunning())
+	}
+}
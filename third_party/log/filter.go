@@ -0,0 +1,178 @@
+package log
+
+import (
+	"fmt"
+	"strings"
+	"sync/atomic"
+
+	cmlog "github.com/cometbft/cometbft/libs/log"
+)
+
+// Level defines severity of log messages.
+type Level int32
+
+// Supported log levels, from the most verbose.
+const (
+	LevelDebug Level = iota
+	LevelInfo
+	LevelWarn
+	LevelError
+	LevelNone
+)
+
+// moduleKey is the key used to tag loggers of node components (see cmlog.Logger.With).
+const moduleKey = "module"
+
+// ParseLevel converts textual representation of log level (debug, info, warn, error, none) into Level.
+func ParseLevel(lvl string) (Level, error) {
+	switch strings.ToLower(strings.TrimSpace(lvl)) {
+	case "debug", "":
+		return LevelDebug, nil
+	case "info":
+		return LevelInfo, nil
+	case "warn", "warning":
+		return LevelWarn, nil
+	case "error":
+		return LevelError, nil
+	case "none":
+		return LevelNone, nil
+	default:
+		return LevelNone, fmt.Errorf("unknown log level: %q (expected debug, info, warn, error or none)", lvl)
+	}
+}
+
+// Levels holds the default log level and levels of individual modules.
+type Levels struct {
+	Default Level
+	// Modules maps lower-cased module names to their levels.
+	Modules map[string]Level
+}
+
+// ParseLevels parses log level configuration.
+//
+// Configuration is either a single level (e.g. "info"), or a comma separated list of module:level pairs, where
+// "*" sets the default level (e.g. "p2p:error,blockmanager:debug,*:info"). Module names are case-insensitive.
+// Default level is debug, if it's not set.
+func ParseLevels(spec string) (Levels, error) {
+	levels := Levels{Default: LevelDebug, Modules: make(map[string]Level)}
+	if !strings.Contains(spec, ":") {
+		lvl, err := ParseLevel(spec)
+		levels.Default = lvl
+		return levels, err
+	}
+	for _, item := range strings.Split(spec, ",") {
+		module, lvlStr, ok := strings.Cut(strings.TrimSpace(item), ":")
+		module = strings.ToLower(strings.TrimSpace(module))
+		if !ok || module == "" {
+			return levels, fmt.Errorf("invalid log level entry: %q (expected module:level)", item)
+		}
+		lvl, err := ParseLevel(lvlStr)
+		if err != nil {
+			return levels, fmt.Errorf("invalid log level of module %q: %w", module, err)
+		}
+		if module == "*" {
+			levels.Default = lvl
+		} else {
+			levels.Modules[module] = lvl
+		}
+	}
+	return levels, nil
+}
+
+func (l *Levels) level(module string) Level {
+	if lvl, ok := l.Modules[module]; ok {
+		return lvl
+	}
+	return l.Default
+}
+
+// LevelFilter is a logger that drops messages below configured level.
+//
+// Level can be changed at runtime with SetLevel. Loggers created with With share the levels with
+// their parent, so changing the levels of the root filter affects all derived loggers. Logger derived
+// with "module" key uses the level configured for this module (if any).
+type LevelFilter struct {
+	next   cmlog.Logger
+	levels *atomic.Pointer[Levels]
+	module string
+}
+
+var _ cmlog.Logger = &LevelFilter{}
+
+// NewLevelFilter wraps given logger with LevelFilter. Empty level doesn't filter any messages.
+// See ParseLevels for the format of level configuration.
+func NewLevelFilter(next cmlog.Logger, level string) (*LevelFilter, error) {
+	levels, err := ParseLevels(level)
+	if err != nil {
+		return nil, err
+	}
+	f := &LevelFilter{next: next, levels: new(atomic.Pointer[Levels])}
+	f.levels.Store(&levels)
+	return f, nil
+}
+
+// SetLevel changes the levels of the filter (and all loggers derived from it).
+func (f *LevelFilter) SetLevel(level string) error {
+	levels, err := ParseLevels(level)
+	if err != nil {
+		return err
+	}
+	f.SetLevels(levels)
+	return nil
+}
+
+// SetLevels changes the levels of the filter (and all loggers derived from it) to already parsed levels.
+func (f *LevelFilter) SetLevels(levels Levels) {
+	f.levels.Store(&levels)
+}
+
+// Debug logs a message at debug level.
+func (f *LevelFilter) Debug(msg string, keyvals ...interface{}) {
+	if f.allowed(LevelDebug) {
+		f.next.Debug(msg, keyvals...)
+	}
+}
+
+// Info logs a message at info level.
+func (f *LevelFilter) Info(msg string, keyvals ...interface{}) {
+	if f.allowed(LevelInfo) {
+		f.next.Info(msg, keyvals...)
+	}
+}
+
+// Warn logs a message at warn level.
+func (f *LevelFilter) Warn(msg string, keyvals ...interface{}) {
+	if f.allowed(LevelWarn) {
+		Warn(f.next, msg, keyvals...)
+	}
+}
+
+// Error logs a message at error level.
+func (f *LevelFilter) Error(msg string, keyvals ...interface{}) {
+	if f.allowed(LevelError) {
+		f.next.Error(msg, keyvals...)
+	}
+}
+
+// With returns a new logger with given key-values, sharing levels with f.
+func (f *LevelFilter) With(keyvals ...interface{}) cmlog.Logger {
+	module := f.module
+	if m, ok := findModule(keyvals); ok {
+		module = m
+	}
+	return &LevelFilter{next: f.next.With(keyvals...), levels: f.levels, module: module}
+}
+
+func (f *LevelFilter) allowed(lvl Level) bool {
+	return lvl >= f.levels.Load().level(f.module)
+}
+
+// findModule returns lower-cased value of "module" key, if it's present in keyvals.
+func findModule(keyvals []interface{}) (string, bool) {
+	for i := 0; i+1 < len(keyvals); i += 2 {
+		if keyvals[i] == moduleKey {
+			return strings.ToLower(fmt.Sprint(keyvals[i+1])), true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,156 @@
+package log
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	cmlog "github.com/cometbft/cometbft/libs/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingLogger records logged messages as "level: msg keyvals".
+type recordingLogger struct {
+	keyvals []interface{}
+	lines   *[]string
+}
+
+func newRecordingLogger() *recordingLogger {
+	return &recordingLogger{lines: new([]string)}
+}
+
+func (l *recordingLogger) record(lvl, msg string, keyvals []interface{}) {
+	*l.lines = append(*l.lines, strings.TrimSpace(fmt.Sprintln(append([]interface{}{lvl + ":", msg}, append(l.keyvals, keyvals...)...)...)))
+}
+
+func (l *recordingLogger) Debug(msg string, keyvals ...interface{}) { l.record("debug", msg, keyvals) }
+func (l *recordingLogger) Info(msg string, keyvals ...interface{})  { l.record("info", msg, keyvals) }
+func (l *recordingLogger) Error(msg string, keyvals ...interface{}) { l.record("error", msg, keyvals) }
+
+func (l *recordingLogger) With(keyvals ...interface{}) cmlog.Logger {
+	return &recordingLogger{keyvals: append(l.keyvals[:len(l.keyvals):len(l.keyvals)], keyvals...), lines: l.lines}
+}
+
+func TestParseLevels(t *testing.T) {
+	cases := []struct {
+		spec     string
+		expected Levels
+		err      bool
+	}{
+		{"", Levels{Default: LevelDebug, Modules: map[string]Level{}}, false},
+		{"info", Levels{Default: LevelInfo, Modules: map[string]Level{}}, false},
+		{"WARNING", Levels{Default: LevelWarn, Modules: map[string]Level{}}, false},
+		{"p2p:error, BlockManager:debug, *:info", Levels{Default: LevelInfo, Modules: map[string]Level{"p2p": LevelError, "blockmanager": LevelDebug}}, false},
+		{"p2p:none", Levels{Default: LevelDebug, Modules: map[string]Level{"p2p": LevelNone}}, false},
+		{"verbose", Levels{}, true},
+		{"p2p:verbose", Levels{}, true},
+		{"p2p:error,info", Levels{}, true},
+	}
+	for _, c := range cases {
+		t.Run(c.spec, func(t *testing.T) {
+			levels, err := ParseLevels(c.spec)
+			if c.err {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, c.expected, levels)
+		})
+	}
+}
+
+func TestLevelFilterModules(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	rec := newRecordingLogger()
+	filter, err := NewLevelFilter(rec, "p2p:error,BlockManager:debug,*:info")
+	require.NoError(err)
+
+	p2p := filter.With("module", "p2p")
+	manager := filter.With("module", "BlockManager").With("height", 1)
+
+	filter.Debug("root debug")
+	filter.Info("root info")
+	p2p.Info("p2p info")
+	Warn(p2p, "p2p warn")
+	p2p.Error("p2p error")
+	manager.Debug("manager debug")
+	Warn(manager, "manager warn")
+
+	assert.Equal([]string{
+		"info: root info",
+		"error: p2p error module p2p",
+		"debug: manager debug module BlockManager height 1",
+		"info: manager warn module BlockManager height 1 severity warn",
+	}, *rec.lines)
+
+	// levels are shared with derived loggers
+	*rec.lines = nil
+	require.NoError(filter.SetLevel("error"))
+	manager.Info("manager info")
+	p2p.Error("p2p error")
+	assert.Equal([]string{"error: p2p error module p2p"}, *rec.lines)
+}
+
+func TestSampler(t *testing.T) {
+	assert := assert.New(t)
+
+	rec := newRecordingLogger()
+	sampler := NewSampler(rec, 10*time.Second)
+	now := time.Unix(0, 0)
+	sampler.state.now = func() time.Time { return now }
+	indexer := sampler.With("module", "txindex")
+
+	for i := 1; i <= 25; i++ {
+		indexer.Info("indexed block", "height", i)
+		sampler.Info("indexed block", "height", i)
+		if i == 3 {
+			indexer.Error("failed to index block", "height", i)
+			Warn(indexer, "slow indexing", "height", i)
+		}
+		now = now.Add(time.Second)
+	}
+
+	assert.Equal([]string{
+		"info: indexed block module txindex height 1",
+		"info: indexed block height 1",
+		"error: failed to index block module txindex height 3",
+		"info: slow indexing module txindex height 3 severity warn",
+		"info: indexed block module txindex height 11 suppressed 9",
+		"info: indexed block height 11 suppressed 9",
+		"info: indexed block module txindex height 21 suppressed 9",
+		"info: indexed block height 21 suppressed 9",
+	}, *rec.lines)
+}
+
+func TestNewLoggerJSON(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	var buf bytes.Buffer
+	logger, err := NewLogger(&buf, FormatJSON)
+	require.NoError(err)
+	Warn(logger.With("module", "p2p"), "peer disconnected", "peer", "abc")
+
+	var entry map[string]interface{}
+	require.NoError(json.Unmarshal(buf.Bytes(), &entry))
+	assert.Equal("warn", entry["level"])
+	assert.Equal("peer disconnected", entry["_msg"])
+	assert.Equal("p2p", entry["module"])
+	assert.Equal("abc", entry["peer"])
+	assert.Contains(entry, "ts")
+
+	buf.Reset()
+	logger, err = NewLogger(&buf, "")
+	require.NoError(err)
+	Warn(logger, "plain warning")
+	assert.True(strings.HasPrefix(buf.String(), "W["), buf.String())
+
+	_, err = NewLogger(&buf, "xml")
+	assert.Error(err)
+}
@@ -0,0 +1,98 @@
+package log
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	cmlog "github.com/cometbft/cometbft/libs/log"
+	kitlog "github.com/go-kit/log"
+	kitlevel "github.com/go-kit/log/level"
+	"github.com/go-kit/log/term"
+)
+
+// Supported log output formats.
+const (
+	FormatPlain = "plain"
+	FormatJSON  = "json"
+)
+
+// msgKey is the key of log message, the same as in CometBFT loggers.
+const msgKey = "_msg"
+
+// kitLogger is CometBFT compatible logger (see cmlog.NewTMLogger) supporting warn level.
+type kitLogger struct {
+	src kitlog.Logger
+}
+
+var (
+	_ cmlog.Logger = &kitLogger{}
+	_ Warner       = &kitLogger{}
+)
+
+// ValidateFormat checks if log output format is supported. Empty format means plain.
+func ValidateFormat(format string) error {
+	switch strings.ToLower(format) {
+	case "", FormatPlain, FormatJSON:
+		return nil
+	default:
+		return fmt.Errorf("unknown log format: %q (expected %s or %s)", format, FormatPlain, FormatJSON)
+	}
+}
+
+// NewLogger returns logger writing to w in given format: plain (CometBFT text format) or json
+// (single JSON object per line, with "level", "_msg" and "ts" keys).
+func NewLogger(w io.Writer, format string) (cmlog.Logger, error) {
+	if err := ValidateFormat(format); err != nil {
+		return nil, err
+	}
+	if strings.ToLower(format) == FormatJSON {
+		l := kitlog.NewJSONLogger(kitlog.NewSyncWriter(w))
+		return &kitLogger{src: kitlog.With(l, "ts", kitlog.DefaultTimestampUTC)}, nil
+	}
+	colorFn := func(keyvals ...interface{}) term.FgBgColor {
+		switch fmt.Sprint(keyvals[1]) {
+		case "debug":
+			return term.FgBgColor{Fg: term.DarkGray}
+		case "warn":
+			return term.FgBgColor{Fg: term.Yellow}
+		case "error":
+			return term.FgBgColor{Fg: term.Red}
+		default:
+			return term.FgBgColor{}
+		}
+	}
+	return &kitLogger{src: term.NewLogger(kitlog.NewSyncWriter(w), cmlog.NewTMFmtLogger, colorFn)}, nil
+}
+
+// Debug logs a message at debug level.
+func (l *kitLogger) Debug(msg string, keyvals ...interface{}) {
+	l.log(kitlevel.Debug(l.src), msg, keyvals)
+}
+
+// Info logs a message at info level.
+func (l *kitLogger) Info(msg string, keyvals ...interface{}) {
+	l.log(kitlevel.Info(l.src), msg, keyvals)
+}
+
+// Warn logs a message at warn level.
+func (l *kitLogger) Warn(msg string, keyvals ...interface{}) {
+	l.log(kitlevel.Warn(l.src), msg, keyvals)
+}
+
+// Error logs a message at error level.
+func (l *kitLogger) Error(msg string, keyvals ...interface{}) {
+	l.log(kitlevel.Error(l.src), msg, keyvals)
+}
+
+// With returns a new logger with given key-values.
+func (l *kitLogger) With(keyvals ...interface{}) cmlog.Logger {
+	return &kitLogger{src: kitlog.With(l.src, keyvals...)}
+}
+
+func (l *kitLogger) log(leveled kitlog.Logger, msg string, keyvals []interface{}) {
+	withMsg := kitlog.With(leveled, msgKey, msg)
+	if err := withMsg.Log(keyvals...); err != nil {
+		kitlevel.Error(l.src).Log(msgKey, msg, "err", err) //nolint:errcheck // no need to check error again
+	}
+}
@@ -6,3 +6,17 @@ type Logger interface {
 	Info(msg string, keyvals ...interface{})
 	Error(msg string, keyvals ...interface{})
 }
+
+// Warner is implemented by loggers supporting warn level (like loggers created with NewLogger).
+type Warner interface {
+	Warn(msg string, keyvals ...interface{})
+}
+
+// Warn logs a message at warn level. If logger doesn't support warn level, message is logged at info level.
+func Warn(logger Logger, msg string, keyvals ...interface{}) {
+	if w, ok := logger.(Warner); ok {
+		w.Warn(msg, keyvals...)
+		return
+	}
+	logger.Info(msg, append(keyvals[:len(keyvals):len(keyvals)], "severity", "warn")...)
+}
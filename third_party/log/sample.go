@@ -0,0 +1,115 @@
+package log
+
+import (
+	"sync"
+	"time"
+
+	cmlog "github.com/cometbft/cometbft/libs/log"
+)
+
+// maxSampledMessages limits the number of tracked messages; entries older than sampling interval are
+// removed when the limit is exceeded.
+const maxSampledMessages = 1024
+
+// Sampler is a logger that limits repetitive debug and info messages (like messages logged for every block).
+//
+// Each message (identified by module and text) is logged at most once per interval. The number of dropped
+// occurrences is added to the next logged occurrence, with "suppressed" key. Warn and error messages are
+// never dropped. Loggers created with With share the state with their parent.
+type Sampler struct {
+	next     cmlog.Logger
+	interval time.Duration
+	module   string
+	state    *samplerState
+}
+
+type samplerState struct {
+	mtx     sync.Mutex
+	now     func() time.Time
+	entries map[string]*sampleEntry
+}
+
+type sampleEntry struct {
+	last       time.Time
+	suppressed uint64
+}
+
+var (
+	_ cmlog.Logger = &Sampler{}
+	_ Warner       = &Sampler{}
+)
+
+// NewSampler wraps given logger with Sampler, logging each debug and info message at most once per interval.
+func NewSampler(next cmlog.Logger, interval time.Duration) *Sampler {
+	return &Sampler{
+		next:     next,
+		interval: interval,
+		state:    &samplerState{now: time.Now, entries: make(map[string]*sampleEntry)},
+	}
+}
+
+// Debug logs a message at debug level, unless it was logged recently.
+func (s *Sampler) Debug(msg string, keyvals ...interface{}) {
+	if keyvals, ok := s.sample(msg, keyvals); ok {
+		s.next.Debug(msg, keyvals...)
+	}
+}
+
+// Info logs a message at info level, unless it was logged recently.
+func (s *Sampler) Info(msg string, keyvals ...interface{}) {
+	if keyvals, ok := s.sample(msg, keyvals); ok {
+		s.next.Info(msg, keyvals...)
+	}
+}
+
+// Warn logs a message at warn level.
+func (s *Sampler) Warn(msg string, keyvals ...interface{}) {
+	Warn(s.next, msg, keyvals...)
+}
+
+// Error logs a message at error level.
+func (s *Sampler) Error(msg string, keyvals ...interface{}) {
+	s.next.Error(msg, keyvals...)
+}
+
+// With returns a new logger with given key-values, sharing sampling state with s.
+func (s *Sampler) With(keyvals ...interface{}) cmlog.Logger {
+	module := s.module
+	if m, ok := findModule(keyvals); ok {
+		module = m
+	}
+	return &Sampler{next: s.next.With(keyvals...), interval: s.interval, module: module, state: s.state}
+}
+
+// sample returns true if message should be logged, along with key-values extended with the number of
+// suppressed occurrences.
+func (s *Sampler) sample(msg string, keyvals []interface{}) ([]interface{}, bool) {
+	key := s.module + "\x00" + msg
+	st := s.state
+	st.mtx.Lock()
+	defer st.mtx.Unlock()
+
+	now := st.now()
+	e, ok := st.entries[key]
+	if !ok {
+		if len(st.entries) >= maxSampledMessages {
+			for k, old := range st.entries {
+				if now.Sub(old.last) >= s.interval {
+					delete(st.entries, k)
+				}
+			}
+		}
+		st.entries[key] = &sampleEntry{last: now}
+		return keyvals, true
+	}
+	if now.Sub(e.last) < s.interval {
+		e.suppressed++
+		return nil, false
+	}
+	suppressed := e.suppressed
+	e.last, e.suppressed = now, 0
+	if suppressed > 0 {
+		keyvals = append(keyvals[:len(keyvals):len(keyvals)], "suppressed", suppressed)
+	}
+	return keyvals, true
+}
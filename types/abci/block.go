@@ -1,6 +1,9 @@
 package abci
 
 import (
+	"errors"
+	"fmt"
+
 	cmbytes "github.com/cometbft/cometbft/libs/bytes"
 	cmproto "github.com/cometbft/cometbft/proto/tendermint/types"
 	cmversion "github.com/cometbft/cometbft/proto/tendermint/version"
@@ -35,7 +38,7 @@ func ToABCIHeaderPB(header *types.Header) (cmproto.Header, error) {
 		ProposerAddress:    header.ProposerAddress,
 		ChainID:            header.ChainID(),
 		ValidatorsHash:     header.ValidatorHash,
-		NextValidatorsHash: header.ValidatorHash,
+		NextValidatorsHash: header.NextValidatorsHash(),
 	}, nil
 }
 
@@ -61,7 +64,7 @@ func ToABCIHeader(header *types.Header) (cmtypes.Header, error) {
 		ConsensusHash:      cmbytes.HexBytes(header.ConsensusHash),
 		AppHash:            cmbytes.HexBytes(header.AppHash),
 		ValidatorsHash:     cmbytes.HexBytes(header.ValidatorHash),
-		NextValidatorsHash: cmbytes.HexBytes(header.ValidatorHash),
+		NextValidatorsHash: cmbytes.HexBytes(header.NextValidatorsHash()),
 		LastResultsHash:    cmbytes.HexBytes(header.LastResultsHash),
 		EvidenceHash:       new(cmtypes.EvidenceData).Hash(),
 		ProposerAddress:    header.ProposerAddress,
@@ -102,6 +105,43 @@ func ToABCIBlock(block *types.Block) (*cmtypes.Block, error) {
 	return &abciBlock, nil
 }
 
+// ToABCISignedHeader converts Rollkit signed header into CometBFT signed header, with commit verifiable by
+// CometBFT light client.
+func ToABCISignedHeader(header *types.SignedHeader) (*cmtypes.SignedHeader, error) {
+	abciHeader, err := ToABCIHeader(&header.Header)
+	if err != nil {
+		return nil, err
+	}
+	abciCommit := header.Commit.ToABCICommit(header.Height(), header.Hash(), header.ProposerAddress, header.Time())
+	return &cmtypes.SignedHeader{
+		Header: &abciHeader,
+		Commit: abciCommit,
+	}, nil
+}
+
+// ToABCILightBlock converts Rollkit signed header into CometBFT light block (signed header with validator set).
+//
+// NextValidatorsHash is the hash of validator set of the next block, signed as a part of the header (see
+// types.Header.NextValidatorsHash), so light clients can verify adjacent headers across validator set changes.
+// Returned light block passes `ValidateBasic`.
+func ToABCILightBlock(header *types.SignedHeader) (*cmtypes.LightBlock, error) {
+	if header.Validators == nil || len(header.Validators.Validators) == 0 {
+		return nil, errors.New("light block can't be created for header without validators")
+	}
+	signedHeader, err := ToABCISignedHeader(header)
+	if err != nil {
+		return nil, err
+	}
+	lightBlock := &cmtypes.LightBlock{
+		SignedHeader: signedHeader,
+		ValidatorSet: header.Validators,
+	}
+	if err := lightBlock.ValidateBasic(header.ChainID()); err != nil {
+		return nil, fmt.Errorf("invalid light block at height %d: %w", header.Height(), err)
+	}
+	return lightBlock, nil
+}
+
 // ToABCIBlockMeta converts Rollkit block into BlockMeta format defined by ABCI
 func ToABCIBlockMeta(block *types.Block) (*cmtypes.BlockMeta, error) {
 	cmblock, err := ToABCIBlock(block)
@@ -11,9 +11,6 @@ import (
 	cmtypes "github.com/cometbft/cometbft/types"
 )
 
-// NamespaceID is a unique identifier of a namespace.
-type NamespaceID [8]byte
-
 // Version captures the consensus rules for processing a block in the blockchain,
 // including all blockchain data structures and the rules of the application's
 // state transition machine.
@@ -33,7 +33,7 @@ func (h *Header) Hash() Hash {
 
 		// Backward compatibility
 		ValidatorsHash:     cmbytes.HexBytes(h.ValidatorHash),
-		NextValidatorsHash: cmbytes.HexBytes(h.ValidatorHash),
+		NextValidatorsHash: cmbytes.HexBytes(h.NextValidatorsHash()),
 
 		ChainID: h.ChainID(),
 	}
@@ -43,6 +43,8 @@ type Header struct {
 
 	// compablity with light client
 	ValidatorHash Hash
+	// hash of validator set of the next block; empty if validator set doesn't change (see NextValidatorsHash)
+	NextValidatorHash Hash
 
 	// Root hash of all results from the txs from the previous block.
 	// This is ABCI specific but smart-contract chains require some way of committing
@@ -81,6 +83,15 @@ func (h *Header) LastHeader() Hash {
 	return h.LastHeaderHash[:]
 }
 
+// NextValidatorsHash returns hash of validator set of the next block. Headers without NextValidatorHash (including
+// headers created before the field was introduced) commit to the same validator set at the next height.
+func (h *Header) NextValidatorsHash() Hash {
+	if len(h.NextValidatorHash) == 0 {
+		return h.ValidatorHash
+	}
+	return h.NextValidatorHash
+}
+
 // Time returns timestamp as unix time with nanosecond precision
 func (h *Header) Time() time.Time {
 	return time.Unix(0, int64(h.BaseHeader.Time))
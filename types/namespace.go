@@ -0,0 +1,106 @@
+package types
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// MaxNamespaceIDSize is the maximum size of namespace identifier, in bytes.
+const MaxNamespaceIDSize = 32
+
+// NamespaceVersionZero is the first namespace version. Celestia DA layer client maps it to Celestia version 0
+// blob namespace, with up to 10 bytes of identifier.
+const NamespaceVersionZero = uint8(0)
+
+// Namespace identifies data of a chain in DA layer. It consists of a version and variable length identifier;
+// supported versions and identifier sizes depend on DA layer. Zero value means that namespace is not set.
+//
+// Namespace is comparable, so it can be used as a map key.
+type Namespace struct {
+	version uint8
+	id      string
+}
+
+// NewNamespace returns namespace with given version and identifier of 1 to MaxNamespaceIDSize bytes.
+func NewNamespace(version uint8, id []byte) (Namespace, error) {
+	if len(id) == 0 || len(id) > MaxNamespaceIDSize {
+		return Namespace{}, fmt.Errorf("namespace identifier must have 1 to %d bytes, but it has %d", MaxNamespaceIDSize, len(id))
+	}
+	return Namespace{version: version, id: string(id)}, nil
+}
+
+// MustNewNamespace returns namespace with given version and identifier, it panics if identifier is invalid.
+func MustNewNamespace(version uint8, id []byte) Namespace {
+	ns, err := NewNamespace(version, id)
+	if err != nil {
+		panic(err)
+	}
+	return ns
+}
+
+// ParseNamespace parses namespace in text form: "<version>:<hex encoded identifier>". Identifier without
+// version (legacy form, used by 8 byte namespace IDs) is version 0. Empty string is parsed as zero Namespace.
+func ParseNamespace(s string) (Namespace, error) {
+	if s == "" {
+		return Namespace{}, nil
+	}
+	version := NamespaceVersionZero
+	idHex := s
+	if v, id, ok := strings.Cut(s, ":"); ok {
+		parsed, err := strconv.ParseUint(v, 10, 8)
+		if err != nil {
+			return Namespace{}, fmt.Errorf("invalid namespace version %q: %w", v, err)
+		}
+		version, idHex = uint8(parsed), id
+	}
+	id, err := hex.DecodeString(idHex)
+	if err != nil {
+		return Namespace{}, fmt.Errorf("invalid namespace identifier %q: %w", idHex, err)
+	}
+	return NewNamespace(version, id)
+}
+
+// Version returns namespace version.
+func (n Namespace) Version() uint8 {
+	return n.version
+}
+
+// ID returns namespace identifier.
+func (n Namespace) ID() []byte {
+	return []byte(n.id)
+}
+
+// Bytes returns version followed by identifier.
+func (n Namespace) Bytes() []byte {
+	return append([]byte{n.version}, n.id...)
+}
+
+// IsZero returns true if namespace is not set.
+func (n Namespace) IsZero() bool {
+	return n == Namespace{}
+}
+
+// String returns namespace in text form accepted by ParseNamespace, or empty string if namespace is not set.
+func (n Namespace) String() string {
+	if n.IsZero() {
+		return ""
+	}
+	return strconv.Itoa(int(n.version)) + ":" + hex.EncodeToString([]byte(n.id))
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (n Namespace) MarshalText() ([]byte, error) {
+	return []byte(n.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (n *Namespace) UnmarshalText(text []byte) error {
+	ns, err := ParseNamespace(string(text))
+	if err != nil {
+		return err
+	}
+	*n = ns
+	return nil
+}
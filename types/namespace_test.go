@@ -0,0 +1,61 @@
+package types
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseNamespace(t *testing.T) {
+	cases := []struct {
+		name     string
+		input    string
+		expected Namespace
+		wantErr  bool
+	}{
+		{"empty", "", Namespace{}, false},
+		{"legacy 8 bytes", "0102030405060708", MustNewNamespace(NamespaceVersionZero, []byte{1, 2, 3, 4, 5, 6, 7, 8}), false},
+		{"version 0", "0:0102030405060708090a", MustNewNamespace(NamespaceVersionZero, []byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}), false},
+		{"other version", "7:ff", MustNewNamespace(7, []byte{0xff}), false},
+		{"max size", "1:" + string(bytes.Repeat([]byte("ab"), MaxNamespaceIDSize)), MustNewNamespace(1, bytes.Repeat([]byte{0xab}, MaxNamespaceIDSize)), false},
+		{"too long", "1:" + string(bytes.Repeat([]byte("ab"), MaxNamespaceIDSize+1)), Namespace{}, true},
+		{"empty identifier", "0:", Namespace{}, true},
+		{"invalid version", "256:01", Namespace{}, true},
+		{"invalid hex", "0:xyz", Namespace{}, true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			ns, err := ParseNamespace(c.input)
+			if c.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, c.expected, ns)
+		})
+	}
+}
+
+func TestNamespaceRoundTrip(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	ns := MustNewNamespace(3, []byte{1, 2, 3})
+	assert.Equal("3:010203", ns.String())
+	assert.Equal([]byte{3, 1, 2, 3}, ns.Bytes())
+	assert.False(ns.IsZero())
+
+	text, err := ns.MarshalText()
+	require.NoError(err)
+	var parsed Namespace
+	require.NoError(parsed.UnmarshalText(text))
+	assert.Equal(ns, parsed)
+
+	// namespaces with the same identifier, but different versions are different
+	assert.NotEqual(ns, MustNewNamespace(4, []byte{1, 2, 3}))
+
+	assert.True(Namespace{}.IsZero())
+	assert.Empty(Namespace{}.String())
+}
@@ -110,6 +110,8 @@ type Header struct {
 	ValidatorHash []byte `protobuf:"bytes,11,opt,name=validator_hash,json=validatorHash,proto3" json:"validator_hash,omitempty"`
 	// Chain ID the block belongs to
 	ChainId string `protobuf:"bytes,12,opt,name=chain_id,json=chainId,proto3" json:"chain_id,omitempty"`
+	// Hash of validator set of the next block; if empty, validator set of the next block is the same
+	NextValidatorHash []byte `protobuf:"bytes,13,opt,name=next_validator_hash,json=nextValidatorHash,proto3" json:"next_validator_hash,omitempty"`
 }
 
 func (m *Header) Reset()         { *m = Header{} }
@@ -229,6 +231,13 @@ func (m *Header) GetChainId() string {
 	return ""
 }
 
+func (m *Header) GetNextValidatorHash() []byte {
+	if m != nil {
+		return m.NextValidatorHash
+	}
+	return nil
+}
+
 type Commit struct {
 	Signatures [][]byte `protobuf:"bytes,1,rep,name=signatures,proto3" json:"signatures,omitempty"`
 }
@@ -510,46 +519,47 @@ func init() {
 func init() { proto.RegisterFile("rollkit/rollkit.proto", fileDescriptor_ed489fb7f4d78b3f) }
 
 var fileDescriptor_ed489fb7f4d78b3f = []byte{
-	// 619 bytes of a gzipped FileDescriptorProto
+	// 634 bytes of a gzipped FileDescriptorProto
 	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x5c, 0x94, 0xc1, 0x6e, 0x13, 0x3d,
-	0x10, 0xc7, 0xbb, 0x49, 0x9a, 0x6d, 0xa7, 0xdb, 0x36, 0x9f, 0xf5, 0xb5, 0x2c, 0x20, 0xad, 0xc2,
-	0x4a, 0x88, 0x50, 0xa4, 0x44, 0x94, 0x0b, 0xe2, 0x80, 0x44, 0x01, 0xa9, 0xb9, 0x21, 0x07, 0x15,
-	0x89, 0xcb, 0xca, 0xc9, 0x9a, 0xac, 0xd5, 0x64, 0x6d, 0xd9, 0x4e, 0x15, 0xde, 0x82, 0x47, 0xe0,
-	0x01, 0x78, 0x10, 0x8e, 0x3d, 0x72, 0x44, 0xed, 0x8b, 0x20, 0x8f, 0x77, 0xb7, 0x81, 0xcb, 0xae,
-	0xe7, 0x3f, 0x3f, 0x8f, 0x67, 0x3c, 0x23, 0xc3, 0x91, 0x96, 0x8b, 0xc5, 0xa5, 0xb0, 0xa3, 0xea,
-	0x3f, 0x54, 0x5a, 0x5a, 0x49, 0xc2, 0xca, 0x7c, 0xd0, 0xb7, 0xbc, 0xcc, 0xb9, 0x5e, 0x8a, 0xd2,
-	0x8e, 0xec, 0x57, 0xc5, 0xcd, 0xe8, 0x8a, 0x2d, 0x44, 0xce, 0xac, 0xd4, 0x1e, 0x4d, 0x9f, 0x43,
-	0x78, 0xc1, 0xb5, 0x11, 0xb2, 0x24, 0xff, 0xc3, 0xf6, 0x74, 0x21, 0x67, 0x97, 0x71, 0xd0, 0x0f,
-	0x06, 0x1d, 0xea, 0x0d, 0xd2, 0x83, 0x36, 0x53, 0x2a, 0x6e, 0xa1, 0xe6, 0x96, 0xe9, 0x8f, 0x36,
-	0x74, 0xcf, 0x39, 0xcb, 0xb9, 0x26, 0x27, 0x10, 0x5e, 0xf9, 0xdd, 0xb8, 0x69, 0xef, 0xb4, 0x37,
-	0xac, 0x33, 0xa9, 0xa2, 0xd2, 0x1a, 0x20, 0xc7, 0xd0, 0x2d, 0xb8, 0x98, 0x17, 0xb6, 0x8a, 0x55,
-	0x59, 0x84, 0x40, 0xc7, 0x8a, 0x25, 0x8f, 0xdb, 0xa8, 0xe2, 0x9a, 0x0c, 0xa0, 0xb7, 0x60, 0xc6,
-	0x66, 0x05, 0x1e, 0x93, 0x15, 0xcc, 0x14, 0x71, 0xa7, 0x1f, 0x0c, 0x22, 0x7a, 0xe0, 0x74, 0x7f,
-	0xfa, 0x39, 0x33, 0x45, 0x43, 0xce, 0xe4, 0x72, 0x29, 0xac, 0x27, 0xb7, 0xef, 0xc8, 0xb7, 0x28,
-	0x23, 0xf9, 0x10, 0x76, 0x73, 0x66, 0x99, 0x47, 0xba, 0x88, 0xec, 0x38, 0x01, 0x9d, 0x8f, 0xe1,
-	0x60, 0x26, 0x4b, 0xc3, 0x4b, 0xb3, 0x32, 0x9e, 0x08, 0x91, 0xd8, 0x6f, 0x54, 0xc4, 0xee, 0xc3,
-	0x0e, 0x53, 0xca, 0x03, 0x3b, 0x08, 0x84, 0x4c, 0x29, 0x74, 0x9d, 0xc0, 0x7f, 0x98, 0x88, 0xe6,
-	0x66, 0xb5, 0xb0, 0x55, 0x90, 0x5d, 0x64, 0x0e, 0x9d, 0x83, 0x7a, 0x1d, 0xd9, 0xa7, 0xd0, 0x53,
-	0x5a, 0x2a, 0x69, 0xb8, 0xce, 0x58, 0x9e, 0x6b, 0x6e, 0x4c, 0x0c, 0x1e, 0xad, 0xf5, 0x37, 0x5e,
-	0x76, 0x89, 0x35, 0x2d, 0xf3, 0x31, 0xf7, 0x7c, 0x62, 0x8d, 0x5a, 0x27, 0x36, 0x2b, 0x98, 0x28,
-	0x33, 0x91, 0xc7, 0x51, 0x3f, 0x18, 0xec, 0xd2, 0x10, 0xed, 0x71, 0x9e, 0x0e, 0xa0, 0xeb, 0x6f,
-	0x81, 0x24, 0x00, 0x46, 0xcc, 0x4b, 0x66, 0x57, 0x9a, 0x9b, 0x38, 0xe8, 0xb7, 0x07, 0x11, 0xdd,
-	0x50, 0xd2, 0xef, 0x01, 0x44, 0x13, 0x31, 0x2f, 0x79, 0x5e, 0xb5, 0xf7, 0x89, 0x6b, 0x99, 0x5b,
-	0x55, 0xdd, 0x3d, 0x6c, 0xba, 0xeb, 0x01, 0x5a, 0xb9, 0x1d, 0xe8, 0x1b, 0x80, 0xbd, 0xdd, 0x04,
-	0xfd, 0xd1, 0xb4, 0x72, 0x93, 0xd7, 0x00, 0x4d, 0xe2, 0x06, 0x5b, 0xbe, 0x77, 0x9a, 0x0c, 0xef,
-	0xa6, 0x74, 0x88, 0x53, 0x3a, 0xbc, 0xa8, 0x99, 0x09, 0xb7, 0x74, 0x63, 0x47, 0x4a, 0xa1, 0xf3,
-	0x8e, 0x59, 0xe6, 0xa6, 0xd2, 0xae, 0xeb, 0x1a, 0xdc, 0x92, 0xbc, 0x84, 0x58, 0x94, 0x96, 0xeb,
-	0x25, 0xcf, 0x05, 0xb3, 0x3c, 0x33, 0xd6, 0x7d, 0xb5, 0x94, 0xd6, 0xc4, 0x2d, 0xc4, 0x8e, 0x37,
-	0xfd, 0x13, 0xe7, 0xa6, 0xce, 0x9b, 0x7e, 0x81, 0xed, 0x33, 0x1c, 0xf5, 0x57, 0xb0, 0x6f, 0xb0,
-	0xfc, 0xec, 0xaf, 0xaa, 0x8f, 0x9a, 0x62, 0x36, 0x2f, 0x87, 0x46, 0x66, 0xf3, 0xaa, 0x1e, 0x41,
-	0xc7, 0x0d, 0x53, 0x55, 0xff, 0x7e, 0xb3, 0xc5, 0x65, 0x4b, 0xd1, 0x95, 0x7e, 0x00, 0xf8, 0xb8,
-	0xfe, 0x24, 0x6c, 0x31, 0x9e, 0x50, 0x43, 0xee, 0x41, 0xa8, 0x34, 0xcf, 0x84, 0xf1, 0xc7, 0x44,
-	0xb4, 0xab, 0x34, 0x1f, 0x1b, 0x4d, 0x0e, 0xa0, 0x65, 0xd7, 0x18, 0x27, 0xa2, 0x2d, 0xbb, 0x76,
-	0xad, 0x55, 0xd2, 0x58, 0x24, 0xdb, 0x7e, 0xe6, 0x9c, 0x3d, 0x36, 0xfa, 0xec, 0xfd, 0xcf, 0x9b,
-	0x24, 0xb8, 0xbe, 0x49, 0x82, 0xdf, 0x37, 0x49, 0xf0, 0xed, 0x36, 0xd9, 0xba, 0xbe, 0x4d, 0xb6,
-	0x7e, 0xdd, 0x26, 0x5b, 0x9f, 0x9f, 0xcd, 0x85, 0x2d, 0x56, 0xd3, 0xe1, 0x4c, 0x2e, 0x47, 0xff,
-	0xbc, 0x11, 0xd5, 0x43, 0xa0, 0xa6, 0xb5, 0x30, 0xed, 0xe2, 0x53, 0xf0, 0xe2, 0x4f, 0x00, 0x00,
-	0x00, 0xff, 0xff, 0x01, 0xd1, 0x4b, 0xe5, 0x4e, 0x04, 0x00, 0x00,
+	0x10, 0xc7, 0xbb, 0x49, 0x9a, 0xb4, 0xd3, 0x4d, 0x9b, 0xfa, 0xfb, 0xda, 0x6f, 0x3f, 0x90, 0x56,
+	0x61, 0x25, 0x44, 0x28, 0xd2, 0x46, 0x94, 0x0b, 0xe2, 0x80, 0x44, 0x01, 0xa9, 0xb9, 0x21, 0x07,
+	0x15, 0x89, 0xcb, 0xca, 0xc9, 0x9a, 0xac, 0xd5, 0x64, 0x6d, 0xd9, 0x4e, 0x15, 0xde, 0x82, 0x47,
+	0xe0, 0x71, 0x38, 0xf6, 0xc8, 0x81, 0x03, 0x6a, 0x5f, 0x04, 0x79, 0xbc, 0xd9, 0x86, 0x5e, 0x5a,
+	0xcf, 0x7f, 0x7e, 0x1e, 0xcf, 0xac, 0xff, 0x31, 0x1c, 0x69, 0x39, 0x9f, 0x5f, 0x0a, 0x3b, 0xac,
+	0xfe, 0xa7, 0x4a, 0x4b, 0x2b, 0x49, 0xa7, 0x0a, 0x1f, 0xf4, 0x2d, 0x2f, 0x73, 0xae, 0x17, 0xa2,
+	0xb4, 0x43, 0xfb, 0x55, 0x71, 0x33, 0xbc, 0x62, 0x73, 0x91, 0x33, 0x2b, 0xb5, 0x47, 0x93, 0xe7,
+	0xd0, 0xb9, 0xe0, 0xda, 0x08, 0x59, 0x92, 0x7f, 0x61, 0x7b, 0x32, 0x97, 0xd3, 0xcb, 0x28, 0xe8,
+	0x07, 0x83, 0x16, 0xf5, 0x01, 0xe9, 0x41, 0x93, 0x29, 0x15, 0x35, 0x50, 0x73, 0xcb, 0xe4, 0x57,
+	0x13, 0xda, 0xe7, 0x9c, 0xe5, 0x5c, 0x93, 0x13, 0xe8, 0x5c, 0xf9, 0xdd, 0xb8, 0x69, 0xef, 0xb4,
+	0x97, 0xae, 0x3b, 0xa9, 0xaa, 0xd2, 0x35, 0x40, 0x8e, 0xa1, 0x5d, 0x70, 0x31, 0x2b, 0x6c, 0x55,
+	0xab, 0x8a, 0x08, 0x81, 0x96, 0x15, 0x0b, 0x1e, 0x35, 0x51, 0xc5, 0x35, 0x19, 0x40, 0x6f, 0xce,
+	0x8c, 0xcd, 0x0a, 0x3c, 0x26, 0x2b, 0x98, 0x29, 0xa2, 0x56, 0x3f, 0x18, 0x84, 0x74, 0xdf, 0xe9,
+	0xfe, 0xf4, 0x73, 0x66, 0x8a, 0x9a, 0x9c, 0xca, 0xc5, 0x42, 0x58, 0x4f, 0x6e, 0xdf, 0x91, 0x6f,
+	0x51, 0x46, 0xf2, 0x21, 0xec, 0xe6, 0xcc, 0x32, 0x8f, 0xb4, 0x11, 0xd9, 0x71, 0x02, 0x26, 0x1f,
+	0xc3, 0xfe, 0x54, 0x96, 0x86, 0x97, 0x66, 0x69, 0x3c, 0xd1, 0x41, 0xa2, 0x5b, 0xab, 0x88, 0xfd,
+	0x0f, 0x3b, 0x4c, 0x29, 0x0f, 0xec, 0x20, 0xd0, 0x61, 0x4a, 0x61, 0xea, 0x04, 0x0e, 0xb1, 0x11,
+	0xcd, 0xcd, 0x72, 0x6e, 0xab, 0x22, 0xbb, 0xc8, 0x1c, 0xb8, 0x04, 0xf5, 0x3a, 0xb2, 0x4f, 0xa1,
+	0xa7, 0xb4, 0x54, 0xd2, 0x70, 0x9d, 0xb1, 0x3c, 0xd7, 0xdc, 0x98, 0x08, 0x3c, 0xba, 0xd6, 0xdf,
+	0x78, 0xd9, 0x35, 0x56, 0x5f, 0x99, 0xaf, 0xb9, 0xe7, 0x1b, 0xab, 0xd5, 0x75, 0x63, 0xd3, 0x82,
+	0x89, 0x32, 0x13, 0x79, 0x14, 0xf6, 0x83, 0xc1, 0x2e, 0xed, 0x60, 0x3c, 0xca, 0x49, 0x0a, 0xff,
+	0x94, 0x7c, 0x65, 0xb3, 0x7b, 0x65, 0xba, 0x58, 0xe6, 0xd0, 0xa5, 0x2e, 0x36, 0x4b, 0x25, 0x03,
+	0x68, 0xfb, 0xaf, 0x46, 0x62, 0x00, 0x23, 0x66, 0x25, 0xb3, 0x4b, 0xcd, 0x4d, 0x14, 0xf4, 0x9b,
+	0x83, 0x90, 0x6e, 0x28, 0xc9, 0xf7, 0x00, 0xc2, 0xb1, 0x98, 0x95, 0x3c, 0xaf, 0xec, 0xf0, 0xc4,
+	0x5d, 0xb1, 0x5b, 0x55, 0x6e, 0x38, 0xa8, 0xdd, 0xe0, 0x01, 0xda, 0x2e, 0x6a, 0xd0, 0x5f, 0x58,
+	0xd4, 0xb8, 0x07, 0xfa, 0xa3, 0x69, 0x95, 0x26, 0xaf, 0x01, 0xea, 0xbe, 0x0d, 0x5a, 0x64, 0xef,
+	0x34, 0x4e, 0xef, 0x5c, 0x9d, 0xa2, 0xab, 0xd3, 0x7a, 0x82, 0x31, 0xb7, 0x74, 0x63, 0x47, 0x42,
+	0xa1, 0xf5, 0x8e, 0x59, 0xe6, 0x5c, 0x6c, 0x57, 0xeb, 0x19, 0xdc, 0x92, 0xbc, 0x84, 0x48, 0x94,
+	0x96, 0xeb, 0x05, 0xcf, 0x05, 0xb3, 0x3c, 0x33, 0xd6, 0xfd, 0xd5, 0x52, 0x5a, 0x13, 0x35, 0x10,
+	0x3b, 0xde, 0xcc, 0x8f, 0x5d, 0x9a, 0xba, 0x6c, 0xf2, 0x05, 0xb6, 0xcf, 0xf0, 0xa7, 0xf1, 0x0a,
+	0xba, 0x06, 0xc7, 0xcf, 0xfe, 0x9a, 0xfa, 0xa8, 0x1e, 0x66, 0xf3, 0xe3, 0xd0, 0xd0, 0x6c, 0x44,
+	0xe4, 0x11, 0xb4, 0x9c, 0xf9, 0xaa, 0xf9, 0xbb, 0xf5, 0x16, 0xd7, 0x2d, 0xc5, 0x54, 0xf2, 0x01,
+	0xe0, 0xe3, 0xea, 0x93, 0xb0, 0xc5, 0x68, 0x4c, 0x0d, 0xf9, 0x0f, 0x3a, 0x4a, 0xf3, 0x4c, 0x18,
+	0x7f, 0x4c, 0x48, 0xdb, 0x4a, 0xf3, 0x91, 0xd1, 0x64, 0x1f, 0x1a, 0x76, 0x85, 0x75, 0x42, 0xda,
+	0xb0, 0x2b, 0x67, 0x05, 0x25, 0x8d, 0x45, 0xb2, 0xe9, 0x3d, 0xea, 0xe2, 0x91, 0xd1, 0x67, 0xef,
+	0x3f, 0x3f, 0x9b, 0x09, 0x5b, 0x2c, 0x27, 0xe9, 0x54, 0x2e, 0x86, 0xf7, 0xde, 0x8e, 0xea, 0x81,
+	0x50, 0x93, 0xb5, 0xf0, 0xe3, 0x26, 0x0e, 0xae, 0x6f, 0xe2, 0xe0, 0xf7, 0x4d, 0x1c, 0x7c, 0xbb,
+	0x8d, 0xb7, 0xae, 0x6f, 0xe3, 0xad, 0x9f, 0xb7, 0xf1, 0xd6, 0xa4, 0x8d, 0x4f, 0xc7, 0x8b, 0x3f,
+	0x03, 0x00, 0x4d, 0x2f, 0x45, 0x18, 0x7e, 0x04, 0x00, 0x00,
 }
 
 func (m *Version) Marshal() (dAtA []byte, err error) {
@@ -605,6 +615,13 @@ func (m *Header) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if len(m.NextValidatorHash) > 0 {
+		i -= len(m.NextValidatorHash)
+		copy(dAtA[i:], m.NextValidatorHash)
+		i = encodeVarintRollkit(dAtA, i, uint64(len(m.NextValidatorHash)))
+		i--
+		dAtA[i] = 0x6a
+	}
 	if len(m.ChainId) > 0 {
 		i -= len(m.ChainId)
 		copy(dAtA[i:], m.ChainId)
@@ -994,6 +1011,10 @@ func (m *Header) Size() (n int) {
 	if l > 0 {
 		n += 1 + l + sovRollkit(uint64(l))
 	}
+	l = len(m.NextValidatorHash)
+	if l > 0 {
+		n += 1 + l + sovRollkit(uint64(l))
+	}
 	return n
 }
 
@@ -1593,6 +1614,40 @@ func (m *Header) Unmarshal(dAtA []byte) error {
 			}
 			m.ChainId = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
+		case 13:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field NextValidatorHash", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRollkit
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthRollkit
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthRollkit
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.NextValidatorHash = append(m.NextValidatorHash[:0], dAtA[iNdEx:postIndex]...)
+			if m.NextValidatorHash == nil {
+				m.NextValidatorHash = []byte{}
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipRollkit(dAtA[iNdEx:])
@@ -150,6 +150,8 @@ func (h *Header) ToProto() *pb.Header {
 		ProposerAddress: h.ProposerAddress[:],
 		ChainId:         h.BaseHeader.ChainID,
 		ValidatorHash:   h.ValidatorHash,
+		// set only if validator set changes, so hashes and encoding of other headers are not affected
+		NextValidatorHash: h.NextValidatorHash,
 	}
 }
 
@@ -171,6 +173,7 @@ func (h *Header) FromProto(other *pb.Header) error {
 		copy(h.ProposerAddress, other.ProposerAddress)
 	}
 	h.ValidatorHash = other.ValidatorHash
+	h.NextValidatorHash = other.NextValidatorHash
 	return nil
 }
 
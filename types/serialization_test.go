@@ -41,13 +41,15 @@ func TestBlockSerializationRoundTrip(t *testing.T) {
 			Height: 3,
 			Time:   4567,
 		},
-		LastHeaderHash:  h[0],
-		LastCommitHash:  h[1],
-		DataHash:        h[2],
-		ConsensusHash:   h[3],
-		AppHash:         h[4],
-		LastResultsHash: h[5],
-		ProposerAddress: []byte{4, 3, 2, 1},
+		LastHeaderHash:    h[0],
+		LastCommitHash:    h[1],
+		DataHash:          h[2],
+		ConsensusHash:     h[3],
+		AppHash:           h[4],
+		LastResultsHash:   h[5],
+		ValidatorHash:     h[6],
+		NextValidatorHash: h[7],
+		ProposerAddress:   []byte{4, 3, 2, 1},
 	}
 
 	pubKey1 := ed25519.GenPrivKey().PubKey()